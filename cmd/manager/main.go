@@ -28,6 +28,7 @@ import (
 
 	"github.com/openshift/hive/pkg/apis"
 	"github.com/openshift/hive/pkg/controller"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	"github.com/openshift/hive/pkg/controller/utils"
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -71,12 +72,23 @@ func newRootCommand() *cobra.Command {
 			}
 
 			// Create a new Cmd to provide shared dependencies and start components
-			mgr, err := manager.New(cfg, manager.Options{
-				MetricsBindAddress: ":2112",
-			})
+			metricsOptions := hivemetrics.ServerOptionsFromEnv()
+			managerOptions := manager.Options{
+				MetricsBindAddress: metricsOptions.ManagerBindAddress(),
+			}
+			if namespaces := utils.TargetNamespacesFromEnv(); len(namespaces) > 0 {
+				log.WithField("namespaces", namespaces).Info("restricting controllers to target namespaces")
+				managerOptions.NewCache = utils.NewMultiNamespaceCache(namespaces)
+			}
+			mgr, err := manager.New(cfg, managerOptions)
 			if err != nil {
 				log.Fatal(err)
 			}
+			if metricsServer := metricsOptions.TLSServer(); metricsServer != nil {
+				if err := mgr.Add(metricsServer); err != nil {
+					log.Fatal(err)
+				}
+			}
 
 			log.Printf("Registering Components.")
 
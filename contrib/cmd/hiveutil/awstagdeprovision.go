@@ -18,6 +18,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 
@@ -25,33 +26,44 @@ import (
 	"github.com/spf13/cobra"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/hive/pkg/install"
 )
 
 // NewDeprovisionAWSWithTagsCommand is the entrypoint to create the 'aws-tag-deprovision' subcommand
 func NewDeprovisionAWSWithTagsCommand() *cobra.Command {
 	opt := &aws.ClusterUninstaller{}
 	var logLevel string
+	var reportFile string
+	counter := install.NewDeletionCounter()
 	cmd := &cobra.Command{
 		Use:   "aws-tag-deprovision KEY=VALUE ...",
 		Short: "Deprovision AWS assets (as created by openshift-installer) with the given tag(s)",
 		Long:  "Deprovision AWS assets (as created by openshift-installer) with the given tag(s).  A resource matches the filter if any of the key/value pairs are in its tags.",
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := completeAWSUninstaller(opt, logLevel, args); err != nil {
+			if err := completeAWSUninstaller(opt, logLevel, args, counter); err != nil {
 				log.WithError(err).Error("Cannot complete command")
 				return
 			}
 			if err := opt.Run(); err != nil {
 				log.WithError(err).Error("Runtime error")
+				return
+			}
+			if reportFile != "" {
+				if err := writeDeprovisionReport(counter, reportFile); err != nil {
+					log.WithError(err).Error("Cannot write deprovision report")
+				}
 			}
 		},
 	}
 	flags := cmd.Flags()
 	flags.StringVar(&logLevel, "loglevel", "info", "log level, one of: debug, info, warn, error, fatal, panic")
 	flags.StringVar(&opt.Region, "region", "us-east-1", "AWS region to use")
+	flags.StringVar(&reportFile, "report-file", "", "file to write the counts of the deleted resources to")
 	return cmd
 }
 
-func completeAWSUninstaller(o *aws.ClusterUninstaller, logLevel string, args []string) error {
+func completeAWSUninstaller(o *aws.ClusterUninstaller, logLevel string, args []string, counter *install.DeletionCounter) error {
 	for _, arg := range args {
 		filter := aws.Filter{}
 		err := parseFilter(filter, arg)
@@ -68,18 +80,29 @@ func completeAWSUninstaller(o *aws.ClusterUninstaller, logLevel string, args []s
 		return err
 	}
 
-	o.Logger = log.NewEntry(&log.Logger{
+	logger := &log.Logger{
 		Out: os.Stdout,
 		Formatter: &log.TextFormatter{
 			FullTimestamp: true,
 		},
 		Hooks: make(log.LevelHooks),
 		Level: level,
-	})
+	}
+	logger.AddHook(counter)
+	o.Logger = log.NewEntry(logger)
 
 	return nil
 }
 
+// writeDeprovisionReport writes the counts of the resources deleted by the uninstaller to the given file.
+func writeDeprovisionReport(counter *install.DeletionCounter, reportFile string) error {
+	report, err := counter.Report()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(reportFile, report, 0644)
+}
+
 func parseFilter(filterMap aws.Filter, str string) error {
 	parts := strings.SplitN(str, "=", 2)
 	if len(parts) != 2 {
@@ -24,6 +24,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/hive/contrib/pkg/createcluster"
+	"github.com/openshift/hive/contrib/pkg/installconfig"
 	"github.com/openshift/hive/contrib/pkg/report"
 	"github.com/openshift/hive/contrib/pkg/testresource"
 	"github.com/openshift/hive/contrib/pkg/verification"
@@ -60,6 +61,7 @@ func newCOUtilityCommand() *cobra.Command {
 	cmd.AddCommand(testresource.NewTestResourceCommand())
 	cmd.AddCommand(createcluster.NewCreateClusterCommand())
 	cmd.AddCommand(report.NewClusterReportCommand())
+	cmd.AddCommand(installconfig.NewInstallConfigCommand())
 
 	return cmd
 }
@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installconfig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	contributils "github.com/openshift/hive/contrib/pkg/utils"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/install"
+)
+
+const (
+	adminSSHKeySecretKey = "ssh-publickey"
+
+	// hiveNamespace is the namespace holding the default secrets named in the HiveConfig.
+	hiveNamespace = "hive"
+
+	// hiveConfigName is the name of the HiveConfig the hive controllers are configured from.
+	hiveConfigName = "hive"
+
+	// redactedPullSecret stands in for the pull secret unless it is asked for, so that the output can be
+	// shared safely.
+	redactedPullSecret = `{"auths":{}}`
+)
+
+// Options is the set of options for rendering the install-config of a cluster deployment.
+type Options struct {
+	// Name is the name of the cluster deployment.
+	Name string
+	// Namespace is the namespace of the cluster deployment.
+	Namespace string
+	// IncludePullSecret includes the real pull secret in the output rather than a placeholder.
+	IncludePullSecret bool
+}
+
+// NewInstallConfigCommand creates a command that prints the install-config hive generates for a cluster
+// deployment, without launching an install. The defaults the clusterdeployment controller applies from the
+// HiveConfig, such as the default SSH key, are applied in the same way.
+func NewInstallConfigCommand() *cobra.Command {
+	opt := &Options{}
+	cmd := &cobra.Command{
+		Use:   "install-config CLUSTER_DEPLOYMENT_NAME",
+		Short: "Prints the install-config hive generates for a cluster deployment",
+		Run: func(cmd *cobra.Command, args []string) {
+			log.SetLevel(log.InfoLevel)
+			if err := opt.Complete(cmd, args); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+
+			dynClient, err := contributils.GetClient()
+			if err != nil {
+				log.WithError(err).Fatal("error creating kube clients")
+			}
+
+			if err := opt.Run(dynClient, os.Stdout); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.Namespace, "namespace", "n", "", "Namespace of the cluster deployment")
+	flags.BoolVar(&opt.IncludePullSecret, "include-pull-secret", false, "Include the pull secret in the output rather than a placeholder")
+	return cmd
+}
+
+// Complete finishes parsing arguments for the command
+func (o *Options) Complete(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		cmd.Usage()
+		return fmt.Errorf("expected a cluster deployment name")
+	}
+	o.Name = args[0]
+	if o.Namespace == "" {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+		ns, _, err := kubeconfig.Namespace()
+		if err != nil {
+			return err
+		}
+		o.Namespace = ns
+	}
+	return nil
+}
+
+// Run executes the command
+func (o *Options) Run(c client.Client, out io.Writer) error {
+	cd := &hivev1.ClusterDeployment{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: o.Namespace, Name: o.Name}, cd); err != nil {
+		return err
+	}
+
+	hiveConfig := &hivev1.HiveConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: hiveConfigName}, hiveConfig); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	sshKey := ""
+	if name, namespace := secretWithDefault(cd.Spec.SSHKey, hiveConfig.Spec.DefaultSSHKey, cd.Namespace); name != "" {
+		var err error
+		sshKey, err = controllerutils.LoadSecretData(c, name, namespace, adminSSHKeySecretKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	pullSecret := redactedPullSecret
+	var pullSecretRef *corev1.LocalObjectReference
+	if cd.Spec.PullSecret.Name != "" {
+		pullSecretRef = &cd.Spec.PullSecret
+	}
+	if name, namespace := secretWithDefault(pullSecretRef, hiveConfig.Spec.DefaultPullSecret, cd.Namespace); o.IncludePullSecret && name != "" {
+		var err error
+		pullSecret, err = controllerutils.LoadSecretData(c, name, namespace, corev1.DockerConfigJsonKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	additionalTrustBundle := ""
+	if cd.Spec.AdditionalTrustBundle != nil {
+		var err error
+		additionalTrustBundle, err = controllerutils.LoadSecretData(c, cd.Spec.AdditionalTrustBundle.Name, cd.Namespace, hivev1.AdditionalTrustBundleSecretKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	installConfig, err := install.GenerateInstallConfigYAML(cd, sshKey, pullSecret, additionalTrustBundle)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(out, installConfig)
+	return err
+}
+
+// secretWithDefault returns the name and namespace of the secret the clusterdeployment controller uses: the
+// secret of the cluster deployment when it names one, otherwise the default secret in the hive namespace. An
+// empty name is returned when there is neither.
+func secretWithDefault(ref, defaultRef *corev1.LocalObjectReference, namespace string) (string, string) {
+	if ref != nil && ref.Name != "" {
+		return ref.Name, namespace
+	}
+	if defaultRef != nil && defaultRef.Name != "" {
+		return defaultRef.Name, hiveNamespace
+	}
+	return "", ""
+}
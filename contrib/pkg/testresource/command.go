@@ -118,7 +118,7 @@ func newPatchCommand() *cobra.Command {
 				return
 			}
 			_, ok := patchTypes[patchTypeStr]
-			if !ok {
+			if !ok && patchTypeStr != resource.AutoPatchType {
 				fmt.Printf("Invalid patch type %s\n", patchTypeStr)
 				cmd.Usage()
 				return
@@ -150,7 +150,7 @@ func newPatchCommand() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&kubeconfigPath, "kubeconfig", "k", os.Getenv("KUBECONFIG"), "Kubeconfig file to connect to target server")
-	cmd.Flags().StringVar(&patchTypeStr, "type", "strategic", "Type of patch to apply. Available types are: json, merge, strategic")
+	cmd.Flags().StringVar(&patchTypeStr, "type", "strategic", "Type of patch to apply. Available types are: json, merge, strategic, auto")
 	cmd.Flags().StringVar(&namespace, "namespace", "", "Namespace of resource to patch")
 	cmd.Flags().StringVar(&name, "name", "", "Name of the resource to patch")
 	cmd.Flags().StringVar(&kind, "kind", "", "Kind of the resource to patch")
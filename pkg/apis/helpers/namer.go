@@ -59,6 +59,15 @@ func GetResourceName(base, suffix string) string {
 	return GetName(base, suffix, validation.DNS1123LabelMaxLength)
 }
 
+// GetLabelValue returns value unchanged when it fits in a label value. Longer values are truncated and given a
+// hash of the full value in the same way as GetName, so that long object names can still be used in labels.
+func GetLabelValue(value, suffix string) string {
+	if len(value) <= validation.LabelValueMaxLength {
+		return value
+	}
+	return GetName(value, suffix, validation.LabelValueMaxLength)
+}
+
 // max returns the greater of its 2 inputs
 func max(a, b int) int {
 	if b > a {
@@ -105,6 +105,21 @@ func TestGetNameReturnShortNames(t *testing.T) {
 	}
 }
 
+func TestGetLabelValue(t *testing.T) {
+	short := randSeq(63)
+	if got := GetLabelValue(short, "cd"); got != short {
+		t.Errorf("expected short value to be unchanged, got %q", got)
+	}
+	long := randSeq(100)
+	got := GetLabelValue(long, "cd")
+	if errs := validation.IsValidLabelValue(got); len(errs) > 0 {
+		t.Errorf("invalid label value %q: %v", got, errs)
+	}
+	if other := GetLabelValue(long+"x", "cd"); other == got {
+		t.Errorf("expected different values for different long values: %q", got)
+	}
+}
+
 // From k8s.io/kubernetes/pkg/api/generator.go
 var letters = []rune("abcdefghijklmnopqrstuvwxyz0123456789-")
 
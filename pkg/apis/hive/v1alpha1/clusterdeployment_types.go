@@ -21,6 +21,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	openshiftapiv1 "github.com/openshift/api/config/v1"
 	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/networkoperator/v1"
@@ -34,6 +35,10 @@ const (
 	// job before cleaning up the API object.
 	FinalizerDeprovision string = "hive.openshift.io/deprovision"
 
+	// DefaultAWSCredentialsSecretName is the name of the secret holding the AWS credentials used for AWS
+	// ClusterDeployments in the same namespace which do not set PlatformSecrets.AWS.
+	DefaultAWSCredentialsSecretName string = "default-aws-credentials"
+
 	// HiveClusterTypeLabel is an optional label that can be applied to ClusterDeployments. It is
 	// shown in short output, usable in searching, and adds metrics vectors which can be used to
 	// alert on cluster types differently.
@@ -49,6 +54,14 @@ const (
 
 	// HiveInstallLogLabel is used on ConfigMaps uploaded by the install manager which contain an install log.
 	HiveInstallLogLabel = "hive.openshift.io/install-log"
+
+	// AdditionalTrustBundleSecretKey is the key in the secret referenced by a ClusterDeployment's
+	// AdditionalTrustBundle which holds the PEM-encoded certificate bundle.
+	AdditionalTrustBundleSecretKey = "ca-bundle.crt"
+
+	// BootstrapIgnitionOverrideSecretKey is the key in the secret referenced by a ClusterDeployment's
+	// BootstrapIgnitionOverrideRef which holds the bootstrap ignition config.
+	BootstrapIgnitionOverrideSecretKey = "bootstrap.ign"
 )
 
 // ClusterDeploymentSpec defines the desired state of ClusterDeployment
@@ -84,11 +97,14 @@ type ClusterDeploymentSpec struct {
 	// +required
 	Platform `json:"platform"`
 
-	// PullSecret is the reference to the secret to use when pulling images.
-	// +required
+	// PullSecret is the reference to the secret to use when pulling images. When unset, the default pull
+	// secret configured in the HiveConfig is used.
+	// +optional
 	PullSecret corev1.LocalObjectReference `json:"pullSecret"`
 
-	// PlatformSecrets contains credentials and secrets for the cluster infrastructure.
+	// PlatformSecrets contains credentials and secrets for the cluster infrastructure. When no AWS
+	// credentials are set for an AWS cluster, the default-aws-credentials secret in the namespace of the
+	// ClusterDeployment is used if it exists.
 	// +required
 	PlatformSecrets PlatformSecrets `json:"platformSecrets"`
 
@@ -118,6 +134,107 @@ type ClusterDeploymentSpec struct {
 	// for this ClusterDeployment
 	// +optional
 	ManageDNS bool `json:"manageDNS,omitempty"`
+
+	// ManagedDNSRecordTTL is the TTL, in seconds, for the DNS records created for a managed DNS zone.
+	// If unset, the DNSZone default is used.
+	// +optional
+	ManagedDNSRecordTTL TTL `json:"managedDNSRecordTTL,omitempty"`
+
+	// AdditionalDNSRecords is a list of DNS records to create in the managed DNS zone in addition to the
+	// records required by the cluster, for example a vanity CNAME. Only used when ManageDNS is true.
+	// +optional
+	AdditionalDNSRecords []Endpoint `json:"additionalDNSRecords,omitempty"`
+
+	// ManagedDNSZoneRef references an existing DNSZone, in the ClusterDeployment's namespace, which is
+	// managed outside of hive. When set along with ManageDNS, hive waits for the referenced zone to become
+	// available instead of creating its own, and never deletes it.
+	// +optional
+	ManagedDNSZoneRef *corev1.LocalObjectReference `json:"managedDNSZoneRef,omitempty"`
+
+	// ManagedDNSZoneName is the name of the DNSZone created by hive when ManageDNS is true. Defaults to a name
+	// derived from the ClusterDeployment name. It cannot be set along with ManagedDNSZoneRef.
+	// +optional
+	ManagedDNSZoneName string `json:"managedDNSZoneName,omitempty"`
+
+	// AdditionalTrustBundle is a reference to a secret containing a PEM-encoded X.509 certificate bundle,
+	// stored under the "ca-bundle.crt" key, that the installer and the cluster's nodes should trust. This is
+	// typically required when installing through a proxy or from a registry using a private certificate authority.
+	// +optional
+	AdditionalTrustBundle *corev1.LocalObjectReference `json:"additionalTrustBundle,omitempty"`
+
+	// BootstrapIgnitionOverrideRef is a reference to a secret containing an ignition config, stored under the
+	// "bootstrap.ign" key, which replaces the bootstrap ignition config generated by the installer.
+	// +optional
+	BootstrapIgnitionOverrideRef *corev1.LocalObjectReference `json:"bootstrapIgnitionOverrideRef,omitempty"`
+
+	// ManifestsConfigMapRef is a reference to a config map of additional manifests which are added to the
+	// manifests generated by the installer. Each key is the file name of a manifest and must end in .yaml,
+	// .yml or .json.
+	// +optional
+	ManifestsConfigMapRef *corev1.LocalObjectReference `json:"manifestsConfigMapRef,omitempty"`
+
+	// ImageContentSources lists sources/repositories for the release-image content, used to install
+	// from a mirror registry in disconnected environments.
+	// +optional
+	ImageContentSources []ImageContentSource `json:"imageContentSources,omitempty"`
+
+	// InstallTokenAudience, when set, mounts a projected service account token with this audience into
+	// the install pod, for installers which must authenticate to external systems.
+	// +optional
+	InstallTokenAudience string `json:"installTokenAudience,omitempty"`
+
+	// PostInstallManifests is a list of manifests to apply to the cluster once it has been installed. They
+	// are synced to the cluster through a SyncSet owned by the ClusterDeployment.
+	// +optional
+	PostInstallManifests []runtime.RawExtension `json:"postInstallManifests,omitempty"`
+
+	// InstallConfigSecret, when true, stores the generated install-config in a Secret rather than a ConfigMap,
+	// as it may contain sensitive values such as proxy credentials. Defaults to false.
+	// +optional
+	InstallConfigSecret bool `json:"installConfigSecret,omitempty"`
+
+	// InstallPodRestartPolicy is the restart policy of the install pod. OnFailure retries a failed install
+	// within the same pod, while Never has the install job create a new pod for each attempt. Defaults to
+	// OnFailure, and is always Never for clusters which should only try to install once.
+	// +kubebuilder:validation:Enum=OnFailure,Never
+	// +optional
+	InstallPodRestartPolicy corev1.RestartPolicy `json:"installPodRestartPolicy,omitempty"`
+
+	// InstallJobPriorityClassName is the name of the PriorityClass used for the install pod, so that
+	// installs are not preempted by lower priority workloads on busy clusters.
+	// +optional
+	InstallJobPriorityClassName string `json:"installJobPriorityClassName,omitempty"`
+
+	// InstallJobPodAnnotations are added to the install pod, for example to opt the pod out of service mesh
+	// sidecar injection. Changing them results in a new install job while the cluster is installing.
+	// Annotations set by hive on the install pod take precedence.
+	// +optional
+	InstallJobPodAnnotations map[string]string `json:"installJobPodAnnotations,omitempty"`
+
+	// ConsoleRoute is the route in the cluster from which the web console URL is read, for clusters which
+	// serve the web console from a different route. Defaults to the console route in the openshift-console
+	// namespace.
+	// +optional
+	ConsoleRoute *ConsoleRouteReference `json:"consoleRoute,omitempty"`
+}
+
+// ConsoleRouteReference is a reference to the route of the web console in a cluster.
+type ConsoleRouteReference struct {
+	// Namespace is the namespace of the route.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the route.
+	Name string `json:"name"`
+}
+
+// ImageContentSource defines a list of sources/repositories that can be used to pull content.
+type ImageContentSource struct {
+	// Source is the repository that users refer to, e.g. in image pull specifications.
+	Source string `json:"source"`
+
+	// Mirrors is one or more repositories that may also contain the same images.
+	// +optional
+	Mirrors []string `json:"mirrors,omitempty"`
 }
 
 // ProvisionImages allows overriding the default images used to provision a cluster.
@@ -157,6 +274,13 @@ type AWSPlatformSecrets struct {
 	// Credentials refers to a secret that contains the AWS account access
 	// credentials.
 	Credentials corev1.LocalObjectReference `json:"credentials"`
+
+	// DNSCredentials refers to a secret that contains the access credentials of the AWS account
+	// hosting the managed DNS zone of the cluster, for when DNS is delegated to a different account
+	// than the one the cluster is installed in. Credentials is used for the managed DNS zone when
+	// this is unset.
+	// +optional
+	DNSCredentials *corev1.LocalObjectReference `json:"dnsCredentials,omitempty"`
 }
 
 // ClusterDeploymentStatus defines the observed state of ClusterDeployment
@@ -177,6 +301,15 @@ type ClusterDeploymentStatus struct {
 	// InstallRestarts is the total count of container restarts on the clusters install job.
 	InstallRestarts int `json:"installRestarts,omitempty"`
 
+	// InstallPodTerminationReason is the most common reason the containers of the clusters install pods
+	// last terminated, for example OOMKilled or Error.
+	InstallPodTerminationReason string `json:"installPodTerminationReason,omitempty"`
+
+	// InstallPhase is the phase the running install has reached, for example Bootstrapping, as last reported
+	// by the install manager. It is cleared once the cluster is installed.
+	// +optional
+	InstallPhase string `json:"installPhase,omitempty"`
+
 	// FederatedClusterRef is the reference to the federated cluster resource associated with
 	// this ClusterDeployment.
 	FederatedClusterRef *corev1.ObjectReference `json:"federatedClusterRef,omitempty"`
@@ -190,12 +323,21 @@ type ClusterDeploymentStatus struct {
 	// ClusterVersionStatus will hold a copy of the remote cluster's ClusterVersion.Status
 	ClusterVersionStatus openshiftapiv1.ClusterVersionStatus `json:"clusterVersionStatus,omitempty"`
 
+	// ClusterVersionLastCheckTime is the last time the remote cluster's ClusterVersion was fetched.
+	// +optional
+	ClusterVersionLastCheckTime *metav1.Time `json:"clusterVersionLastCheckTime,omitempty"`
+
 	// APIURL is the URL where the cluster's API can be accessed.
 	APIURL string `json:"apiURL,omitempty"`
 
 	// WebConsoleURL is the URL for the cluster's web console UI.
 	WebConsoleURL string `json:"webConsoleURL,omitempty"`
 
+	// IngressDomainMigrations records the ingress domains in the spec which were rewritten by hive
+	// to remove a leading wildcard.
+	// +optional
+	IngressDomainMigrations []IngressDomainMigration `json:"ingressDomainMigrations,omitempty"`
+
 	// SyncSetStatus is the list of status for SyncSets which apply to the cluster deployment.
 	// +optional
 	SyncSetStatus []SyncSetObjectStatus `json:"syncSetStatus,omitempty"`
@@ -208,6 +350,17 @@ type ClusterDeploymentStatus struct {
 	// +optional
 	InstallerImage *string `json:"installerImage,omitempty"`
 
+	// InstallerImageReleaseImage is the release image InstallerImage was resolved from, when it was resolved
+	// from a release image. The installer image is resolved again when the release image changes before the
+	// cluster is installed.
+	// +optional
+	InstallerImageReleaseImage string `json:"installerImageReleaseImage,omitempty"`
+
+	// ImageResolutionTimedOutGeneration is the generation of the cluster deployment for which the installer image
+	// was not resolved in time. No imageset job is created again until the generation changes.
+	// +optional
+	ImageResolutionTimedOutGeneration int64 `json:"imageResolutionTimedOutGeneration,omitempty"`
+
 	// Conditions includes more detailed status for the cluster deployment
 	// +optional
 	Conditions []ClusterDeploymentCondition `json:"conditions,omitempty"`
@@ -215,6 +368,60 @@ type ClusterDeploymentStatus struct {
 	// CertificateBundles contains of the status of the certificate bundles associated with this cluster deployment.
 	// +optional
 	CertificateBundles []CertificateBundleStatus `json:"certificateBundles,omitempty"`
+
+	// InstallStartedTimestamp is when the first install job for the cluster was created. It is not reset when
+	// the install job is replaced.
+	// +optional
+	InstallStartedTimestamp *metav1.Time `json:"installStartedTimestamp,omitempty"`
+
+	// InstalledTimestamp is when the cluster finished installing.
+	// +optional
+	InstalledTimestamp *metav1.Time `json:"installedTimestamp,omitempty"`
+
+	// InstallDuration is how long the install has taken, from InstallStartedTimestamp to InstalledTimestamp, in a
+	// human readable form. While the install is running it is the time elapsed so far, rounded down to the
+	// minute.
+	// +optional
+	InstallDuration string `json:"installDuration,omitempty"`
+
+	// InstallMetadata is a subset of the installer metadata for the cluster. It is copied from the
+	// cluster's metadata ConfigMap, which remains the source of truth, when enabled in the HiveConfig.
+	// +optional
+	InstallMetadata *InstallMetadata `json:"installMetadata,omitempty"`
+
+	// DeprovisionProgress is the cleanup step reached since the ClusterDeployment was deleted. Each step
+	// is safe to repeat, so cleanup resumes from here when hive is restarted.
+	// +optional
+	DeprovisionProgress DeprovisionProgress `json:"deprovisionProgress,omitempty"`
+}
+
+// DeprovisionProgress is the cleanup step reached by a deleted ClusterDeployment.
+type DeprovisionProgress string
+
+const (
+	// DeprovisionProgressDeletingDNSZone is set while waiting for the managed DNSZone to be deleted.
+	DeprovisionProgressDeletingDNSZone DeprovisionProgress = "DeletingDNSZone"
+	// DeprovisionProgressDeletingInstallJob is set while waiting for the install job to be deleted.
+	DeprovisionProgressDeletingInstallJob DeprovisionProgress = "DeletingInstallJob"
+	// DeprovisionProgressDeprovisioning is set while the ClusterDeprovisionRequest for the cluster is in
+	// progress.
+	DeprovisionProgressDeprovisioning DeprovisionProgress = "Deprovisioning"
+	// DeprovisionProgressCompleted is set once cleanup is complete, or was skipped, and the deprovision
+	// finalizer is being removed.
+	DeprovisionProgressCompleted DeprovisionProgress = "Completed"
+)
+
+// InstallMetadata is a subset of the metadata written by the installer for a cluster.
+type InstallMetadata struct {
+	// InfraID is the identifier used by the installer to tag and name cloud resources.
+	InfraID string `json:"infraID,omitempty"`
+
+	// ClusterID is the globally unique identifier of the cluster.
+	ClusterID string `json:"clusterID,omitempty"`
+
+	// Region is the cloud region the cluster was installed in.
+	// +optional
+	Region string `json:"region,omitempty"`
 }
 
 // ClusterDeploymentCondition contains details for the current condition of a cluster deployment
@@ -242,7 +449,8 @@ type ClusterDeploymentConditionType string
 
 const (
 	// ClusterImageSetNotFoundCondition is set when the ClusterImageSet referenced by the
-	// ClusterDeployment is not found.
+	// ClusterDeployment is not found. The reason is ClusterImageSetNotFound when no ClusterImageSet
+	// with the referenced name exists, and ClusterImageSetNameEmpty when the reference has no name.
 	ClusterImageSetNotFoundCondition ClusterDeploymentConditionType = "ClusterImageSetNotFound"
 
 	// InstallerImageResolutionFailedCondition is a condition that indicates whether the job
@@ -264,6 +472,77 @@ const (
 	// InstallFailingCondition indicates that a failure has been detected and we will attempt to offer some
 	// information as to why in the reason.
 	InstallFailingCondition ClusterDeploymentConditionType = "InstallFailing"
+
+	// InstallConfigInvalidCondition is set when the install-config generated for the cluster deployment
+	// fails validation. No install will be launched while this condition is true.
+	InstallConfigInvalidCondition ClusterDeploymentConditionType = "InstallConfigInvalid"
+
+	// ProvisionCompletedCondition reports the terminal outcome of provisioning. It is true once the cluster
+	// has been installed, false with a reason when the install has failed and will not be retried, and
+	// absent while the install is still in progress.
+	ProvisionCompletedCondition ClusterDeploymentConditionType = "ProvisionCompleted"
+
+	// BootstrapIgnitionOverrideInvalidCondition is set when the secret referenced by BootstrapIgnitionOverrideRef
+	// does not contain a valid ignition config. No install will be launched while this condition is true.
+	BootstrapIgnitionOverrideInvalidCondition ClusterDeploymentConditionType = "BootstrapIgnitionOverrideInvalid"
+
+	// AdminKubeconfigInvalidCondition is set when the admin kubeconfig secret for the cluster contains
+	// neither a kubeconfig nor a raw-kubeconfig key.
+	AdminKubeconfigInvalidCondition ClusterDeploymentConditionType = "AdminKubeconfigInvalid"
+
+	// InstallPodDuplicationCondition is set when more than one active install pod exists for the cluster,
+	// which usually means a previous install pod was not cleaned up.
+	InstallPodDuplicationCondition ClusterDeploymentConditionType = "InstallPodDuplication"
+
+	// IngressDomainInvalidCondition is set when one or more ingress domains do not fall under the
+	// cluster's base domain. No install will be launched while this condition is true.
+	IngressDomainInvalidCondition ClusterDeploymentConditionType = "IngressDomainInvalid"
+
+	// InstallCancelledCondition is set when the install has been cancelled with the cancel-install
+	// annotation. No install will be launched while this condition is true.
+	InstallCancelledCondition ClusterDeploymentConditionType = "InstallCancelled"
+
+	// HiveImageUnresolvedCondition is set when no hive image is configured for the cluster deployment, its
+	// ClusterImageSet or the hive controller, and the hardcoded default image is used.
+	HiveImageUnresolvedCondition ClusterDeploymentConditionType = "HiveImageUnresolved"
+
+	// DeprovisionFailedCondition is set when the deprovision of a deleted cluster has failed on every attempt
+	// and will not be retried. The cloud resources of the cluster may need to be cleaned up manually.
+	DeprovisionFailedCondition ClusterDeploymentConditionType = "DeprovisionFailed"
+
+	// RegionUnavailableCondition is set when region validation is enabled in the HiveConfig and the region of
+	// the cluster is not enabled for its AWS credentials. No install will be launched while this condition
+	// is true.
+	RegionUnavailableCondition ClusterDeploymentConditionType = "RegionUnavailable"
+
+	// ManifestsInvalidCondition is set when the config map referenced by ManifestsConfigMapRef holds a manifest
+	// file name which cannot be added to the installer's manifests. No install will be launched while this
+	// condition is true.
+	ManifestsInvalidCondition ClusterDeploymentConditionType = "ManifestsInvalid"
+
+	// PullSecretInvalidCondition is set when the pull secret of the cluster does not hold a valid docker config.
+	// No install will be launched while this condition is true.
+	PullSecretInvalidCondition ClusterDeploymentConditionType = "PullSecretInvalid"
+
+	// ImageResolutionTimedOutCondition is set when the installer image of the cluster was not resolved within the
+	// image resolution timeout of the HiveConfig. No imageset job is created while it is true, until the spec of
+	// the cluster deployment changes.
+	ImageResolutionTimedOutCondition ClusterDeploymentConditionType = "ImageResolutionTimedOut"
+
+	// DeprovisionSkippedCondition is set when a deleted cluster is removed without deprovisioning its cloud
+	// resources. Its reason records why, for example PreserveOnDelete, NoInfraID,
+	// UnsupportedPlatform or NamespaceTerminating.
+	DeprovisionSkippedCondition ClusterDeploymentConditionType = "DeprovisionSkipped"
+
+	// DNSZoneConflictCondition is set when the DNSZone the cluster deployment would manage already exists and
+	// is not controlled by it, for example because ManagedDNSZoneName names the zone of another cluster. The
+	// zone is neither used nor deleted by the cluster deployment.
+	DNSZoneConflictCondition ClusterDeploymentConditionType = "DNSZoneConflict"
+
+	// ConsoleRouteNotFoundCondition is set when the web console route of an installed cluster does not exist on
+	// the remote cluster. The route is checked for again until the condition has been true for an hour, after
+	// which it is only checked when the cluster deployment is reconciled for another reason.
+	ConsoleRouteNotFoundCondition ClusterDeploymentConditionType = "ConsoleRouteNotFound"
 )
 
 // AllClusterDeploymentConditions is a slice containing all condition types. This can be used for dealing with
@@ -275,6 +554,22 @@ var AllClusterDeploymentConditions = []ClusterDeploymentConditionType{
 	IngressCertificateNotFoundCondition,
 	UnreachableCondition,
 	InstallFailingCondition,
+	InstallConfigInvalidCondition,
+	ProvisionCompletedCondition,
+	BootstrapIgnitionOverrideInvalidCondition,
+	AdminKubeconfigInvalidCondition,
+	InstallPodDuplicationCondition,
+	IngressDomainInvalidCondition,
+	InstallCancelledCondition,
+	HiveImageUnresolvedCondition,
+	DeprovisionFailedCondition,
+	RegionUnavailableCondition,
+	ManifestsInvalidCondition,
+	PullSecretInvalidCondition,
+	ImageResolutionTimedOutCondition,
+	DeprovisionSkippedCondition,
+	DNSZoneConflictCondition,
+	ConsoleRouteNotFoundCondition,
 }
 
 // +genclient
@@ -288,6 +583,7 @@ var AllClusterDeploymentConditions = []ClusterDeploymentConditionType{
 // +kubebuilder:printcolumn:name="BaseDomain",type="string",JSONPath=".spec.baseDomain"
 // +kubebuilder:printcolumn:name="Installed",type="boolean",JSONPath=".status.installed"
 // +kubebuilder:printcolumn:name="InfraID",type="string",JSONPath=".status.infraID"
+// +kubebuilder:printcolumn:name="InstallDuration",type="string",JSONPath=".status.installDuration"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:path=clusterdeployments,shortName=cd
 type ClusterDeployment struct {
@@ -354,8 +650,30 @@ type AWSPlatform struct {
 	// installing on AWS for machine pools which do not define their own
 	// platform configuration.
 	DefaultMachinePlatform *AWSMachinePoolPlatform `json:"defaultMachinePlatform,omitempty"`
+
+	// Subnets specifies existing subnets (by ID) where cluster resources will be created. The
+	// availability zones of these subnets bound where machines, including the control plane, are placed.
+	// Leave unset to have the installer create new subnets.
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+
+	// Publish controls whether the API and ingress of the cluster are reachable from the internet (External)
+	// or only from within the VPC of the cluster and the networks connected to it (Internal). Internal
+	// clusters must be installed into existing Subnets. Defaults to External.
+	// +optional
+	Publish PublishingStrategy `json:"publish,omitempty"`
 }
 
+// PublishingStrategy controls how the API and ingress of a cluster are exposed.
+type PublishingStrategy string
+
+const (
+	// ExternalPublishingStrategy exposes the API and ingress of the cluster to the internet.
+	ExternalPublishingStrategy PublishingStrategy = "External"
+	// InternalPublishingStrategy exposes the API and ingress of the cluster only within its VPC.
+	InternalPublishingStrategy PublishingStrategy = "Internal"
+)
+
 // LibvirtPlatform stores all the global configuration that
 // all machinesets use.
 type LibvirtPlatform struct {
@@ -413,6 +731,18 @@ type ClusterIngress struct {
 	ServingCertificate string `json:"servingCertificate,omitempty"`
 }
 
+// IngressDomainMigration records the rewrite of a ClusterIngress domain by hive.
+type IngressDomainMigration struct {
+	// Name of the ClusterIngress whose domain was rewritten.
+	Name string `json:"name"`
+
+	// From is the domain before the rewrite.
+	From string `json:"from"`
+
+	// To is the domain after the rewrite.
+	To string `json:"to"`
+}
+
 // ControlPlaneConfigSpec contains additional configuration settings for a target
 // cluster's control plane.
 type ControlPlaneConfigSpec struct {
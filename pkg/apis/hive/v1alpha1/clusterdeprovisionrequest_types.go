@@ -37,6 +37,15 @@ type ClusterDeprovisionRequestSpec struct {
 type ClusterDeprovisionRequestStatus struct {
 	// Completed is true when the uninstall has completed successfully
 	Completed bool `json:"completed,omitempty"`
+
+	// Failed is true when the uninstall job has failed and will not be retried by this request
+	// +optional
+	Failed bool `json:"failed,omitempty"`
+
+	// DeletedResources is the number of cloud resources removed by the uninstall, by resource type such as
+	// "ec2:instance". It is set once the uninstall has completed, when the uninstall job reported the counts.
+	// +optional
+	DeletedResources map[string]int `json:"deletedResources,omitempty"`
 }
 
 // ClusterDeprovisionRequestPlatform contains platform-specific configuration for the
@@ -53,6 +62,10 @@ type AWSClusterDeprovisionRequest struct {
 
 	// Credentials is the AWS account credentials to use for deprovisioning the cluster
 	Credentials *corev1.LocalObjectReference `json:"credentials,omitempty"`
+
+	// UserTags are the additional tags the cluster's AWS resources were created with.
+	// +optional
+	UserTags map[string]string `json:"userTags,omitempty"`
 }
 
 // +genclient
@@ -64,6 +77,7 @@ type AWSClusterDeprovisionRequest struct {
 // +kubebuilder:printcolumn:name="InfraID",type="string",JSONPath=".spec.infraID"
 // +kubebuilder:printcolumn:name="ClusterID",type="string",JSONPath=".spec.clusterID"
 // +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="Failed",type="boolean",JSONPath=".status.failed"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:path=clusterdeprovisionrequests,shortName=cdr
 type ClusterDeprovisionRequest struct {
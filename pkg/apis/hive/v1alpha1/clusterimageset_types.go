@@ -24,7 +24,9 @@ import (
 type ClusterImageSetSpec struct {
 	// HiveImage is the Hive image to use when installing or destroying a cluster.
 	// If not present, the default Hive image for the clusterdeployment controller
-	// is used.
+	// is used. Changing it recreates the install job of clusters which are still
+	// installing, without resolving their installer image again. Installed clusters
+	// are not affected.
 	// +optional
 	HiveImage *string `json:"hiveImage,omitempty"`
 
@@ -41,7 +43,18 @@ type ClusterImageSetSpec struct {
 }
 
 // ClusterImageSetStatus defines the observed state of ClusterImageSet
-type ClusterImageSetStatus struct{}
+type ClusterImageSetStatus struct {
+	// InstallerImage is the installer image resolved from ReleaseImage by the imageset job of a cluster
+	// deployment. Other cluster deployments using the ClusterImageSet reuse it instead of resolving the
+	// same release image again.
+	// +optional
+	InstallerImage *string `json:"installerImage,omitempty"`
+
+	// ReleaseImage is the release image from which InstallerImage was resolved. InstallerImage is ignored
+	// once spec.releaseImage no longer matches it.
+	// +optional
+	ReleaseImage string `json:"releaseImage,omitempty"`
+}
 
 // +genclient:nonNamespaced
 // +genclient
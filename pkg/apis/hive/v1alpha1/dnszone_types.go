@@ -38,6 +38,17 @@ type DNSZoneSpec struct {
 	// +optional
 	LinkToParentDomain bool `json:"linkToParentDomain,omitempty"`
 
+	// RecordTTL is the TTL, in seconds, used for the DNS records created for this zone,
+	// such as the records linking it to its parent domain. If unset, a default TTL is used.
+	// +optional
+	RecordTTL TTL `json:"recordTTL,omitempty"`
+
+	// AdditionalRecords is a list of DNS records to create in the zone in addition to the records
+	// required by the cluster, for example a vanity CNAME. Records without a TTL use the RecordTTL of
+	// the zone.
+	// +optional
+	AdditionalRecords []Endpoint `json:"additionalRecords,omitempty"`
+
 	// AWS specifies AWS-specific cloud configuration
 	// +optional
 	AWS *AWSDNSZoneSpec `json:"aws,omitempty"`
@@ -56,6 +67,17 @@ type AWSDNSZoneSpec struct {
 	// to these tags,the DNS Zone controller will set a hive.openhsift.io/hostedzone tag
 	// identifying the HostedZone record that it belongs to.
 	AdditionalTags []AWSResourceTag `json:"additionalTags,omitempty"`
+
+	// PrivateZone makes the hosted zone a private hosted zone, whose records only resolve from within the
+	// VPC it is associated with. Private zones are not linked to their parent domain.
+	// +optional
+	PrivateZone *AWSPrivateZone `json:"privateZone,omitempty"`
+}
+
+// AWSPrivateZone describes the VPC a private hosted zone is associated with.
+type AWSPrivateZone struct {
+	// Subnets are subnets (by ID) within the VPC to associate the private hosted zone with.
+	Subnets []string `json:"subnets"`
 }
 
 // AWSResourceTag represents a tag that is applied to an AWS cloud resource
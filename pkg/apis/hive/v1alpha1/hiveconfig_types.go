@@ -41,6 +41,266 @@ type HiveConfigSpec struct {
 	// CA generated by each cluster on installation.
 	// +optional
 	AdditionalCertificateAuthorities []corev1.LocalObjectReference `json:"additionalCertificateAuthorities,omitempty"`
+
+	// ValidateInstallConfig enables validation of the install-config generated for each ClusterDeployment
+	// before an install is launched. Clusters with an invalid install-config will have the
+	// InstallConfigInvalid condition set instead of starting an install that is bound to fail.
+	// +optional
+	ValidateInstallConfig bool `json:"validateInstallConfig,omitempty"`
+
+	// ValidateRegion enables checking that the AWS region of each ClusterDeployment is enabled for its
+	// credentials before an install is launched. Clusters whose region is not enabled will have the
+	// RegionUnavailable condition set instead of starting an install that is bound to fail.
+	// +optional
+	ValidateRegion bool `json:"validateRegion,omitempty"`
+
+	// SkipCRDReapply disables the re-application of the hive CRDs on every reconcile of the operator.
+	// This should be set when the CRDs are managed outside of hive, for example through GitOps.
+	// +optional
+	SkipCRDReapply bool `json:"skipCRDReapply,omitempty"`
+
+	// MaintenanceMode stops hive from launching new install and imageset jobs, for example during
+	// an upgrade of hive. Jobs which are already running are left to complete, and status updates
+	// and deletions of ClusterDeployments continue to be processed.
+	// +optional
+	MaintenanceMode bool `json:"maintenanceMode,omitempty"`
+
+	// ReadOnlyMode stops the clusterdeployment controller from creating, updating or deleting any objects,
+	// for example to audit its behavior in a new environment. Each write the controller would have made,
+	// including the events it would have recorded, is logged instead.
+	// +optional
+	ReadOnlyMode bool `json:"readOnlyMode,omitempty"`
+
+	// ClusterVersionPollInterval is the interval at which the ClusterVersion of installed clusters is
+	// re-fetched to detect upgrades performed outside of hive, for example "30m". A value of "0"
+	// disables periodic polling. Defaults to 30 minutes.
+	// +optional
+	ClusterVersionPollInterval string `json:"clusterVersionPollInterval,omitempty"`
+
+	// ClientMetricsExcludedControllers is a list of controller names whose kube client requests should not
+	// be reported individually in the hive_kube_client_requests_total metric. Requests from these controllers
+	// are counted under the "other" controller label instead.
+	// +optional
+	ClientMetricsExcludedControllers []string `json:"clientMetricsExcludedControllers,omitempty"`
+
+	// FailedInstallLogBytes is the number of bytes from the end of the install pod log to save when an
+	// install fails. The log tail is stored in a ConfigMap owned by the ClusterDeployment so that it
+	// survives the deletion of the install pod. ConfigMaps are limited to 1MiB so this should be kept
+	// well below that. Zero disables capturing install logs.
+	// +optional
+	FailedInstallLogBytes int64 `json:"failedInstallLogBytes,omitempty"`
+
+	// ConsoleRouteCheckInterval is how often a freshly installed cluster is checked for its console route
+	// until the route has been created, for example "30s". The cluster stops being checked when the route
+	// has not been created within an hour. Defaults to 30 seconds.
+	// +optional
+	ConsoleRouteCheckInterval string `json:"consoleRouteCheckInterval,omitempty"`
+
+	// MaxConcurrentDeprovisions is the maximum number of deprovision requests which may be in progress at
+	// once across all namespaces. Deleted clusters beyond this limit wait for running deprovisions to
+	// complete before their deprovision request is created. Zero means no limit.
+	// +optional
+	MaxConcurrentDeprovisions int32 `json:"maxConcurrentDeprovisions,omitempty"`
+
+	// DefaultPullSecret is a reference to a pull secret in the hive namespace which is used for
+	// ClusterDeployments that do not reference a pull secret of their own. It is copied into the
+	// namespace of each ClusterDeployment using it.
+	// +optional
+	DefaultPullSecret *corev1.LocalObjectReference `json:"defaultPullSecret,omitempty"`
+
+	// DefaultSSHKey is a reference to a secret in the hive namespace holding the public SSH key which is used
+	// for ClusterDeployments that do not reference an SSH key of their own. It is copied into the namespace of
+	// each ClusterDeployment using it.
+	// +optional
+	DefaultSSHKey *corev1.LocalObjectReference `json:"defaultSSHKey,omitempty"`
+
+	// ReportInstallMetadata copies the infra ID, cluster ID and region from the metadata ConfigMap of each
+	// installed ClusterDeployment into its status, so that they can be read without fetching the ConfigMap.
+	// +optional
+	ReportInstallMetadata bool `json:"reportInstallMetadata,omitempty"`
+
+	// ImageSetJobCPURequest is the CPU requested by each container of the jobs which resolve the installer
+	// image for a release image, for example "10m". Defaults to 10m.
+	// +optional
+	ImageSetJobCPURequest string `json:"imageSetJobCPURequest,omitempty"`
+
+	// ImageSetJobMemoryRequest is the memory requested by each container of the jobs which resolve the
+	// installer image for a release image, for example "64Mi". Defaults to 64Mi.
+	// +optional
+	ImageSetJobMemoryRequest string `json:"imageSetJobMemoryRequest,omitempty"`
+
+	// InstallLogScanLines is the number of lines from the end of a failed install log which are scanned
+	// for known install failures. Patterns spanning several lines are matched as long as all of their
+	// lines fall within this window. Defaults to 1000.
+	// +optional
+	InstallLogScanLines int32 `json:"installLogScanLines,omitempty"`
+
+	// ClusterDeploymentResyncInterval is the longest a ClusterDeployment which is still installing goes
+	// without being reconciled, for example "10m". This catches drift which produces no events, such as a
+	// manually deleted install job. Periodic resyncs are disabled when unset.
+	// +optional
+	ClusterDeploymentResyncInterval string `json:"clusterDeploymentResyncInterval,omitempty"`
+
+	// MaxInstallLifetime is the longest a cluster may spend installing, measured from the creation of its
+	// first install job, for example "6h". A cluster which exceeds it has its install job deleted and the
+	// ProvisionCompleted condition set to false with the InstallTimedOut reason, and no further install jobs
+	// are created for it. Unlike a deadline on an individual install job, this covers every install attempt
+	// for the cluster. Disabled when unset.
+	// +optional
+	MaxInstallLifetime string `json:"maxInstallLifetime,omitempty"`
+
+	// InstallFailureCircuitBreaker pauses the creation of new install jobs for all ClusterDeployments when
+	// too many recent installs have failed, for example because of a bad release image or a cloud provider
+	// outage. The InstallCircuitBreakerOpen condition is set while installs are paused. The circuit breaker
+	// is disabled when unset.
+	// +optional
+	InstallFailureCircuitBreaker *InstallFailureCircuitBreakerConfig `json:"installFailureCircuitBreaker,omitempty"`
+
+	// InstallPodAntiAffinity asks the scheduler to avoid placing install pods on nodes which are already
+	// running an install pod. When TargetNamespaces is set, install pods are spread across all the target
+	// namespaces; otherwise only install pods from the same namespace are spread, as pod anti-affinity cannot
+	// select every namespace. Enabling or disabling it replaces the install jobs of clusters which are still
+	// installing.
+	// +optional
+	InstallPodAntiAffinity bool `json:"installPodAntiAffinity,omitempty"`
+
+	// InstallPodTerminationGracePeriodSeconds is how long install pods are given to clean up cloud resources
+	// when they are terminated, including when an outdated install job is replaced. Defaults to 600 seconds.
+	// Changing it replaces the install jobs of clusters which are still installing.
+	// +optional
+	InstallPodTerminationGracePeriodSeconds int64 `json:"installPodTerminationGracePeriodSeconds,omitempty"`
+
+	// DeleteInstallConfigAfterInstall deletes the install-config ConfigMap or Secret of a cluster once it is
+	// installed, rather than keeping it for debugging. It is created again if the cluster is reinstalled.
+	// +optional
+	DeleteInstallConfigAfterInstall bool `json:"deleteInstallConfigAfterInstall,omitempty"`
+
+	// ImageSetJobTTLSecondsAfterFinished is how long a finished job which resolves the installer image of a
+	// cluster is kept before it is garbage collected, so that failed jobs are cleaned up even while the hive
+	// controllers are down. Defaults to 3600 seconds.
+	// +optional
+	ImageSetJobTTLSecondsAfterFinished int32 `json:"imageSetJobTTLSecondsAfterFinished,omitempty"`
+
+	// DisableInstallPodServiceAccountToken stops the service account token from being mounted into the
+	// installer container of install pods, for environments where the installer only needs its cloud
+	// credentials. The install manager container keeps the token, as it reads the cluster deployment and
+	// reports the progress of the install. Enabling or disabling it replaces the install jobs of clusters
+	// which are still installing.
+	// +optional
+	DisableInstallPodServiceAccountToken bool `json:"disableInstallPodServiceAccountToken,omitempty"`
+
+	// Metrics configures the endpoint on which the hive controllers serve their metrics.
+	// +optional
+	Metrics *MetricsConfig `json:"metrics,omitempty"`
+
+	// ReconcileFailureBackoff configures how long the clusterdeployment controller waits before retrying a
+	// ClusterDeployment whose reconcile keeps failing. Each ClusterDeployment backs off on its own, so a
+	// ClusterDeployment which fails on every reconcile does not delay the others.
+	// +optional
+	ReconcileFailureBackoff *ReconcileFailureBackoffConfig `json:"reconcileFailureBackoff,omitempty"`
+
+	// TargetNamespaces restricts the hive controllers to the objects in the listed namespaces. Namespaced objects
+	// are only watched in these namespaces, and ClusterDeployments and the other namespaced hive objects in any
+	// other namespace are ignored. Cluster-scoped objects such as ClusterImageSets are still used. The controllers
+	// reconcile every namespace when unset.
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+
+	// ImageResolutionTimeout is the longest the installer image of a cluster may take to be resolved, for example
+	// "30m". It is measured from the creation of the cluster deployment, or from the first spec change after a
+	// previous timeout. A cluster which exceeds it has its imageset job deleted and the ImageResolutionTimedOut
+	// condition set, and no further imageset jobs are created for it until its spec changes. Disabled when unset.
+	// +optional
+	ImageResolutionTimeout string `json:"imageResolutionTimeout,omitempty"`
+
+	// JobDeletionPropagation is the propagation policy with which the install and imageset jobs of clusters are
+	// deleted. Foreground waits for the pods of a job to be deleted before the job is removed, while Background
+	// removes the job straight away and lets the garbage collector delete its pods, which speeds up teardown
+	// when clusters are created and deleted frequently. The install job of a deleted cluster is always deleted
+	// in the foreground, so that deprovision does not start while the installer is still running. Defaults to
+	// Foreground.
+	// +kubebuilder:validation:Enum=Foreground,Background
+	// +optional
+	JobDeletionPropagation metav1.DeletionPropagation `json:"jobDeletionPropagation,omitempty"`
+
+	// ControllerHeartbeatTimeout is how long a hive controller may go without completing a reconcile before
+	// the HiveControllersUnhealthy condition is set, for example "2h". Every controller is probed every few
+	// minutes with a request that does no work, so an idle controller keeps reconciling and only a controller
+	// which has stopped working through its queue is reported. Defaults to 1h.
+	// +optional
+	ControllerHeartbeatTimeout string `json:"controllerHeartbeatTimeout,omitempty"`
+}
+
+// ReconcileFailureBackoffConfig contains the delays with which a failing ClusterDeployment is retried. The delay
+// doubles with each consecutive failure, starting from the base delay and capped at the max delay, and is reset
+// once a reconcile succeeds.
+type ReconcileFailureBackoffConfig struct {
+	// BaseDelay is the delay before the first retry, for example "1s". Defaults to 1 second.
+	// +optional
+	BaseDelay string `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the longest delay between retries, for example "10m". Defaults to 10 minutes.
+	// +optional
+	MaxDelay string `json:"maxDelay,omitempty"`
+}
+
+// MetricsConfig configures the endpoint on which the hive controllers serve their metrics, and the metrics
+// they serve.
+type MetricsConfig struct {
+	// BindAddress is the address the metrics endpoint listens on. Defaults to ":2112".
+	// +optional
+	BindAddress string `json:"bindAddress,omitempty"`
+
+	// TLSSecret references a secret in the hive namespace holding the tls.crt and tls.key with which the
+	// metrics endpoint is served over HTTPS. Metrics are served over plain HTTP when unset.
+	// +optional
+	TLSSecret *corev1.LocalObjectReference `json:"tlsSecret,omitempty"`
+
+	// Buckets overrides the bucket boundaries of the hive histograms. Histograms without an override keep
+	// their default buckets.
+	// +optional
+	Buckets *MetricsBucketsConfig `json:"buckets,omitempty"`
+}
+
+// MetricsBucketsConfig contains the bucket boundaries, in seconds, of the hive histograms. The boundaries of
+// each histogram must be strictly increasing.
+type MetricsBucketsConfig struct {
+	// InstallJobDuration are the buckets of hive_cluster_deployment_install_job_duration_seconds.
+	// +optional
+	InstallJobDuration []int64 `json:"installJobDuration,omitempty"`
+
+	// InstallJobDelay are the buckets of hive_cluster_deployment_install_job_delay_seconds.
+	// +optional
+	InstallJobDelay []int64 `json:"installJobDelay,omitempty"`
+
+	// ImageSetJobDelay are the buckets of hive_cluster_deployment_imageset_job_delay_seconds.
+	// +optional
+	ImageSetJobDelay []int64 `json:"imageSetJobDelay,omitempty"`
+}
+
+// InstallFailureCircuitBreakerConfig contains the thresholds at which the creation of new install jobs is
+// paused after repeated install failures.
+type InstallFailureCircuitBreakerConfig struct {
+	// FailurePercent is the percentage of the installs finished within the window which must have failed
+	// for new installs to be paused.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	FailurePercent int32 `json:"failurePercent"`
+
+	// MinimumInstalls is the number of installs which must have finished within the window before new
+	// installs can be paused, so that a single early failure does not trip the circuit breaker. Defaults to 5.
+	// +optional
+	MinimumInstalls int32 `json:"minimumInstalls,omitempty"`
+
+	// Window is how far back finished installs are counted towards the failure rate, for example "1h".
+	// Defaults to 1 hour.
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// Cooldown is how long new installs are paused once the circuit breaker trips, for example "30m".
+	// Install outcomes are counted afresh once the cooldown has passed. Defaults to 30 minutes.
+	// +optional
+	Cooldown string `json:"cooldown,omitempty"`
 }
 
 // HiveConfigStatus defines the observed state of Hive
@@ -49,8 +309,46 @@ type HiveConfigStatus struct {
 	// configmap data from the openshift-config-managed namespace. When the configmap changes,
 	// admission is redeployed.
 	AggregatorClientCAHash string `json:"aggregatorClientCAHash,omitempty"`
+
+	// Conditions includes more detailed status for hive.
+	// +optional
+	Conditions []HiveConfigCondition `json:"conditions,omitempty"`
+}
+
+// HiveConfigCondition contains details for the current condition of hive
+type HiveConfigCondition struct {
+	// Type is the type of the condition.
+	Type HiveConfigConditionType `json:"type"`
+	// Status is the status of the condition.
+	Status corev1.ConditionStatus `json:"status"`
+	// LastProbeTime is the last time we probed the condition.
+	// +optional
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a unique, one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
+// HiveConfigConditionType is a valid value for HiveConfigCondition.Type
+type HiveConfigConditionType string
+
+const (
+	// InstallCircuitBreakerOpenCondition is true while the creation of new install jobs is paused because
+	// the recent install failure rate exceeded the configured threshold.
+	InstallCircuitBreakerOpenCondition HiveConfigConditionType = "InstallCircuitBreakerOpen"
+
+	// HiveControllersUnhealthyCondition is true while the heartbeat of at least one hive controller is older
+	// than the controller heartbeat timeout. The heartbeats are kept in the hive-controller-heartbeats config
+	// map of the hive namespace.
+	HiveControllersUnhealthyCondition HiveConfigConditionType = "HiveControllersUnhealthy"
+)
+
 // ExternalDNSConfig contains settings for running external-dns in a Hive
 // environment.
 type ExternalDNSConfig struct {
@@ -76,7 +76,9 @@ type SyncObjectPatch struct {
 	// Patch is the patch to apply.
 	Patch string `json:"patch"`
 
-	// PatchType indicates the PatchType as "strategic" (default), "json", or "merge".
+	// PatchType indicates the PatchType as "strategic" (default), "json", "merge", or "auto".
+	// The "auto" type uses a strategic merge patch where the resource supports it, and a merge
+	// patch otherwise (for example for custom resources).
 	// +optional
 	PatchType string `json:"patchType,omitempty"`
 }
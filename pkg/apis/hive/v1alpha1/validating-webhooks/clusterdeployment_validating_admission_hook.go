@@ -29,6 +29,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -53,6 +54,12 @@ const (
 	ManagedDomainsFileEnvVar = "MANAGED_DOMAINS_FILE"
 )
 
+const (
+	awsTagKeyMaxLength   = 128
+	awsTagValueMaxLength = 256
+	awsReservedTagPrefix = "aws:"
+)
+
 var (
 	mutableFields = []string{"CertificateBundles", "Compute", "ControlPlaneConfig", "Ingress", "PreserveOnDelete"}
 )
@@ -233,6 +240,79 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 		}
 	}
 
+	switch newObject.Spec.InstallPodRestartPolicy {
+	case "", corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever:
+	default:
+		message := fmt.Sprintf("Invalid install pod restart policy (.spec.installPodRestartPolicy): %s, must be one of %s or %s",
+			newObject.Spec.InstallPodRestartPolicy, corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever)
+		contextLogger.Error(message)
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+				Message: message,
+			},
+		}
+	}
+
+	if aws := newObject.Spec.AWS; aws != nil {
+		message := ""
+		switch aws.Publish {
+		case "", hivev1.ExternalPublishingStrategy:
+		case hivev1.InternalPublishingStrategy:
+			if len(aws.Subnets) == 0 {
+				message = "Internal clusters (.spec.platform.aws.publish) must be installed into existing subnets (.spec.platform.aws.subnets)"
+			}
+		default:
+			message = fmt.Sprintf("Invalid publishing strategy (.spec.platform.aws.publish): %s, must be one of %s or %s",
+				aws.Publish, hivev1.ExternalPublishingStrategy, hivev1.InternalPublishingStrategy)
+		}
+		if message == "" {
+			message = validateAWSUserTags(aws.UserTags)
+		}
+		if message != "" {
+			contextLogger.Error(message)
+			return &admissionv1beta1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+					Message: message,
+				},
+			}
+		}
+	}
+
+	if route := newObject.Spec.ConsoleRoute; route != nil && (route.Namespace == "" || route.Name == "") {
+		message := "Console route override (.spec.consoleRoute) must specify both a namespace and a name"
+		contextLogger.Error(message)
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+				Message: message,
+			},
+		}
+	}
+
+	if zoneName := newObject.Spec.ManagedDNSZoneName; zoneName != "" {
+		message := ""
+		if errs := validation.IsDNS1123Subdomain(zoneName); len(errs) > 0 {
+			message = fmt.Sprintf("Invalid managed DNS zone name (.spec.managedDNSZoneName): %s", strings.Join(errs, ", "))
+		} else if newObject.Spec.ManagedDNSZoneRef != nil {
+			message = "Managed DNS zone name (.spec.managedDNSZoneName) cannot be set along with an externally managed DNS zone (.spec.managedDNSZoneRef)"
+		}
+		if message != "" {
+			contextLogger.Error(message)
+			return &admissionv1beta1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+					Message: message,
+				},
+			}
+		}
+	}
+
 	// validate the ingress
 	if ingressValidationResult := validateIngress(newObject, contextLogger); ingressValidationResult != nil {
 		return ingressValidationResult
@@ -318,6 +398,22 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateUpdate(admissionSpec
 		}
 	}
 
+	// The user tags are immutable, but a cluster deployment created before they were validated may still have
+	// tags which AWS rejects.
+	if aws := newObject.Spec.AWS; aws != nil {
+		if message := validateAWSUserTags(aws.UserTags); message != "" {
+			contextLogger.Infof("Failed validation: %v", message)
+
+			return &admissionv1beta1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+					Message: message,
+				},
+			}
+		}
+	}
+
 	// validate the newly incoming ingress
 	if ingressValidationResult := validateIngress(newObject, contextLogger); ingressValidationResult != nil {
 		return ingressValidationResult
@@ -374,6 +470,24 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateUpdate(admissionSpec
 	}
 }
 
+// validateAWSUserTags returns a message describing the first AWS user tag which AWS would reject, or an empty
+// string if all of them are valid. The user tags are applied to every AWS resource created for the cluster.
+func validateAWSUserTags(tags map[string]string) string {
+	for k, v := range tags {
+		switch {
+		case k == "":
+			return "Invalid user tag (.spec.platform.aws.userTags): keys must not be empty"
+		case len(k) > awsTagKeyMaxLength:
+			return fmt.Sprintf("Invalid user tag key (.spec.platform.aws.userTags): %s, must be no more than %d characters", k, awsTagKeyMaxLength)
+		case strings.HasPrefix(strings.ToLower(k), awsReservedTagPrefix):
+			return fmt.Sprintf("Invalid user tag key (.spec.platform.aws.userTags): %s, the %s prefix is reserved for use by AWS", k, awsReservedTagPrefix)
+		case len(v) > awsTagValueMaxLength:
+			return fmt.Sprintf("Invalid user tag value (.spec.platform.aws.userTags) for key %s: must be no more than %d characters", k, awsTagValueMaxLength)
+		}
+	}
+	return ""
+}
+
 // isFieldMutable says whether the ClusterDeployment.spec field is meant to be mutable or not.
 func isFieldMutable(value string) bool {
 	for _, mutableField := range mutableFields {
@@ -469,7 +583,7 @@ func hasClearedOutPreviouslyDefinedIngressList(oldObject, newObject *hivev1.Clus
 func validateIngressDomainsShareClusterDomain(newObject *hivev1.ClusterDeploymentSpec) bool {
 	// ingress entries must share the same domain as the cluster
 	// so watch for an ingress domain ending in: .<clusterName>.<baseDomain>
-	regexString := fmt.Sprintf(`(?i).*\.%s.%s$`, newObject.ClusterName, newObject.BaseDomain)
+	regexString := fmt.Sprintf(`(?i).*\.%s\.%s$`, regexp.QuoteMeta(newObject.ClusterName), regexp.QuoteMeta(newObject.BaseDomain))
 	sharedSubdomain := regexp.MustCompile(regexString)
 
 	for _, ingress := range newObject.Ingress {
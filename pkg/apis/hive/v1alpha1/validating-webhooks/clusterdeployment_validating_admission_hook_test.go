@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -286,6 +287,48 @@ func TestClusterDeploymentValidate(t *testing.T) {
 			operation:       admissionv1beta1.Create,
 			expectedAllowed: false,
 		},
+		{
+			name: "Test valid managed DNS zone name",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := clusterDeploymentWithManagedDomain("bar.foo.aaa.com")
+				cd.Spec.ManagedDNSZoneName = "custom-zone"
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "Test invalid managed DNS zone name",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := clusterDeploymentWithManagedDomain("bar.foo.aaa.com")
+				cd.Spec.ManagedDNSZoneName = "Custom_Zone"
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Test managed DNS zone name with externally managed zone",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := clusterDeploymentWithManagedDomain("bar.foo.aaa.com")
+				cd.Spec.ManagedDNSZoneName = "custom-zone"
+				cd.Spec.ManagedDNSZoneRef = &corev1.LocalObjectReference{Name: "external-zone"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test unallowed update of managed DNS zone name",
+			oldObject: clusterDeploymentWithManagedDomain("bar.foo.aaa.com"),
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := clusterDeploymentWithManagedDomain("bar.foo.aaa.com")
+				cd.Spec.ManagedDNSZoneName = "custom-zone"
+				return cd
+			}(),
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: false,
+		},
 		{
 			name:      "Test allow modifying controlPlaneConfig",
 			oldObject: validClusterDeployment(),
@@ -372,6 +415,16 @@ func TestClusterDeploymentValidate(t *testing.T) {
 			operation:       admissionv1beta1.Create,
 			expectedAllowed: false,
 		},
+		{
+			name: "Test ingress domain matching base domain only through regex wildcard",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeploymentWithIngress()
+				cd.Spec.Ingress[0].Domain = "apps.sameclustername.exampleXcom"
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
 		{
 			name: "Cluster deployment name is too long",
 			newObject: func() *hivev1.ClusterDeployment {
@@ -392,6 +445,148 @@ func TestClusterDeploymentValidate(t *testing.T) {
 			operation:       admissionv1beta1.Create,
 			expectedAllowed: false,
 		},
+		{
+			name: "Install pod restart policy Never",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.InstallPodRestartPolicy = corev1.RestartPolicyNever
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "Install pod restart policy not valid for jobs",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.InstallPodRestartPolicy = corev1.RestartPolicyAlways
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Internal cluster with subnets",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.AWS = &hivev1.AWSPlatform{
+					Region:  "us-east-1",
+					Subnets: []string{"subnet-a", "subnet-b"},
+					Publish: hivev1.InternalPublishingStrategy,
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "Internal cluster without subnets",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.AWS = &hivev1.AWSPlatform{
+					Region:  "us-east-1",
+					Publish: hivev1.InternalPublishingStrategy,
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Console route override",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.ConsoleRoute = &hivev1.ConsoleRouteReference{Namespace: "custom-console", Name: "custom"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "Console route override without name",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.ConsoleRoute = &hivev1.ConsoleRouteReference{Namespace: "custom-console"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Invalid publishing strategy",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.AWS = &hivev1.AWSPlatform{
+					Region:  "us-east-1",
+					Subnets: []string{"subnet-a", "subnet-b"},
+					Publish: "Private",
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "AWS user tags",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.AWS = &hivev1.AWSPlatform{
+					Region:   "us-east-1",
+					UserTags: map[string]string{"owner": "hive", "cost center": "1234"},
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "AWS user tag with reserved prefix",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.AWS = &hivev1.AWSPlatform{
+					Region:   "us-east-1",
+					UserTags: map[string]string{"aws:createdBy": "hive"},
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "AWS user tag key too long",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.AWS = &hivev1.AWSPlatform{
+					Region:   "us-east-1",
+					UserTags: map[string]string{strings.Repeat("k", 129): "hive"},
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "Update with AWS user tag with reserved prefix",
+			oldObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.AWS = &hivev1.AWSPlatform{
+					Region:   "us-east-1",
+					UserTags: map[string]string{"aws:createdBy": "hive"},
+				}
+				return cd
+			}(),
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validClusterDeployment()
+				cd.Spec.AWS = &hivev1.AWSPlatform{
+					Region:   "us-east-1",
+					UserTags: map[string]string{"aws:createdBy": "hive"},
+				}
+				cd.Spec.PreserveOnDelete = true
+				return cd
+			}(),
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -69,6 +69,12 @@ func TestSelectorSyncSetValidate(t *testing.T) {
 			selectorSyncSet: testValidPatchSelectorSyncSet(),
 			expectedAllowed: true,
 		},
+		{
+			name:            "Test auto patch type create",
+			operation:       admissionv1beta1.Create,
+			selectorSyncSet: testPatchSelectorSyncSet("auto"),
+			expectedAllowed: true,
+		},
 		{
 			name:            "Test invalid patch type create",
 			operation:       admissionv1beta1.Create,
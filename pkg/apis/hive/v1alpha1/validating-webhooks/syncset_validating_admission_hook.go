@@ -232,7 +232,7 @@ func (a *SyncSetValidatingAdmissionHook) validateUpdate(admissionSpec *admission
 }
 
 func checkValidPatchTypes(patches []hivev1.SyncObjectPatch) (string, bool) {
-	validTypes := map[string]struct{}{"json": {}, "merge": {}, "strategic": {}}
+	validTypes := map[string]struct{}{"json": {}, "merge": {}, "strategic": {}, "auto": {}}
 	for _, patch := range patches {
 		if _, ok := validTypes[patch.PatchType]; !ok {
 			return patch.PatchType, false
@@ -69,6 +69,12 @@ func TestSyncSetValidate(t *testing.T) {
 			syncSet:         testValidPatchSyncSet(),
 			expectedAllowed: true,
 		},
+		{
+			name:            "Test auto patch type create",
+			operation:       admissionv1beta1.Create,
+			syncSet:         testPatchSyncSet("auto"),
+			expectedAllowed: true,
+		},
 		{
 			name:            "Test invalid patch type create",
 			operation:       admissionv1beta1.Create,
@@ -38,6 +38,13 @@ func (in *AWSClusterDeprovisionRequest) DeepCopyInto(out *AWSClusterDeprovisionR
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.UserTags != nil {
+		in, out := &in.UserTags, &out.UserTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -60,6 +67,11 @@ func (in *AWSDNSZoneSpec) DeepCopyInto(out *AWSDNSZoneSpec) {
 		*out = make([]AWSResourceTag, len(*in))
 		copy(*out, *in)
 	}
+	if in.PrivateZone != nil {
+		in, out := &in.PrivateZone, &out.PrivateZone
+		*out = new(AWSPrivateZone)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -131,6 +143,11 @@ func (in *AWSPlatform) DeepCopyInto(out *AWSPlatform) {
 		*out = new(AWSMachinePoolPlatform)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -148,6 +165,11 @@ func (in *AWSPlatform) DeepCopy() *AWSPlatform {
 func (in *AWSPlatformSecrets) DeepCopyInto(out *AWSPlatformSecrets) {
 	*out = *in
 	out.Credentials = in.Credentials
+	if in.DNSCredentials != nil {
+		in, out := &in.DNSCredentials, &out.DNSCredentials
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	return
 }
 
@@ -161,6 +183,27 @@ func (in *AWSPlatformSecrets) DeepCopy() *AWSPlatformSecrets {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSPrivateZone) DeepCopyInto(out *AWSPrivateZone) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSPrivateZone.
+func (in *AWSPrivateZone) DeepCopy() *AWSPrivateZone {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSPrivateZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSResourceTag) DeepCopyInto(out *AWSResourceTag) {
 	*out = *in
@@ -328,6 +371,59 @@ func (in *ClusterDeploymentSpec) DeepCopyInto(out *ClusterDeploymentSpec) {
 		*out = make([]CertificateBundleSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.AdditionalDNSRecords != nil {
+		in, out := &in.AdditionalDNSRecords, &out.AdditionalDNSRecords
+		*out = make([]Endpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManagedDNSZoneRef != nil {
+		in, out := &in.ManagedDNSZoneRef, &out.ManagedDNSZoneRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.AdditionalTrustBundle != nil {
+		in, out := &in.AdditionalTrustBundle, &out.AdditionalTrustBundle
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.BootstrapIgnitionOverrideRef != nil {
+		in, out := &in.BootstrapIgnitionOverrideRef, &out.BootstrapIgnitionOverrideRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ManifestsConfigMapRef != nil {
+		in, out := &in.ManifestsConfigMapRef, &out.ManifestsConfigMapRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ImageContentSources != nil {
+		in, out := &in.ImageContentSources, &out.ImageContentSources
+		*out = make([]ImageContentSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostInstallManifests != nil {
+		in, out := &in.PostInstallManifests, &out.PostInstallManifests
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstallJobPodAnnotations != nil {
+		in, out := &in.InstallJobPodAnnotations, &out.InstallJobPodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ConsoleRoute != nil {
+		in, out := &in.ConsoleRoute, &out.ConsoleRoute
+		*out = new(ConsoleRouteReference)
+		**out = **in
+	}
 	return
 }
 
@@ -352,6 +448,15 @@ func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 	out.AdminKubeconfigSecret = in.AdminKubeconfigSecret
 	out.AdminPasswordSecret = in.AdminPasswordSecret
 	in.ClusterVersionStatus.DeepCopyInto(&out.ClusterVersionStatus)
+	if in.ClusterVersionLastCheckTime != nil {
+		in, out := &in.ClusterVersionLastCheckTime, &out.ClusterVersionLastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.IngressDomainMigrations != nil {
+		in, out := &in.IngressDomainMigrations, &out.IngressDomainMigrations
+		*out = make([]IngressDomainMigration, len(*in))
+		copy(*out, *in)
+	}
 	if in.SyncSetStatus != nil {
 		in, out := &in.SyncSetStatus, &out.SyncSetStatus
 		*out = make([]SyncSetObjectStatus, len(*in))
@@ -383,6 +488,19 @@ func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 		*out = make([]CertificateBundleStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.InstallStartedTimestamp != nil {
+		in, out := &in.InstallStartedTimestamp, &out.InstallStartedTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.InstalledTimestamp != nil {
+		in, out := &in.InstalledTimestamp, &out.InstalledTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.InstallMetadata != nil {
+		in, out := &in.InstallMetadata, &out.InstallMetadata
+		*out = new(InstallMetadata)
+		**out = **in
+	}
 	return
 }
 
@@ -402,7 +520,7 @@ func (in *ClusterDeprovisionRequest) DeepCopyInto(out *ClusterDeprovisionRequest
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -498,6 +616,13 @@ func (in *ClusterDeprovisionRequestSpec) DeepCopy() *ClusterDeprovisionRequestSp
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterDeprovisionRequestStatus) DeepCopyInto(out *ClusterDeprovisionRequestStatus) {
 	*out = *in
+	if in.DeletedResources != nil {
+		in, out := &in.DeletedResources, &out.DeletedResources
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -517,7 +642,7 @@ func (in *ClusterImageSet) DeepCopyInto(out *ClusterImageSet) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -622,6 +747,11 @@ func (in *ClusterImageSetSpec) DeepCopy() *ClusterImageSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterImageSetStatus) DeepCopyInto(out *ClusterImageSetStatus) {
 	*out = *in
+	if in.InstallerImage != nil {
+		in, out := &in.InstallerImage, &out.InstallerImage
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -661,6 +791,22 @@ func (in *ClusterIngress) DeepCopy() *ClusterIngress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleRouteReference) DeepCopyInto(out *ConsoleRouteReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleRouteReference.
+func (in *ConsoleRouteReference) DeepCopy() *ConsoleRouteReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleRouteReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneAdditionalCertificate) DeepCopyInto(out *ControlPlaneAdditionalCertificate) {
 	*out = *in
@@ -901,6 +1047,13 @@ func (in *DNSZoneList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSZoneSpec) DeepCopyInto(out *DNSZoneSpec) {
 	*out = *in
+	if in.AdditionalRecords != nil {
+		in, out := &in.AdditionalRecords, &out.AdditionalRecords
+		*out = make([]Endpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.AWS != nil {
 		in, out := &in.AWS, &out.AWS
 		*out = new(AWSDNSZoneSpec)
@@ -1051,7 +1204,7 @@ func (in *HiveConfig) DeepCopyInto(out *HiveConfig) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1073,6 +1226,24 @@ func (in *HiveConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HiveConfigCondition) DeepCopyInto(out *HiveConfigCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfigCondition.
+func (in *HiveConfigCondition) DeepCopy() *HiveConfigCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(HiveConfigCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HiveConfigList) DeepCopyInto(out *HiveConfigList) {
 	*out = *in
@@ -1124,6 +1295,41 @@ func (in *HiveConfigSpec) DeepCopyInto(out *HiveConfigSpec) {
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ClientMetricsExcludedControllers != nil {
+		in, out := &in.ClientMetricsExcludedControllers, &out.ClientMetricsExcludedControllers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultPullSecret != nil {
+		in, out := &in.DefaultPullSecret, &out.DefaultPullSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.DefaultSSHKey != nil {
+		in, out := &in.DefaultSSHKey, &out.DefaultSSHKey
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.InstallFailureCircuitBreaker != nil {
+		in, out := &in.InstallFailureCircuitBreaker, &out.InstallFailureCircuitBreaker
+		*out = new(InstallFailureCircuitBreakerConfig)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReconcileFailureBackoff != nil {
+		in, out := &in.ReconcileFailureBackoff, &out.ReconcileFailureBackoff
+		*out = new(ReconcileFailureBackoffConfig)
+		**out = **in
+	}
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1140,6 +1346,13 @@ func (in *HiveConfigSpec) DeepCopy() *HiveConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HiveConfigStatus) DeepCopyInto(out *HiveConfigStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HiveConfigCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1169,6 +1382,75 @@ func (in *IdentityProviderStatus) DeepCopy() *IdentityProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageContentSource) DeepCopyInto(out *ImageContentSource) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageContentSource.
+func (in *ImageContentSource) DeepCopy() *ImageContentSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageContentSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressDomainMigration) DeepCopyInto(out *IngressDomainMigration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressDomainMigration.
+func (in *IngressDomainMigration) DeepCopy() *IngressDomainMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressDomainMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallFailureCircuitBreakerConfig) DeepCopyInto(out *InstallFailureCircuitBreakerConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallFailureCircuitBreakerConfig.
+func (in *InstallFailureCircuitBreakerConfig) DeepCopy() *InstallFailureCircuitBreakerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallFailureCircuitBreakerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallMetadata) DeepCopyInto(out *InstallMetadata) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallMetadata.
+func (in *InstallMetadata) DeepCopy() *InstallMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Labels) DeepCopyInto(out *Labels) {
 	{
@@ -1323,6 +1605,63 @@ func (in *MachinePoolPlatform) DeepCopy() *MachinePoolPlatform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsBucketsConfig) DeepCopyInto(out *MetricsBucketsConfig) {
+	*out = *in
+	if in.InstallJobDuration != nil {
+		in, out := &in.InstallJobDuration, &out.InstallJobDuration
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstallJobDelay != nil {
+		in, out := &in.InstallJobDelay, &out.InstallJobDelay
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageSetJobDelay != nil {
+		in, out := &in.ImageSetJobDelay, &out.ImageSetJobDelay
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsBucketsConfig.
+func (in *MetricsBucketsConfig) DeepCopy() *MetricsBucketsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsBucketsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
+	*out = *in
+	if in.TLSSecret != nil {
+		in, out := &in.TLSSecret, &out.TLSSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = new(MetricsBucketsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfig.
+func (in *MetricsConfig) DeepCopy() *MetricsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Networking) DeepCopyInto(out *Networking) {
 	*out = *in
@@ -1409,7 +1748,7 @@ func (in *PlatformSecrets) DeepCopyInto(out *PlatformSecrets) {
 	if in.AWS != nil {
 		in, out := &in.AWS, &out.AWS
 		*out = new(AWSPlatformSecrets)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -1462,6 +1801,22 @@ func (in *ProvisionImages) DeepCopy() *ProvisionImages {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileFailureBackoffConfig) DeepCopyInto(out *ReconcileFailureBackoffConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileFailureBackoffConfig.
+func (in *ReconcileFailureBackoffConfig) DeepCopy() *ReconcileFailureBackoffConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileFailureBackoffConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelectorSyncIdentityProvider) DeepCopyInto(out *SelectorSyncIdentityProvider) {
 	*out = *in
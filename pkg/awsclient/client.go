@@ -70,6 +70,7 @@ type Client interface {
 	//EC2
 	DescribeAvailabilityZones(*ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error)
 	DescribeImages(*ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error)
+	DescribeRegions(*ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error)
 	DescribeVpcs(*ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error)
 	DescribeSubnets(*ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error)
 	DescribeSecurityGroups(*ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error)
@@ -127,6 +128,11 @@ func (c *awsClient) DescribeAvailabilityZones(input *ec2.DescribeAvailabilityZon
 	return c.ec2Client.DescribeAvailabilityZones(input)
 }
 
+func (c *awsClient) DescribeRegions(input *ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error) {
+	metricAWSAPICalls.WithLabelValues("DescribeRegions").Inc()
+	return c.ec2Client.DescribeRegions(input)
+}
+
 func (c *awsClient) DescribeImages(input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
 	metricAWSAPICalls.WithLabelValues("DescribeImages").Inc()
 	return c.ec2Client.DescribeImages(input)
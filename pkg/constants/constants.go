@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package constants contains values shared between the hive operator and the hive controllers.
+// Settings from HiveConfig are typically passed from the operator to the controllers as
+// environment variables on the hive-controllers deployment, and the names of those variables
+// live here so both sides agree on them.
+package constants
+
+const (
+	// ValidateInstallConfigEnvVar is the environment variable which, when set to "true", causes the
+	// clusterdeployment controller to validate the generated install-config before launching an install.
+	ValidateInstallConfigEnvVar = "VALIDATE_INSTALL_CONFIG"
+
+	// ValidateRegionEnvVar is the environment variable which, when set to "true", causes the
+	// clusterdeployment controller to check that the region of a cluster is enabled for its AWS credentials
+	// before launching an install.
+	ValidateRegionEnvVar = "VALIDATE_REGION"
+
+	// MaintenanceModeEnvVar is the environment variable which, when set to "true", stops the
+	// clusterdeployment controller from creating new install and imageset jobs.
+	MaintenanceModeEnvVar = "MAINTENANCE_MODE"
+
+	// ReadOnlyModeEnvVar is the environment variable which, when set to "true", causes the clusterdeployment
+	// controller to log the writes it would make instead of making them.
+	ReadOnlyModeEnvVar = "READ_ONLY_MODE"
+
+	// ClusterVersionPollIntervalEnvVar is the environment variable holding the duration between fetches of
+	// the remote ClusterVersion for installed clusters.
+	ClusterVersionPollIntervalEnvVar = "CLUSTER_VERSION_POLL_INTERVAL"
+
+	// ClientMetricsExcludedControllersEnvVar is the environment variable holding a comma separated list of
+	// controllers whose kube client requests are counted under the "other" controller label.
+	ClientMetricsExcludedControllersEnvVar = "CLIENT_METRICS_EXCLUDED_CONTROLLERS"
+
+	// FailedInstallLogBytesEnvVar is the environment variable holding the number of bytes of install pod
+	// log to save when an install fails.
+	FailedInstallLogBytesEnvVar = "FAILED_INSTALL_LOG_BYTES"
+
+	// ConsoleRouteCheckIntervalEnvVar is the environment variable holding the duration between checks for the
+	// console route of an installed cluster which does not have one yet.
+	ConsoleRouteCheckIntervalEnvVar = "CONSOLE_ROUTE_CHECK_INTERVAL"
+
+	// MaxConcurrentDeprovisionsEnvVar is the environment variable holding the maximum number of deprovision
+	// requests which may be in progress at once.
+	MaxConcurrentDeprovisionsEnvVar = "MAX_CONCURRENT_DEPROVISIONS"
+
+	// DefaultPullSecretEnvVar is the environment variable holding the name of the pull secret in the hive
+	// namespace to use for clusters which do not reference one.
+	DefaultPullSecretEnvVar = "DEFAULT_PULL_SECRET"
+
+	// DefaultSSHKeyEnvVar is the environment variable holding the name of the SSH key secret in the hive
+	// namespace to use for clusters which do not reference one.
+	DefaultSSHKeyEnvVar = "DEFAULT_SSH_KEY"
+
+	// ReportInstallMetadataEnvVar is the environment variable which, when set to "true", causes the
+	// clusterdeployment controller to copy the install metadata of clusters into their status.
+	ReportInstallMetadataEnvVar = "REPORT_INSTALL_METADATA"
+
+	// ImageSetJobCPURequestEnvVar is the environment variable holding the CPU request for the containers of
+	// imageset jobs.
+	ImageSetJobCPURequestEnvVar = "IMAGESET_JOB_CPU_REQUEST"
+
+	// ImageSetJobMemoryRequestEnvVar is the environment variable holding the memory request for the
+	// containers of imageset jobs.
+	ImageSetJobMemoryRequestEnvVar = "IMAGESET_JOB_MEMORY_REQUEST"
+
+	// InstallLogScanLinesEnvVar is the environment variable holding the number of trailing install log
+	// lines scanned for known errors.
+	InstallLogScanLinesEnvVar = "INSTALL_LOG_SCAN_LINES"
+
+	// ClusterDeploymentResyncIntervalEnvVar is the environment variable holding the longest duration an
+	// in-progress cluster deployment goes without being reconciled.
+	ClusterDeploymentResyncIntervalEnvVar = "CLUSTERDEPLOYMENT_RESYNC_INTERVAL"
+
+	// MaxInstallLifetimeEnvVar is the environment variable holding the longest duration a cluster may spend
+	// installing before it is failed.
+	MaxInstallLifetimeEnvVar = "MAX_INSTALL_LIFETIME"
+
+	// InstallCircuitBreakerFailurePercentEnvVar is the environment variable holding the percentage of recent
+	// installs which must have failed for the creation of new install jobs to be paused.
+	InstallCircuitBreakerFailurePercentEnvVar = "INSTALL_CIRCUIT_BREAKER_FAILURE_PERCENT"
+
+	// InstallCircuitBreakerMinimumInstallsEnvVar is the environment variable holding the number of recent
+	// installs required before the install circuit breaker can trip.
+	InstallCircuitBreakerMinimumInstallsEnvVar = "INSTALL_CIRCUIT_BREAKER_MINIMUM_INSTALLS"
+
+	// InstallCircuitBreakerWindowEnvVar is the environment variable holding how far back install outcomes
+	// are counted by the install circuit breaker.
+	InstallCircuitBreakerWindowEnvVar = "INSTALL_CIRCUIT_BREAKER_WINDOW"
+
+	// InstallCircuitBreakerCooldownEnvVar is the environment variable holding how long new installs are
+	// paused once the install circuit breaker trips.
+	InstallCircuitBreakerCooldownEnvVar = "INSTALL_CIRCUIT_BREAKER_COOLDOWN"
+
+	// InstallPodAntiAffinityEnvVar is the environment variable which, when set to "true", adds pod
+	// anti-affinity to install jobs so that install pods in the target namespaces, or in the same namespace
+	// when there are no target namespaces, are spread across nodes.
+	InstallPodAntiAffinityEnvVar = "INSTALL_POD_ANTI_AFFINITY"
+
+	// InstallPodTerminationGracePeriodEnvVar is the environment variable holding the termination grace
+	// period of install pods in seconds.
+	InstallPodTerminationGracePeriodEnvVar = "INSTALL_POD_TERMINATION_GRACE_PERIOD_SECONDS"
+
+	// DeleteInstallConfigAfterInstallEnvVar is the environment variable which, when set to "true", deletes the
+	// install-config ConfigMap or Secret of a cluster once it is installed.
+	DeleteInstallConfigAfterInstallEnvVar = "DELETE_INSTALL_CONFIG_AFTER_INSTALL"
+
+	// ImageSetJobTTLSecondsAfterFinishedEnvVar is the environment variable holding how long finished imageset
+	// jobs are kept, in seconds.
+	ImageSetJobTTLSecondsAfterFinishedEnvVar = "IMAGESET_JOB_TTL_SECONDS_AFTER_FINISHED"
+
+	// DisableInstallPodServiceAccountTokenEnvVar is the environment variable which, when set to "true", stops
+	// the service account token from being mounted into the installer container of install pods.
+	DisableInstallPodServiceAccountTokenEnvVar = "DISABLE_INSTALL_POD_SERVICE_ACCOUNT_TOKEN"
+
+	// MetricsBindAddressEnvVar is the environment variable holding the address the hive controllers serve
+	// metrics on.
+	MetricsBindAddressEnvVar = "METRICS_BIND_ADDRESS"
+
+	// MetricsTLSCertDirEnvVar is the environment variable holding the directory of the certificate and key
+	// with which the hive controllers serve metrics over TLS.
+	MetricsTLSCertDirEnvVar = "METRICS_TLS_CERT_DIR"
+
+	// InstallJobDurationBucketsEnvVar is the environment variable holding the comma separated bucket boundaries,
+	// in seconds, of the install job duration histogram.
+	InstallJobDurationBucketsEnvVar = "METRICS_INSTALL_JOB_DURATION_BUCKETS"
+
+	// InstallJobDelayBucketsEnvVar is the environment variable holding the comma separated bucket boundaries, in
+	// seconds, of the install job delay histogram.
+	InstallJobDelayBucketsEnvVar = "METRICS_INSTALL_JOB_DELAY_BUCKETS"
+
+	// ImageSetJobDelayBucketsEnvVar is the environment variable holding the comma separated bucket boundaries, in
+	// seconds, of the imageset job delay histogram.
+	ImageSetJobDelayBucketsEnvVar = "METRICS_IMAGESET_JOB_DELAY_BUCKETS"
+
+	// ReconcileFailureBaseDelayEnvVar is the environment variable holding the delay before a cluster deployment
+	// whose reconcile failed is first retried.
+	ReconcileFailureBaseDelayEnvVar = "RECONCILE_FAILURE_BASE_DELAY"
+
+	// ReconcileFailureMaxDelayEnvVar is the environment variable holding the longest delay before a cluster
+	// deployment whose reconcile keeps failing is retried.
+	ReconcileFailureMaxDelayEnvVar = "RECONCILE_FAILURE_MAX_DELAY"
+
+	// TargetNamespacesEnvVar is the environment variable holding the comma separated namespaces the hive
+	// controllers are restricted to.
+	TargetNamespacesEnvVar = "TARGET_NAMESPACES"
+
+	// ImageResolutionTimeoutEnvVar is the environment variable holding the longest duration the installer image
+	// of a cluster may take to be resolved.
+	ImageResolutionTimeoutEnvVar = "IMAGE_RESOLUTION_TIMEOUT"
+
+	// JobDeletionPropagationEnvVar is the environment variable holding the propagation policy with which install
+	// and imageset jobs are deleted.
+	JobDeletionPropagationEnvVar = "JOB_DELETION_PROPAGATION"
+
+	// ControllerHeartbeatTimeoutEnvVar is the environment variable holding how long a hive controller may go
+	// without completing a reconcile before it is reported as unhealthy.
+	ControllerHeartbeatTimeoutEnvVar = "CONTROLLER_HEARTBEAT_TIMEOUT"
+)
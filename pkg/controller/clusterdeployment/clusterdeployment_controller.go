@@ -18,26 +18,41 @@ package clusterdeployment
 
 import (
 	"context"
-	"crypto/md5"
-	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -49,9 +64,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	routev1 "github.com/openshift/api/route/v1"
+	installertypes "github.com/openshift/installer/pkg/types"
 
 	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/awsclient"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/heartbeat"
 	"github.com/openshift/hive/pkg/controller/images"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
@@ -75,17 +94,129 @@ const (
 	clusterVersionObjectName    = "version"
 	clusterVersionUnknown       = "undef"
 
+	// deleteAfterInstalledAnnotation is the annotation that contains a duration after which the cluster should
+	// be cleaned up, counted from when the cluster finished installing rather than from its creation.
+	deleteAfterInstalledAnnotation = "hive.openshift.io/delete-after-installed"
+
+	// expireNowAnnotation is the annotation that, when set to "true", causes the cluster to be treated as
+	// expired and cleaned up immediately.
+	expireNowAnnotation = "hive.openshift.io/expire-now"
+
+	// noExpiryAnnotation is the annotation that, when set to "true", protects a long-lived cluster from
+	// expiry by causing any delete-after annotation to be ignored.
+	noExpiryAnnotation = "hive.openshift.io/no-expiry"
+
+	// cancelInstallAnnotation is the annotation that, when set to "true", deletes any running install job and
+	// stops new install jobs from being launched until it is removed. It is ignored for installed clusters.
+	cancelInstallAnnotation = "hive.openshift.io/cancel-install"
+
+	// logLevelAnnotation is the annotation holding a log level, such as "debug", used when reconciling the
+	// cluster deployment. It can only make logging more verbose than the controller's own level.
+	logLevelAnnotation = "hive.openshift.io/log-level"
+
+	// adoptAnnotation is the annotation that, when set to "true", adopts a cluster which was installed outside
+	// of hive. No install is launched. Instead the cluster is marked installed once its infra ID, cluster ID and
+	// admin kubeconfig secret have been filled in on its status, and hive manages it from then on.
+	adoptAnnotation = "hive.openshift.io/adopt"
+
+	// deprovisionAttemptsAnnotation is the annotation holding the number of deprovision requests created for a
+	// deleted cluster deployment. Failed deprovision requests are retried until maxDeprovisionAttempts is reached.
+	deprovisionAttemptsAnnotation = "hive.openshift.io/deprovision-attempts"
+	maxDeprovisionAttempts        = 3
+
 	clusterDeploymentGenerationAnnotation = "hive.openshift.io/cluster-deployment-generation"
 	clusterImageSetNotFoundReason         = "ClusterImageSetNotFound"
 	clusterImageSetFoundReason            = "ClusterImageSetFound"
+	clusterImageSetNameEmptyReason        = "ClusterImageSetNameEmpty"
+	installConfigInvalidReason            = "InstallConfigInvalid"
+	installConfigValidReason              = "InstallConfigValid"
+	bootstrapIgnitionInvalidReason        = "BootstrapIgnitionOverrideInvalid"
+	bootstrapIgnitionValidReason          = "BootstrapIgnitionOverrideValid"
+	manifestsInvalidReason                = "ManifestsInvalid"
+	manifestsValidReason                  = "ManifestsValid"
+	pullSecretInvalidReason               = "PullSecretInvalid"
+	pullSecretValidReason                 = "PullSecretValid"
+	adminKubeconfigInvalidReason          = "AdminKubeconfigMissingData"
+	adminKubeconfigValidReason            = "AdminKubeconfigValid"
+	installPodDuplicationReason           = "DuplicateInstallPods"
+	installPodSingleReason                = "SingleInstallPod"
+	ingressDomainMigratedReason           = "IngressDomainMigrated"
+	ingressDomainInvalidReason            = "IngressDomainNotInBaseDomain"
+	ingressDomainValidReason              = "IngressDomainsValid"
+	installCancelledReason                = "InstallCancelled"
+	installNotCancelledReason             = "InstallNotCancelled"
+	hiveImageDefaultReason                = "DefaultHiveImage"
+	hiveImageResolvedReason               = "HiveImageResolved"
+	imageResolutionTimedOutReason         = "ImageResolutionTimedOut"
+	imageResolutionRestartedReason        = "SpecChanged"
+
+	provisionSucceededReason         = "InstallSucceeded"
+	provisionAttemptsExhaustedReason = "InstallAttemptsExhausted"
+	provisionDeadlineExceededReason  = "InstallDeadlineExceeded"
+	provisionFailedReason            = "InstallFailed"
+	provisionTimedOutReason          = "InstallTimedOut"
+
+	deprovisionAttemptsExhaustedReason = "DeprovisionAttemptsExhausted"
+	namespaceTerminatingReason         = "NamespaceTerminating"
+	deprovisionCompletedReason         = "DeprovisionCompleted"
+	preserveOnDeleteReason             = "PreserveOnDelete"
+	noInfraIDReason                    = "NoInfraID"
+	unsupportedPlatformReason          = "UnsupportedPlatform"
+
+	dnsZoneOwnedByOtherReason = "DNSZoneOwnedByOther"
+	dnsZoneOwnedReason        = "DNSZoneOwned"
+
+	regionUnavailableReason = "RegionUnavailable"
+	regionAvailableReason   = "RegionAvailable"
+
+	clusterAdoptedReason     = "ClusterAdopted"
+	adoptionIncompleteReason = "AdoptionIncomplete"
 
 	dnsZoneCheckInterval = 30 * time.Second
 
+	defaultConsoleRouteCheckInterval = 30 * time.Second
+
+	// consoleRouteWaitTimeout is how long an installed cluster is checked for its console route before giving up.
+	consoleRouteWaitTimeout = time.Hour
+
+	consoleRouteNotFoundReason     = "ConsoleRouteNotFound"
+	consoleRouteCheckStoppedReason = "ConsoleRouteCheckStopped"
+	consoleRouteFoundReason        = "ConsoleRouteFound"
+
+	defaultConsoleRouteNamespace = "openshift-console"
+	defaultConsoleRouteName      = "console"
+
 	defaultRequeueTime = 10 * time.Second
 
 	jobHashAnnotation = "hive.openshift.io/jobhash"
+
+	// installPodLogContainer is the install pod container running the install manager, whose log is
+	// captured when an install fails.
+	installPodLogContainer = "hive"
+	installLogConfigMapKey = "install.log"
+
+	// hiveNamespace is the namespace hive runs in, which holds the default pull secret.
+	hiveNamespace = "hive"
+
+	// redactedLogValue replaces the values of sensitive fields in objects written to debug logs.
+	redactedLogValue = "REDACTED"
 )
 
+// sensitiveLogFields are the paths, from the root of the object, of the fields whose values are redacted from
+// objects written to debug logs. Fields with the same name elsewhere in the object are logged.
+var sensitiveLogFields = [][]string{
+	// Route TLS configuration
+	{"spec", "tls", "key"},
+	{"spec", "tls", "certificate"},
+	{"spec", "tls", "caCertificate"},
+	{"spec", "tls", "destinationCACertificate"},
+	// Secret contents
+	{"data"},
+	{"stringData"},
+	// May hold a full copy of the object as it was applied
+	{"metadata", "annotations", corev1.LastAppliedConfigAnnotation},
+}
+
 var (
 	metricCompletedInstallJobRestarts = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -95,27 +226,40 @@ var (
 		},
 		[]string{"cluster_type"},
 	)
-	metricInstallJobDuration = prometheus.NewHistogram(
+	metricInstallJobDuration = hivemetrics.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "hive_cluster_deployment_install_job_duration_seconds",
 			Help:    "Distribution of the runtime of completed install jobs.",
 			Buckets: []float64{60, 300, 600, 1200, 1800, 2400, 3000, 3600},
 		},
+		constants.InstallJobDurationBucketsEnvVar,
 	)
-	metricInstallDelaySeconds = prometheus.NewHistogram(
+	metricInstallDelaySeconds = hivemetrics.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "hive_cluster_deployment_install_job_delay_seconds",
 			Help:    "Time between cluster deployment creation and creation of the job to install/provision the cluster.",
 			Buckets: []float64{30, 60, 120, 300, 600, 1200, 1800},
 		},
+		constants.InstallJobDelayBucketsEnvVar,
 	)
-	metricImageSetDelaySeconds = prometheus.NewHistogram(
+	metricImageSetDelaySeconds = hivemetrics.NewHistogram(
 		prometheus.HistogramOpts{
 			Name:    "hive_cluster_deployment_imageset_job_delay_seconds",
 			Help:    "Time between cluster deployment creation and creation of the job which resolves the installer image to use for a ClusterImageSet.",
 			Buckets: []float64{10, 30, 60, 300, 600, 1200, 1800},
 		},
+		constants.ImageSetJobDelayBucketsEnvVar,
 	)
+	metricImageSetJobsRecreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_cluster_deployment_imageset_job_recreated_total",
+		Help: "Counter incremented every time a finished imageset job which did not resolve the installer image is deleted to be run again.",
+	},
+		[]string{"cluster_type"},
+	)
+	metricReconcileErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "hive_cluster_deployment_reconcile_errors_total",
+		Help: "Counter incremented every time a cluster deployment reconcile fails. Failed reconciles are retried after a backoff rather than reported to the controller.",
+	})
 	metricClustersCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "hive_cluster_deployments_created_total",
 		Help: "Counter incremented every time we observe a new cluster.",
@@ -145,6 +289,8 @@ func init() {
 	metrics.Registry.MustRegister(metricCompletedInstallJobRestarts)
 	metrics.Registry.MustRegister(metricInstallDelaySeconds)
 	metrics.Registry.MustRegister(metricImageSetDelaySeconds)
+	metrics.Registry.MustRegister(metricImageSetJobsRecreated)
+	metrics.Registry.MustRegister(metricReconcileErrors)
 	metrics.Registry.MustRegister(metricClustersCreated)
 	metrics.Registry.MustRegister(metricClustersInstalled)
 	metrics.Registry.MustRegister(metricClustersDeleted)
@@ -158,21 +304,258 @@ func Add(mgr manager.Manager) error {
 
 // NewReconciler returns a new reconcile.Reconciler
 func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		log.WithError(err).Fatal("unable to create kube client")
+	}
+	c := hivemetrics.NewClientWithMetricsOrDie(mgr, controllerName)
+	apiReader, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		log.WithError(err).Fatal("unable to create API reader")
+	}
+	eventRecorder := mgr.GetRecorder(controllerName)
+	if os.Getenv(constants.ReadOnlyModeEnvVar) == "true" {
+		log.WithField("controller", controllerName).Warn("read-only mode is enabled, writes will be logged but not made")
+		c = controllerutils.NewReadOnlyClient(c, mgr.GetScheme(), log.WithField("controller", controllerName))
+		eventRecorder = controllerutils.NewReadOnlyEventRecorder(mgr.GetScheme(), log.WithField("controller", controllerName))
+	}
 	return &ReconcileClusterDeployment{
-		Client:                        hivemetrics.NewClientWithMetricsOrDie(mgr, controllerName),
+		Client:                        c,
+		apiReader:                     apiReader,
 		scheme:                        mgr.GetScheme(),
 		remoteClusterAPIClientBuilder: controllerutils.BuildClusterAPIClientFromKubeconfig,
+		eventRecorder:                 eventRecorder,
+		validateInstallConfig:         os.Getenv(constants.ValidateInstallConfigEnvVar) == "true",
+		validateRegion:                os.Getenv(constants.ValidateRegionEnvVar) == "true",
+		awsClientBuilder:              awsclient.NewClient,
+		maintenanceMode:               os.Getenv(constants.MaintenanceModeEnvVar) == "true",
+		failedInstallLogBytes:         getFailedInstallLogBytes(),
+		consoleRouteCheckInterval:     getConsoleRouteCheckInterval(),
+		maxConcurrentDeprovisions:     getMaxConcurrentDeprovisions(),
+		defaultPullSecret:             os.Getenv(constants.DefaultPullSecretEnvVar),
+		defaultSSHKey:                 os.Getenv(constants.DefaultSSHKeyEnvVar),
+		reportInstallMetadata:         os.Getenv(constants.ReportInstallMetadataEnvVar) == "true",
+		imageSetJobResources:          getImageSetJobResources(),
+		resyncInterval:                getResyncInterval(),
+		installCircuitBreaker:         newInstallCircuitBreaker(),
+		maxInstallLifetime:            getMaxInstallLifetime(),
+		installPodAntiAffinity:        os.Getenv(constants.InstallPodAntiAffinityEnvVar) == "true",
+		targetNamespaces:              controllerutils.TargetNamespacesFromEnv(),
+		installPodGracePeriod:         getInstallPodTerminationGracePeriod(),
+		cleanupInstallConfig:          os.Getenv(constants.DeleteInstallConfigAfterInstallEnvVar) == "true",
+		installPodLogReader:           newInstallPodLogReader(kubeClient),
+		imageSetJobTTL:                getImageSetJobTTL(),
+		disableInstallPodSAToken:      os.Getenv(constants.DisableInstallPodServiceAccountTokenEnvVar) == "true",
+		imageResolutionTimeout:        getImageResolutionTimeout(),
+		jobDeletionPropagation:        getJobDeletionPropagation(),
+	}
+}
+
+// getFailedInstallLogBytes returns the number of bytes of install log to capture for failed installs. Zero
+// is returned when capturing is not configured or the configured value is invalid.
+func getFailedInstallLogBytes() int64 {
+	value := os.Getenv(constants.FailedInstallLogBytesEnvVar)
+	if value == "" {
+		return 0
+	}
+	logBytes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || logBytes < 0 {
+		log.WithField("value", value).Warn("invalid failed install log size, install logs will not be captured")
+		return 0
+	}
+	return logBytes
+}
+
+// getMaxConcurrentDeprovisions returns the maximum number of deprovision requests which may be in progress at
+// once. Zero is returned when no limit is configured or the configured value is invalid.
+func getMaxConcurrentDeprovisions() int {
+	value := os.Getenv(constants.MaxConcurrentDeprovisionsEnvVar)
+	if value == "" {
+		return 0
+	}
+	max, err := strconv.Atoi(value)
+	if err != nil || max < 0 {
+		log.WithField("value", value).Warn("invalid maximum concurrent deprovisions, deprovisions will not be throttled")
+		return 0
+	}
+	return max
+}
+
+// getInstallPodTerminationGracePeriod returns the termination grace period of install pods from the
+// environment. Zero is returned when it is not configured or the configured value is invalid.
+func getInstallPodTerminationGracePeriod() int64 {
+	value := os.Getenv(constants.InstallPodTerminationGracePeriodEnvVar)
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		log.WithField("value", value).Warn("invalid install pod termination grace period, using default")
+		return 0
+	}
+	return seconds
+}
+
+// getImageSetJobTTL returns how long finished imageset jobs are kept, in seconds, from the environment. Zero is
+// returned when it is not configured or the configured value is invalid.
+func getImageSetJobTTL() int32 {
+	value := os.Getenv(constants.ImageSetJobTTLSecondsAfterFinishedEnvVar)
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || seconds <= 0 {
+		log.WithField("value", value).Warn("invalid imageset job TTL, using default")
+		return 0
+	}
+	return int32(seconds)
+}
+
+// getResyncInterval returns the periodic resync interval for in-progress cluster deployments from the
+// environment. Zero is returned when resyncs are not configured or the configured value is invalid.
+func getResyncInterval() time.Duration {
+	value := os.Getenv(constants.ClusterDeploymentResyncIntervalEnvVar)
+	if value == "" {
+		return 0
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.WithError(err).WithField("interval", value).Warn("invalid clusterdeployment resync interval, periodic resyncs disabled")
+		return 0
+	}
+	return interval
+}
+
+// getMaxInstallLifetime returns the longest a cluster may spend installing from the environment. Zero is
+// returned when no limit is configured or the configured value is invalid.
+func getMaxInstallLifetime() time.Duration {
+	value := os.Getenv(constants.MaxInstallLifetimeEnvVar)
+	if value == "" {
+		return 0
+	}
+	lifetime, err := time.ParseDuration(value)
+	if err != nil || lifetime < 0 {
+		log.WithError(err).WithField("lifetime", value).Warn("invalid max install lifetime, installs will not time out")
+		return 0
+	}
+	return lifetime
+}
+
+// getImageResolutionTimeout returns the longest the installer image of a cluster may take to be resolved from
+// the environment. Zero is returned when no limit is configured or the configured value is invalid.
+func getImageResolutionTimeout() time.Duration {
+	value := os.Getenv(constants.ImageResolutionTimeoutEnvVar)
+	if value == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		log.WithError(err).WithField("timeout", value).Warn("invalid image resolution timeout, image resolution will not time out")
+		return 0
+	}
+	return timeout
+}
+
+// getJobDeletionPropagation returns the propagation policy with which install and imageset jobs are deleted from
+// the environment. Foreground is returned when it is not configured or the configured value is invalid.
+func getJobDeletionPropagation() metav1.DeletionPropagation {
+	value := metav1.DeletionPropagation(os.Getenv(constants.JobDeletionPropagationEnvVar))
+	switch value {
+	case "":
+		return metav1.DeletePropagationForeground
+	case metav1.DeletePropagationForeground, metav1.DeletePropagationBackground:
+		return value
+	default:
+		log.WithField("propagation", value).Warn("invalid job deletion propagation policy, using foreground deletion")
+		return metav1.DeletePropagationForeground
+	}
+}
+
+// getImageSetJobResources returns the resources requested by the containers of imageset jobs, using the
+// defaults for any request which is unset or invalid in the environment.
+func getImageSetJobResources() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    getImageSetJobRequest(constants.ImageSetJobCPURequestEnvVar, imageset.DefaultCPURequest),
+			corev1.ResourceMemory: getImageSetJobRequest(constants.ImageSetJobMemoryRequestEnvVar, imageset.DefaultMemoryRequest),
+		},
+	}
+}
+
+func getImageSetJobRequest(envVar, defaultValue string) resource.Quantity {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return resource.MustParse(defaultValue)
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil || quantity.Sign() <= 0 {
+		log.WithError(err).WithField("value", value).Warnf("invalid %s, using default", envVar)
+		return resource.MustParse(defaultValue)
+	}
+	return quantity
+}
+
+// getConsoleRouteCheckInterval returns the console route check interval from the environment, falling back to
+// the default if it is unset or invalid.
+func getConsoleRouteCheckInterval() time.Duration {
+	value := os.Getenv(constants.ConsoleRouteCheckIntervalEnvVar)
+	if value == "" {
+		return defaultConsoleRouteCheckInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.WithError(err).WithField("interval", value).Warn("invalid console route check interval, using default")
+		return defaultConsoleRouteCheckInterval
+	}
+	return interval
+}
+
+// newInstallPodLogReader returns a function reading at most the last limitBytes bytes of the install manager log
+// of an install pod. Every line holds at least one byte, so only that many lines are requested from the API server.
+func newInstallPodLogReader(kubeClient kubernetes.Interface) func(namespace, podName string, limitBytes int64) ([]byte, error) {
+	return func(namespace, podName string, limitBytes int64) ([]byte, error) {
+		stream, err := kubeClient.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+			Container: installPodLogContainer,
+			TailLines: &limitBytes,
+		}).Stream()
+		if err != nil {
+			return nil, err
+		}
+		defer stream.Close()
+		return readTail(stream, limitBytes)
+	}
+}
+
+// readTail reads r to the end and returns its last limitBytes bytes, without ever holding much more than that in
+// memory.
+func readTail(r io.Reader, limitBytes int64) ([]byte, error) {
+	tail := []byte{}
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(chunk)
+		tail = append(tail, chunk[:n]...)
+		if excess := int64(len(tail)) - limitBytes; excess > 0 {
+			tail = append(tail[:0], tail[excess:]...)
+		}
+		if err == io.EOF {
+			return tail, nil
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 }
 
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
 func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("clusterdeployment-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	c, err := heartbeat.NewController("clusterdeployment-controller", mgr, controller.Options{Reconciler: newRateLimitedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
 	if err != nil {
 		return err
 	}
 
+	reconciler := r.(*ReconcileClusterDeployment)
+
 	// Watch for changes to ClusterDeployment
 	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -187,7 +570,7 @@ func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	// Watch for pods created by an install job:
 	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &handler.EnqueueRequestsFromMapFunc{
-		ToRequests: handler.ToRequestsFunc(selectorPodWatchHandler),
+		ToRequests: handler.ToRequestsFunc(reconciler.selectorPodWatchHandler),
 	})
 	if err != nil {
 		return err
@@ -208,6 +591,14 @@ func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to ClusterImageSets referenced by clusters which are still installing:
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterImageSet{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(reconciler.clusterImageSetWatchHandler),
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -218,9 +609,103 @@ type ReconcileClusterDeployment struct {
 	client.Client
 	scheme *runtime.Scheme
 
+	// apiReader reads objects straight from the API server, for when the cache has not caught up with an
+	// object created on a previous reconcile.
+	apiReader client.Reader
+
 	// remoteClusterAPIClientBuilder is a function pointer to the function that builds a client for the
 	// remote cluster's cluster-api
 	remoteClusterAPIClientBuilder func(string) (client.Client, error)
+
+	// eventRecorder is used to record events on cluster deployments.
+	eventRecorder record.EventRecorder
+
+	// validateInstallConfig enables validation of the generated install-config before an install job
+	// is launched.
+	validateInstallConfig bool
+
+	// validateRegion enables checking that the region of a cluster is enabled for its AWS credentials
+	// before an install job is launched.
+	validateRegion bool
+
+	// awsClientBuilder is a function pointer to the function that builds the aws client
+	awsClientBuilder func(kClient client.Client, secretName, namespace, region string) (awsclient.Client, error)
+
+	// maintenanceMode stops the creation of new install and imageset jobs. Existing jobs are still
+	// tracked and clusters are still updated and deleted.
+	maintenanceMode bool
+
+	// failedInstallLogBytes is the number of bytes from the end of the install pod log to save when an
+	// install fails. Zero disables capturing install logs.
+	failedInstallLogBytes int64
+
+	// installPodLogReader reads at most the last limitBytes bytes of the log of the install pod with the given
+	// namespace and name.
+	installPodLogReader func(namespace, podName string, limitBytes int64) ([]byte, error)
+
+	// consoleRouteCheckInterval is how long to wait before checking again for the console route of an
+	// installed cluster which does not have one yet.
+	consoleRouteCheckInterval time.Duration
+
+	// maxConcurrentDeprovisions is the maximum number of incomplete deprovision requests across all
+	// namespaces. Zero means no limit.
+	maxConcurrentDeprovisions int
+
+	// defaultPullSecret is the name of the pull secret in the hive namespace used for cluster deployments
+	// which do not reference a pull secret.
+	defaultPullSecret string
+
+	// defaultSSHKey is the name of the SSH key secret in the hive namespace used for cluster deployments which
+	// do not reference an SSH key.
+	defaultSSHKey string
+
+	// reportInstallMetadata enables copying the install metadata of clusters from their metadata ConfigMap
+	// into their status.
+	reportInstallMetadata bool
+
+	// imageSetJobResources are the resources requested by the containers of imageset jobs.
+	imageSetJobResources corev1.ResourceRequirements
+
+	// resyncInterval is the longest an in-progress cluster deployment goes without being reconciled. Zero
+	// disables periodic resyncs.
+	resyncInterval time.Duration
+
+	// installCircuitBreaker pauses the creation of new install jobs after too many recent install failures.
+	// Nil when the circuit breaker is disabled.
+	installCircuitBreaker *installCircuitBreaker
+
+	// maxInstallLifetime is the longest a cluster may spend installing across all of its install jobs before
+	// it is failed. Zero disables the limit.
+	maxInstallLifetime time.Duration
+
+	// installPodAntiAffinity enables pod anti-affinity on install jobs so that install pods are spread
+	// across nodes.
+	installPodAntiAffinity bool
+
+	// targetNamespaces are the namespaces hive is restricted to, across which install pods are spread. Install
+	// pods are only spread within their own namespace when it is empty.
+	targetNamespaces []string
+
+	// installPodGracePeriod is the termination grace period in seconds set on install pods. Zero
+	// leaves the default.
+	installPodGracePeriod int64
+
+	// cleanupInstallConfig deletes the install-config ConfigMap or Secret of clusters once they are installed.
+	cleanupInstallConfig bool
+
+	// imageSetJobTTL is how long finished imageset jobs are kept, in seconds. Zero leaves the default.
+	imageSetJobTTL int32
+
+	// disableInstallPodSAToken stops the service account token from being mounted into install pods.
+	disableInstallPodSAToken bool
+
+	// imageResolutionTimeout is the longest the installer image of a cluster may take to be resolved before
+	// imageset jobs stop being created for it. Zero disables the limit.
+	imageResolutionTimeout time.Duration
+
+	// jobDeletionPropagation is the propagation policy with which install and imageset jobs are deleted. Empty
+	// means foreground deletion.
+	jobDeletionPropagation metav1.DeletionPropagation
 }
 
 // Reconcile reads that state of the cluster for a ClusterDeployment object and makes changes based on the state read
@@ -231,10 +716,16 @@ type ReconcileClusterDeployment struct {
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts;secrets;configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods;namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeployments;clusterdeployments/status;clusterdeployments/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterimagesets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=syncsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeprovisionrequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterimagesets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=hiveconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=hiveconfigs/status,verbs=get;update;patch
 func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	start := time.Now()
 	cdLog := log.WithFields(log.Fields{
@@ -265,40 +756,89 @@ func (r *ReconcileClusterDeployment) Reconcile(request reconcile.Request) (recon
 		return reconcile.Result{}, err
 	}
 
-	return r.reconcile(request, cd, cdLog)
+	cdLog = loggerForClusterDeployment(cd, cdLog)
+	result, err := r.reconcile(request, cd, cdLog)
+	if err == nil && r.resyncInterval > 0 && !cd.Status.Installed && cd.DeletionTimestamp == nil && !result.Requeue &&
+		(result.RequeueAfter == 0 || result.RequeueAfter > r.resyncInterval) {
+		// Make sure in-progress clusters are reconciled periodically, even when no events arrive for them,
+		// so that drift such as a manually deleted job is noticed.
+		cdLog.WithField("requeueAfter", r.resyncInterval).Debug("requeueing in-progress cluster for periodic resync")
+		result.RequeueAfter = r.resyncInterval
+	}
+	return result, err
+}
+
+// loggerForClusterDeployment returns a logger for the cluster deployment with the level from its log-level
+// annotation, when that is more verbose than the level of cdLog. Otherwise cdLog is returned unchanged.
+func loggerForClusterDeployment(cd *hivev1.ClusterDeployment, cdLog *log.Entry) *log.Entry {
+	value, ok := cd.Annotations[logLevelAnnotation]
+	if !ok {
+		return cdLog
+	}
+	level, err := log.ParseLevel(value)
+	if err != nil {
+		cdLog.WithField("level", value).Warn("ignoring invalid log level annotation")
+		return cdLog
+	}
+	if level <= cdLog.Logger.GetLevel() {
+		return cdLog
+	}
+	logger := log.New()
+	logger.Out = cdLog.Logger.Out
+	logger.Formatter = cdLog.Logger.Formatter
+	logger.Hooks = cdLog.Logger.Hooks
+	logger.ReportCaller = cdLog.Logger.ReportCaller
+	logger.SetLevel(level)
+	return logger.WithFields(cdLog.Data)
 }
 
 func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
 	origCD := cd
 	cd = cd.DeepCopy()
 
+	// Once our deprovision finalizer is gone, hive's cleanup is done. Other controllers may still hold
+	// finalizers of their own, so leave the cluster deployment alone while they finish.
+	if cd.DeletionTimestamp != nil && !controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision) {
+		cdLog.WithField("finalizers", cd.Finalizers).Debug("cluster deployment is being deleted and hive cleanup is complete")
+		clearUnderwaySecondsMetrics(cd)
+		return reconcile.Result{}, nil
+	}
+
 	// We previously allowed clusterdeployment.spec.ingress[] entries to have ingress domains with a leading '*'.
 	// Migrate the clusterdeployment to the new format if we find a wildcard ingress domain.
 	// TODO: we can one day remove this once all clusterdeployment are known to have non-wildcard data
-	if migrateWildcardIngress(cd) {
-		cdLog.Info("migrating wildcard ingress entries")
+	if migrations := migrateWildcardIngress(cd); len(migrations) > 0 {
+		cdLog.WithField("migrations", migrations).Info("migrating wildcard ingress entries")
 		err := r.Update(context.TODO(), cd)
 		if err != nil {
 			cdLog.WithError(err).Error("failed to update cluster deployment")
 			return reconcile.Result{}, err
 		}
+		for _, migration := range migrations {
+			r.eventRecorder.Eventf(cd, corev1.EventTypeNormal, ingressDomainMigratedReason,
+				"migrated domain of ingress %s from %s to %s", migration.Name, migration.From, migration.To)
+		}
+		cd.Status.IngressDomainMigrations = append(cd.Status.IngressDomainMigrations, migrations...)
+		err = r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.WithError(err).Error("failed to record ingress domain migrations")
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{}, nil
 	}
 
-	imageSet, modified, err := r.getClusterImageSet(cd, cdLog)
-	if modified || err != nil {
+	imageSet, err := r.getClusterImageSet(cd, cdLog)
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	hiveImage := r.getHiveImage(cd, imageSet, cdLog)
+	hiveImage, defaultHiveImage := r.getHiveImage(cd, imageSet, cdLog)
+	if _, err := r.setHiveImageUnresolvedCondition(cd, defaultHiveImage, cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
 	releaseImage := r.getReleaseImage(cd, imageSet, cdLog)
 
 	if cd.DeletionTimestamp != nil {
-		if !controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision) {
-			clearUnderwaySecondsMetrics(cd)
-			return reconcile.Result{}, nil
-		}
-
 		// Deprovision still underway, report metric for this cluster.
 		hivemetrics.MetricClusterDeploymentDeprovisioningUnderwaySeconds.WithLabelValues(
 			cd.Name,
@@ -318,27 +858,28 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 		return r.syncDeletedClusterDeployment(cd, hiveImage, cdLog)
 	}
 
+	if cd.Annotations[expireNowAnnotation] == "true" {
+		cdLog.Debug("found expire now annotation")
+		return r.deleteExpiredClusterDeployment(cd, time.Now(), cdLog)
+	}
+
 	// requeueAfter will be used to determine if cluster should be requeued after
 	// reconcile has completed
 	var requeueAfter time.Duration
-	// Check for the delete-after annotation, and if the cluster has expired, delete it
-	deleteAfter, ok := cd.Annotations[deleteAfterAnnotation]
-	if ok {
-		cdLog.Debugf("found delete after annotation: %s", deleteAfter)
-		dur, err := time.ParseDuration(deleteAfter)
+	// Check for the delete-after annotations, and if the cluster has expired, delete it
+	_, hasDeleteAfter := cd.Annotations[deleteAfterAnnotation]
+	_, hasDeleteAfterInstalled := cd.Annotations[deleteAfterInstalledAnnotation]
+	if (hasDeleteAfter || hasDeleteAfterInstalled) && cd.Annotations[noExpiryAnnotation] == "true" {
+		cdLog.Info("cluster is protected by the no-expiry annotation, ignoring delete after annotations")
+	} else if hasDeleteAfter || hasDeleteAfterInstalled {
+		expiry, err := getClusterExpiry(cd)
 		if err != nil {
-			return reconcile.Result{}, fmt.Errorf("error parsing %s as a duration: %v", deleteAfterAnnotation, err)
+			return reconcile.Result{}, err
 		}
-		if !cd.CreationTimestamp.IsZero() {
-			expiry := cd.CreationTimestamp.Add(dur)
+		if expiry != nil {
 			cdLog.Debugf("cluster expires at: %s", expiry)
-			if time.Now().After(expiry) {
-				cdLog.WithField("expiry", expiry).Info("cluster has expired, issuing delete")
-				err := r.Delete(context.TODO(), cd)
-				if err != nil {
-					cdLog.WithError(err).Error("error deleting expired cluster")
-				}
-				return reconcile.Result{}, err
+			if time.Now().After(*expiry) {
+				return r.deleteExpiredClusterDeployment(cd, *expiry, cdLog)
 			}
 
 			// We have an expiry time but we're not expired yet. Set requeueAfter for just after expiry time
@@ -347,6 +888,17 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 		}
 	}
 
+	// An installed cluster should always carry the deprovision finalizer. If it has been removed, for example
+	// by a manual edit, deleting the cluster would leave its cloud resources behind, so put it back.
+	if cd.Status.Installed && !controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision) {
+		cdLog.Warn("installed clusterdeployment is missing the deprovision finalizer, re-adding it")
+		if err := r.addClusterDeploymentFinalizer(cd); err != nil {
+			cdLog.WithError(err).Error("error re-adding finalizer")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
 	if !controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision) {
 		cdLog.Debugf("adding clusterdeployment finalizer")
 		if err := r.addClusterDeploymentFinalizer(cd); err != nil {
@@ -357,33 +909,80 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 		return reconcile.Result{}, nil
 	}
 
+	if !cd.Status.Installed && cd.Annotations[adoptAnnotation] == "true" {
+		return r.adoptClusterDeployment(cd, requeueAfter, cdLog)
+	}
+
+	if !cd.Status.Installed {
+		cancelled := cd.Annotations[cancelInstallAnnotation] == "true"
+		if cancelled {
+			if err := r.deleteInstallJob(cd, cdLog); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		modified, err := r.setInstallCancelledCondition(cd, cancelled, cdLog)
+		if err != nil || modified || cancelled {
+			return reconcile.Result{}, err
+		}
+	} else if cd.Annotations[cancelInstallAnnotation] == "true" {
+		cdLog.Warn("cluster is already installed, ignoring install cancellation")
+	}
+
+	if !cd.Status.Installed && r.maxInstallLifetime > 0 && cd.Status.InstallStartedTimestamp != nil {
+		remaining := r.maxInstallLifetime - time.Since(cd.Status.InstallStartedTimestamp.Time)
+		if remaining <= 0 {
+			return reconcile.Result{}, r.failTimedOutInstall(cd, cdLog)
+		}
+		// Nothing may queue a stuck install, so check again when it runs out of time.
+		if requeueAfter == 0 || requeueAfter > remaining {
+			requeueAfter = remaining
+		}
+	}
+
+	// genCD is the cluster deployment with the default secrets of the HiveConfig filled in. The secrets of the
+	// cluster are read, and its jobs and managed DNS zone generated, from genCD. cd is replaced with the copy
+	// read back from the server on every status update, so it must not hold the defaults.
+	genCD := cd.DeepCopy()
+	if genCD.Spec.SSHKey == nil && r.defaultSSHKey != "" {
+		if err := r.useDefaultSSHKey(genCD, cdLog); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
 	cdLog.Debug("loading SSH key secret")
-	if cd.Spec.SSHKey == nil {
+	if genCD.Spec.SSHKey == nil {
 		cdLog.Error("cluster has no ssh key set, unable to launch install")
 		return reconcile.Result{}, fmt.Errorf("cluster has no ssh key set, unable to launch install")
 	}
-	sshKey, err := controllerutils.LoadSecretData(r.Client, cd.Spec.SSHKey.Name,
+	sshKey, err := controllerutils.LoadSecretData(r.Client, genCD.Spec.SSHKey.Name,
 		cd.Namespace, adminSSHKeySecretKey)
 	if err != nil {
 		cdLog.WithError(err).Error("unable to load ssh key from secret")
 		return reconcile.Result{}, err
 	}
 
-	if cd.Status.InstallerImage == nil {
-		return r.resolveInstallerImage(cd, imageSet, releaseImage, hiveImage, cdLog)
-	}
-
-	if cd.Spec.ManageDNS {
-		managedDNSZoneAvailable, err := r.ensureManagedDNSZone(cd, cdLog)
-		if err != nil {
+	if genCD.Spec.PullSecret.Name == "" && r.defaultPullSecret != "" {
+		if err := r.useDefaultPullSecret(genCD, cdLog); err != nil {
 			return reconcile.Result{}, err
 		}
-		if !managedDNSZoneAvailable {
-			// The clusterdeployment will be queued when the owned DNSZone's status
-			// is updated to available.
-			cdLog.Debug("DNSZone is not yet available. Waiting for zone to become available.")
-			return reconcile.Result{}, nil
-		}
+	}
+
+	if err := r.useDefaultAWSCredentials(genCD, cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Adopted clusters were installed outside of hive, so they are installed without an installer image.
+	if cd.Status.InstallerImage == nil && !cd.Status.Installed {
+		return r.resolveInstallerImage(cd, genCD, imageSet, releaseImage, hiveImage, cdLog)
+	}
+	if installerImageOutdated(cd, imageSet, releaseImage) {
+		cdLog.WithField("releaseImage", releaseImage).Info("installer image is outdated, resolving installer image again")
+		// No install job may be started with the outdated installer image while the new one is resolved.
+		cd.Status.InstallerImage = nil
+		return r.resolveInstallerImage(cd, genCD, imageSet, releaseImage, hiveImage, cdLog)
+	}
+	if err := r.cacheInstallerImage(cd, imageSet, cdLog); err != nil {
+		return reconcile.Result{}, err
 	}
 
 	// firstInstalledObserve is the flag that is used for reporting the provision job duration metric
@@ -403,14 +1002,28 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 		cdLog.WithError(err).Error("install job is being deleted, requeueing to wait for deletion")
 		return reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
 	} else {
-		// setting the flag so that we can report the metric after cd is installed
-		if existingJob.Status.Succeeded > 0 && !cd.Status.Installed {
+		// setting the flag so that we can report the metric after cd is installed. Completion is detected
+		// from the JobComplete condition, as Status.Succeeded can briefly lag behind it.
+		if controllerutils.IsSuccessful(existingJob) && !cd.Status.Installed {
 			firstInstalledObserve = true
 		}
 	}
 
 	if cd.Status.Installed {
 		cdLog.Debug("cluster is already installed, no processing of install job needed")
+		if cd.Spec.ManageDNS {
+			if available, result, err := r.waitForManagedDNSZone(cd, genCD, cdLog); !available || err != nil {
+				return result, err
+			}
+		}
+		if err := r.syncPostInstallSyncSet(cd, cdLog); err != nil {
+			return reconcile.Result{}, err
+		}
+		if r.cleanupInstallConfig {
+			if err := r.deleteInstallConfig(cd, cdLog); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
 	} else {
 		// Indicate that the cluster is still installing:
 		hivemetrics.MetricClusterDeploymentProvisionUnderwaySeconds.WithLabelValues(
@@ -420,23 +1033,150 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 			time.Since(cd.CreationTimestamp.Time).Seconds())
 
 		cdLog.Debug("loading pull secret secret")
-		pullSecret, err := controllerutils.LoadSecretData(r.Client, cd.Spec.PullSecret.Name, cd.Namespace, corev1.DockerConfigJsonKey)
+		pullSecret, err := r.loadPullSecret(genCD)
 		if err != nil {
 			cdLog.WithError(err).Error("unable to load pull secret from secret")
 			return reconcile.Result{}, err
 		}
+		pullSecretErr := install.ValidatePullSecret([]byte(pullSecret))
+		if modified, err := r.setPullSecretInvalidCondition(cd, pullSecretErr, cdLog); err != nil || modified {
+			return reconcile.Result{}, err
+		}
+		if pullSecretErr != nil {
+			cdLog.WithError(pullSecretErr).Warn("pull secret is invalid, not launching install")
+			return reconcile.Result{}, nil
+		}
+
+		additionalTrustBundle := ""
+		if cd.Spec.AdditionalTrustBundle != nil {
+			cdLog.Debug("loading additional trust bundle")
+			additionalTrustBundle, err = controllerutils.LoadSecretData(r.Client, cd.Spec.AdditionalTrustBundle.Name, cd.Namespace, hivev1.AdditionalTrustBundleSecretKey)
+			if err != nil {
+				cdLog.WithError(err).Error("unable to load additional trust bundle from secret")
+				return reconcile.Result{}, err
+			}
+		}
+
+		bootstrapIgnitionOverride := ""
+		if cd.Spec.BootstrapIgnitionOverrideRef != nil {
+			cdLog.Debug("loading bootstrap ignition override")
+			bootstrapIgnitionOverride, err = controllerutils.LoadSecretData(r.Client, cd.Spec.BootstrapIgnitionOverrideRef.Name, cd.Namespace, hivev1.BootstrapIgnitionOverrideSecretKey)
+			if err != nil {
+				cdLog.WithError(err).Error("unable to load bootstrap ignition override from secret")
+				return reconcile.Result{}, err
+			}
+			validationErr := install.ValidateIgnitionConfig([]byte(bootstrapIgnitionOverride))
+			modified, err := r.setBootstrapIgnitionOverrideInvalidCondition(cd, validationErr, cdLog)
+			if err != nil || modified {
+				return reconcile.Result{}, err
+			}
+			if validationErr != nil {
+				cdLog.WithError(validationErr).Warn("bootstrap ignition override is invalid, not launching install")
+				return reconcile.Result{}, nil
+			}
+		}
+
+		var manifests map[string]string
+		if cd.Spec.ManifestsConfigMapRef != nil {
+			cdLog.Debug("loading custom manifests")
+			manifestsConfigMap := &kapi.ConfigMap{}
+			err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.ManifestsConfigMapRef.Name}, manifestsConfigMap)
+			if err != nil {
+				cdLog.WithError(err).Error("unable to load custom manifests from config map")
+				return reconcile.Result{}, err
+			}
+			manifests = manifestsConfigMap.Data
+			validationErr := install.ValidateCustomManifests(manifests)
+			modified, err := r.setManifestsInvalidCondition(cd, validationErr, cdLog)
+			if err != nil || modified {
+				return reconcile.Result{}, err
+			}
+			if validationErr != nil {
+				cdLog.WithError(validationErr).Warn("custom manifests are invalid, not launching install")
+				return reconcile.Result{}, nil
+			}
+		}
+
+		invalidDomains := invalidIngressDomains(cd)
+		modified, err := r.setIngressDomainInvalidCondition(cd, invalidDomains, cdLog)
+		if err != nil || modified {
+			return reconcile.Result{}, err
+		}
+		if len(invalidDomains) > 0 {
+			cdLog.WithField("domains", invalidDomains).Warn("ingress domains are not within the base domain, not launching install")
+			return reconcile.Result{}, nil
+		}
+
+		if r.validateInstallConfig {
+			ic, err := install.GenerateInstallConfig(genCD, sshKey, pullSecret, true)
+			if err != nil {
+				cdLog.WithError(err).Error("error generating install config")
+				return reconcile.Result{}, err
+			}
+			validationErr := install.ValidateInstallConfig(ic)
+			modified, err := r.setInstallConfigInvalidCondition(cd, validationErr, cdLog)
+			if err != nil || modified {
+				return reconcile.Result{}, err
+			}
+			if validationErr != nil {
+				cdLog.WithError(validationErr).Warn("install config is invalid, not launching install")
+				return reconcile.Result{}, nil
+			}
+		}
+
+		// The region is only checked before an install job is created, so that running installs do not make
+		// an AWS API call on every reconcile.
+		if r.validateRegion && existingJob == nil && cd.Spec.AWS != nil {
+			available, err := r.isRegionAvailable(genCD, cdLog)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			modified, err := r.setRegionUnavailableCondition(cd, available, cdLog)
+			if err != nil || modified {
+				return reconcile.Result{}, err
+			}
+			if !available {
+				cdLog.WithField("region", cd.Spec.AWS.Region).Warn("region is not enabled for the AWS credentials, not launching install")
+				return reconcile.Result{}, nil
+			}
+		}
+
+		// The managed DNSZone is only created once the install prerequisites above have passed, so that
+		// clusters which fail early do not leave zones behind.
+		if cd.Spec.ManageDNS {
+			if available, result, err := r.waitForManagedDNSZone(cd, genCD, cdLog); !available || err != nil {
+				return result, err
+			}
+		}
 
 		job, cfgMap, err := install.GenerateInstallerJob(
-			cd,
+			genCD,
 			hiveImage,
 			releaseImage,
 			serviceAccountName,
 			sshKey,
-			pullSecret)
+			pullSecret,
+			additionalTrustBundle,
+			bootstrapIgnitionOverride)
 		if err != nil {
 			cdLog.WithError(err).Error("error generating install job")
 			return reconcile.Result{}, err
 		}
+		if r.installPodAntiAffinity {
+			install.SetInstallPodAntiAffinity(job, r.targetNamespaces)
+		}
+		if r.installPodGracePeriod > 0 {
+			install.SetInstallPodTerminationGracePeriod(job, r.installPodGracePeriod)
+		}
+		if r.disableInstallPodSAToken {
+			install.DisableInstallPodServiceAccountToken(job)
+		}
+		if cd.Spec.ManifestsConfigMapRef != nil {
+			if err := install.SetCustomManifests(job, cd.Spec.ManifestsConfigMapRef.Name, manifests); err != nil {
+				cdLog.WithError(err).Error("error adding custom manifests to install job")
+				return reconcile.Result{}, err
+			}
+		}
 
 		jobHash, err := calculateJobSpecHash(job)
 		if err != nil {
@@ -456,26 +1196,67 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 			cdLog.WithError(err).Error("error setting controller reference on config map")
 			return reconcile.Result{}, err
 		}
+		var cfgSecret *kapi.Secret
+		if cd.Spec.InstallConfigSecret {
+			cfgSecret = install.GenerateInstallConfigSecret(cfgMap)
+		}
 
 		cdLog = cdLog.WithField("job", job.Name)
 
-		// Check if the ConfigMap already exists for this ClusterDeployment:
-		cdLog.Debug("checking if install-config.yaml config map exists")
-		existingCfgMap := &kapi.ConfigMap{}
-		err = r.Get(context.TODO(), types.NamespacedName{Name: cfgMap.Name, Namespace: cfgMap.Namespace}, existingCfgMap)
-		if err != nil && errors.IsNotFound(err) {
-			cdLog.WithField("configMap", cfgMap.Name).Infof("creating config map")
-			err = r.Create(context.TODO(), cfgMap)
-			if err != nil {
-				cdLog.Errorf("error creating config map: %v", err)
+		if cfgSecret != nil {
+			// Check if the install-config Secret already exists for this ClusterDeployment:
+			cdLog.Debug("checking if install-config.yaml secret exists")
+			existingCfgSecret := &kapi.Secret{}
+			err = r.Get(context.TODO(), types.NamespacedName{Name: cfgSecret.Name, Namespace: cfgSecret.Namespace}, existingCfgSecret)
+			if err != nil && errors.IsNotFound(err) {
+				cdLog.WithField("secret", cfgSecret.Name).Infof("creating install config secret")
+				err = r.Create(context.TODO(), cfgSecret)
+				if err != nil {
+					cdLog.Errorf("error creating install config secret: %v", err)
+					return reconcile.Result{}, err
+				}
+			} else if err != nil {
+				cdLog.Errorf("error getting install config secret: %v", err)
 				return reconcile.Result{}, err
 			}
-		} else if err != nil {
-			cdLog.Errorf("error getting config map: %v", err)
-			return reconcile.Result{}, err
+		} else {
+			// Check if the ConfigMap already exists for this ClusterDeployment:
+			cdLog.Debug("checking if install-config.yaml config map exists")
+			existingCfgMap := &kapi.ConfigMap{}
+			err = r.Get(context.TODO(), types.NamespacedName{Name: cfgMap.Name, Namespace: cfgMap.Namespace}, existingCfgMap)
+			if err != nil && errors.IsNotFound(err) {
+				cdLog.WithField("configMap", cfgMap.Name).Infof("creating config map")
+				err = r.Create(context.TODO(), cfgMap)
+				if err != nil {
+					cdLog.Errorf("error creating config map: %v", err)
+					return reconcile.Result{}, err
+				}
+			} else if err != nil {
+				cdLog.Errorf("error getting config map: %v", err)
+				return reconcile.Result{}, err
+			}
+		}
+
+		var installsPausedFor time.Duration
+		if existingJob == nil && !r.maintenanceMode && r.installCircuitBreaker != nil {
+			var message string
+			installsPausedFor, message = r.installCircuitBreaker.check()
+			if err := r.setInstallCircuitBreakerCondition(installsPausedFor > 0, message, cdLog); err != nil {
+				cdLog.WithError(err).Warn("unable to update install circuit breaker condition but continuing")
+			}
 		}
 
-		if existingJob == nil {
+		if existingJob == nil && r.maintenanceMode {
+			cdLog.Info("maintenance mode is enabled, not creating install job")
+			if requeueAfter == 0 || requeueAfter > defaultRequeueTime {
+				requeueAfter = defaultRequeueTime
+			}
+		} else if existingJob == nil && installsPausedFor > 0 {
+			cdLog.WithField("pausedFor", installsPausedFor).Info("too many recent install failures, not creating install job")
+			if requeueAfter == 0 || requeueAfter > installsPausedFor {
+				requeueAfter = installsPausedFor
+			}
+		} else if existingJob == nil {
 			cdLog.Infof("creating install job")
 			_, err = controllerutils.SetupClusterInstallServiceAccount(r, cd.Namespace, cdLog)
 			if err != nil {
@@ -484,20 +1265,50 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 			}
 
 			err = r.Create(context.TODO(), job)
-			if err != nil {
+			switch {
+			case errors.IsAlreadyExists(err):
+				// The cache can lag behind a job we created on a previous reconcile. Read the job from
+				// the API server instead, and carry on with status handling using it.
+				cdLog.Info("install job already exists, reading it from the API server")
+				existingJob = &batchv1.Job{}
+				err = r.apiReader.Get(context.TODO(), types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, existingJob)
+				if err != nil {
+					cdLog.WithError(err).Error("error getting existing install job")
+					return reconcile.Result{}, err
+				}
+				if cd.Status.InstallStartedTimestamp == nil {
+					started := existingJob.CreationTimestamp
+					cd.Status.InstallStartedTimestamp = &started
+				}
+			case err != nil:
 				cdLog.Errorf("error creating job: %v", err)
 				return reconcile.Result{}, err
+			default:
+				if cd.Status.InstallStartedTimestamp == nil {
+					now := metav1.Now()
+					cd.Status.InstallStartedTimestamp = &now
+				}
+				kickstartDuration := time.Since(cd.CreationTimestamp.Time)
+				cdLog.WithField("elapsed", kickstartDuration.Seconds()).Info("calculated time to install job seconds")
+				metricInstallDelaySeconds.Observe(float64(kickstartDuration.Seconds()))
 			}
-			kickstartDuration := time.Since(cd.CreationTimestamp.Time)
-			cdLog.WithField("elapsed", kickstartDuration.Seconds()).Info("calculated time to install job seconds")
-			metricInstallDelaySeconds.Observe(float64(kickstartDuration.Seconds()))
 		} else {
 			cdLog.Debug("provision job exists")
-			containerRestarts, err = r.calcInstallPodRestarts(cd, cdLog)
+			if cd.Status.InstallStartedTimestamp == nil {
+				// Clusters installing before the start of installs was recorded use their current job.
+				started := existingJob.CreationTimestamp
+				cd.Status.InstallStartedTimestamp = &started
+			}
+			if r.installCircuitBreaker != nil {
+				r.installCircuitBreaker.recordInstallJob(existingJob)
+			}
+			installPods, err := r.listInstallPods(cd)
 			if err != nil {
 				// Metrics calculation should not shut down reconciliation, logging and moving on.
-				log.WithError(err).Warn("error listing pods, unable to calculate pod restarts but continuing")
+				log.WithError(err).Warn("error listing pods, unable to calculate pod restarts, check for duplicate install pods or capture the install log but continuing")
 			} else {
+				var terminationReason string
+				containerRestarts, terminationReason = calcInstallPodRestarts(installPods, cdLog)
 				if containerRestarts > 0 {
 					cdLog.WithFields(log.Fields{
 						"restarts": containerRestarts,
@@ -506,10 +1317,23 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 
 				// Store the restart count on the cluster deployment status.
 				cd.Status.InstallRestarts = containerRestarts
+
+				// Keep the last known termination reason if the current pods have not terminated.
+				if terminationReason != "" {
+					cd.Status.InstallPodTerminationReason = terminationReason
+				}
+
+				r.setInstallPodDuplicationCondition(cd, installPods, cdLog)
+			}
+
+			if r.failedInstallLogBytes > 0 && controllerutils.IsFailed(existingJob) && err == nil {
+				if err := r.captureFailedInstallLog(cd, installPods, cdLog); err != nil {
+					cdLog.WithError(err).Warn("unable to capture install log but continuing")
+				}
 			}
 
 			if existingJob.Annotations != nil && cfgMap.Annotations != nil {
-				didGenerationChange, err := r.updateOutdatedConfigurations(cd.Generation, existingJob, cfgMap, cdLog)
+				didGenerationChange, err := r.updateOutdatedConfigurations(cd.Generation, existingJob, cfgMap, cfgSecret, cdLog)
 				if didGenerationChange || err != nil {
 					return reconcile.Result{}, err
 				}
@@ -528,13 +1352,23 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 		return reconcile.Result{}, err
 	}
 
+	// Nothing queues the cluster deployment when the console route is created on the remote cluster,
+	// so check for it again shortly.
+	if isWaitingForConsoleRoute(cd) && r.consoleRouteCheckInterval > 0 {
+		if requeueAfter == 0 || requeueAfter > r.consoleRouteCheckInterval {
+			requeueAfter = r.consoleRouteCheckInterval
+		}
+	}
+
 	// firstInstalledObserve will be true if this is the first time we've noticed the install job completed.
 	// If true, we know we can report the metrics associated with a completed job.
 	if firstInstalledObserve {
 		// jobDuration calculates the time elapsed since the install job started
-		jobDuration := existingJob.Status.CompletionTime.Time.Sub(existingJob.Status.StartTime.Time)
-		cdLog.WithField("duration", jobDuration.Seconds()).Debug("install job completed")
-		metricInstallJobDuration.Observe(float64(jobDuration.Seconds()))
+		if existingJob.Status.StartTime != nil && existingJob.Status.CompletionTime != nil {
+			jobDuration := existingJob.Status.CompletionTime.Time.Sub(existingJob.Status.StartTime.Time)
+			cdLog.WithField("duration", jobDuration.Seconds()).Debug("install job completed")
+			metricInstallJobDuration.Observe(float64(jobDuration.Seconds()))
+		}
 
 		// Report a metric for the total number of container restarts:
 		metricCompletedInstallJobRestarts.WithLabelValues(hivemetrics.GetClusterDeploymentType(cd)).
@@ -552,7 +1386,7 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 
 	// Check for requeueAfter duration
 	if requeueAfter != 0 {
-		cdLog.Debugf("cluster will re-sync due to expiry time in: %v", requeueAfter)
+		cdLog.Debugf("cluster will re-sync in: %v", requeueAfter)
 		return reconcile.Result{RequeueAfter: requeueAfter}, nil
 	}
 	return reconcile.Result{}, nil
@@ -563,14 +1397,16 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 // 2 - referenced in the cluster deployment spec.imageSet
 // 3 - specified via environment variable to the hive controller
 // 4 - fallback default hardcoded image reference
-func (r *ReconcileClusterDeployment) getHiveImage(cd *hivev1.ClusterDeployment, imageSet *hivev1.ClusterImageSet, cdLog log.FieldLogger) string {
+// The returned bool is true when the fallback default is used.
+func (r *ReconcileClusterDeployment) getHiveImage(cd *hivev1.ClusterDeployment, imageSet *hivev1.ClusterImageSet, cdLog log.FieldLogger) (string, bool) {
 	if cd.Spec.Images.HiveImage != "" {
-		return cd.Spec.Images.HiveImage
+		return cd.Spec.Images.HiveImage, false
 	}
 	if imageSet != nil && imageSet.Spec.HiveImage != nil {
-		return *imageSet.Spec.HiveImage
+		return *imageSet.Spec.HiveImage, false
 	}
-	return images.GetHiveImage(cdLog)
+	_, fromEnv := os.LookupEnv(images.HiveImageEnvVar)
+	return images.GetHiveImage(cdLog), !fromEnv
 }
 
 // getReleaseImage looks for a a release image in clusterdeployment or its corresponding imageset in the following order:
@@ -586,23 +1422,38 @@ func (r *ReconcileClusterDeployment) getReleaseImage(cd *hivev1.ClusterDeploymen
 	return ""
 }
 
-func (r *ReconcileClusterDeployment) getClusterImageSet(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (*hivev1.ClusterImageSet, bool, error) {
-	if cd.Spec.ImageSet == nil || len(cd.Spec.ImageSet.Name) == 0 {
-		return nil, false, nil
+func (r *ReconcileClusterDeployment) getClusterImageSet(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (*hivev1.ClusterImageSet, error) {
+	if cd.Spec.ImageSet == nil {
+		_, err := r.setImageSetNotFoundCondition(cd, false, cdLog)
+		return nil, err
+	}
+	if len(cd.Spec.ImageSet.Name) == 0 {
+		cdLog.Warning("clusterdeployment references a clusterimageset without a name")
+		_, err := r.setImageSetNotFoundCondition(cd, true, cdLog)
+		return nil, err
 	}
 	imageSet := &hivev1.ClusterImageSet{}
 	err := r.Get(context.TODO(), types.NamespacedName{Name: cd.Spec.ImageSet.Name}, imageSet)
 	switch {
 	case errors.IsNotFound(err):
 		cdLog.WithField("clusterimageset", cd.Spec.ImageSet.Name).Warning("clusterdeployment references non-existent clusterimageset")
-		modified, err := r.setImageSetNotFoundCondition(cd, false, cdLog)
-		return nil, modified, err
+		_, err := r.setImageSetNotFoundCondition(cd, true, cdLog)
+		return nil, err
 	case err != nil:
 		cdLog.WithError(err).WithField("clusterimageset", cd.Spec.ImageSet.Name).Error("unexpected error retrieving clusterimageset")
-		return nil, false, err
+		return nil, err
 	default:
-		return imageSet, false, nil
+		_, err := r.setImageSetNotFoundCondition(cd, false, cdLog)
+		return imageSet, err
+	}
+}
+
+// jobDeletionPropagationPolicy returns the delete option with which install and imageset jobs are deleted.
+func (r *ReconcileClusterDeployment) jobDeletionPropagationPolicy() client.DeleteOptionFunc {
+	if r.jobDeletionPropagation == "" {
+		return client.PropagationPolicy(metav1.DeletePropagationForeground)
 	}
+	return client.PropagationPolicy(r.jobDeletionPropagation)
 }
 
 func (r *ReconcileClusterDeployment) statusUpdate(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
@@ -613,20 +1464,36 @@ func (r *ReconcileClusterDeployment) statusUpdate(cd *hivev1.ClusterDeployment,
 	return err
 }
 
-func (r *ReconcileClusterDeployment) resolveInstallerImage(cd *hivev1.ClusterDeployment, imageSet *hivev1.ClusterImageSet, releaseImage, hiveImage string, cdLog log.FieldLogger) (reconcile.Result, error) {
+func (r *ReconcileClusterDeployment) resolveInstallerImage(cd, genCD *hivev1.ClusterDeployment, imageSet *hivev1.ClusterImageSet, releaseImage, hiveImage string, cdLog log.FieldLogger) (reconcile.Result, error) {
 	if len(cd.Spec.Images.InstallerImage) > 0 {
 		cdLog.WithField("image", cd.Spec.Images.InstallerImage).
 			Debug("setting status.InstallerImage to the value in spec.images.installerImage")
 		cd.Status.InstallerImage = &cd.Spec.Images.InstallerImage
+		cd.Status.InstallerImageReleaseImage = ""
 		return reconcile.Result{}, r.statusUpdate(cd, cdLog)
 	}
 	if imageSet != nil && imageSet.Spec.InstallerImage != nil {
 		cd.Status.InstallerImage = imageSet.Spec.InstallerImage
+		cd.Status.InstallerImageReleaseImage = ""
 		cdLog.WithField("imageset", imageSet.Name).Debug("setting status.InstallerImage using imageSet.Spec.InstallerImage")
 		return reconcile.Result{}, r.statusUpdate(cd, cdLog)
 	}
+	if installerImage := cachedInstallerImage(imageSet, releaseImage); installerImage != nil {
+		cd.Status.InstallerImage = installerImage
+		cd.Status.InstallerImageReleaseImage = releaseImage
+		cdLog.WithField("imageset", imageSet.Name).Info("using installer image already resolved for the clusterimageset")
+		return reconcile.Result{}, r.statusUpdate(cd, cdLog)
+	}
+	if r.imageResolutionTimeout > 0 {
+		if timedOut, err := r.checkImageResolutionTimeout(cd, cdLog); timedOut || err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 	cliImage := images.GetCLIImage(cdLog)
-	job := imageset.GenerateImageSetJob(cd, releaseImage, serviceAccountName, imageset.AlwaysPullImage(cliImage), imageset.AlwaysPullImage(hiveImage))
+	job := imageset.GenerateImageSetJob(genCD, releaseImage, serviceAccountName, imageset.AlwaysPullImage(cliImage), imageset.AlwaysPullImage(hiveImage), r.imageSetJobResources)
+	if r.imageSetJobTTL > 0 {
+		imageset.SetJobTTLSecondsAfterFinished(job, r.imageSetJobTTL)
+	}
 	if err := controllerutil.SetControllerReference(cd, job, r.scheme); err != nil {
 		cdLog.WithError(err).Error("error setting controller reference on job")
 		return reconcile.Result{}, err
@@ -647,12 +1514,16 @@ func (r *ReconcileClusterDeployment) resolveInstallerImage(cd *hivev1.ClusterDep
 	case err == nil && controllerutils.IsFinished(existingJob):
 		jobLog.WithField("successful", controllerutils.IsSuccessful(existingJob)).
 			Warning("Finished job found, but installer image is not yet resolved. Deleting.")
-		err := r.Delete(context.Background(), existingJob,
-			client.PropagationPolicy(metav1.DeletePropagationForeground))
+		err := r.Delete(context.Background(), existingJob, r.jobDeletionPropagationPolicy())
 		if err != nil {
 			jobLog.WithError(err).Error("cannot delete imageset job")
+		} else {
+			metricImageSetJobsRecreated.WithLabelValues(hivemetrics.GetClusterDeploymentType(cd)).Inc()
 		}
 		return reconcile.Result{}, err
+	case errors.IsNotFound(err) && r.maintenanceMode:
+		jobLog.Info("maintenance mode is enabled, not creating imageset job")
+		return reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
 	case errors.IsNotFound(err):
 		jobLog.WithField("releaseImage", releaseImage).Info("creating imageset job")
 		_, err = controllerutils.SetupClusterInstallServiceAccount(r, cd.Namespace, cdLog)
@@ -664,31 +1535,202 @@ func (r *ReconcileClusterDeployment) resolveInstallerImage(cd *hivev1.ClusterDep
 		err = r.Create(context.TODO(), job)
 		if err != nil {
 			jobLog.WithError(err).Error("error creating job")
-		} else {
-			// kickstartDuration calculates the delay between creation of cd and start of imageset job
-			kickstartDuration := time.Since(cd.CreationTimestamp.Time)
-			cdLog.WithField("elapsed", kickstartDuration.Seconds()).Info("calculated time to imageset job seconds")
-			metricImageSetDelaySeconds.Observe(float64(kickstartDuration.Seconds()))
+			return reconcile.Result{}, err
 		}
-		return reconcile.Result{}, err
+		// kickstartDuration calculates the delay between creation of cd and start of imageset job
+		kickstartDuration := time.Since(cd.CreationTimestamp.Time)
+		cdLog.WithField("elapsed", kickstartDuration.Seconds()).Info("calculated time to imageset job seconds")
+		metricImageSetDelaySeconds.Observe(float64(kickstartDuration.Seconds()))
+		// The installer image the job writes to the status is resolved from this release image.
+		cd.Status.InstallerImageReleaseImage = releaseImage
+		return reconcile.Result{}, r.statusUpdate(cd, cdLog)
 	case err != nil:
 		jobLog.WithError(err).Error("cannot get job")
 		return reconcile.Result{}, err
+	case imageset.GetJobReleaseImage(existingJob) != releaseImage:
+		jobLog.WithField("releaseImage", releaseImage).Info("imageset job is resolving an outdated release image, deleting it")
+		err := r.Delete(context.Background(), existingJob, r.jobDeletionPropagationPolicy())
+		if err != nil && !errors.IsNotFound(err) {
+			jobLog.WithError(err).Error("cannot delete imageset job")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
 	default:
 		jobLog.Debug("job exists and is in progress")
+		if cd.Status.InstallerImageReleaseImage != releaseImage {
+			cd.Status.InstallerImageReleaseImage = releaseImage
+			if err := r.statusUpdate(cd, cdLog); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		if r.imageResolutionTimeout > 0 {
+			// Requeue for the deadline, as a stuck imageset job may not produce any further events.
+			return reconcile.Result{RequeueAfter: time.Until(r.imageResolutionDeadline(cd))}, nil
+		}
 	}
 	return reconcile.Result{}, nil
 }
 
+// imageResolutionDeadline returns when resolving the installer image of the cluster deployment times out. The
+// timeout is measured from the creation of the cluster deployment, or from when resolution was restarted after
+// a previous timeout.
+func (r *ReconcileClusterDeployment) imageResolutionDeadline(cd *hivev1.ClusterDeployment) time.Time {
+	start := cd.CreationTimestamp.Time
+	condition := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ImageResolutionTimedOutCondition)
+	if condition != nil && condition.Status == corev1.ConditionFalse {
+		start = condition.LastTransitionTime.Time
+	}
+	return start.Add(r.imageResolutionTimeout)
+}
+
+// checkImageResolutionTimeout returns true when the installer image of the cluster deployment has not been
+// resolved within the image resolution timeout. The imageset job is then deleted and the ImageResolutionTimedOut
+// condition set, and no imageset job is created until the spec of the cluster deployment changes.
+func (r *ReconcileClusterDeployment) checkImageResolutionTimeout(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (bool, error) {
+	condition := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ImageResolutionTimedOutCondition)
+	if condition != nil && condition.Status == corev1.ConditionTrue {
+		if cd.Status.ImageResolutionTimedOutGeneration == cd.Generation {
+			cdLog.Debug("installer image resolution timed out, waiting for the spec to change")
+			return true, nil
+		}
+		cdLog.Info("spec has changed since installer image resolution timed out, resolving installer image again")
+		_, err := r.setImageResolutionTimedOutCondition(cd, false, cdLog)
+		return true, err
+	}
+	if time.Now().Before(r.imageResolutionDeadline(cd)) {
+		return false, nil
+	}
+
+	cdLog.WithField("timeout", r.imageResolutionTimeout).Error("installer image was not resolved in time, no further imageset jobs will be created")
+	job := &batchv1.Job{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: imageset.GetImageSetJobName(cd.Name), Namespace: cd.Namespace}, job)
+	switch {
+	case err == nil:
+		err = r.Delete(context.TODO(), job, r.jobDeletionPropagationPolicy())
+		if err != nil && !errors.IsNotFound(err) {
+			cdLog.WithError(err).Error("cannot delete imageset job")
+			return true, err
+		}
+	case !errors.IsNotFound(err):
+		cdLog.WithError(err).Error("cannot get imageset job")
+		return true, err
+	}
+	_, err = r.setImageResolutionTimedOutCondition(cd, true, cdLog)
+	return true, err
+}
+
+// setImageResolutionTimedOutCondition sets the ImageResolutionTimedOut condition, and records the generation that
+// timed out in the status so that the spec, which may hold in-memory defaults, is never written.
+func (r *ReconcileClusterDeployment) setImageResolutionTimedOutCondition(cd *hivev1.ClusterDeployment, timedOut bool, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := imageResolutionRestartedReason
+	message := "the spec has changed, resolving the installer image again"
+	cd.Status.ImageResolutionTimedOutGeneration = 0
+	if timedOut {
+		status = corev1.ConditionTrue
+		reason = imageResolutionTimedOutReason
+		message = fmt.Sprintf("the installer image was not resolved within %v", r.imageResolutionTimeout)
+		cd.Status.ImageResolutionTimedOutGeneration = cd.Generation
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.ImageResolutionTimedOutCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if reflect.DeepEqual(original.Status, cd.Status) {
+		return false, nil
+	}
+	return true, r.statusUpdate(cd, cdLog)
+}
+
+// cachedInstallerImage returns the installer image resolved for the release image by the imageset job of another
+// cluster deployment using the ClusterImageSet, or nil when it has not been resolved yet.
+func cachedInstallerImage(imageSet *hivev1.ClusterImageSet, releaseImage string) *string {
+	if imageSet == nil || imageSet.Spec.ReleaseImage == nil || *imageSet.Spec.ReleaseImage != releaseImage {
+		return nil
+	}
+	if imageSet.Status.ReleaseImage != releaseImage {
+		return nil
+	}
+	return imageSet.Status.InstallerImage
+}
+
+// cacheInstallerImage records the installer image resolved by the imageset job of the cluster deployment onto the
+// status of its ClusterImageSet, so that other cluster deployments using the same release image reuse it. Only
+// images resolved from the release image of the ClusterImageSet are recorded.
+func (r *ReconcileClusterDeployment) cacheInstallerImage(cd *hivev1.ClusterDeployment, imageSet *hivev1.ClusterImageSet, cdLog log.FieldLogger) error {
+	if imageSet == nil || imageSet.Spec.ReleaseImage == nil || imageSet.Spec.InstallerImage != nil {
+		return nil
+	}
+	if cd.Status.Installed || cd.Status.InstallerImage == nil || cd.Spec.Images.InstallerImage != "" {
+		return nil
+	}
+	releaseImage := *imageSet.Spec.ReleaseImage
+	if cached := cachedInstallerImage(imageSet, releaseImage); cached != nil && *cached == *cd.Status.InstallerImage {
+		return nil
+	}
+	// The imageset job records the release image which was resolved, which may differ from the ClusterImageSet
+	// when the cluster deployment overrides it or the ClusterImageSet has since changed.
+	job := &batchv1.Job{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: imageset.GetImageSetJobName(cd.Name), Namespace: cd.Namespace}, job)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		cdLog.WithError(err).Error("cannot get imageset job")
+		return err
+	}
+	if imageset.GetJobReleaseImage(job) != releaseImage {
+		return nil
+	}
+	imageSetLog := cdLog.WithFields(log.Fields{
+		"imageset":       imageSet.Name,
+		"installerImage": *cd.Status.InstallerImage,
+	})
+	imageSetLog.Info("recording resolved installer image on clusterimageset")
+	imageSet.Status.InstallerImage = cd.Status.InstallerImage
+	imageSet.Status.ReleaseImage = releaseImage
+	if err := r.Status().Update(context.TODO(), imageSet); err != nil {
+		imageSetLog.WithError(err).Error("cannot update clusterimageset status")
+		return err
+	}
+	return nil
+}
+
+// installerImageOutdated returns true when the installer image of a cluster which is still installing no longer
+// matches its ClusterImageSet: either the installer image of the ClusterImageSet has changed, or the installer
+// image was resolved from a release image other than the current one.
+func installerImageOutdated(cd *hivev1.ClusterDeployment, imageSet *hivev1.ClusterImageSet, releaseImage string) bool {
+	if cd.Status.Installed || cd.Spec.Images.InstallerImage != "" || cd.Status.InstallerImage == nil {
+		return false
+	}
+	if imageSet != nil && imageSet.Spec.InstallerImage != nil {
+		return *imageSet.Spec.InstallerImage != *cd.Status.InstallerImage
+	}
+	resolvedFrom := cd.Status.InstallerImageReleaseImage
+	return resolvedFrom != "" && resolvedFrom != releaseImage
+}
+
 func (r *ReconcileClusterDeployment) setImageSetNotFoundCondition(cd *hivev1.ClusterDeployment, isNotFound bool, cdLog log.FieldLogger) (modified bool, err error) {
 	original := cd.DeepCopy()
 	status := corev1.ConditionFalse
 	reason := clusterImageSetFoundReason
-	message := fmt.Sprintf("ClusterImageSet %s is available", cd.Spec.ImageSet.Name)
-	if isNotFound {
+	message := "no ClusterImageSet is referenced"
+	switch {
+	case isNotFound && cd.Spec.ImageSet.Name == "":
+		status = corev1.ConditionTrue
+		reason = clusterImageSetNameEmptyReason
+		message = "the referenced ClusterImageSet has no name"
+	case isNotFound:
 		status = corev1.ConditionTrue
 		reason = clusterImageSetNotFoundReason
 		message = fmt.Sprintf("ClusterImageSet %s is not available", cd.Spec.ImageSet.Name)
+	case cd.Spec.ImageSet != nil:
+		message = fmt.Sprintf("ClusterImageSet %s is available", cd.Spec.ImageSet.Name)
 	}
 	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
 		cd.Status.Conditions,
@@ -696,9 +1738,9 @@ func (r *ReconcileClusterDeployment) setImageSetNotFoundCondition(cd *hivev1.Clu
 		status,
 		reason,
 		message,
-		controllerutils.UpdateConditionNever)
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
 	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
-		cdLog.Info("setting ClusterImageSetNotFoundCondition to %v", status)
+		cdLog.Infof("setting ClusterImageSetNotFoundCondition to %v with reason %s", status, reason)
 		err := r.Status().Update(context.TODO(), cd)
 		if err != nil {
 			cdLog.WithError(err).Error("cannot update status conditions")
@@ -708,154 +1750,1075 @@ func (r *ReconcileClusterDeployment) setImageSetNotFoundCondition(cd *hivev1.Clu
 	return false, nil
 }
 
-// Deletes the job if it exists and its generation does not match the cluster deployment's
-// genetation. Updates the config map if it is outdated too
-func (r *ReconcileClusterDeployment) updateOutdatedConfigurations(cdGeneration int64, existingJob *batchv1.Job, cfgMap *corev1.ConfigMap, cdLog log.FieldLogger) (bool, error) {
-	var err error
-	var didGenerationChange bool
-	if jobGeneration, ok := existingJob.Annotations[clusterDeploymentGenerationAnnotation]; ok {
-		convertedJobGeneration, _ := strconv.ParseInt(jobGeneration, 10, 64)
-		if convertedJobGeneration < cdGeneration {
-			didGenerationChange = true
-			cdLog.Info("deleting outdated install job due to cluster deployment generation change")
-			err = r.Delete(context.TODO(), existingJob, client.PropagationPolicy(metav1.DeletePropagationForeground))
-			if err != nil {
-				cdLog.WithError(err).Errorf("error deleting outdated install job")
-				return didGenerationChange, err
-			}
-		}
+func (r *ReconcileClusterDeployment) setHiveImageUnresolvedCondition(cd *hivev1.ClusterDeployment, isDefault bool, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := hiveImageResolvedReason
+	message := "hive image is configured"
+	if isDefault {
+		status = corev1.ConditionTrue
+		reason = hiveImageDefaultReason
+		message = fmt.Sprintf("no hive image is configured, using the default %s", images.DefaultHiveImage)
 	}
-	if cfgMapGeneration, ok := cfgMap.Annotations[clusterDeploymentGenerationAnnotation]; ok {
-		convertedMapGeneration, _ := strconv.ParseInt(cfgMapGeneration, 10, 64)
-		if convertedMapGeneration < cdGeneration {
-			didGenerationChange = true
-			cdLog.Info("deleting outdated installconfig configmap due to cluster deployment generation change")
-			err = r.Update(context.TODO(), cfgMap)
-			if err != nil {
-				cdLog.WithError(err).Errorf("error deleting outdated config map")
-				return didGenerationChange, err
-			}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.HiveImageUnresolvedCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Infof("setting HiveImageUnresolvedCondition to %v", status)
+		err := r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.WithError(err).Error("cannot update status conditions")
 		}
+		return true, err
 	}
-	return didGenerationChange, err
+	return false, nil
 }
 
-func (r *ReconcileClusterDeployment) updateClusterDeploymentStatus(cd *hivev1.ClusterDeployment, origCD *hivev1.ClusterDeployment, job *batchv1.Job, cdLog log.FieldLogger) error {
-	cdLog.Debug("updating cluster deployment status")
-	if job != nil && job.Name != "" && job.Namespace != "" {
-		// Job exists, check it's status:
-		cd.Status.Installed = controllerutils.IsSuccessful(job)
+func (r *ReconcileClusterDeployment) setInstallConfigInvalidCondition(cd *hivev1.ClusterDeployment, validationErr error, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := installConfigValidReason
+	message := "install config is valid"
+	if validationErr != nil {
+		status = corev1.ConditionTrue
+		reason = installConfigInvalidReason
+		message = validationErr.Error()
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.InstallConfigInvalidCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Infof("setting InstallConfigInvalidCondition to %v", status)
+		err := r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.WithError(err).Error("cannot update status conditions")
+		}
+		return true, err
 	}
+	return false, nil
+}
 
-	// The install manager sets this secret name, but we don't consider it a critical failure and
-	// will attempt to heal it here, as the value is predictable.
-	if cd.Status.Installed && cd.Status.AdminKubeconfigSecret.Name == "" {
-		cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: apihelpers.GetResourceName(cd.Name, "admin-kubeconfig")}
+// regionCheckAWSRegions are the regions used to list the regions enabled for an AWS account, keyed by
+// partition. They are enabled for every account of their partition, unlike the opt-in region being checked.
+var regionCheckAWSRegions = map[string]string{
+	endpoints.AwsPartitionID:      "us-east-1",
+	endpoints.AwsCnPartitionID:    "cn-north-1",
+	endpoints.AwsUsGovPartitionID: "us-gov-west-1",
+}
+
+// regionCheckAWSRegion returns the region used to list the regions enabled for an AWS account, from the partition
+// of the region being checked. The region itself is used when its partition is not known.
+func regionCheckAWSRegion(region string) string {
+	if partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		if checkRegion, ok := regionCheckAWSRegions[partition.ID()]; ok {
+			return checkRegion
+		}
 	}
+	return region
+}
 
-	if cd.Status.AdminKubeconfigSecret.Name != "" {
-		adminKubeconfigSecret := &corev1.Secret{}
-		err := r.Get(context.Background(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Status.AdminKubeconfigSecret.Name}, adminKubeconfigSecret)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				log.Warn("admin kubeconfig does not yet exist")
-			} else {
-				return err
-			}
-		} else {
-			err = r.fixupAdminKubeconfigSecret(adminKubeconfigSecret, cdLog)
-			if err != nil {
-				return err
-			}
-			err = r.setAdminKubeconfigStatus(cd, adminKubeconfigSecret, cdLog)
-			if err != nil {
-				return err
-			}
+// isRegionAvailable returns whether the region of the cluster is enabled for its AWS credentials. The region is
+// assumed to be available when the enabled regions cannot be listed, so that the check never blocks installs
+// on its own.
+func (r *ReconcileClusterDeployment) isRegionAvailable(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (bool, error) {
+	secretName := ""
+	if cd.Spec.PlatformSecrets.AWS != nil {
+		secretName = cd.Spec.PlatformSecrets.AWS.Credentials.Name
+	}
+	awsClient, err := r.awsClientBuilder(r.Client, secretName, cd.Namespace, regionCheckAWSRegion(cd.Spec.AWS.Region))
+	if err != nil {
+		cdLog.WithError(err).Error("error creating AWS client to check region")
+		return false, err
+	}
+	output, err := awsClient.DescribeRegions(&ec2.DescribeRegionsInput{})
+	if err != nil {
+		cdLog.WithError(err).Warn("error listing available AWS regions, assuming the region is available")
+		return true, nil
+	}
+	for _, region := range output.Regions {
+		if aws.StringValue(region.RegionName) == cd.Spec.AWS.Region {
+			return true, nil
 		}
 	}
+	return false, nil
+}
 
-	// Update cluster deployment status if changed:
-	if !reflect.DeepEqual(cd.Status, origCD.Status) {
-		cdLog.Infof("status has changed, updating cluster deployment")
-		cdLog.Debugf("orig: %v", origCD)
-		cdLog.Debugf("new : %v", cd.Status)
+func (r *ReconcileClusterDeployment) setRegionUnavailableCondition(cd *hivev1.ClusterDeployment, available bool, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := regionAvailableReason
+	message := fmt.Sprintf("region %s is enabled for the AWS credentials", cd.Spec.AWS.Region)
+	if !available {
+		status = corev1.ConditionTrue
+		reason = regionUnavailableReason
+		message = fmt.Sprintf("region %s is not enabled for the AWS credentials", cd.Spec.AWS.Region)
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.RegionUnavailableCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Infof("setting RegionUnavailableCondition to %v", status)
 		err := r.Status().Update(context.TODO(), cd)
 		if err != nil {
-			cdLog.Errorf("error updating cluster deployment: %v", err)
-			return err
+			cdLog.WithError(err).Error("cannot update status conditions")
 		}
-	} else {
-		cdLog.Debug("cluster deployment status unchanged")
+		return true, err
 	}
-	return nil
+	return false, nil
 }
 
-func (r *ReconcileClusterDeployment) fixupAdminKubeconfigSecret(secret *corev1.Secret, cdLog log.FieldLogger) error {
-	originalSecret := secret.DeepCopy()
+// syncPostInstallSyncSet keeps the SyncSet holding the post-install manifests of the cluster deployment in step
+// with its spec, deleting it once the cluster deployment no longer has any post-install manifests.
+func (r *ReconcileClusterDeployment) syncPostInstallSyncSet(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	ssLog := cdLog.WithField("syncset", postInstallSyncSetName(cd))
+	existing := &hivev1.SyncSet{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: postInstallSyncSetName(cd), Namespace: cd.Namespace}, existing)
+	switch {
+	case errors.IsNotFound(err):
+		existing = nil
+	case err != nil:
+		ssLog.WithError(err).Error("error looking for post-install syncset")
+		return err
+	}
 
-	rawData, hasRawData := secret.Data[rawAdminKubeconfigKey]
-	if !hasRawData {
-		secret.Data[rawAdminKubeconfigKey] = secret.Data[adminKubeconfigKey]
-		rawData = secret.Data[adminKubeconfigKey]
+	if len(cd.Spec.PostInstallManifests) == 0 {
+		if existing == nil {
+			return nil
+		}
+		ssLog.Info("deleting post-install syncset")
+		if err := r.Delete(context.TODO(), existing); err != nil && !errors.IsNotFound(err) {
+			ssLog.WithError(err).Error("error deleting post-install syncset")
+			return err
+		}
+		return nil
 	}
 
-	var err error
-	secret.Data[adminKubeconfigKey], err = controllerutils.FixupKubeconfig(rawData)
-	if err != nil {
-		cdLog.WithError(err).Errorf("cannot fixup kubeconfig to generate new one")
-		return err
+	spec := hivev1.SyncSetSpec{
+		SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+			Resources: cd.Spec.PostInstallManifests,
+		},
+		ClusterDeploymentRefs: []corev1.LocalObjectReference{
+			{
+				Name: cd.Name,
+			},
+		},
 	}
 
-	if reflect.DeepEqual(originalSecret.Data, secret.Data) {
-		cdLog.Debug("secret data has not changed, no need to update")
+	if existing == nil {
+		syncSet := &hivev1.SyncSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      postInstallSyncSetName(cd),
+				Namespace: cd.Namespace,
+			},
+			Spec: spec,
+		}
+		if err := controllerutil.SetControllerReference(cd, syncSet, r.scheme); err != nil {
+			ssLog.WithError(err).Error("error setting controller reference on syncset")
+			return err
+		}
+		ssLog.Info("creating post-install syncset")
+		if err := r.Create(context.TODO(), syncSet); err != nil {
+			ssLog.WithError(err).Error("error creating post-install syncset")
+			return err
+		}
 		return nil
 	}
 
-	err = r.Update(context.TODO(), secret)
-	if err != nil {
-		cdLog.WithError(err).Error("error updated admin kubeconfig secret")
+	if reflect.DeepEqual(existing.Spec, spec) {
+		return nil
+	}
+	existing.Spec = spec
+	ssLog.Info("updating post-install syncset")
+	if err := r.Update(context.TODO(), existing); err != nil {
+		ssLog.WithError(err).Error("error updating post-install syncset")
 		return err
 	}
-
 	return nil
 }
 
-// setAdminKubeconfigStatus sets all cluster status fields that depend on the admin kubeconfig.
-func (r *ReconcileClusterDeployment) setAdminKubeconfigStatus(cd *hivev1.ClusterDeployment, adminKubeconfigSecret *corev1.Secret, cdLog log.FieldLogger) error {
-	if cd.Status.WebConsoleURL == "" || cd.Status.APIURL == "" {
-		remoteClusterAPIClient, err := r.remoteClusterAPIClientBuilder(string(adminKubeconfigSecret.Data[adminKubeconfigKey]))
-		if err != nil {
-			cdLog.WithError(err).Error("error building remote cluster-api client connection")
-			return err
-		}
-
-		// Parse the admin kubeconfig for the server URL:
-		config, err := clientcmd.Load(adminKubeconfigSecret.Data["kubeconfig"])
-		if err != nil {
-			return err
-		}
-		cluster, ok := config.Clusters[cd.Spec.ClusterName]
+// loadPullSecret loads the pull secret of the cluster deployment in the docker config json format expected by
+// the installer. Legacy dockercfg secrets hold only the auths of a docker config json and are wrapped to match.
+func (r *ReconcileClusterDeployment) loadPullSecret(cd *hivev1.ClusterDeployment) (string, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.PullSecret.Name}, secret)
+	if err != nil {
+		return "", err
+	}
+	if secret.Type == corev1.SecretTypeDockercfg {
+		data, ok := secret.Data[corev1.DockerConfigKey]
 		if !ok {
-			return fmt.Errorf("error parsing admin kubeconfig secret data")
+			return "", fmt.Errorf("secret %s did not contain key %s", secret.Name, corev1.DockerConfigKey)
+		}
+		return fmt.Sprintf(`{"auths":%s}`, data), nil
+	}
+	data, ok := secret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return "", fmt.Errorf("secret %s did not contain key %s", secret.Name, corev1.DockerConfigJsonKey)
+	}
+	return string(data), nil
+}
+
+func (r *ReconcileClusterDeployment) setPullSecretInvalidCondition(cd *hivev1.ClusterDeployment, validationErr error, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := pullSecretValidReason
+	message := "pull secret is valid"
+	if validationErr != nil {
+		status = corev1.ConditionTrue
+		reason = pullSecretInvalidReason
+		message = validationErr.Error()
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.PullSecretInvalidCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Infof("setting PullSecretInvalidCondition to %v", status)
+		err := r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.WithError(err).Error("cannot update status conditions")
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+// useDefaultPullSecret copies the default pull secret from the hive namespace into the namespace of the cluster
+// deployment and points the in-memory spec at the copy, so that the jobs generated for the cluster can use it.
+func (r *ReconcileClusterDeployment) useDefaultPullSecret(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	name, err := r.copyDefaultSecret(cd, r.defaultPullSecret, defaultPullSecretName(cd), corev1.SecretTypeDockerConfigJson, "pull secret", cdLog)
+	if err != nil {
+		return err
+	}
+	cd.Spec.PullSecret = corev1.LocalObjectReference{Name: name}
+	return nil
+}
+
+// useDefaultSSHKey copies the default SSH key secret from the hive namespace into the namespace of the cluster
+// deployment and points the in-memory spec at the copy, so that the jobs generated for the cluster can use it.
+func (r *ReconcileClusterDeployment) useDefaultSSHKey(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	name, err := r.copyDefaultSecret(cd, r.defaultSSHKey, defaultSSHKeyName(cd), corev1.SecretTypeOpaque, "ssh key", cdLog)
+	if err != nil {
+		return err
+	}
+	cd.Spec.SSHKey = &corev1.LocalObjectReference{Name: name}
+	return nil
+}
+
+// copyDefaultSecret copies the named default secret from the hive namespace into a secret owned by the cluster
+// deployment, keeping the copy up to date with the default. It returns the name of the copy.
+func (r *ReconcileClusterDeployment) copyDefaultSecret(cd *hivev1.ClusterDeployment, defaultName, copyName string, secretType corev1.SecretType, description string, cdLog log.FieldLogger) (string, error) {
+	defaultSecret := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: hiveNamespace, Name: defaultName}, defaultSecret); err != nil {
+		cdLog.WithError(err).WithField("secret", defaultName).Errorf("error getting default %s", description)
+		return "", err
+	}
+	secretLog := cdLog.WithField("secret", copyName)
+	secret := &corev1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: copyName}, secret)
+	switch {
+	case errors.IsNotFound(err):
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      copyName,
+				Namespace: cd.Namespace,
+			},
+			Type: secretType,
+			Data: defaultSecret.Data,
+		}
+		if err := controllerutil.SetControllerReference(cd, secret, r.scheme); err != nil {
+			secretLog.WithError(err).Errorf("error setting controller reference on %s", description)
+			return "", err
+		}
+		secretLog.Infof("copying default %s", description)
+		if err := r.Create(context.TODO(), secret); err != nil {
+			secretLog.WithError(err).Errorf("error creating %s", description)
+			return "", err
+		}
+	case err != nil:
+		secretLog.WithError(err).Errorf("error getting %s", description)
+		return "", err
+	case !reflect.DeepEqual(secret.Data, defaultSecret.Data):
+		secret.Data = defaultSecret.Data
+		secretLog.Infof("updating copy of default %s", description)
+		if err := r.Update(context.TODO(), secret); err != nil {
+			secretLog.WithError(err).Errorf("error updating %s", description)
+			return "", err
+		}
+	}
+	return secret.Name, nil
+}
+
+// useDefaultAWSCredentials points the in-memory spec of an AWS cluster deployment which does not name its AWS
+// credentials secret at the default AWS credentials secret of its namespace, if there is one, so that the install
+// job and the managed DNS zone use it.
+func (r *ReconcileClusterDeployment) useDefaultAWSCredentials(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	credentials, err := controllerutils.DefaultAWSCredentialsSecret(r.Client, cd)
+	if err != nil {
+		cdLog.WithError(err).Error("error getting default AWS credentials secret")
+		return err
+	}
+	if credentials != nil {
+		cdLog.WithField("secret", credentials.Name).Debug("using default AWS credentials secret")
+		cd.Spec.PlatformSecrets.AWS = &hivev1.AWSPlatformSecrets{Credentials: *credentials}
+	}
+	return nil
+}
+
+func defaultPullSecretName(cd *hivev1.ClusterDeployment) string {
+	return apihelpers.GetResourceName(cd.Name, "default-pull-secret")
+}
+
+func defaultSSHKeyName(cd *hivev1.ClusterDeployment) string {
+	return apihelpers.GetResourceName(cd.Name, "default-ssh-key")
+}
+
+func postInstallSyncSetName(cd *hivev1.ClusterDeployment) string {
+	return apihelpers.GetResourceName(cd.Name, "post-install")
+}
+
+// deleteInstallJob deletes the install job for the cluster deployment if one exists.
+func (r *ReconcileClusterDeployment) deleteInstallJob(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	job := &batchv1.Job{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: install.GetInstallJobName(cd), Namespace: cd.Namespace}, job)
+	switch {
+	case errors.IsNotFound(err):
+		return nil
+	case err != nil:
+		cdLog.WithError(err).Error("error looking for install job")
+		return err
+	case !job.DeletionTimestamp.IsZero():
+		return nil
+	}
+	cdLog.Info("deleting install job")
+	err = r.Delete(context.TODO(), job, r.jobDeletionPropagationPolicy())
+	if err != nil && !errors.IsNotFound(err) {
+		cdLog.WithError(err).Error("error deleting install job")
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileClusterDeployment) setInstallCancelledCondition(cd *hivev1.ClusterDeployment, cancelled bool, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := installNotCancelledReason
+	message := "install has not been cancelled"
+	if cancelled {
+		status = corev1.ConditionTrue
+		reason = installCancelledReason
+		message = fmt.Sprintf("install cancelled by the %s annotation", cancelInstallAnnotation)
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.InstallCancelledCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Infof("setting InstallCancelledCondition to %v", status)
+		err := r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.WithError(err).Error("cannot update status conditions")
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+// invalidIngressDomains returns the ingress domains for the cluster deployment that are neither the base
+// domain nor a subdomain of it. Nothing is reported when the cluster deployment has no base domain.
+func invalidIngressDomains(cd *hivev1.ClusterDeployment) []string {
+	invalid := []string{}
+	baseDomain := strings.ToLower(strings.TrimSuffix(cd.Spec.BaseDomain, "."))
+	if baseDomain == "" {
+		return invalid
+	}
+	for _, ingress := range cd.Spec.Ingress {
+		domain := strings.ToLower(strings.TrimSuffix(ingress.Domain, "."))
+		if domain != baseDomain && !strings.HasSuffix(domain, "."+baseDomain) {
+			invalid = append(invalid, ingress.Domain)
+		}
+	}
+	return invalid
+}
+
+func (r *ReconcileClusterDeployment) setIngressDomainInvalidCondition(cd *hivev1.ClusterDeployment, invalidDomains []string, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := ingressDomainValidReason
+	message := "ingress domains are within the base domain"
+	if len(invalidDomains) > 0 {
+		status = corev1.ConditionTrue
+		reason = ingressDomainInvalidReason
+		message = fmt.Sprintf("ingress domains %s are not within base domain %s", strings.Join(invalidDomains, ", "), cd.Spec.BaseDomain)
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.IngressDomainInvalidCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Infof("setting IngressDomainInvalidCondition to %v", status)
+		err := r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.WithError(err).Error("cannot update status conditions")
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+func (r *ReconcileClusterDeployment) setBootstrapIgnitionOverrideInvalidCondition(cd *hivev1.ClusterDeployment, validationErr error, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := bootstrapIgnitionValidReason
+	message := "bootstrap ignition override is valid"
+	if validationErr != nil {
+		status = corev1.ConditionTrue
+		reason = bootstrapIgnitionInvalidReason
+		message = validationErr.Error()
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.BootstrapIgnitionOverrideInvalidCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Infof("setting BootstrapIgnitionOverrideInvalidCondition to %v", status)
+		err := r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.WithError(err).Error("cannot update status conditions")
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+func (r *ReconcileClusterDeployment) setManifestsInvalidCondition(cd *hivev1.ClusterDeployment, validationErr error, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	status := corev1.ConditionFalse
+	reason := manifestsValidReason
+	message := "custom manifests are valid"
+	if validationErr != nil {
+		status = corev1.ConditionTrue
+		reason = manifestsInvalidReason
+		message = validationErr.Error()
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.ManifestsInvalidCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Infof("setting ManifestsInvalidCondition to %v", status)
+		err := r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.WithError(err).Error("cannot update status conditions")
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+// Deletes the job if it exists and its generation does not match the cluster deployment's
+// genetation. Updates the config map, or the install config secret when one is used, if it is outdated too
+func (r *ReconcileClusterDeployment) updateOutdatedConfigurations(cdGeneration int64, existingJob *batchv1.Job, cfgMap *corev1.ConfigMap, cfgSecret *corev1.Secret, cdLog log.FieldLogger) (bool, error) {
+	var err error
+	var didGenerationChange bool
+	if jobGeneration, ok := existingJob.Annotations[clusterDeploymentGenerationAnnotation]; ok {
+		convertedJobGeneration, _ := strconv.ParseInt(jobGeneration, 10, 64)
+		if convertedJobGeneration < cdGeneration {
+			didGenerationChange = true
+			cdLog.Info("deleting outdated install job due to cluster deployment generation change")
+			err = r.Delete(context.TODO(), existingJob, r.jobDeletionPropagationPolicy())
+			if err != nil {
+				cdLog.WithError(err).Errorf("error deleting outdated install job")
+				return didGenerationChange, err
+			}
+		}
+	}
+	if cfgSecret != nil {
+		secretChanged, err := r.updateOutdatedInstallConfigSecret(cdGeneration, cfgSecret, cdLog)
+		return didGenerationChange || secretChanged, err
+	}
+	// The generated config map always carries the current generation, so compare against the stored one
+	// and update that, keeping its resource version.
+	existingCfgMap := &corev1.ConfigMap{}
+	err = r.Get(context.TODO(), types.NamespacedName{Name: cfgMap.Name, Namespace: cfgMap.Namespace}, existingCfgMap)
+	if errors.IsNotFound(err) {
+		return didGenerationChange, nil
+	} else if err != nil {
+		cdLog.WithError(err).Error("error getting installconfig configmap")
+		return didGenerationChange, err
+	}
+	if cfgMapGeneration, ok := existingCfgMap.Annotations[clusterDeploymentGenerationAnnotation]; ok {
+		convertedMapGeneration, _ := strconv.ParseInt(cfgMapGeneration, 10, 64)
+		if convertedMapGeneration < cdGeneration {
+			didGenerationChange = true
+			cdLog.Info("updating outdated installconfig configmap due to cluster deployment generation change")
+			existingCfgMap.Annotations[clusterDeploymentGenerationAnnotation] = strconv.FormatInt(cdGeneration, 10)
+			existingCfgMap.Data = cfgMap.Data
+			err = r.Update(context.TODO(), existingCfgMap)
+			if err != nil {
+				cdLog.WithError(err).Errorf("error updating outdated config map")
+				return didGenerationChange, err
+			}
+		}
+	}
+	return didGenerationChange, nil
+}
+
+// updateOutdatedInstallConfigSecret updates the stored install config secret if it was generated from an
+// older generation of the cluster deployment.
+func (r *ReconcileClusterDeployment) updateOutdatedInstallConfigSecret(cdGeneration int64, cfgSecret *corev1.Secret, cdLog log.FieldLogger) (bool, error) {
+	existingCfgSecret := &corev1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: cfgSecret.Name, Namespace: cfgSecret.Namespace}, existingCfgSecret)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		cdLog.WithError(err).Error("error getting install config secret")
+		return false, err
+	}
+	secretGeneration, ok := existingCfgSecret.Annotations[clusterDeploymentGenerationAnnotation]
+	if !ok {
+		return false, nil
+	}
+	convertedSecretGeneration, _ := strconv.ParseInt(secretGeneration, 10, 64)
+	if convertedSecretGeneration >= cdGeneration {
+		return false, nil
+	}
+	cdLog.Info("updating outdated install config secret due to cluster deployment generation change")
+	existingCfgSecret.Annotations[clusterDeploymentGenerationAnnotation] = strconv.FormatInt(cdGeneration, 10)
+	existingCfgSecret.Data = cfgSecret.Data
+	if err := r.Update(context.TODO(), existingCfgSecret); err != nil {
+		cdLog.WithError(err).Error("error updating outdated install config secret")
+		return true, err
+	}
+	return true, nil
+}
+
+// setInstallMetadataStatus copies a subset of the install metadata from the metadata ConfigMap uploaded by the
+// install manager into the status. Nothing is changed until the ConfigMap exists.
+func (r *ReconcileClusterDeployment) setInstallMetadataStatus(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	cfgMap := &corev1.ConfigMap{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: fmt.Sprintf("%s-metadata", cd.Name)}, cfgMap)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		cdLog.WithError(err).Error("error getting metadata configmap")
+		return err
+	}
+	metadata, err := parseInstallMetadata(cfgMap.Data["metadata.json"])
+	if err != nil {
+		cdLog.WithError(err).WithField("configMap", cfgMap.Name).Warn("unable to parse install metadata")
+		return nil
+	}
+	cd.Status.InstallMetadata = metadata
+	return nil
+}
+
+// parseInstallMetadata extracts the subset of the installer metadata kept in the status from metadata.json.
+func parseInstallMetadata(metadataJSON string) (*hivev1.InstallMetadata, error) {
+	md := &installertypes.ClusterMetadata{}
+	if err := json.Unmarshal([]byte(metadataJSON), md); err != nil {
+		return nil, err
+	}
+	metadata := &hivev1.InstallMetadata{
+		InfraID:   md.InfraID,
+		ClusterID: md.ClusterID,
+	}
+	if md.AWS != nil {
+		metadata.Region = md.AWS.Region
+	}
+	return metadata, nil
+}
+
+func (r *ReconcileClusterDeployment) updateClusterDeploymentStatus(cd *hivev1.ClusterDeployment, origCD *hivev1.ClusterDeployment, job *batchv1.Job, cdLog log.FieldLogger) error {
+	cdLog.Debug("updating cluster deployment status")
+	if job != nil && job.Name != "" && job.Namespace != "" {
+		// Job exists, check it's status:
+		cd.Status.Installed = controllerutils.IsSuccessful(job)
+	}
+	if cd.Status.Installed && cd.Status.InstalledTimestamp == nil {
+		// Clusters installed before the completion of installs was recorded start counting from now.
+		installed := metav1.Now()
+		if job != nil && job.Status.CompletionTime != nil {
+			installed = *job.Status.CompletionTime
+		}
+		cd.Status.InstalledTimestamp = &installed
+	}
+	if cd.Status.Installed {
+		cd.Status.InstallPhase = ""
+	}
+	cd.Status.InstallDuration = installDuration(cd, time.Now())
+	setProvisionCompletedCondition(cd, job)
+
+	// The install manager sets this secret name, but we don't consider it a critical failure and
+	// will attempt to heal it here, as the value is predictable.
+	if cd.Status.Installed && cd.Status.AdminKubeconfigSecret.Name == "" {
+		cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: apihelpers.GetResourceName(cd.Name, "admin-kubeconfig")}
+	}
+
+	if cd.Status.AdminKubeconfigSecret.Name != "" {
+		adminKubeconfigSecret := &corev1.Secret{}
+		err := r.Get(context.Background(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Status.AdminKubeconfigSecret.Name}, adminKubeconfigSecret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				log.Warn("admin kubeconfig does not yet exist")
+			} else {
+				return err
+			}
+		} else if !hasAdminKubeconfigData(adminKubeconfigSecret) {
+			cdLog.WithField("secret", adminKubeconfigSecret.Name).Warn("admin kubeconfig secret has no kubeconfig data")
+			cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+				cd.Status.Conditions,
+				hivev1.AdminKubeconfigInvalidCondition,
+				corev1.ConditionTrue,
+				adminKubeconfigInvalidReason,
+				fmt.Sprintf("secret %s contains neither a %s nor a %s key", adminKubeconfigSecret.Name, adminKubeconfigKey, rawAdminKubeconfigKey),
+				controllerutils.UpdateConditionIfReasonOrMessageChange)
+		} else {
+			cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+				cd.Status.Conditions,
+				hivev1.AdminKubeconfigInvalidCondition,
+				corev1.ConditionFalse,
+				adminKubeconfigValidReason,
+				"admin kubeconfig secret contains kubeconfig data",
+				controllerutils.UpdateConditionIfReasonOrMessageChange)
+			err = r.fixupAdminKubeconfigSecret(adminKubeconfigSecret, cdLog)
+			if err != nil {
+				return err
+			}
+			err = r.setAdminKubeconfigStatus(cd, adminKubeconfigSecret, cdLog)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.reportInstallMetadata {
+		if err := r.setInstallMetadataStatus(cd, cdLog); err != nil {
+			return err
+		}
+	}
+
+	// Update cluster deployment status if changed:
+	if !reflect.DeepEqual(cd.Status, origCD.Status) {
+		cdLog.Infof("status has changed, updating cluster deployment")
+		cdLog.Debugf("orig: %s", sanitizeForLog(origCD))
+		cdLog.Debugf("new : %s", sanitizeForLog(cd.Status))
+		err := r.Status().Update(context.TODO(), cd)
+		if err != nil {
+			cdLog.Errorf("error updating cluster deployment: %v", err)
+			return err
+		}
+	} else {
+		cdLog.Debug("cluster deployment status unchanged")
+	}
+	return nil
+}
+
+// installDuration returns the human readable duration of the install of the cluster deployment. Running
+// installs are rounded down to the minute so that the status only changes once a minute while they run.
+func installDuration(cd *hivev1.ClusterDeployment, now time.Time) string {
+	if cd.Status.InstallStartedTimestamp == nil || cd.Status.InstallStartedTimestamp.IsZero() {
+		return ""
+	}
+	started := cd.Status.InstallStartedTimestamp.Time
+	if cd.Status.InstalledTimestamp != nil {
+		return cd.Status.InstalledTimestamp.Sub(started).Round(time.Second).String()
+	}
+	return now.Sub(started).Truncate(time.Minute).String()
+}
+
+// setProvisionCompletedCondition maintains the ProvisionCompleted condition from the installed status of the
+// cluster deployment and the state of its install job. The condition is removed while an install is in progress.
+func setProvisionCompletedCondition(cd *hivev1.ClusterDeployment, job *batchv1.Job) {
+	if cd.Status.Installed {
+		cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+			cd.Status.Conditions,
+			hivev1.ProvisionCompletedCondition,
+			corev1.ConditionTrue,
+			provisionSucceededReason,
+			"cluster has been installed",
+			controllerutils.UpdateConditionIfReasonOrMessageChange)
+		return
+	}
+
+	var failedCondition *batchv1.JobCondition
+	if job != nil {
+		for i, cond := range job.Status.Conditions {
+			if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+				failedCondition = &job.Status.Conditions[i]
+				break
+			}
+		}
+	}
+	if failedCondition == nil {
+		conditions := []hivev1.ClusterDeploymentCondition{}
+		for _, cond := range cd.Status.Conditions {
+			if cond.Type != hivev1.ProvisionCompletedCondition {
+				conditions = append(conditions, cond)
+			}
+		}
+		if len(conditions) != len(cd.Status.Conditions) {
+			cd.Status.Conditions = conditions
+		}
+		return
+	}
+
+	var reason string
+	switch failedCondition.Reason {
+	case "BackoffLimitExceeded":
+		reason = provisionAttemptsExhaustedReason
+	case "DeadlineExceeded":
+		reason = provisionDeadlineExceededReason
+	default:
+		reason = provisionFailedReason
+	}
+	message := failedCondition.Message
+	if message == "" {
+		message = "install job has failed"
+	}
+	setProvisionFailedCondition(cd, reason, message)
+}
+
+// setProvisionFailedCondition sets the ProvisionCompleted condition to false with the given reason.
+func setProvisionFailedCondition(cd *hivev1.ClusterDeployment, reason, message string) {
+	// SetClusterDeploymentCondition only adds new conditions when they are true, but a terminal failure
+	// must be reported even if the condition was not previously present.
+	if controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ProvisionCompletedCondition) == nil {
+		now := metav1.Now()
+		cd.Status.Conditions = append(cd.Status.Conditions, hivev1.ClusterDeploymentCondition{
+			Type:               hivev1.ProvisionCompletedCondition,
+			Status:             corev1.ConditionFalse,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: now,
+			LastProbeTime:      now,
+		})
+		return
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.ProvisionCompletedCondition,
+		corev1.ConditionFalse,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+}
+
+// failTimedOutInstall stops the install of a cluster which has exceeded the maximum install lifetime. The install
+// job is deleted and the ProvisionCompleted condition is set to false.
+func (r *ReconcileClusterDeployment) failTimedOutInstall(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	cdLog.WithFields(log.Fields{
+		"installStarted":     cd.Status.InstallStartedTimestamp.Time,
+		"maxInstallLifetime": r.maxInstallLifetime,
+	}).Warn("install has exceeded the maximum install lifetime")
+	if err := r.deleteInstallJob(cd, cdLog); err != nil {
+		return err
+	}
+	original := cd.DeepCopy()
+	setProvisionFailedCondition(cd, provisionTimedOutReason,
+		fmt.Sprintf("install did not complete within the maximum install lifetime of %v", r.maxInstallLifetime))
+	if reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		return nil
+	}
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Error("cannot update status conditions")
+		return err
+	}
+	return nil
+}
+
+// captureFailedInstallLog saves the tail of the log of the most recent of the given install pods into a
+// ConfigMap owned by the cluster deployment. The log is only captured once per cluster deployment.
+func (r *ReconcileClusterDeployment) captureFailedInstallLog(cd *hivev1.ClusterDeployment, podList *corev1.PodList, cdLog log.FieldLogger) error {
+	cmName := install.GetInstallLogConfigMapName(cd)
+	existing := &corev1.ConfigMap{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cmName}, existing)
+	if err == nil {
+		cdLog.Debug("install log already captured")
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	var pod *corev1.Pod
+	for i := range podList.Items {
+		if pod == nil || pod.CreationTimestamp.Before(&podList.Items[i].CreationTimestamp) {
+			pod = &podList.Items[i]
+		}
+	}
+	if pod == nil {
+		cdLog.Debug("no install pods found, unable to capture install log")
+		return nil
+	}
+
+	installLog, err := r.installPodLogReader(pod.Namespace, pod.Name, r.failedInstallLogBytes)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cmName,
+			Namespace: cd.Namespace,
+		},
+		Data: map[string]string{
+			installLogConfigMapKey: string(installLog),
+		},
+	}
+	if err := controllerutil.SetControllerReference(cd, cm, r.scheme); err != nil {
+		return err
+	}
+	cdLog.WithField("pod", pod.Name).Info("capturing install log for failed install")
+	return r.Create(context.TODO(), cm)
+}
+
+// hasAdminKubeconfigData returns true if the admin kubeconfig secret holds a kubeconfig under at least one of
+// the kubeconfig or raw-kubeconfig keys.
+func hasAdminKubeconfigData(secret *corev1.Secret) bool {
+	return len(secret.Data[adminKubeconfigKey]) > 0 || len(secret.Data[rawAdminKubeconfigKey]) > 0
+}
+
+func (r *ReconcileClusterDeployment) fixupAdminKubeconfigSecret(secret *corev1.Secret, cdLog log.FieldLogger) error {
+	err := r.updateWithRetry(secret, func() error {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		rawData, hasRawData := secret.Data[rawAdminKubeconfigKey]
+		if !hasRawData {
+			secret.Data[rawAdminKubeconfigKey] = secret.Data[adminKubeconfigKey]
+			rawData = secret.Data[adminKubeconfigKey]
+		}
+
+		var err error
+		secret.Data[adminKubeconfigKey], err = controllerutils.FixupKubeconfig(rawData)
+		if err != nil {
+			cdLog.WithError(err).Errorf("cannot fixup kubeconfig to generate new one")
+		}
+		return err
+	})
+	if err != nil {
+		cdLog.WithError(err).Error("error updated admin kubeconfig secret")
+	}
+	return err
+}
+
+// setAdminKubeconfigStatus sets all cluster status fields that depend on the admin kubeconfig.
+func (r *ReconcileClusterDeployment) setAdminKubeconfigStatus(cd *hivev1.ClusterDeployment, adminKubeconfigSecret *corev1.Secret, cdLog log.FieldLogger) error {
+	if cd.Status.WebConsoleURL == "" || cd.Status.APIURL == "" {
+		// Parse the admin kubeconfig for the server URL:
+		config, err := clientcmd.Load(adminKubeconfigSecret.Data["kubeconfig"])
+		if err != nil {
+			return err
+		}
+		cluster, err := adminKubeconfigCluster(config, cd.Spec.ClusterName, cdLog)
+		if err != nil {
+			return err
 		}
 
-		// We should be able to assume only one cluster in here:
 		server := cluster.Server
 		cdLog.Debugf("found cluster API URL in kubeconfig: %s", server)
 		cd.Status.APIURL = server
+
+		remoteClusterAPIClient, err := r.remoteClusterAPIClientBuilder(string(adminKubeconfigSecret.Data[adminKubeconfigKey]))
+		if err != nil && isPrivateCluster(cd) {
+			// The API of a private cluster may not be reachable from the hive cluster. The API URL is known from
+			// the kubeconfig regardless, so carry on without the web console URL.
+			cdLog.WithError(err).Warn("unable to reach the API of private cluster, web console URL is unknown")
+			return nil
+		}
+		if err != nil {
+			cdLog.WithError(err).Error("error building remote cluster-api client connection")
+			return err
+		}
 		routeObject := &routev1.Route{}
-		err = remoteClusterAPIClient.Get(context.Background(),
-			types.NamespacedName{Namespace: "openshift-console", Name: "console"}, routeObject)
+		err = remoteClusterAPIClient.Get(context.Background(), consoleRouteName(cd), routeObject)
+		if errors.IsNotFound(err) {
+			// The console operator may not have created the route yet on a freshly installed cluster.
+			cdLog.Info("remote console route does not exist yet")
+			setConsoleRouteNotFoundCondition(cd, consoleRouteName(cd), time.Now())
+			return nil
+		}
+		if err != nil && isPrivateCluster(cd) {
+			cdLog.WithError(err).Warn("unable to reach the API of private cluster, web console URL is unknown")
+			return nil
+		}
 		if err != nil {
 			cdLog.WithError(err).Error("error fetching remote route object")
 			return err
 		}
-		cdLog.Debugf("read remote route object: %s", routeObject)
-		cd.Status.WebConsoleURL = "https://" + routeObject.Spec.Host
+		cdLog.Debugf("read remote route object: %s", sanitizeForLog(routeObject))
+		webConsoleURL, err := routeURL(routeObject)
+		if err != nil {
+			cdLog.WithError(err).WithField("host", routeObject.Spec.Host).Warn("unable to build web console URL from remote route")
+			return nil
+		}
+		cd.Status.WebConsoleURL = webConsoleURL
+		cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+			cd.Status.Conditions,
+			hivev1.ConsoleRouteNotFoundCondition,
+			corev1.ConditionFalse,
+			consoleRouteFoundReason,
+			"console route exists on the remote cluster",
+			controllerutils.UpdateConditionIfReasonOrMessageChange)
 	}
 	return nil
 }
 
+// setConsoleRouteNotFoundCondition sets the ConsoleRouteNotFound condition for the given route, recording when
+// the route has not been found for so long that it is no longer checked for.
+func setConsoleRouteNotFoundCondition(cd *hivev1.ClusterDeployment, route types.NamespacedName, now time.Time) {
+	reason := consoleRouteNotFoundReason
+	message := fmt.Sprintf("console route %s does not exist on the remote cluster", route)
+	condition := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ConsoleRouteNotFoundCondition)
+	if condition != nil && condition.Status == corev1.ConditionTrue && now.Sub(condition.LastTransitionTime.Time) >= consoleRouteWaitTimeout {
+		reason = consoleRouteCheckStoppedReason
+		message = fmt.Sprintf("console route %s was not created on the remote cluster within %v, no longer checking for it", route, consoleRouteWaitTimeout)
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.ConsoleRouteNotFoundCondition,
+		corev1.ConditionTrue,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+}
+
+// isWaitingForConsoleRoute returns true when the console route of the installed cluster was not found on the
+// remote cluster, and it has not been missing long enough to stop checking for it.
+func isWaitingForConsoleRoute(cd *hivev1.ClusterDeployment) bool {
+	if !cd.Status.Installed || cd.Status.WebConsoleURL != "" {
+		return false
+	}
+	condition := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ConsoleRouteNotFoundCondition)
+	return condition != nil && condition.Status == corev1.ConditionTrue && condition.Reason == consoleRouteNotFoundReason
+}
+
+// adminKubeconfigCluster returns the cluster named clusterName in the admin kubeconfig. The cluster name may have
+// been edited after install, so when no cluster has that name but the kubeconfig holds exactly one cluster, that
+// cluster is used instead.
+func adminKubeconfigCluster(config *clientcmdapi.Config, clusterName string, cdLog log.FieldLogger) (*clientcmdapi.Cluster, error) {
+	if cluster, ok := config.Clusters[clusterName]; ok {
+		return cluster, nil
+	}
+	if len(config.Clusters) == 1 {
+		for name, cluster := range config.Clusters {
+			cdLog.WithField("kubeconfigCluster", name).Warnf("admin kubeconfig has no cluster named %q, using its only cluster", clusterName)
+			return cluster, nil
+		}
+	}
+	return nil, fmt.Errorf("admin kubeconfig has no cluster named %q and contains %d clusters", clusterName, len(config.Clusters))
+}
+
+// consoleRouteName returns the namespace and name of the web console route in the cluster.
+func consoleRouteName(cd *hivev1.ClusterDeployment) types.NamespacedName {
+	if route := cd.Spec.ConsoleRoute; route != nil && route.Namespace != "" && route.Name != "" {
+		return types.NamespacedName{Namespace: route.Namespace, Name: route.Name}
+	}
+	return types.NamespacedName{Namespace: defaultConsoleRouteNamespace, Name: defaultConsoleRouteName}
+}
+
+// sanitizeForLog returns the JSON representation of obj with the values of sensitive fields redacted, so that
+// it can be written to debug logs without leaking secret material.
+func sanitizeForLog(obj interface{}) string {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Sprintf("<unable to sanitize %T: %v>", obj, err)
+	}
+	var fields interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Sprintf("<unable to sanitize %T: %v>", obj, err)
+	}
+	data, err = json.Marshal(redactSensitiveFields(fields, nil))
+	if err != nil {
+		return fmt.Sprintf("<unable to sanitize %T: %v>", obj, err)
+	}
+	return string(data)
+}
+
+// redactSensitiveFields redacts the sensitive fields of value, which is found at path in the object being logged.
+// The items of a list share the path of the list.
+func redactSensitiveFields(value interface{}, path []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			fieldPath := append(path[:len(path):len(path)], key)
+			if isSensitiveLogField(fieldPath) {
+				v[key] = redactedLogValue
+				continue
+			}
+			v[key] = redactSensitiveFields(field, fieldPath)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactSensitiveFields(v[i], path)
+		}
+	}
+	return value
+}
+
+func isSensitiveLogField(path []string) bool {
+	for _, sensitivePath := range sensitiveLogFields {
+		if reflect.DeepEqual(path, sensitivePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeURL returns the URL a route is served at. The host of the route may itself include a port, a path or
+// a scheme. Routes without TLS configuration are served over plain http.
+func routeURL(route *routev1.Route) (string, error) {
+	host := strings.TrimSpace(route.Spec.Host)
+	if host == "" {
+		return "", fmt.Errorf("route has no host")
+	}
+	if !strings.Contains(host, "://") {
+		scheme := "https"
+		if route.Spec.TLS == nil {
+			scheme = "http"
+		}
+		host = scheme + "://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("route host %q has no host name", route.Spec.Host)
+	}
+	if (u.Path == "" || u.Path == "/") && route.Spec.Path != "" {
+		u.Path = route.Spec.Path
+	}
+	return u.String(), nil
+}
+
 // ensureManagedDNSZoneDeleted is a safety check to ensure that the child managed DNSZone
 // linked to the parent cluster deployment gets a deletionTimestamp when the parent is deleted.
 // Normally we expect Kube garbage collection to do this for us, but in rare cases we've seen it
@@ -864,8 +2827,12 @@ func (r *ReconcileClusterDeployment) ensureManagedDNSZoneDeleted(cd *hivev1.Clus
 	if !cd.Spec.ManageDNS {
 		return nil, nil
 	}
+	if cd.Spec.ManagedDNSZoneRef != nil {
+		cdLog.Debug("dnszone is managed externally, leaving it in place")
+		return nil, nil
+	}
 	dnsZone := &hivev1.DNSZone{}
-	dnsZoneNamespacedName := types.NamespacedName{Namespace: cd.Namespace, Name: dnsZoneName(cd.Name)}
+	dnsZoneNamespacedName := types.NamespacedName{Namespace: cd.Namespace, Name: dnsZoneName(cd)}
 	err := r.Get(context.TODO(), dnsZoneNamespacedName, dnsZone)
 	if err != nil && !errors.IsNotFound(err) {
 		cdLog.WithError(err).Error("error looking up managed dnszone")
@@ -875,6 +2842,10 @@ func (r *ReconcileClusterDeployment) ensureManagedDNSZoneDeleted(cd *hivev1.Clus
 		cdLog.Debug("managed zone does not exist, nothing to cleanup")
 		return nil, nil
 	}
+	if !metav1.IsControlledBy(dnsZone, cd) {
+		cdLog.WithField("zone", dnsZoneNamespacedName.String()).Warn("managed dnszone is not controlled by the cluster deployment, leaving it in place")
+		return nil, nil
+	}
 	if err == nil && !dnsZone.DeletionTimestamp.IsZero() {
 		cdLog.Debug("managed zone is being deleted, will wait for its deletion to complete")
 		return &reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
@@ -882,16 +2853,44 @@ func (r *ReconcileClusterDeployment) ensureManagedDNSZoneDeleted(cd *hivev1.Clus
 	cdLog.Warn("managed dnszone did not get a deletionTimestamp when parent cluster deployment was deleted, deleting manually")
 	err = r.Delete(context.TODO(), dnsZone,
 		client.PropagationPolicy(metav1.DeletePropagationForeground))
-	if err != nil {
+	if err != nil && !errors.IsNotFound(err) {
 		cdLog.WithError(err).Error("error deleting managed dnszone")
+		return &reconcile.Result{}, err
 	}
-	return &reconcile.Result{}, err
+	return &reconcile.Result{}, nil
 }
 
 func (r *ReconcileClusterDeployment) syncDeletedClusterDeployment(cd *hivev1.ClusterDeployment, hiveImage string, cdLog log.FieldLogger) (reconcile.Result, error) {
 
+	// If the namespace is being deleted, nothing can be created in it to deprovision the cluster. Give up on
+	// deprovision and remove the finalizer so that the namespace deletion is not blocked.
+	terminating, err := r.isNamespaceTerminating(cd.Namespace)
+	if err != nil {
+		cdLog.WithError(err).Error("error checking for deletionTimestamp on namespace")
+		return reconcile.Result{}, err
+	}
+	if terminating {
+		if !controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision) {
+			return reconcile.Result{}, nil
+		}
+		cdLog.Warn("detected a terminating namespace, giving up on deprovision and removing finalizer")
+		err := r.setDeprovisionSkippedCondition(cd, namespaceTerminatingReason,
+			"namespace is being deleted, skipping deprovision of the cluster", cdLog)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := r.removeClusterDeploymentFinalizer(cd); err != nil {
+			cdLog.WithError(err).Error("error removing finalizer")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
 	result, err := r.ensureManagedDNSZoneDeleted(cd, cdLog)
 	if result != nil {
+		if err == nil {
+			err = r.setDeprovisionProgress(cd, hivev1.DeprovisionProgressDeletingDNSZone, cdLog)
+		}
 		return *result, err
 	}
 	if err != nil {
@@ -913,16 +2912,20 @@ func (r *ReconcileClusterDeployment) syncDeletedClusterDeployment(cd *hivev1.Clu
 		return reconcile.Result{}, err
 	} else if err == nil && !installJob.DeletionTimestamp.IsZero() {
 		cdLog.Debug("install job is being deleted, requeueing to wait for deletion")
-		return reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
+		err = r.setDeprovisionProgress(cd, hivev1.DeprovisionProgressDeletingInstallJob, cdLog)
+		return reconcile.Result{RequeueAfter: defaultRequeueTime}, err
 	} else {
-		err = r.Delete(context.Background(), installJob,
-			client.PropagationPolicy(metav1.DeletePropagationForeground))
-		if err != nil {
+		// Always delete in the foreground here, regardless of the configured propagation policy, so that the
+		// job is only gone once its pod has stopped. Deprovisioning while the installer is still running could
+		// leak the cloud resources it creates after the deprovision has looked for them.
+		err = r.Delete(context.Background(), installJob, client.PropagationPolicy(metav1.DeletePropagationForeground))
+		if err != nil && !errors.IsNotFound(err) {
 			cdLog.WithError(err).Errorf("error deleting existing install job for deleted cluster deployment")
 			return reconcile.Result{}, err
 		}
 		cdLog.WithField("jobName", installJob.Name).Info("install job deleted")
-		return reconcile.Result{}, nil
+		err = r.setDeprovisionProgress(cd, hivev1.DeprovisionProgressDeletingInstallJob, cdLog)
+		return reconcile.Result{}, err
 	}
 
 	// Skips creation of deprovision request if PreserveOnDelete is true and cluster is installed
@@ -930,11 +2933,12 @@ func (r *ReconcileClusterDeployment) syncDeletedClusterDeployment(cd *hivev1.Clu
 		if cd.Status.Installed {
 			cdLog.Warn("skipping creation of deprovisioning request for installed cluster due to PreserveOnDelete=true")
 			if controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision) {
-				err = r.removeClusterDeploymentFinalizer(cd)
+				err := r.setDeprovisionSkippedCondition(cd, preserveOnDeleteReason,
+					"PreserveOnDelete is set for the installed cluster, skipping deprovision of the cluster", cdLog)
 				if err != nil {
-					cdLog.WithError(err).Error("error removing finalizer")
+					return reconcile.Result{}, err
 				}
-				return reconcile.Result{}, err
+				return reconcile.Result{}, r.completeDeprovision(cd, cdLog)
 			}
 			return reconcile.Result{}, nil
 		}
@@ -943,80 +2947,323 @@ func (r *ReconcileClusterDeployment) syncDeletedClusterDeployment(cd *hivev1.Clu
 		cdLog.Infof("PreserveOnDelete=true but creating deprovisioning request as cluster was never successfully provisioned")
 	}
 
-	if cd.Status.InfraID == "" {
-		cdLog.Warn("skipping uninstall for cluster that never had clusterID set")
-		err = r.removeClusterDeploymentFinalizer(cd)
-		if err != nil {
-			cdLog.WithError(err).Error("error removing finalizer")
+	if cd.Status.InfraID == "" {
+		cdLog.Warn("skipping uninstall for cluster that never had clusterID set")
+		err := r.setDeprovisionSkippedCondition(cd, noInfraIDReason,
+			"the cluster has no infra ID, so there are no cloud resources to deprovision", cdLog)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.completeDeprovision(cd, cdLog)
+	}
+
+	if !isDeprovisionSupported(cd) {
+		platform := getPlatformName(cd)
+		cdLog.WithField("platform", platform).Warn("skipping uninstall for cluster on a platform that cannot be deprovisioned")
+		err := r.setDeprovisionSkippedCondition(cd, unsupportedPlatformReason,
+			fmt.Sprintf("deprovisioning is not supported for platform %q, skipping deprovision of the cluster", platform), cdLog)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.completeDeprovision(cd, cdLog)
+	}
+
+	// Generate a deprovision request
+	request, err := generateDeprovisionRequest(r.Client, cd)
+	if err != nil {
+		cdLog.WithError(err).Error("error generating deprovision request")
+		return reconcile.Result{}, err
+	}
+	err = controllerutil.SetControllerReference(cd, request, r.scheme)
+	if err != nil {
+		cdLog.Errorf("error setting controller reference on deprovision request: %v", err)
+		return reconcile.Result{}, err
+	}
+
+	// Check if deprovision request already exists:
+	existingRequest := &hivev1.ClusterDeprovisionRequest{}
+	err = r.Get(context.TODO(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, existingRequest)
+	if err != nil && errors.IsNotFound(err) {
+		if throttled, err := r.deprovisionThrottled(cdLog); throttled || err != nil {
+			return reconcile.Result{RequeueAfter: defaultRequeueTime}, err
+		}
+		cdLog.Infof("creating deprovision request for cluster deployment")
+		err = r.Create(context.TODO(), request)
+		if errors.IsAlreadyExists(err) {
+			// The cache can lag behind a request created before a restart. It will be picked up once the
+			// cache catches up.
+			cdLog.Info("deprovision request already exists")
+			return reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
+		}
+		if err != nil {
+			// A namespace which started terminating since the check above is picked up on the next reconcile.
+			cdLog.WithError(err).Errorf("error creating deprovision request")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.setDeprovisionProgress(cd, hivev1.DeprovisionProgressDeprovisioning, cdLog)
+	} else if err != nil {
+		cdLog.WithError(err).Errorf("error getting deprovision request")
+		return reconcile.Result{}, err
+	}
+
+	if !existingRequest.DeletionTimestamp.IsZero() {
+		cdLog.Debug("deprovision request is being deleted, requeueing to wait for deletion")
+		return reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
+	}
+
+	// Deprovision request exists, check whether it has completed
+	if existingRequest.Status.Completed {
+		cdLog.Infof("deprovision request completed, removing finalizer")
+		r.reportDeletedResources(cd, existingRequest, cdLog)
+		return reconcile.Result{}, r.completeDeprovision(cd, cdLog)
+	}
+
+	if existingRequest.Status.Failed {
+		return r.retryFailedDeprovision(cd, existingRequest, cdLog)
+	}
+
+	cdLog.Debug("deprovision request not yet completed")
+
+	return reconcile.Result{}, r.setDeprovisionProgress(cd, hivev1.DeprovisionProgressDeprovisioning, cdLog)
+}
+
+// isNamespaceTerminating returns whether the given namespace is being deleted.
+func (r *ReconcileClusterDeployment) isNamespaceTerminating(namespace string) (bool, error) {
+	ns := &corev1.Namespace{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return ns.DeletionTimestamp != nil, nil
+}
+
+// reportDeletedResources logs the counts of the cloud resources removed by a completed deprovision request,
+// and records them in an event on the cluster deployment.
+func (r *ReconcileClusterDeployment) reportDeletedResources(cd *hivev1.ClusterDeployment, request *hivev1.ClusterDeprovisionRequest, cdLog log.FieldLogger) {
+	if len(request.Status.DeletedResources) == 0 {
+		return
+	}
+	resourceTypes := make([]string, 0, len(request.Status.DeletedResources))
+	for resourceType := range request.Status.DeletedResources {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+	total := 0
+	counts := make([]string, 0, len(resourceTypes))
+	fields := log.Fields{}
+	for _, resourceType := range resourceTypes {
+		count := request.Status.DeletedResources[resourceType]
+		total += count
+		counts = append(counts, fmt.Sprintf("%s=%d", resourceType, count))
+		fields[resourceType] = count
+	}
+	cdLog.WithFields(fields).WithField("total", total).Info("deprovision deleted cloud resources")
+	r.eventRecorder.Eventf(cd, corev1.EventTypeNormal, deprovisionCompletedReason,
+		"deprovision deleted %d cloud resources: %s", total, strings.Join(counts, ", "))
+}
+
+// completeDeprovision records that cleanup of the deleted cluster deployment is complete and removes the
+// deprovision finalizer.
+func (r *ReconcileClusterDeployment) completeDeprovision(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	if err := r.setDeprovisionProgress(cd, hivev1.DeprovisionProgressCompleted, cdLog); err != nil {
+		return err
+	}
+	if err := r.removeClusterDeploymentFinalizer(cd); err != nil {
+		cdLog.WithError(err).Error("error removing finalizer")
+		return err
+	}
+	return nil
+}
+
+// setDeprovisionProgress records the cleanup step reached by the deleted cluster deployment in its status.
+func (r *ReconcileClusterDeployment) setDeprovisionProgress(cd *hivev1.ClusterDeployment, progress hivev1.DeprovisionProgress, cdLog log.FieldLogger) error {
+	if cd.Status.DeprovisionProgress == progress {
+		return nil
+	}
+	cdLog.WithFields(log.Fields{
+		"from": cd.Status.DeprovisionProgress,
+		"to":   progress,
+	}).Info("deprovision progressed")
+	cd.Status.DeprovisionProgress = progress
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Error("error updating deprovision progress")
+		return err
+	}
+	return nil
+}
+
+// retryFailedDeprovision deletes a failed deprovision request so that a new one is created, unless the maximum
+// number of deprovision attempts has been reached. The DeprovisionFailed condition is then set so that an
+// operator can intervene.
+func (r *ReconcileClusterDeployment) retryFailedDeprovision(cd *hivev1.ClusterDeployment, request *hivev1.ClusterDeprovisionRequest, cdLog log.FieldLogger) (reconcile.Result, error) {
+	attempts := 1
+	if value, ok := cd.Annotations[deprovisionAttemptsAnnotation]; ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			cdLog.WithField("attempts", value).Warn("ignoring invalid deprovision attempts annotation")
+		} else {
+			attempts = parsed
 		}
+	}
+	attemptsLog := cdLog.WithFields(log.Fields{
+		"attempts":    attempts,
+		"maxAttempts": maxDeprovisionAttempts,
+	})
+
+	if attempts >= maxDeprovisionAttempts {
+		attemptsLog.Error("deprovision request failed and will not be retried")
+		_, err := r.setDeprovisionFailedCondition(cd, attempts, cdLog)
 		return reconcile.Result{}, err
 	}
 
-	// Generate a deprovision request
-	request := generateDeprovisionRequest(cd)
-	err = controllerutil.SetControllerReference(cd, request, r.scheme)
-	if err != nil {
-		cdLog.Errorf("error setting controller reference on deprovision request: %v", err)
+	attemptsLog.Warn("deprovision request failed, retrying")
+	if cd.Annotations == nil {
+		cd.Annotations = map[string]string{}
+	}
+	cd.Annotations[deprovisionAttemptsAnnotation] = strconv.Itoa(attempts + 1)
+	if err := r.Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Error("error updating deprovision attempts")
 		return reconcile.Result{}, err
 	}
+	err := r.Delete(context.TODO(), request, client.PropagationPolicy(metav1.DeletePropagationForeground))
+	if err != nil && !errors.IsNotFound(err) {
+		cdLog.WithError(err).Error("error deleting failed deprovision request")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
 
-	// Check if deprovision request already exists:
-	existingRequest := &hivev1.ClusterDeprovisionRequest{}
-	err = r.Get(context.TODO(), types.NamespacedName{Name: cd.Name, Namespace: cd.Namespace}, existingRequest)
-	if err != nil && errors.IsNotFound(err) {
-		cdLog.Infof("creating deprovision request for cluster deployment")
-		err = r.Create(context.TODO(), request)
+// setDeprovisionSkippedCondition records why a deleted cluster is being removed without deprovisioning its cloud
+// resources, both on the DeprovisionSkipped condition and in an event, so that resources left behind can be
+// accounted for.
+func (r *ReconcileClusterDeployment) setDeprovisionSkippedCondition(cd *hivev1.ClusterDeployment, reason, message string, cdLog log.FieldLogger) error {
+	original := cd.DeepCopy()
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.DeprovisionSkippedCondition,
+		corev1.ConditionTrue,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		return nil
+	}
+	cdLog.WithField("reason", reason).Info("setting DeprovisionSkippedCondition to true")
+	r.eventRecorder.Event(cd, corev1.EventTypeWarning, reason, message)
+	return r.statusUpdate(cd, cdLog)
+}
+
+func (r *ReconcileClusterDeployment) setDeprovisionFailedCondition(cd *hivev1.ClusterDeployment, attempts int, cdLog log.FieldLogger) (modified bool, err error) {
+	original := cd.DeepCopy()
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.DeprovisionFailedCondition,
+		corev1.ConditionTrue,
+		deprovisionAttemptsExhaustedReason,
+		fmt.Sprintf("deprovision failed after %d attempts", attempts),
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		cdLog.Info("setting DeprovisionFailedCondition to true")
+		err := r.Status().Update(context.TODO(), cd)
 		if err != nil {
-			cdLog.WithError(err).Errorf("error creating deprovision request")
-			// Check if namespace is terminated, if so we can give up, remove the finalizer, and let
-			// the cluster go away.
-			ns := &corev1.Namespace{}
-			err = r.Get(context.TODO(), types.NamespacedName{Name: cd.Namespace}, ns)
-			if err != nil {
-				cdLog.WithError(err).Error("error checking for deletionTimestamp on namespace")
-				return reconcile.Result{}, err
-			}
-			if ns.DeletionTimestamp != nil {
-				cdLog.Warn("detected a namespace deleted before deprovision request could be created, giving up on deprovision and removing finalizer")
-				err = r.removeClusterDeploymentFinalizer(cd)
-				if err != nil {
-					cdLog.WithError(err).Error("error removing finalizer")
-				}
-			}
-			return reconcile.Result{}, err
+			cdLog.WithError(err).Error("cannot update status conditions")
 		}
-		return reconcile.Result{}, nil
-	} else if err != nil {
-		cdLog.WithError(err).Errorf("error getting deprovision request")
-		return reconcile.Result{}, err
+		return true, err
+	}
+	return false, nil
+}
+
+// deprovisionThrottled returns true when the maximum number of deprovision requests are already in progress.
+func (r *ReconcileClusterDeployment) deprovisionThrottled(cdLog log.FieldLogger) (bool, error) {
+	if r.maxConcurrentDeprovisions <= 0 {
+		return false, nil
+	}
+	requests := &hivev1.ClusterDeprovisionRequestList{}
+	if err := r.List(context.TODO(), &client.ListOptions{}, requests); err != nil {
+		cdLog.WithError(err).Error("error listing deprovision requests")
+		return false, err
 	}
+	inProgress := 0
+	for _, request := range requests.Items {
+		if !request.Status.Completed && !request.Status.Failed {
+			inProgress++
+		}
+	}
+	if inProgress >= r.maxConcurrentDeprovisions {
+		cdLog.WithFields(log.Fields{
+			"inProgress": inProgress,
+			"max":        r.maxConcurrentDeprovisions,
+		}).Info("maximum concurrent deprovisions reached, waiting to create deprovision request")
+		return true, nil
+	}
+	return false, nil
+}
 
-	// Deprovision request exists, check whether it has completed
-	if existingRequest.Status.Completed {
-		cdLog.Infof("deprovision request completed, removing finalizer")
-		err = r.removeClusterDeploymentFinalizer(cd)
+// getClusterExpiry returns when the cluster expires according to its delete-after annotations, or nil when it
+// has no expiry yet. The delete-after annotation counts from the creation of the cluster deployment, while
+// the delete-after-installed annotation counts from the completion of the install, so it only starts once
+// the cluster is installed. The earliest expiry is returned when both are set.
+func getClusterExpiry(cd *hivev1.ClusterDeployment) (*time.Time, error) {
+	var expiry *time.Time
+	setExpiry := func(annotation string, start *metav1.Time) error {
+		value, ok := cd.Annotations[annotation]
+		if !ok {
+			return nil
+		}
+		dur, err := time.ParseDuration(value)
 		if err != nil {
-			cdLog.WithError(err).Error("error removing finalizer")
+			return fmt.Errorf("error parsing %s as a duration: %v", annotation, err)
 		}
-		return reconcile.Result{}, err
+		if start == nil || start.IsZero() {
+			return nil
+		}
+		if t := start.Add(dur); expiry == nil || t.Before(*expiry) {
+			expiry = &t
+		}
+		return nil
 	}
+	if err := setExpiry(deleteAfterAnnotation, &cd.CreationTimestamp); err != nil {
+		return nil, err
+	}
+	var installed *metav1.Time
+	if cd.Status.Installed {
+		installed = cd.Status.InstalledTimestamp
+	}
+	if err := setExpiry(deleteAfterInstalledAnnotation, installed); err != nil {
+		return nil, err
+	}
+	return expiry, nil
+}
 
-	cdLog.Debug("deprovision request not yet completed")
-
-	return reconcile.Result{}, nil
+// deleteExpiredClusterDeployment issues the delete for a cluster deployment which has expired.
+func (r *ReconcileClusterDeployment) deleteExpiredClusterDeployment(cd *hivev1.ClusterDeployment, expiry time.Time, cdLog log.FieldLogger) (reconcile.Result, error) {
+	cdLog.WithField("expiry", expiry).Info("cluster has expired, issuing delete")
+	err := r.Delete(context.TODO(), cd)
+	if err != nil {
+		cdLog.WithError(err).Error("error deleting expired cluster")
+	}
+	return reconcile.Result{}, err
 }
 
+// addClusterDeploymentFinalizer adds the deprovision finalizer to the cluster deployment.
 func (r *ReconcileClusterDeployment) addClusterDeploymentFinalizer(cd *hivev1.ClusterDeployment) error {
 	cd = cd.DeepCopy()
-	controllerutils.AddFinalizer(cd, hivev1.FinalizerDeprovision)
-	return r.Update(context.TODO(), cd)
+	return r.updateWithRetry(cd, func() error {
+		controllerutils.AddFinalizer(cd, hivev1.FinalizerDeprovision)
+		return nil
+	})
 }
 
+// removeClusterDeploymentFinalizer removes the deprovision finalizer from the cluster deployment, leaving any
+// finalizers added by other controllers in place.
 func (r *ReconcileClusterDeployment) removeClusterDeploymentFinalizer(cd *hivev1.ClusterDeployment) error {
-
 	cd = cd.DeepCopy()
-	controllerutils.DeleteFinalizer(cd, hivev1.FinalizerDeprovision)
-	err := r.Update(context.TODO(), cd)
+	err := r.updateWithRetry(cd, func() error {
+		controllerutils.DeleteFinalizer(cd, hivev1.FinalizerDeprovision)
+		return nil
+	})
 
 	if err == nil {
 		clearUnderwaySecondsMetrics(cd)
@@ -1028,47 +3275,163 @@ func (r *ReconcileClusterDeployment) removeClusterDeploymentFinalizer(cd *hivev1
 	return err
 }
 
-func (r *ReconcileClusterDeployment) ensureManagedDNSZone(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (bool, error) {
+// updateWithRetry applies mutate to obj and updates obj if mutate changed it. Update conflicts, for example with
+// another controller writing the same object, are retried a bounded number of times by reading obj again and
+// reapplying mutate rather than failing the whole reconcile. On success obj holds what was written.
+func (r *ReconcileClusterDeployment) updateWithRetry(obj runtime.Object, mutate func() error) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	key := types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}
+	first := true
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if !first {
+			// Clear obj first so nothing of the stale copy survives the read.
+			v := reflect.ValueOf(obj).Elem()
+			v.Set(reflect.Zero(v.Type()))
+			if err := r.Get(context.TODO(), key, obj); err != nil {
+				return err
+			}
+		}
+		first = false
+		original := obj.DeepCopyObject()
+		if err := mutate(); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(original, obj) {
+			return nil
+		}
+		return r.Update(context.TODO(), obj)
+	})
+}
+
+// waitForManagedDNSZone ensures the managed DNSZone for the cluster deployment exists, generating it from genCD.
+// It returns false along with the result reconcile should return while the zone is not yet available.
+func (r *ReconcileClusterDeployment) waitForManagedDNSZone(cd, genCD *hivev1.ClusterDeployment, cdLog log.FieldLogger) (bool, reconcile.Result, error) {
+	managedDNSZoneAvailable, err := r.ensureManagedDNSZone(cd, genCD, cdLog)
+	if err != nil {
+		return false, reconcile.Result{}, err
+	}
+	if !managedDNSZoneAvailable && (cd.Spec.ManagedDNSZoneRef != nil || hasDNSZoneConflict(cd)) {
+		// Externally managed zones, and conflicting zones controlled by something else, are not owned by the
+		// clusterdeployment, so changes to them will not queue the clusterdeployment.
+		cdLog.Debug("external DNSZone is not yet available, will check again")
+		return false, reconcile.Result{RequeueAfter: dnsZoneCheckInterval}, nil
+	}
+	if !managedDNSZoneAvailable {
+		// The clusterdeployment will be queued when the owned DNSZone's status
+		// is updated to available.
+		cdLog.Debug("DNSZone is not yet available. Waiting for zone to become available.")
+		return false, reconcile.Result{}, nil
+	}
+	return true, reconcile.Result{}, nil
+}
+
+func (r *ReconcileClusterDeployment) ensureManagedDNSZone(cd, genCD *hivev1.ClusterDeployment, cdLog log.FieldLogger) (bool, error) {
+	if cd.Spec.ManagedDNSZoneRef != nil {
+		return r.isExternalDNSZoneAvailable(cd, cdLog)
+	}
 	// for now we only support AWS
-	if cd.Spec.AWS == nil || cd.Spec.PlatformSecrets.AWS == nil {
+	if genCD.Spec.AWS == nil || genCD.Spec.PlatformSecrets.AWS == nil {
 		cdLog.Error("cluster deployment platform is not AWS, cannot manage DNS zone")
 		return false, fmt.Errorf("only AWS managed DNS is supported")
 	}
 	dnsZone := &hivev1.DNSZone{}
-	dnsZoneNamespacedName := types.NamespacedName{Namespace: cd.Namespace, Name: dnsZoneName(cd.Name)}
+	dnsZoneNamespacedName := types.NamespacedName{Namespace: cd.Namespace, Name: dnsZoneName(cd)}
 	logger := cdLog.WithField("zone", dnsZoneNamespacedName.String())
 
 	err := r.Get(context.TODO(), dnsZoneNamespacedName, dnsZone)
 	if err == nil {
+		// The zone name may be chosen by the user, so make sure the zone is not another cluster's.
+		owned := metav1.IsControlledBy(dnsZone, cd)
+		if !owned {
+			logger.Warn("DNSZone is not controlled by the cluster deployment, refusing to use it")
+		}
+		if err := r.setDNSZoneConflictCondition(cd, !owned, dnsZoneNamespacedName.Name, logger); err != nil || !owned {
+			return false, err
+		}
+		// Keep the zone in sync with the cluster deployment, whose DNS settings may have changed since the zone
+		// was created. Empty and unset collections are stored alike, so they must compare equal.
+		if spec := managedDNSZoneSpec(genCD); !equality.Semantic.DeepEqual(dnsZone.Spec, spec) {
+			logger.Info("updating DNSZone to match the cluster deployment")
+			dnsZone.Spec = spec
+			if err := r.Update(context.TODO(), dnsZone); err != nil {
+				logger.WithError(err).Error("cannot update DNS zone")
+				return false, err
+			}
+			return false, nil
+		}
 		availableCondition := controllerutils.FindDNSZoneCondition(dnsZone.Status.Conditions, hivev1.ZoneAvailableDNSZoneCondition)
 		return availableCondition != nil && availableCondition.Status == corev1.ConditionTrue, nil
 	}
 	if errors.IsNotFound(err) {
 		logger.Info("creating new DNSZone for cluster deployment")
-		return false, r.createManagedDNSZone(cd, logger)
+		return false, r.createManagedDNSZone(cd, managedDNSZoneSpec(genCD), logger)
 	}
 	logger.WithError(err).Error("failed to fetch DNS zone")
 	return false, err
 }
 
-func (r *ReconcileClusterDeployment) createManagedDNSZone(cd *hivev1.ClusterDeployment, logger log.FieldLogger) error {
+// setDNSZoneConflictCondition sets the DNSZoneConflict condition, which records whether the managed DNSZone of
+// the cluster deployment exists but is controlled by something else.
+func (r *ReconcileClusterDeployment) setDNSZoneConflictCondition(cd *hivev1.ClusterDeployment, conflict bool, zoneName string, cdLog log.FieldLogger) error {
+	status := corev1.ConditionFalse
+	reason := dnsZoneOwnedReason
+	message := fmt.Sprintf("DNSZone %s is controlled by the cluster deployment", zoneName)
+	if conflict {
+		status = corev1.ConditionTrue
+		reason = dnsZoneOwnedByOtherReason
+		message = fmt.Sprintf("DNSZone %s already exists and is not controlled by the cluster deployment", zoneName)
+	}
+	original := cd.DeepCopy()
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.DNSZoneConflictCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if reflect.DeepEqual(original.Status.Conditions, cd.Status.Conditions) {
+		return nil
+	}
+	cdLog.Infof("setting DNSZoneConflictCondition to %v", status)
+	return r.statusUpdate(cd, cdLog)
+}
+
+// hasDNSZoneConflict reports whether the managed DNSZone of the cluster deployment is controlled by something else.
+func hasDNSZoneConflict(cd *hivev1.ClusterDeployment) bool {
+	condition := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.DNSZoneConflictCondition)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// isExternalDNSZoneAvailable reports whether the externally managed DNSZone referenced by the cluster deployment
+// exists and is available. The zone is never created by hive.
+func (r *ReconcileClusterDeployment) isExternalDNSZoneAvailable(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (bool, error) {
+	dnsZone := &hivev1.DNSZone{}
+	dnsZoneNamespacedName := types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.ManagedDNSZoneRef.Name}
+	logger := cdLog.WithField("zone", dnsZoneNamespacedName.String())
+
+	err := r.Get(context.TODO(), dnsZoneNamespacedName, dnsZone)
+	if errors.IsNotFound(err) {
+		logger.Info("waiting for externally managed DNSZone to be created")
+		return false, nil
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to fetch DNS zone")
+		return false, err
+	}
+	availableCondition := controllerutils.FindDNSZoneCondition(dnsZone.Status.Conditions, hivev1.ZoneAvailableDNSZoneCondition)
+	return availableCondition != nil && availableCondition.Status == corev1.ConditionTrue, nil
+}
+
+func (r *ReconcileClusterDeployment) createManagedDNSZone(cd *hivev1.ClusterDeployment, spec hivev1.DNSZoneSpec, logger log.FieldLogger) error {
 	dnsZone := &hivev1.DNSZone{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      dnsZoneName(cd.Name),
+			Name:      dnsZoneName(cd),
 			Namespace: cd.Namespace,
 		},
-		Spec: hivev1.DNSZoneSpec{
-			Zone:               cd.Spec.BaseDomain,
-			LinkToParentDomain: true,
-			AWS: &hivev1.AWSDNSZoneSpec{
-				AccountSecret: cd.Spec.PlatformSecrets.AWS.Credentials,
-				Region:        cd.Spec.AWS.Region,
-			},
-		},
-	}
-
-	for k, v := range cd.Spec.AWS.UserTags {
-		dnsZone.Spec.AWS.AdditionalTags = append(dnsZone.Spec.AWS.AdditionalTags, hivev1.AWSResourceTag{Key: k, Value: v})
+		Spec: spec,
 	}
 
 	if err := controllerutil.SetControllerReference(cd, dnsZone, r.scheme); err != nil {
@@ -1085,11 +3448,65 @@ func (r *ReconcileClusterDeployment) createManagedDNSZone(cd *hivev1.ClusterDepl
 	return nil
 }
 
-func dnsZoneName(cdName string) string {
-	return apihelpers.GetResourceName(cdName, "zone")
+// managedDNSZoneSpec returns the spec of the managed DNSZone of the cluster deployment.
+func managedDNSZoneSpec(cd *hivev1.ClusterDeployment) hivev1.DNSZoneSpec {
+	spec := hivev1.DNSZoneSpec{
+		Zone:               cd.Spec.BaseDomain,
+		LinkToParentDomain: true,
+		RecordTTL:          cd.Spec.ManagedDNSRecordTTL,
+		AdditionalRecords:  cd.Spec.AdditionalDNSRecords,
+		AWS: &hivev1.AWSDNSZoneSpec{
+			AccountSecret: dnsCredentials(cd),
+			Region:        cd.Spec.AWS.Region,
+		},
+	}
+
+	// Sort the tags so that the spec does not change with the iteration order of the user tags.
+	for k, v := range cd.Spec.AWS.UserTags {
+		spec.AWS.AdditionalTags = append(spec.AWS.AdditionalTags, hivev1.AWSResourceTag{Key: k, Value: v})
+	}
+	sort.Slice(spec.AWS.AdditionalTags, func(i, j int) bool {
+		return spec.AWS.AdditionalTags[i].Key < spec.AWS.AdditionalTags[j].Key
+	})
+
+	// The base domain of a private cluster must only resolve from within its VPC, so it cannot be delegated
+	// to from the public parent domain.
+	if isPrivateCluster(cd) {
+		spec.LinkToParentDomain = false
+		spec.AWS.PrivateZone = &hivev1.AWSPrivateZone{
+			Subnets: cd.Spec.AWS.Subnets,
+		}
+	}
+	return spec
+}
+
+// dnsCredentials returns the secret holding the credentials for the managed DNS zone of the cluster
+// deployment, falling back to the platform credentials when no separate DNS credentials are set.
+func dnsCredentials(cd *hivev1.ClusterDeployment) corev1.LocalObjectReference {
+	if credentials := cd.Spec.PlatformSecrets.AWS.DNSCredentials; credentials != nil && credentials.Name != "" {
+		return *credentials
+	}
+	return cd.Spec.PlatformSecrets.AWS.Credentials
+}
+
+// isPrivateCluster returns true when the API and ingress of the cluster are only published within its VPC.
+func isPrivateCluster(cd *hivev1.ClusterDeployment) bool {
+	return cd.Spec.AWS != nil && cd.Spec.AWS.Publish == hivev1.InternalPublishingStrategy
+}
+
+// dnsZoneName returns the name of the DNSZone created for a cluster deployment which manages DNS, which is
+// ManagedDNSZoneName when set and otherwise derived from the cluster deployment name.
+func dnsZoneName(cd *hivev1.ClusterDeployment) string {
+	if cd.Spec.ManagedDNSZoneName != "" {
+		return cd.Spec.ManagedDNSZoneName
+	}
+	return apihelpers.GetResourceName(cd.Name, "zone")
 }
 
-func selectorPodWatchHandler(a handler.MapObject) []reconcile.Request {
+// selectorPodWatchHandler maps a pod to the cluster deployment named in its cluster deployment name label. A
+// label value which may have been shortened from a longer name is looked up among the cluster deployments of
+// the namespace of the pod.
+func (r *ReconcileClusterDeployment) selectorPodWatchHandler(a handler.MapObject) []reconcile.Request {
 	retval := []reconcile.Request{}
 
 	pod := a.Object.(*corev1.Pod)
@@ -1105,6 +3522,22 @@ func selectorPodWatchHandler(a handler.MapObject) []reconcile.Request {
 	if !ok {
 		return retval
 	}
+	if len(cdName) >= validation.LabelValueMaxLength {
+		clusterDeployments := &hivev1.ClusterDeploymentList{}
+		if err := r.List(context.TODO(), &client.ListOptions{Namespace: pod.Namespace}, clusterDeployments); err != nil {
+			log.WithError(err).WithField("pod", pod.Name).Error("error listing cluster deployments for pod")
+			return retval
+		}
+		for _, cd := range clusterDeployments.Items {
+			if install.ClusterDeploymentNameLabelValue(cd.Name) == cdName {
+				retval = append(retval, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      cd.Name,
+					Namespace: cd.Namespace,
+				}})
+			}
+		}
+		return retval
+	}
 	retval = append(retval, reconcile.Request{NamespacedName: types.NamespacedName{
 		Name:      cdName,
 		Namespace: pod.Namespace,
@@ -1112,27 +3545,169 @@ func selectorPodWatchHandler(a handler.MapObject) []reconcile.Request {
 	return retval
 }
 
-func (r *ReconcileClusterDeployment) calcInstallPodRestarts(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (int, error) {
-	installerPodLabels := map[string]string{install.ClusterDeploymentNameLabel: cd.Name, install.InstallJobLabel: "true"}
+// clusterImageSetWatchHandler maps a ClusterImageSet to the cluster deployments referencing it which have not
+// been installed yet. Installed clusters are not affected by changes to their ClusterImageSet.
+func (r *ReconcileClusterDeployment) clusterImageSetWatchHandler(a handler.MapObject) []reconcile.Request {
+	retval := []reconcile.Request{}
+
+	imageSet, ok := a.Object.(*hivev1.ClusterImageSet)
+	if !ok {
+		// Wasn't a ClusterImageSet, bail out. This should not happen.
+		log.Errorf("Error converting MapObject.Object to ClusterImageSet. Value: %+v", a.Object)
+		return retval
+	}
+
+	clusterDeployments := &hivev1.ClusterDeploymentList{}
+	if err := r.List(context.TODO(), &client.ListOptions{}, clusterDeployments); err != nil {
+		log.WithError(err).WithField("clusterimageset", imageSet.Name).Error("error listing cluster deployments for clusterimageset")
+		return retval
+	}
+	for _, cd := range clusterDeployments.Items {
+		if cd.Status.Installed || cd.Spec.ImageSet == nil || cd.Spec.ImageSet.Name != imageSet.Name {
+			continue
+		}
+		retval = append(retval, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      cd.Name,
+			Namespace: cd.Namespace,
+		}})
+	}
+	return retval
+}
+
+// listInstallPods lists the pods of the install jobs for the cluster deployment.
+func (r *ReconcileClusterDeployment) listInstallPods(cd *hivev1.ClusterDeployment) (*corev1.PodList, error) {
+	installerPodLabels := map[string]string{install.ClusterDeploymentNameLabel: install.ClusterDeploymentNameLabelValue(cd.Name), install.InstallJobLabel: "true"}
 	parsedLabels := labels.SelectorFromSet(installerPodLabels)
 	pods := &corev1.PodList{}
 	err := r.Client.List(context.Background(), &client.ListOptions{Namespace: cd.Namespace, LabelSelector: parsedLabels}, pods)
-	if err != nil {
-		return 0, err
-	}
+	return pods, err
+}
 
+// calcInstallPodRestarts returns the total number of container restarts across the given install pods, along
+// with the most common reason those containers last terminated.
+func calcInstallPodRestarts(pods *corev1.PodList, cdLog log.FieldLogger) (int, string) {
 	if len(pods.Items) > 1 {
-		log.Warnf("found %d install pods for cluster", len(pods.Items))
+		cdLog.Warnf("found %d install pods for cluster", len(pods.Items))
 	}
 
-	// Calculate restarts across all containers in the pod:
+	// Calculate restarts and termination reasons across all containers in the pod:
 	containerRestarts := 0
+	terminationReasons := map[string]int{}
 	for _, pod := range pods.Items {
 		for _, cs := range pod.Status.ContainerStatuses {
 			containerRestarts += int(cs.RestartCount)
+			if terminated := cs.LastTerminationState.Terminated; terminated != nil && terminated.Reason != "" {
+				terminationReasons[terminated.Reason]++
+			}
 		}
 	}
-	return containerRestarts, nil
+	return containerRestarts, dominantTerminationReason(terminationReasons)
+}
+
+// setInstallPodDuplicationCondition sets the InstallPodDuplication condition when more than one of the given
+// install pods is active. A warning event is recorded when the duplication is first detected.
+func (r *ReconcileClusterDeployment) setInstallPodDuplicationCondition(cd *hivev1.ClusterDeployment, pods *corev1.PodList, cdLog log.FieldLogger) {
+	activePods := 0
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		activePods++
+	}
+
+	if activePods <= 1 {
+		cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+			cd.Status.Conditions,
+			hivev1.InstallPodDuplicationCondition,
+			corev1.ConditionFalse,
+			installPodSingleReason,
+			"no duplicate install pods found",
+			controllerutils.UpdateConditionIfReasonOrMessageChange)
+		return
+	}
+
+	message := fmt.Sprintf("found %d active install pods for cluster", activePods)
+	cdLog.WithField("pods", activePods).Warn("found multiple active install pods for cluster")
+	cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.InstallPodDuplicationCondition)
+	if cond == nil || cond.Status != corev1.ConditionTrue {
+		r.eventRecorder.Event(cd, corev1.EventTypeWarning, installPodDuplicationReason, message)
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.InstallPodDuplicationCondition,
+		corev1.ConditionTrue,
+		installPodDuplicationReason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+}
+
+// dominantTerminationReason returns the reason with the highest count, preferring the alphabetically
+// first reason on a tie so the result is stable across reconciles.
+func dominantTerminationReason(reasons map[string]int) string {
+	dominant := ""
+	for reason, count := range reasons {
+		if dominant == "" || count > reasons[dominant] || (count == reasons[dominant] && reason < dominant) {
+			dominant = reason
+		}
+	}
+	return dominant
+}
+
+// deleteInstallConfig deletes the install-config ConfigMap and Secret of an installed cluster, if they exist.
+func (r *ReconcileClusterDeployment) deleteInstallConfig(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	name := types.NamespacedName{Namespace: cd.Namespace, Name: install.GetInstallConfigName(cd)}
+	for _, obj := range []runtime.Object{&corev1.ConfigMap{}, &corev1.Secret{}} {
+		err := r.Get(context.TODO(), name, obj)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			cdLog.WithError(err).WithField("name", name.Name).Error("error getting install config")
+			return err
+		}
+		if err := r.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+			cdLog.WithError(err).WithField("name", name.Name).Error("error deleting install config")
+			return err
+		}
+		cdLog.WithField("name", name.Name).Info("deleted install config of installed cluster")
+	}
+	return nil
+}
+
+// adoptClusterDeployment marks a cluster which was installed outside of hive as installed, without launching
+// an install. The identifiers of the cluster must be filled in on its status first. The cluster is requeued
+// after requeueAfter, for example for its expiry, when it is non-zero.
+func (r *ReconcileClusterDeployment) adoptClusterDeployment(cd *hivev1.ClusterDeployment, requeueAfter time.Duration, cdLog log.FieldLogger) (reconcile.Result, error) {
+	var missing []string
+	if cd.Status.InfraID == "" {
+		missing = append(missing, "status.infraID")
+	}
+	if cd.Status.ClusterID == "" {
+		missing = append(missing, "status.clusterID")
+	}
+	if cd.Status.AdminKubeconfigSecret.Name == "" {
+		missing = append(missing, "status.adminKubeconfigSecret")
+	}
+	if len(missing) > 0 {
+		cdLog.WithField("missing", missing).Warn("cluster to adopt is missing identifiers, waiting for them to be set")
+		r.eventRecorder.Eventf(cd, corev1.EventTypeWarning, adoptionIncompleteReason,
+			"cluster cannot be adopted until %s are set", strings.Join(missing, ", "))
+		// Setting the missing identifiers updates the cluster deployment, which reconciles it again.
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	cdLog.WithFields(log.Fields{
+		"infraID":   cd.Status.InfraID,
+		"clusterID": cd.Status.ClusterID,
+	}).Info("adopting cluster installed outside of hive")
+	now := metav1.Now()
+	cd.Status.Installed = true
+	cd.Status.InstalledTimestamp = &now
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Error("failed to mark adopted cluster installed")
+		return reconcile.Result{}, err
+	}
+	r.eventRecorder.Event(cd, corev1.EventTypeNormal, clusterAdoptedReason, "cluster installed outside of hive adopted")
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *ReconcileClusterDeployment) deleteJobOnHashChange(existingJob, generatedJob *batchv1.Job, cdLog log.FieldLogger) (bool, error) {
@@ -1148,9 +3723,10 @@ func (r *ReconcileClusterDeployment) deleteJobOnHashChange(existingJob, generate
 	}
 
 	if newJobNeeded {
-		// delete the existing job
+		// delete the existing job. No grace period is given with the deletion so that the install pod is
+		// given its own termination grace period to clean up.
 		cdLog.Info("deleting existing install job due to updated/missing hash detected")
-		err := r.Delete(context.TODO(), existingJob, client.PropagationPolicy(metav1.DeletePropagationForeground))
+		err := r.Delete(context.TODO(), existingJob, r.jobDeletionPropagationPolicy())
 		if err != nil {
 			cdLog.WithError(err).Errorf("error deleting outdated install job")
 			return newJobNeeded, err
@@ -1160,60 +3736,20 @@ func (r *ReconcileClusterDeployment) deleteJobOnHashChange(existingJob, generate
 	return newJobNeeded, nil
 }
 
-func generateDeprovisionRequest(cd *hivev1.ClusterDeployment) *hivev1.ClusterDeprovisionRequest {
-	req := &hivev1.ClusterDeprovisionRequest{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      cd.Name,
-			Namespace: cd.Namespace,
-		},
-		Spec: hivev1.ClusterDeprovisionRequestSpec{
-			InfraID:   cd.Status.InfraID,
-			ClusterID: cd.Status.ClusterID,
-			Platform: hivev1.ClusterDeprovisionRequestPlatform{
-				AWS: &hivev1.AWSClusterDeprovisionRequest{},
-			},
-		},
-	}
-
-	if cd.Spec.Platform.AWS != nil {
-		req.Spec.Platform.AWS.Region = cd.Spec.Platform.AWS.Region
-	}
-
-	if cd.Spec.PlatformSecrets.AWS != nil {
-		req.Spec.Platform.AWS.Credentials = &cd.Spec.PlatformSecrets.AWS.Credentials
-	}
-
-	return req
-}
-
-func migrateWildcardIngress(cd *hivev1.ClusterDeployment) bool {
-	migrated := false
+func migrateWildcardIngress(cd *hivev1.ClusterDeployment) []hivev1.IngressDomainMigration {
+	var migrations []hivev1.IngressDomainMigration
 	for i, ingress := range cd.Spec.Ingress {
 		newIngress := wildcardDomain.ReplaceAllString(ingress.Domain, "")
 		if newIngress != ingress.Domain {
 			cd.Spec.Ingress[i].Domain = newIngress
-			migrated = true
+			migrations = append(migrations, hivev1.IngressDomainMigration{
+				Name: ingress.Name,
+				From: ingress.Domain,
+				To:   newIngress,
+			})
 		}
 	}
-	return migrated
-}
-
-func calculateJobSpecHash(job *batchv1.Job) (string, error) {
-
-	hasher := md5.New()
-	jobSpecBytes, err := job.Spec.Marshal()
-	if err != nil {
-		return "", err
-	}
-
-	_, err = hasher.Write(jobSpecBytes)
-	if err != nil {
-		return "", err
-	}
-
-	sum := hex.EncodeToString(hasher.Sum(nil))
-
-	return sum, nil
+	return migrations
 }
 
 func strPtr(s string) *string {
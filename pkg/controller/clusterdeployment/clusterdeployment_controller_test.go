@@ -17,33 +17,51 @@ limitations under the License.
 package clusterdeployment
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	dto "github.com/prometheus/client_model/go"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	openshiftapiv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/awsclient"
+	mockaws "github.com/openshift/hive/pkg/awsclient/mock"
+	"github.com/openshift/hive/pkg/constants"
 	"github.com/openshift/hive/pkg/controller/images"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/imageset"
 	"github.com/openshift/hive/pkg/install"
 )
 
@@ -85,6 +103,10 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 	openshiftapiv1.Install(scheme.Scheme)
 	routev1.Install(scheme.Scheme)
 
+	// The operator always configures the hive image of the controller.
+	os.Setenv(images.HiveImageEnvVar, images.DefaultHiveImage)
+	defer os.Unsetenv(images.HiveImageEnvVar)
+
 	// Utility function to get the test CD from the fake client
 	getCD := func(c client.Client) *hivev1.ClusterDeployment {
 		cd := &hivev1.ClusterDeployment{}
@@ -95,14 +117,17 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 		return nil
 	}
 
-	getDNSZone := func(c client.Client) *hivev1.DNSZone {
+	getDNSZoneByName := func(c client.Client, name string) *hivev1.DNSZone {
 		zone := &hivev1.DNSZone{}
-		err := c.Get(context.TODO(), client.ObjectKey{Name: testName + "-zone", Namespace: testNamespace}, zone)
+		err := c.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: testNamespace}, zone)
 		if err == nil {
 			return zone
 		}
 		return nil
 	}
+	getDNSZone := func(c client.Client) *hivev1.DNSZone {
+		return getDNSZoneByName(c, testName+"-zone")
+	}
 
 	getDeprovisionRequest := func(c client.Client) *hivev1.ClusterDeprovisionRequest {
 		req := &hivev1.ClusterDeprovisionRequest{}
@@ -115,6 +140,14 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 	getImageSetJob := func(c client.Client) *batchv1.Job {
 		return getJob(c, imageSetJobName)
 	}
+	getPostInstallSyncSet := func(c client.Client) *hivev1.SyncSet {
+		ss := &hivev1.SyncSet{}
+		err := c.Get(context.TODO(), client.ObjectKey{Name: postInstallSyncSetName(testClusterDeployment()), Namespace: testNamespace}, ss)
+		if err == nil {
+			return ss
+		}
+		return nil
+	}
 
 	tests := []struct {
 		name      string
@@ -126,7 +159,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 			name: "Add finalizer",
 			existing: []runtime.Object{
 				testClusterDeploymentWithoutFinalizer(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -136,11 +169,30 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Re-add finalizer to installed cluster",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeploymentWithoutFinalizer()
+					cd.Status.Installed = true
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					assert.True(t, controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision), "did not get expected clusterdeployment finalizer")
+					assert.True(t, cd.Status.Installed, "cluster should remain installed")
+				}
+			},
+		},
 		{
 			name: "Create install job",
 			existing: []runtime.Object{
 				testClusterDeployment(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -150,12 +202,135 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Create install job with additional trust bundle",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.AdditionalTrustBundle = &corev1.LocalObjectReference{Name: "trust-bundle"}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testSecret(corev1.SecretTypeOpaque, "trust-bundle", hivev1.AdditionalTrustBundleSecretKey, "fakebundle"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				job := getInstallJob(c)
+				if assert.NotNil(t, job, "did not find expected install job") {
+					assert.Contains(t, job.Spec.Template.Spec.Volumes, corev1.Volume{
+						Name: "additionaltrustbundle",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "trust-bundle"},
+						},
+					}, "additional trust bundle not mounted")
+				}
+				cfgMap := &corev1.ConfigMap{}
+				err := c.Get(context.TODO(), client.ObjectKey{Name: testName + "-installconfig", Namespace: testNamespace}, cfgMap)
+				if assert.NoError(t, err, "did not find install config map") {
+					assert.Contains(t, cfgMap.Data["install-config.yaml"], "additionalTrustBundle: fakebundle")
+				}
+			},
+		},
+		{
+			name: "Missing additional trust bundle secret",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.AdditionalTrustBundle = &corev1.LocalObjectReference{Name: "trust-bundle"}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			expectErr: true,
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getInstallJob(c), "install job should not be created without the trust bundle")
+			},
+		},
+		{
+			name: "Create install job with bootstrap ignition override",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.BootstrapIgnitionOverrideRef = &corev1.LocalObjectReference{Name: "bootstrap-override"}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testSecret(corev1.SecretTypeOpaque, "bootstrap-override", hivev1.BootstrapIgnitionOverrideSecretKey, `{"ignition":{"version":"2.2.0"}}`),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				job := getInstallJob(c)
+				if assert.NotNil(t, job, "did not find expected install job") {
+					assert.Contains(t, job.Spec.Template.Spec.Volumes, corev1.Volume{
+						Name: "bootstrapignitionoverride",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: "bootstrap-override"},
+						},
+					}, "bootstrap ignition override not referenced by install job")
+				}
+			},
+		},
+		{
+			name: "Create install config secret when requested",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.InstallConfigSecret = true
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cfgName := testName + "-installconfig"
+				secret := &corev1.Secret{}
+				if assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Name: cfgName, Namespace: testNamespace}, secret), "missing install config secret") {
+					assert.NotEmpty(t, secret.Data["install-config.yaml"], "install config secret has no install config")
+				}
+				cfgMap := &corev1.ConfigMap{}
+				err := c.Get(context.TODO(), client.ObjectKey{Name: cfgName, Namespace: testNamespace}, cfgMap)
+				assert.True(t, errors.IsNotFound(err), "install config map should not be created")
+				job := getInstallJob(c)
+				if assert.NotNil(t, job, "did not find expected install job") {
+					assert.Contains(t, job.Spec.Template.Spec.Volumes, corev1.Volume{
+						Name: "installconfig",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: cfgName},
+						},
+					}, "install config secret not mounted by install job")
+				}
+			},
+		},
+		{
+			name: "Reject invalid bootstrap ignition override",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.BootstrapIgnitionOverrideRef = &corev1.LocalObjectReference{Name: "bootstrap-override"}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testSecret(corev1.SecretTypeOpaque, "bootstrap-override", hivev1.BootstrapIgnitionOverrideSecretKey, "not ignition"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getInstallJob(c), "install job should not be created for invalid ignition")
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.BootstrapIgnitionOverrideInvalidCondition)
+					if assert.NotNil(t, cond, "missing BootstrapIgnitionOverrideInvalid condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+					}
+				}
+			},
+		},
 		{
 			name: "No-op Running install job",
 			existing: []runtime.Object{
 				testClusterDeployment(),
 				testInstallJob(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -180,7 +355,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				}(),
 				testInstallJob(),
 				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 				testMetadataConfigMap(),
 			},
@@ -190,6 +365,56 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				assert.Equal(t, "https://bar-api.clusters.example.com:6443/console", cd.Status.WebConsoleURL)
 			},
 		},
+		{
+			name: "Admin kubeconfig secret missing kubeconfig data",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.Installed = true
+					cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+					return cd
+				}(),
+				testInstallJob(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "not-a-kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testMetadataConfigMap(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.AdminKubeconfigInvalidCondition)
+					if assert.NotNil(t, cond, "missing AdminKubeconfigInvalid condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected AdminKubeconfigInvalid condition status")
+					}
+					assert.Empty(t, cd.Status.APIURL, "API URL should not be set from an invalid secret")
+				}
+			},
+		},
+		{
+			name: "Admin kubeconfig secret with only raw kubeconfig",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.Installed = true
+					cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+					return cd
+				}(),
+				testInstallJob(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, rawAdminKubeconfigKey, adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testMetadataConfigMap(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.AdminKubeconfigInvalidCondition)
+					assert.Nil(t, cond, "unexpected AdminKubeconfigInvalid condition")
+					assert.Equal(t, "https://bar-api.clusters.example.com:6443", cd.Status.APIURL)
+				}
+			},
+		},
 		{
 			name: "Completed install job",
 			existing: []runtime.Object{
@@ -197,7 +422,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				testCompletedInstallJob(),
 				testMetadataConfigMap(),
 				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -206,6 +431,30 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					t.Errorf("did not get a clusterdeployment with a status of Installed")
 					return
 				}
+				assert.NotNil(t, cd.Status.InstalledTimestamp, "installed timestamp should be set")
+			},
+		},
+		{
+			name: "Completed install job with renamed cluster",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ClusterName = "renamed"
+					return cd
+				}(),
+				testCompletedInstallJob(),
+				testMetadataConfigMap(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					assert.True(t, cd.Status.Installed, "cluster should be installed")
+					assert.Equal(t, "https://bar-api.clusters.example.com:6443", cd.Status.APIURL,
+						"API URL should be read from the only cluster in the admin kubeconfig")
+				}
 			},
 		},
 		{
@@ -235,7 +484,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				}(),
 				testMetadataConfigMap(),
 				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -249,7 +498,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 			name: "Delete cluster deployment",
 			existing: []runtime.Object{
 				testDeletedClusterDeployment(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 				func() *batchv1.Job {
 					job, _, _ := install.GenerateInstallerJob(
@@ -258,7 +507,9 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 						"",
 						"fakeserviceaccount",
 						"sshkey",
-						"pullsecret")
+						"pullsecret",
+						"",
+						"")
 					return job
 				}(),
 			},
@@ -273,7 +524,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 			name: "No-op deleted cluster without finalizer",
 			existing: []runtime.Object{
 				testDeletedClusterDeploymentWithoutFinalizer(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -287,7 +538,43 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 			name: "Delete expired cluster deployment",
 			existing: []runtime.Object{
 				testExpiredClusterDeployment(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if cd != nil {
+					t.Errorf("got unexpected cluster deployment (expected deleted)")
+				}
+			},
+		},
+		{
+			name: "Keep expired cluster deployment with no-expiry annotation",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testExpiredClusterDeployment()
+					cd.Annotations[noExpiryAnnotation] = "true"
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "protected cluster deployment should not be deleted") {
+					assert.Nil(t, cd.DeletionTimestamp, "protected cluster deployment should not be deleted")
+				}
+			},
+		},
+		{
+			name: "Delete cluster deployment with expire now annotation",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Annotations[expireNowAnnotation] = "true"
+					return cd
+				}(),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -306,7 +593,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					cd.Spec.PreserveOnDelete = true
 					return cd
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 				func() *batchv1.Job {
 					job, _, _ := install.GenerateInstallerJob(
@@ -315,7 +602,9 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 						"",
 						"fakeserviceaccount",
 						"sshkey",
-						"pullsecret")
+						"pullsecret",
+						"",
+						"")
 					return job
 				}(),
 			},
@@ -333,7 +622,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					return cd
 				}(),
 				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 				func() *batchv1.Job {
 					job, _, _ := install.GenerateInstallerJob(
@@ -342,7 +631,9 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 						"",
 						"fakeserviceaccount",
 						"sshkey",
-						"pullsecret")
+						"pullsecret",
+						"",
+						"")
 					wrongGeneration := "-1"
 					job.Annotations[clusterDeploymentGenerationAnnotation] = wrongGeneration
 					return job
@@ -362,7 +653,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					cd.Status.Installed = false
 					return cd
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -379,7 +670,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					cd.Spec.Images.InstallerImage = "test-installer-image:latest"
 					return cd
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -404,7 +695,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					cis.Spec.InstallerImage = strPtr("test-cis-installer-image:latest")
 					return cis
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -425,7 +716,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					return cd
 				}(),
 				testClusterImageSet(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -457,7 +748,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					return cd
 				}(),
 				testClusterImageSet(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -491,7 +782,7 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					cis.Spec.ReleaseImage = strPtr("test-release-image:latest")
 					return cis
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
@@ -527,144 +818,324 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 					cd.Spec.ManageDNS = true
 					return cd
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
 				zone := getDNSZone(c)
-				assert.NotNil(t, zone, "dns zone should exist")
+				if assert.NotNil(t, zone, "dns zone should exist") {
+					assert.Equal(t, "aws-credentials", zone.Spec.AWS.AccountSecret.Name, "dns zone should use the platform credentials")
+				}
 			},
 		},
 		{
-			name: "Wait when DNSZone is not available yet",
+			name: "Create DNSZone with separate DNS credentials",
 			existing: []runtime.Object{
 				func() *hivev1.ClusterDeployment {
 					cd := testClusterDeployment()
 					cd.Spec.ManageDNS = true
+					cd.Spec.PlatformSecrets.AWS.DNSCredentials = &corev1.LocalObjectReference{Name: "dns-credentials"}
 					return cd
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
-				testDNSZone(),
 			},
 			validate: func(c client.Client, t *testing.T) {
-				installJob := getInstallJob(c)
-				assert.Nil(t, installJob, "install job should not exist")
+				zone := getDNSZone(c)
+				if assert.NotNil(t, zone, "dns zone should exist") {
+					assert.Equal(t, "dns-credentials", zone.Spec.AWS.AccountSecret.Name, "dns zone should use the DNS credentials")
+				}
 			},
 		},
 		{
-			name: "Create install job when DNSZone is ready",
+			name: "Create private DNSZone for private cluster",
 			existing: []runtime.Object{
 				func() *hivev1.ClusterDeployment {
 					cd := testClusterDeployment()
 					cd.Spec.ManageDNS = true
+					cd.Spec.AWS.Publish = hivev1.InternalPublishingStrategy
+					cd.Spec.AWS.Subnets = []string{"subnet-a", "subnet-b"}
 					return cd
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
-				testAvailableDNSZone(),
 			},
 			validate: func(c client.Client, t *testing.T) {
-				installJob := getInstallJob(c)
-				assert.NotNil(t, installJob, "install job should exist")
+				zone := getDNSZone(c)
+				if assert.NotNil(t, zone, "dns zone should exist") {
+					assert.False(t, zone.Spec.LinkToParentDomain, "private zone should not be linked to the parent domain")
+					if assert.NotNil(t, zone.Spec.AWS.PrivateZone, "expected a private zone") {
+						assert.Equal(t, []string{"subnet-a", "subnet-b"}, zone.Spec.AWS.PrivateZone.Subnets, "unexpected private zone subnets")
+					}
+				}
 			},
 		},
 		{
-			name: "Ensure managed DNSZone is deleted with cluster deployment",
+			name: "Do not create DNSZone before installer image is resolved",
 			existing: []runtime.Object{
 				func() *hivev1.ClusterDeployment {
-					cd := testDeletedClusterDeployment()
+					cd := testClusterDeployment()
 					cd.Spec.ManageDNS = true
+					cd.Status.InstallerImage = nil
 					return cd
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
-				testDNSZone(),
 			},
 			validate: func(c client.Client, t *testing.T) {
-				dnsZone := getDNSZone(c)
-				assert.Nil(t, dnsZone, "dnsZone should not exist")
+				assert.Nil(t, getDNSZone(c), "dns zone should not be created before the installer image is resolved")
+				assert.NotNil(t, getImageSetJob(c), "expected imageset job to resolve the installer image")
 			},
 		},
 		{
-			name: "Delete cluster deployment with image from clusterimageset",
+			name: "Do not create DNSZone while install prerequisites fail",
 			existing: []runtime.Object{
 				func() *hivev1.ClusterDeployment {
-					cd := testDeletedClusterDeployment()
-					cd.Spec.Images.HiveImage = ""
-					cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.BaseDomain = "example.com"
+					cd.Spec.Ingress = []hivev1.ClusterIngress{
+						{
+							Name:   "default",
+							Domain: "apps.bar.example.org",
+						},
+					}
 					return cd
 				}(),
-				func() *hivev1.ClusterImageSet {
-					cis := testClusterImageSet()
-					testHiveImage := "hive-image-from-image-set:latest"
-					cis.Spec.HiveImage = &testHiveImage
-					return cis
-				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
-				deprovision := getDeprovisionRequest(c)
-				if deprovision == nil {
-					t.Errorf("did not find expected deprovision request")
-				}
+				assert.Nil(t, getDNSZone(c), "dns zone should not be created while install prerequisites fail")
+				assert.Nil(t, getInstallJob(c), "install job should not exist")
 			},
 		},
 		{
-			name: "Delete cluster deployment with missing clusterimageset",
+			name: "Cancel install deletes running install job",
 			existing: []runtime.Object{
 				func() *hivev1.ClusterDeployment {
-					cd := testDeletedClusterDeployment()
-					cd.Spec.Images.HiveImage = ""
-					cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					cd := testClusterDeployment()
+					cd.Annotations[cancelInstallAnnotation] = "true"
 					return cd
 				}(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testInstallJob(),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
-				deprovision := getDeprovisionRequest(c)
-				if deprovision == nil {
-					t.Errorf("did not find expected deprovision request")
+				assert.Nil(t, getInstallJob(c), "install job should have been deleted")
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "clusterdeployment should not be deleted") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.InstallCancelledCondition)
+					if assert.NotNil(t, cond, "missing InstallCancelled condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected InstallCancelled condition status")
+					}
 				}
 			},
 		},
 		{
-			name: "Migrate wildcard ingress domains",
+			name: "Cancel install before install job is created",
 			existing: []runtime.Object{
 				func() *hivev1.ClusterDeployment {
-					cd := testClusterDeploymentWithIngress()
-					cd.Spec.Ingress[0].Domain = fmt.Sprintf("*.apps.%s.example.com", cd.Spec.ClusterName)
-					cd.Spec.Ingress = append(cd.Spec.Ingress, hivev1.ClusterIngress{
-						Name:   "extraingress",
-						Domain: fmt.Sprintf("*.moreingress.%s.example.com", cd.Spec.ClusterName),
-					})
-					cd.Spec.Ingress = append(cd.Spec.Ingress, hivev1.ClusterIngress{
-						Name:   "notwildcardingress",
-						Domain: fmt.Sprintf("notwild.%s.example.com", cd.Spec.ClusterName),
-					})
+					cd := testClusterDeployment()
+					cd.Annotations[cancelInstallAnnotation] = "true"
+					cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+						{
+							Type:   hivev1.InstallCancelledCondition,
+							Status: corev1.ConditionTrue,
+							Reason: installCancelledReason,
+						},
+					}
 					return cd
 				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
 			},
 			validate: func(c client.Client, t *testing.T) {
-				cd := getCD(c)
-				for _, ingress := range cd.Spec.Ingress {
-					assert.NotRegexp(t, `^\*.*`, ingress.Domain, "Ingress domain %s wasn't migrated from wildcards", ingress.Domain)
+				assert.Nil(t, getInstallJob(c), "install job should not be created while install is cancelled")
+			},
+		},
+		{
+			name: "Resume cancelled install when annotation is removed",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+						{
+							Type:   hivev1.InstallCancelledCondition,
+							Status: corev1.ConditionTrue,
+							Reason: installCancelledReason,
+						},
+					}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.InstallCancelledCondition)
+					if assert.NotNil(t, cond, "missing InstallCancelled condition") {
+						assert.Equal(t, corev1.ConditionFalse, cond.Status, "unexpected InstallCancelled condition status")
+					}
 				}
 			},
 		},
 		{
-			name: "Delete old install job when job hash missing",
+			name: "Ignore cancel install for installed cluster",
 			existing: []runtime.Object{
-				testClusterDeployment(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Annotations[cancelInstallAnnotation] = "true"
+					cd.Status.Installed = true
+					cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+					return cd
+				}(),
+				testInstallJob(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
 				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
-				func() *batchv1.Job {
-					job := testInstallJob()
-					delete(job.Annotations, jobHashAnnotation)
-					return job
+				testMetadataConfigMap(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.NotNil(t, getInstallJob(c), "install job of installed cluster should not be deleted")
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.InstallCancelledCondition)
+					assert.Nil(t, cond, "installed cluster should not be marked cancelled")
+				}
+			},
+		},
+		{
+			name: "Create post-install syncset once installed",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.Installed = true
+					cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+					cd.Spec.PostInstallManifests = testPostInstallManifests()
+					return cd
+				}(),
+				testInstallJob(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testMetadataConfigMap(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				ss := getPostInstallSyncSet(c)
+				if assert.NotNil(t, ss, "expected post-install syncset") {
+					assert.Equal(t, testPostInstallManifests(), ss.Spec.Resources, "unexpected syncset resources")
+					assert.Equal(t, []corev1.LocalObjectReference{{Name: testName}}, ss.Spec.ClusterDeploymentRefs, "unexpected syncset cluster deployment refs")
+					if assert.Len(t, ss.OwnerReferences, 1, "expected syncset owner") {
+						assert.Equal(t, "ClusterDeployment", ss.OwnerReferences[0].Kind, "unexpected syncset owner kind")
+						assert.Equal(t, testName, ss.OwnerReferences[0].Name, "unexpected syncset owner")
+					}
+				}
+			},
+		},
+		{
+			name: "No post-install syncset before install completes",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.PostInstallManifests = testPostInstallManifests()
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getPostInstallSyncSet(c), "post-install syncset should not exist before install")
+			},
+		},
+		{
+			name: "Delete post-install syncset when manifests are removed",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.Installed = true
+					cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+					return cd
+				}(),
+				&hivev1.SyncSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      postInstallSyncSetName(testClusterDeployment()),
+						Namespace: testNamespace,
+					},
+					Spec: hivev1.SyncSetSpec{
+						SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+							Resources: testPostInstallManifests(),
+						},
+					},
+				},
+				testInstallJob(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testMetadataConfigMap(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getPostInstallSyncSet(c), "post-install syncset should have been deleted")
+			},
+		},
+		{
+			name: "Create managed DNSZone with record TTL",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.ManagedDNSRecordTTL = hivev1.TTL(30)
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				zone := getDNSZone(c)
+				if assert.NotNil(t, zone, "dns zone should exist") {
+					assert.Equal(t, hivev1.TTL(30), zone.Spec.RecordTTL, "unexpected record TTL on dns zone")
+				}
+			},
+		},
+		{
+			name: "Create managed DNSZone with additional records",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.AdditionalDNSRecords = []hivev1.Endpoint{
+						{
+							DNSName:    "www.cluster.example.com",
+							Targets:    hivev1.Targets{"console.apps.cluster.example.com"},
+							RecordType: "CNAME",
+						},
+					}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				zone := getDNSZone(c)
+				if assert.NotNil(t, zone, "dns zone should exist") && assert.Len(t, zone.Spec.AdditionalRecords, 1, "unexpected additional records on dns zone") {
+					assert.Equal(t, "www.cluster.example.com", zone.Spec.AdditionalRecords[0].DNSName, "unexpected additional record name")
+					assert.Equal(t, "CNAME", zone.Spec.AdditionalRecords[0].RecordType, "unexpected additional record type")
+				}
+			},
+		},
+		{
+			name: "Wait when DNSZone is not available yet",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					return cd
 				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testDNSZone(),
 			},
 			validate: func(c client.Client, t *testing.T) {
 				installJob := getInstallJob(c)
@@ -672,465 +1143,5695 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 			},
 		},
 		{
-			name: "Delete old install job when job hash changes",
+			name: "Create install job when DNSZone is ready",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testAvailableDNSZone(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				installJob := getInstallJob(c)
+				assert.NotNil(t, installJob, "install job should exist")
+			},
+		},
+		{
+			name: "Use managed DNSZone stored with empty collections",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.AWS.Publish = hivev1.InternalPublishingStrategy
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				func() *hivev1.DNSZone {
+					zone := testAvailableDNSZone()
+					zone.Spec.LinkToParentDomain = false
+					zone.Spec.AdditionalRecords = []hivev1.Endpoint{}
+					zone.Spec.AWS.AdditionalTags = []hivev1.AWSResourceTag{}
+					zone.Spec.AWS.PrivateZone = &hivev1.AWSPrivateZone{Subnets: []string{}}
+					return zone
+				}(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				dnsZone := getDNSZone(c)
+				if assert.NotNil(t, dnsZone, "dnsZone should exist") && assert.NotNil(t, dnsZone.Spec.AWS.PrivateZone, "missing private zone") {
+					assert.NotNil(t, dnsZone.Spec.AWS.PrivateZone.Subnets, "dnsZone should not be updated")
+				}
+				assert.NotNil(t, getInstallJob(c), "install job should be created")
+			},
+		},
+		{
+			name: "Update managed DNSZone when the cluster deployment changes",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.ManagedDNSRecordTTL = hivev1.TTL(30)
+					cd.Spec.PlatformSecrets.AWS.DNSCredentials = &corev1.LocalObjectReference{Name: "dns-creds"}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testAvailableDNSZone(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				dnsZone := getDNSZone(c)
+				if assert.NotNil(t, dnsZone, "dnsZone should exist") && assert.NotNil(t, dnsZone.Spec.AWS, "missing AWS zone spec") {
+					assert.Equal(t, hivev1.TTL(30), dnsZone.Spec.RecordTTL, "unexpected record TTL")
+					assert.Equal(t, "dns-creds", dnsZone.Spec.AWS.AccountSecret.Name, "unexpected DNS credentials")
+				}
+				assert.Nil(t, getInstallJob(c), "install job should not be created while the dnsZone is updated")
+			},
+		},
+		{
+			name: "Create managed DNSZone with AWS user tags",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.AWS.UserTags = map[string]string{"owner": "hive"}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				dnsZone := getDNSZone(c)
+				if assert.NotNil(t, dnsZone, "dnsZone should exist") && assert.NotNil(t, dnsZone.Spec.AWS, "missing AWS zone spec") {
+					assert.Equal(t, []hivev1.AWSResourceTag{{Key: "owner", Value: "hive"}}, dnsZone.Spec.AWS.AdditionalTags,
+						"user tags should be added to the dnsZone")
+				}
+			},
+		},
+		{
+			name: "Ensure managed DNSZone is deleted with cluster deployment",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testDeletedClusterDeployment()
+					cd.Spec.ManageDNS = true
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testDNSZone(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				dnsZone := getDNSZone(c)
+				assert.Nil(t, dnsZone, "dnsZone should not exist")
+			},
+		},
+		{
+			name: "Create managed DNSZone with custom name",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.ManagedDNSZoneName = "custom-zone"
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.NotNil(t, getDNSZoneByName(c, "custom-zone"), "dnsZone with custom name should exist")
+				assert.Nil(t, getDNSZone(c), "dnsZone with derived name should not be created")
+			},
+		},
+		{
+			name: "Ensure managed DNSZone with custom name is deleted with cluster deployment",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testDeletedClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.ManagedDNSZoneName = "custom-zone"
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				func() *hivev1.DNSZone {
+					zone := testDNSZone()
+					zone.Name = "custom-zone"
+					return zone
+				}(),
+				testDNSZone(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getDNSZoneByName(c, "custom-zone"), "dnsZone with custom name should be deleted")
+				assert.NotNil(t, getDNSZone(c), "dnsZone with derived name should not be deleted")
+			},
+		},
+		{
+			name: "Refuse managed DNSZone controlled by another cluster deployment",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testConflictingDNSZone(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getInstallJob(c), "install job should not be created with another cluster's zone")
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.DNSZoneConflictCondition)
+					if assert.NotNil(t, cond, "missing DNSZoneConflict condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+						assert.Equal(t, dnsZoneOwnedByOtherReason, cond.Reason, "unexpected condition reason")
+					}
+				}
+			},
+		},
+		{
+			name: "Leave managed DNSZone controlled by another cluster deployment on delete",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testDeletedClusterDeployment()
+					cd.Spec.ManageDNS = true
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testConflictingDNSZone(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.NotNil(t, getDNSZone(c), "dnsZone of another cluster deployment should not be deleted")
+				assert.NotNil(t, getDeprovisionRequest(c), "deprovision should continue")
+			},
+		},
+		{
+			name: "Wait for externally managed DNSZone",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.ManagedDNSZoneRef = &corev1.LocalObjectReference{Name: "external-zone"}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getDNSZone(c), "dnsZone should not be created for an externally managed zone")
+				assert.Nil(t, getInstallJob(c), "install job should not exist")
+			},
+		},
+		{
+			name: "Create install job when externally managed DNSZone is ready",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.ManagedDNSZoneRef = &corev1.LocalObjectReference{Name: testDNSZone().Name}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testAvailableDNSZone(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.NotNil(t, getInstallJob(c), "install job should exist")
+			},
+		},
+		{
+			name: "Externally managed DNSZone is not deleted with cluster deployment",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testDeletedClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.ManagedDNSZoneRef = &corev1.LocalObjectReference{Name: testDNSZone().Name}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testDNSZone(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.NotNil(t, getDNSZone(c), "externally managed dnsZone should not be deleted")
+			},
+		},
+		{
+			name: "Delete cluster deployment with image from clusterimageset",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testDeletedClusterDeployment()
+					cd.Spec.Images.HiveImage = ""
+					cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					return cd
+				}(),
+				func() *hivev1.ClusterImageSet {
+					cis := testClusterImageSet()
+					testHiveImage := "hive-image-from-image-set:latest"
+					cis.Spec.HiveImage = &testHiveImage
+					return cis
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				deprovision := getDeprovisionRequest(c)
+				if deprovision == nil {
+					t.Errorf("did not find expected deprovision request")
+				}
+			},
+		},
+		{
+			name: "Delete cluster deployment with missing clusterimageset",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testDeletedClusterDeployment()
+					cd.Spec.Images.HiveImage = ""
+					cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				deprovision := getDeprovisionRequest(c)
+				if deprovision == nil {
+					t.Errorf("did not find expected deprovision request")
+				}
+			},
+		},
+		{
+			name: "Migrate wildcard ingress domains",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeploymentWithIngress()
+					cd.Spec.Ingress[0].Domain = fmt.Sprintf("*.apps.%s.example.com", cd.Spec.ClusterName)
+					cd.Spec.Ingress = append(cd.Spec.Ingress, hivev1.ClusterIngress{
+						Name:   "extraingress",
+						Domain: fmt.Sprintf("*.moreingress.%s.example.com", cd.Spec.ClusterName),
+					})
+					cd.Spec.Ingress = append(cd.Spec.Ingress, hivev1.ClusterIngress{
+						Name:   "notwildcardingress",
+						Domain: fmt.Sprintf("notwild.%s.example.com", cd.Spec.ClusterName),
+					})
+					return cd
+				}(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				for _, ingress := range cd.Spec.Ingress {
+					assert.NotRegexp(t, `^\*.*`, ingress.Domain, "Ingress domain %s wasn't migrated from wildcards", ingress.Domain)
+				}
+				assert.Equal(t, []hivev1.IngressDomainMigration{
+					{
+						Name: "default",
+						From: fmt.Sprintf("*.apps.%s.example.com", testClusterName),
+						To:   fmt.Sprintf("apps.%s.example.com", testClusterName),
+					},
+					{
+						Name: "extraingress",
+						From: fmt.Sprintf("*.moreingress.%s.example.com", testClusterName),
+						To:   fmt.Sprintf("moreingress.%s.example.com", testClusterName),
+					},
+				}, cd.Status.IngressDomainMigrations, "unexpected ingress domain migrations")
+			},
+		},
+		{
+			name: "Delete old install job when job hash missing",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				func() *batchv1.Job {
+					job := testInstallJob()
+					delete(job.Annotations, jobHashAnnotation)
+					return job
+				}(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				installJob := getInstallJob(c)
+				assert.Nil(t, installJob, "install job should not exist")
+			},
+		},
+		{
+			name: "Delete old install job when job hash changes",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				func() *batchv1.Job {
+					job := testInstallJob()
+					job.Annotations[jobHashAnnotation] = "DIFFERENTHASH"
+					return job
+				}(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				installJob := getInstallJob(c)
+				assert.Nil(t, installJob, "install job should not exist")
+			},
+		},
+		{
+			name: "Ignore old install job hash difference if cluster already installed",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.Installed = true
+					cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				func() *batchv1.Job {
+					job := testInstallJob()
+					job.Annotations[jobHashAnnotation] = "DIFFERENTHASH"
+					return job
+				}(),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				installJob := getInstallJob(c)
+				assert.NotNil(t, installJob, "install job should not be touched after the clusterdeployment is installed")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(test.existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+
+			if test.validate != nil {
+				test.validate(fakeClient, t)
+			}
+
+			if err != nil && !test.expectErr {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if err == nil && test.expectErr {
+				t.Errorf("Expected error but got none")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentReconcileResults(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                     string
+		existing                 []runtime.Object
+		exptectedReconcileResult reconcile.Result
+	}{
+		{
+			name: "Requeue after adding finalizer",
+			existing: []runtime.Object{
+				testClusterDeploymentWithoutFinalizer(),
+			},
+			exptectedReconcileResult: reconcile.Result{},
+		},
+		{
+			name: "Requeue while waiting for externally managed DNSZone",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.ManagedDNSZoneRef = &corev1.LocalObjectReference{Name: testDNSZone().Name}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testDNSZone(),
+			},
+			exptectedReconcileResult: reconcile.Result{RequeueAfter: dnsZoneCheckInterval},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(test.existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			reconcileResult, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+
+			assert.NoError(t, err, "unexpected error")
+
+			assert.Equal(t, test.exptectedReconcileResult, reconcileResult, "unexpected reconcile result")
+		})
+	}
+}
+
+func TestClusterDeploymentConsoleRouteNotFound(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	checkInterval := 2 * time.Minute
+	tests := []struct {
+		name              string
+		existingCondition *hivev1.ClusterDeploymentCondition
+		expectedResult    reconcile.Result
+		expectedReason    string
+	}{
+		{
+			name:           "route not found",
+			expectedResult: reconcile.Result{RequeueAfter: checkInterval},
+			expectedReason: consoleRouteNotFoundReason,
+		},
+		{
+			name: "route still not found",
+			existingCondition: &hivev1.ClusterDeploymentCondition{
+				Type:               hivev1.ConsoleRouteNotFoundCondition,
+				Status:             corev1.ConditionTrue,
+				Reason:             consoleRouteNotFoundReason,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+			},
+			expectedResult: reconcile.Result{RequeueAfter: checkInterval},
+			expectedReason: consoleRouteNotFoundReason,
+		},
+		{
+			name: "route not found within timeout",
+			existingCondition: &hivev1.ClusterDeploymentCondition{
+				Type:               hivev1.ConsoleRouteNotFoundCondition,
+				Status:             corev1.ConditionTrue,
+				Reason:             consoleRouteNotFoundReason,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * consoleRouteWaitTimeout)),
+			},
+			expectedReason: consoleRouteCheckStoppedReason,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Status.Installed = true
+			cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+			if test.existingCondition != nil {
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{*test.existingCondition}
+			}
+			fakeClient := fake.NewFakeClient(
+				cd,
+				testCompletedInstallJob(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testMetadataConfigMap(),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client: fakeClient,
+				scheme: scheme.Scheme,
+				remoteClusterAPIClientBuilder: func(string) (client.Client, error) {
+					// The remote cluster does not have a console route yet.
+					return fake.NewFakeClient(), nil
+				},
+				eventRecorder:             record.NewFakeRecorder(10),
+				consoleRouteCheckInterval: checkInterval,
+			}
+
+			result, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if assert.NoError(t, err, "unexpected error") {
+				assert.Equal(t, test.expectedResult, result, "unexpected reconcile result")
+			}
+
+			cd = &hivev1.ClusterDeployment{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)
+			if assert.NoError(t, err, "missing cluster deployment") {
+				assert.Equal(t, "https://bar-api.clusters.example.com:6443", cd.Status.APIURL, "unexpected API URL")
+				assert.Empty(t, cd.Status.WebConsoleURL, "web console URL should not be set without a console route")
+				condition := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ConsoleRouteNotFoundCondition)
+				if assert.NotNil(t, condition, "missing console route not found condition") {
+					assert.Equal(t, corev1.ConditionTrue, condition.Status, "unexpected condition status")
+					assert.Equal(t, test.expectedReason, condition.Reason, "unexpected condition reason")
+				}
+			}
+		})
+	}
+}
+
+func TestGetConsoleRouteCheckInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{
+			name:     "unset",
+			expected: defaultConsoleRouteCheckInterval,
+		},
+		{
+			name:     "valid",
+			value:    "5m",
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "invalid",
+			value:    "soon",
+			expected: defaultConsoleRouteCheckInterval,
+		},
+		{
+			name:     "negative",
+			value:    "-1m",
+			expected: defaultConsoleRouteCheckInterval,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			os.Setenv(constants.ConsoleRouteCheckIntervalEnvVar, test.value)
+			defer os.Unsetenv(constants.ConsoleRouteCheckIntervalEnvVar)
+			assert.Equal(t, test.expected, getConsoleRouteCheckInterval(), "unexpected check interval")
+		})
+	}
+}
+
+func TestGetImageSetJobResources(t *testing.T) {
+	tests := []struct {
+		name           string
+		cpuRequest     string
+		memoryRequest  string
+		expectedCPU    string
+		expectedMemory string
+	}{
+		{
+			name:           "unset",
+			expectedCPU:    imageset.DefaultCPURequest,
+			expectedMemory: imageset.DefaultMemoryRequest,
+		},
+		{
+			name:           "valid",
+			cpuRequest:     "100m",
+			memoryRequest:  "256Mi",
+			expectedCPU:    "100m",
+			expectedMemory: "256Mi",
+		},
+		{
+			name:           "invalid",
+			cpuRequest:     "lots",
+			memoryRequest:  "-1Gi",
+			expectedCPU:    imageset.DefaultCPURequest,
+			expectedMemory: imageset.DefaultMemoryRequest,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			os.Setenv(constants.ImageSetJobCPURequestEnvVar, test.cpuRequest)
+			defer os.Unsetenv(constants.ImageSetJobCPURequestEnvVar)
+			os.Setenv(constants.ImageSetJobMemoryRequestEnvVar, test.memoryRequest)
+			defer os.Unsetenv(constants.ImageSetJobMemoryRequestEnvVar)
+			resources := getImageSetJobResources()
+			cpu := resources.Requests[corev1.ResourceCPU]
+			memory := resources.Requests[corev1.ResourceMemory]
+			assert.Equal(t, test.expectedCPU, cpu.String(), "unexpected cpu request")
+			assert.Equal(t, test.expectedMemory, memory.String(), "unexpected memory request")
+		})
+	}
+}
+
+func TestClusterDeploymentImageSetJobRecreatedMetric(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	recreatedCount := func() float64 {
+		m := &dto.Metric{}
+		if err := metricImageSetJobsRecreated.WithLabelValues(hivev1.DefaultClusterType).Write(m); err != nil {
+			t.Fatalf("unexpected error reading metric: %v", err)
+		}
+		return m.GetCounter().GetValue()
+	}
+
+	tests := []struct {
+		name           string
+		jobCondition   batchv1.JobConditionType
+		expectRecreate bool
+	}{
+		{
+			name:           "failed job",
+			jobCondition:   batchv1.JobFailed,
+			expectRecreate: true,
+		},
+		{
+			name:           "completed job without installer image",
+			jobCondition:   batchv1.JobComplete,
+			expectRecreate: true,
+		},
+		{
+			name: "job in progress",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Status.InstallerImage = nil
+			cd.Spec.Images.InstallerImage = ""
+			cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+			job := imageset.GenerateImageSetJob(cd, *testClusterImageSet().Spec.ReleaseImage, serviceAccountName,
+				imageset.AlwaysPullImage("cli"), imageset.AlwaysPullImage("hive"), corev1.ResourceRequirements{})
+			if test.jobCondition != "" {
+				job.Status.Conditions = []batchv1.JobCondition{{Type: test.jobCondition, Status: corev1.ConditionTrue}}
+			}
+			fakeClient := fake.NewFakeClient(
+				cd,
+				job,
+				testClusterImageSet(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			before := recreatedCount()
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			if test.expectRecreate {
+				assert.Nil(t, getJob(fakeClient, imageSetJobName), "finished imageset job should be deleted")
+				assert.Equal(t, before+1, recreatedCount(), "expected recreated counter to increment")
+			} else {
+				assert.NotNil(t, getJob(fakeClient, imageSetJobName), "imageset job in progress should be kept")
+				assert.Equal(t, before, recreatedCount(), "recreated counter should not change")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentInstallJobCompletedBeforeSucceededCount(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	openshiftapiv1.Install(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	observedDurations := func() uint64 {
+		m := &dto.Metric{}
+		if err := metricInstallJobDuration.Write(m); err != nil {
+			t.Fatalf("unexpected error reading metric: %v", err)
+		}
+		return m.GetHistogram().GetSampleCount()
+	}
+
+	// The job has the JobComplete condition, but its Succeeded count has not been updated yet.
+	job := testCompletedInstallJob()
+	started := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	completed := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	job.Status.StartTime = &started
+	job.Status.CompletionTime = &completed
+	job.Status.Succeeded = 0
+
+	fakeClient := fake.NewFakeClient(
+		testClusterDeployment(),
+		job,
+		testMetadataConfigMap(),
+		testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        fakeClient,
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 record.NewFakeRecorder(10),
+	}
+	namespacedName := types.NamespacedName{Name: testName, Namespace: testNamespace}
+
+	before := observedDurations()
+	for i := 0; i < 2; i++ {
+		_, err := rcd.Reconcile(reconcile.Request{NamespacedName: namespacedName})
+		if !assert.NoError(t, err, "unexpected error from reconcile %d", i) {
+			return
+		}
+	}
+
+	cd := &hivev1.ClusterDeployment{}
+	if assert.NoError(t, fakeClient.Get(context.TODO(), namespacedName, cd)) {
+		assert.True(t, cd.Status.Installed, "cluster should be installed once the job has the JobComplete condition")
+		if assert.NotNil(t, cd.Status.InstalledTimestamp, "installed timestamp should be set") {
+			assert.True(t, cd.Status.InstalledTimestamp.Equal(&completed), "installed timestamp should be the job completion time")
+		}
+	}
+	assert.Equal(t, before+1, observedDurations(), "install job duration should be observed exactly once")
+}
+
+func TestClusterDeploymentImageSetJobTTL(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name        string
+		ttl         int32
+		expectedTTL int32
+	}{
+		{
+			name:        "default ttl",
+			expectedTTL: imageset.DefaultJobTTLSecondsAfterFinished,
+		},
+		{
+			name:        "configured ttl",
+			ttl:         600,
+			expectedTTL: 600,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Status.InstallerImage = nil
+			cd.Spec.Images.InstallerImage = ""
+			cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+			fakeClient := fake.NewFakeClient(
+				cd,
+				testClusterImageSet(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				imageSetJobTTL:                test.ttl,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			job := getJob(fakeClient, imageSetJobName)
+			if assert.NotNil(t, job, "expected imageset job") && assert.NotNil(t, job.Spec.TTLSecondsAfterFinished, "missing ttl") {
+				assert.Equal(t, test.expectedTTL, *job.Spec.TTLSecondsAfterFinished, "unexpected ttl")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentImageResolutionTimeout(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	const timeout = 30 * time.Minute
+
+	timedOutCondition := func(status corev1.ConditionStatus, transition time.Time) *hivev1.ClusterDeploymentCondition {
+		return &hivev1.ClusterDeploymentCondition{
+			Type:               hivev1.ImageResolutionTimedOutCondition,
+			Status:             status,
+			LastTransitionTime: metav1.NewTime(transition),
+		}
+	}
+
+	tests := []struct {
+		name                       string
+		created                    time.Duration
+		generation                 int64
+		timedOutGeneration         int64
+		condition                  *hivev1.ClusterDeploymentCondition
+		existingJob                bool
+		defaultPullSecret          bool
+		expectJob                  bool
+		expectRequeue              bool
+		expectedStatus             corev1.ConditionStatus
+		expectedReason             string
+		expectedTimedOutGeneration int64
+	}{
+		{
+			name:      "within timeout creates job",
+			created:   10 * time.Minute,
+			expectJob: true,
+		},
+		{
+			name:          "within timeout requeues for deadline",
+			created:       10 * time.Minute,
+			existingJob:   true,
+			expectJob:     true,
+			expectRequeue: true,
+		},
+		{
+			name:                       "timed out deletes job",
+			created:                    time.Hour,
+			generation:                 2,
+			existingJob:                true,
+			expectedStatus:             corev1.ConditionTrue,
+			expectedReason:             imageResolutionTimedOutReason,
+			expectedTimedOutGeneration: 2,
+		},
+		{
+			name:                       "timed out with default pull secret",
+			created:                    time.Hour,
+			generation:                 2,
+			existingJob:                true,
+			defaultPullSecret:          true,
+			expectedStatus:             corev1.ConditionTrue,
+			expectedReason:             imageResolutionTimedOutReason,
+			expectedTimedOutGeneration: 2,
+		},
+		{
+			name:                       "timed out does not recreate job",
+			created:                    time.Hour,
+			generation:                 2,
+			timedOutGeneration:         2,
+			condition:                  timedOutCondition(corev1.ConditionTrue, time.Now()),
+			expectedStatus:             corev1.ConditionTrue,
+			expectedTimedOutGeneration: 2,
+		},
+		{
+			name:               "spec change restarts resolution",
+			created:            time.Hour,
+			generation:         3,
+			timedOutGeneration: 2,
+			condition:          timedOutCondition(corev1.ConditionTrue, time.Now()),
+			expectedStatus:     corev1.ConditionFalse,
+			expectedReason:     imageResolutionRestartedReason,
+		},
+		{
+			name:           "restarted resolution creates job",
+			created:        2 * time.Hour,
+			generation:     3,
+			condition:      timedOutCondition(corev1.ConditionFalse, time.Now().Add(-time.Minute)),
+			expectJob:      true,
+			expectedStatus: corev1.ConditionFalse,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.CreationTimestamp = metav1.NewTime(time.Now().Add(-test.created))
+			cd.Generation = test.generation
+			cd.Status.InstallerImage = nil
+			cd.Spec.Images.InstallerImage = ""
+			cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+			cd.Status.ImageResolutionTimedOutGeneration = test.timedOutGeneration
+			if test.condition != nil {
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{*test.condition}
+			}
+			existing := []runtime.Object{
+				cd,
+				testClusterImageSet(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			}
+			var defaultPullSecret string
+			if test.defaultPullSecret {
+				cd.Spec.PullSecret = corev1.LocalObjectReference{}
+				secret := testSecret(corev1.SecretTypeDockerConfigJson, "default-pull-secret", corev1.DockerConfigJsonKey, testPullSecretData)
+				secret.Namespace = hiveNamespace
+				existing = append(existing, secret)
+				defaultPullSecret = secret.Name
+			}
+			if test.existingJob {
+				existing = append(existing, imageset.GenerateImageSetJob(cd, *testClusterImageSet().Spec.ReleaseImage, serviceAccountName,
+					imageset.AlwaysPullImage("cli"), imageset.AlwaysPullImage("hive"), corev1.ResourceRequirements{}))
+			}
+			fakeClient := &updateRecordingClient{Client: fake.NewFakeClient(existing...)}
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				imageResolutionTimeout:        timeout,
+				defaultPullSecret:             defaultPullSecret,
+			}
+
+			result, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			if test.expectJob {
+				assert.NotNil(t, getJob(fakeClient, imageSetJobName), "expected imageset job")
+			} else {
+				assert.Nil(t, getJob(fakeClient, imageSetJobName), "unexpected imageset job")
+			}
+			if test.expectRequeue {
+				assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= timeout-10*time.Minute, "unexpected requeue %v", result.RequeueAfter)
+			}
+
+			updated := &hivev1.ClusterDeployment{}
+			if err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: testName, Namespace: testNamespace}, updated); err != nil {
+				t.Fatalf("unexpected error getting cluster deployment: %v", err)
+			}
+			condition := controllerutils.FindClusterDeploymentCondition(updated.Status.Conditions, hivev1.ImageResolutionTimedOutCondition)
+			if test.expectedStatus == "" {
+				assert.Nil(t, condition, "unexpected ImageResolutionTimedOut condition")
+			} else if assert.NotNil(t, condition, "missing ImageResolutionTimedOut condition") {
+				assert.Equal(t, test.expectedStatus, condition.Status, "unexpected condition status")
+				if test.expectedReason != "" {
+					assert.Equal(t, test.expectedReason, condition.Reason, "unexpected condition reason")
+				}
+			}
+			assert.Equal(t, test.expectedTimedOutGeneration, updated.Status.ImageResolutionTimedOutGeneration, "unexpected timed out generation")
+			assert.Zero(t, fakeClient.clusterDeploymentUpdates, "the cluster deployment should not be updated, only its status")
+		})
+	}
+}
+
+func TestClusterDeploymentInstallerImageCache(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	const (
+		secondName        = "bar"
+		resolvedInstaller = "resolved-installer-image:latest"
+	)
+	secondJobName := imageset.GetImageSetJobName(secondName)
+
+	tests := []struct {
+		name                    string
+		jobReleaseImage         string
+		imageSetReleaseImage    string
+		expectCached            bool
+		expectSecondJob         bool
+		expectedSecondInstaller *string
+	}{
+		{
+			name:                    "second cluster reuses resolved installer image",
+			jobReleaseImage:         "test-release-image:latest",
+			imageSetReleaseImage:    "test-release-image:latest",
+			expectCached:            true,
+			expectedSecondInstaller: strPtr(resolvedInstaller),
+		},
+		{
+			name:                 "release image changed since resolution",
+			jobReleaseImage:      "old-release-image:latest",
+			imageSetReleaseImage: "test-release-image:latest",
+			expectSecondJob:      true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			imageSet := testClusterImageSet()
+			imageSet.Spec.ReleaseImage = strPtr(test.imageSetReleaseImage)
+
+			first := testClusterDeployment()
+			first.Spec.Images.InstallerImage = ""
+			first.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+			first.Status.InstallerImage = strPtr(resolvedInstaller)
+			firstJob := imageset.GenerateImageSetJob(first, test.jobReleaseImage, serviceAccountName,
+				imageset.AlwaysPullImage("cli"), imageset.AlwaysPullImage("hive"), corev1.ResourceRequirements{})
+
+			second := testClusterDeployment()
+			second.Name = secondName
+			second.UID = types.UID("5678")
+			second.Spec.Images.InstallerImage = ""
+			second.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+			second.Status.InstallerImage = nil
+
+			fakeClient := fake.NewFakeClient(
+				first,
+				second,
+				firstJob,
+				imageSet,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			for _, name := range []string{testName, secondName} {
+				_, err := rcd.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      name,
+						Namespace: testNamespace,
+					},
+				})
+				if !assert.NoError(t, err, "unexpected error reconciling %s", name) {
+					return
+				}
+			}
+
+			updatedImageSet := &hivev1.ClusterImageSet{}
+			if err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: testClusterImageSetName}, updatedImageSet); err != nil {
+				t.Fatalf("unexpected error getting clusterimageset: %v", err)
+			}
+			if test.expectCached {
+				if assert.NotNil(t, updatedImageSet.Status.InstallerImage, "expected cached installer image") {
+					assert.Equal(t, resolvedInstaller, *updatedImageSet.Status.InstallerImage, "unexpected cached installer image")
+				}
+				assert.Equal(t, test.imageSetReleaseImage, updatedImageSet.Status.ReleaseImage, "unexpected cached release image")
+			} else {
+				assert.Nil(t, updatedImageSet.Status.InstallerImage, "unexpected cached installer image")
+			}
+
+			if test.expectSecondJob {
+				assert.NotNil(t, getJob(fakeClient, secondJobName), "expected imageset job for second cluster")
+			} else {
+				assert.Nil(t, getJob(fakeClient, secondJobName), "unexpected imageset job for second cluster")
+			}
+			updatedSecond := &hivev1.ClusterDeployment{}
+			if err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: secondName, Namespace: testNamespace}, updatedSecond); err != nil {
+				t.Fatalf("unexpected error getting cluster deployment: %v", err)
+			}
+			assert.Equal(t, test.expectedSecondInstaller, updatedSecond.Status.InstallerImage, "unexpected installer image for second cluster")
+		})
+	}
+}
+
+func TestCachedInstallerImage(t *testing.T) {
+	tests := []struct {
+		name         string
+		imageSet     *hivev1.ClusterImageSet
+		releaseImage string
+		expected     *string
+	}{
+		{
+			name:         "no imageset",
+			releaseImage: "release:1",
+		},
+		{
+			name: "not resolved",
+			imageSet: &hivev1.ClusterImageSet{
+				Spec: hivev1.ClusterImageSetSpec{ReleaseImage: strPtr("release:1")},
+			},
+			releaseImage: "release:1",
+		},
+		{
+			name: "resolved",
+			imageSet: &hivev1.ClusterImageSet{
+				Spec:   hivev1.ClusterImageSetSpec{ReleaseImage: strPtr("release:1")},
+				Status: hivev1.ClusterImageSetStatus{InstallerImage: strPtr("installer:1"), ReleaseImage: "release:1"},
+			},
+			releaseImage: "release:1",
+			expected:     strPtr("installer:1"),
+		},
+		{
+			name: "imageset release image changed",
+			imageSet: &hivev1.ClusterImageSet{
+				Spec:   hivev1.ClusterImageSetSpec{ReleaseImage: strPtr("release:2")},
+				Status: hivev1.ClusterImageSetStatus{InstallerImage: strPtr("installer:1"), ReleaseImage: "release:1"},
+			},
+			releaseImage: "release:2",
+		},
+		{
+			name: "cluster deployment overrides release image",
+			imageSet: &hivev1.ClusterImageSet{
+				Spec:   hivev1.ClusterImageSetSpec{ReleaseImage: strPtr("release:1")},
+				Status: hivev1.ClusterImageSetStatus{InstallerImage: strPtr("installer:1"), ReleaseImage: "release:1"},
+			},
+			releaseImage: "custom-release:1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, cachedInstallerImage(test.imageSet, test.releaseImage))
+		})
+	}
+}
+
+// updateRecordingClient counts the updates of cluster deployments, not including status updates.
+type updateRecordingClient struct {
+	client.Client
+	clusterDeploymentUpdates int
+}
+
+func (c *updateRecordingClient) Update(ctx context.Context, obj runtime.Object) error {
+	if _, ok := obj.(*hivev1.ClusterDeployment); ok {
+		c.clusterDeploymentUpdates++
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+// deleteRecordingClient records the propagation policy of each deleted job.
+type deleteRecordingClient struct {
+	client.Client
+	jobPropagation map[string]metav1.DeletionPropagation
+}
+
+func (c *deleteRecordingClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOptionFunc) error {
+	if job, ok := obj.(*batchv1.Job); ok {
+		deleteOpts := (&client.DeleteOptions{}).ApplyOptions(opts)
+		if deleteOpts.PropagationPolicy != nil {
+			c.jobPropagation[job.Name] = *deleteOpts.PropagationPolicy
+		}
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestClusterDeploymentJobDeletionPropagation(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cancelledInstall := func() (*hivev1.ClusterDeployment, *batchv1.Job) {
+		cd := testClusterDeployment()
+		cd.Annotations[cancelInstallAnnotation] = "true"
+		return cd, testInstallJob()
+	}
+	deletedCluster := func() (*hivev1.ClusterDeployment, *batchv1.Job) {
+		return testDeletedClusterDeployment(), testInstallJob()
+	}
+	finishedImageSetJob := func() (*hivev1.ClusterDeployment, *batchv1.Job) {
+		cd := testClusterDeployment()
+		cd.Status.InstallerImage = nil
+		cd.Spec.Images.InstallerImage = ""
+		cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+		job := imageset.GenerateImageSetJob(cd, *testClusterImageSet().Spec.ReleaseImage, serviceAccountName,
+			imageset.AlwaysPullImage("cli"), imageset.AlwaysPullImage("hive"), corev1.ResourceRequirements{})
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+		return cd, job
+	}
+
+	tests := []struct {
+		name                string
+		setup               func() (*hivev1.ClusterDeployment, *batchv1.Job)
+		propagation         metav1.DeletionPropagation
+		expectedPropagation metav1.DeletionPropagation
+	}{
+		{
+			name:                "install job default",
+			setup:               cancelledInstall,
+			expectedPropagation: metav1.DeletePropagationForeground,
+		},
+		{
+			name:                "install job background",
+			setup:               cancelledInstall,
+			propagation:         metav1.DeletePropagationBackground,
+			expectedPropagation: metav1.DeletePropagationBackground,
+		},
+		{
+			name:                "install job of deleted cluster background",
+			setup:               deletedCluster,
+			propagation:         metav1.DeletePropagationBackground,
+			expectedPropagation: metav1.DeletePropagationForeground,
+		},
+		{
+			name:                "imageset job default",
+			setup:               finishedImageSetJob,
+			expectedPropagation: metav1.DeletePropagationForeground,
+		},
+		{
+			name:                "imageset job foreground",
+			setup:               finishedImageSetJob,
+			propagation:         metav1.DeletePropagationForeground,
+			expectedPropagation: metav1.DeletePropagationForeground,
+		},
+		{
+			name:                "imageset job background",
+			setup:               finishedImageSetJob,
+			propagation:         metav1.DeletePropagationBackground,
+			expectedPropagation: metav1.DeletePropagationBackground,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd, job := test.setup()
+			fakeClient := &deleteRecordingClient{
+				Client: fake.NewFakeClient(
+					cd,
+					job,
+					testClusterImageSet(),
+					testPullSecret(),
+					testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				),
+				jobPropagation: map[string]metav1.DeletionPropagation{},
+			}
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				jobDeletionPropagation:        test.propagation,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			assert.Nil(t, getJob(fakeClient, job.Name), "job should have been deleted")
+			propagation, ok := fakeClient.jobPropagation[job.Name]
+			if assert.True(t, ok, "job was not deleted with a propagation policy") {
+				assert.Equal(t, test.expectedPropagation, propagation, "unexpected propagation policy")
+			}
+		})
+	}
+}
+
+func TestGetJobDeletionPropagation(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected metav1.DeletionPropagation
+	}{
+		{value: "", expected: metav1.DeletePropagationForeground},
+		{value: "Foreground", expected: metav1.DeletePropagationForeground},
+		{value: "Background", expected: metav1.DeletePropagationBackground},
+		{value: "Orphan", expected: metav1.DeletePropagationForeground},
+		{value: "background", expected: metav1.DeletePropagationForeground},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			os.Setenv(constants.JobDeletionPropagationEnvVar, test.value)
+			defer os.Unsetenv(constants.JobDeletionPropagationEnvVar)
+			assert.Equal(t, test.expected, getJobDeletionPropagation())
+		})
+	}
+}
+
+func TestClusterDeploymentForeignFinalizer(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	const foreignFinalizer = "example.com/cleanup"
+
+	tests := []struct {
+		name               string
+		cd                 *hivev1.ClusterDeployment
+		existing           []runtime.Object
+		expectedFinalizers []string
+		expectRequest      bool
+		expectUnchanged    bool
+	}{
+		{
+			name: "deprovision with foreign finalizer",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testDeletedClusterDeployment()
+				cd.Finalizers = []string{foreignFinalizer, hivev1.FinalizerDeprovision}
+				return cd
+			}(),
+			expectedFinalizers: []string{foreignFinalizer, hivev1.FinalizerDeprovision},
+			expectRequest:      true,
+		},
+		{
+			name: "completed deprovision leaves foreign finalizer",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testDeletedClusterDeployment()
+				cd.Finalizers = []string{foreignFinalizer, hivev1.FinalizerDeprovision}
+				return cd
+			}(),
+			existing: []runtime.Object{
+				&hivev1.ClusterDeprovisionRequest{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testName,
+						Namespace: testNamespace,
+					},
+					Status: hivev1.ClusterDeprovisionRequestStatus{
+						Completed: true,
+					},
+				},
+			},
+			expectedFinalizers: []string{foreignFinalizer},
+			expectRequest:      true,
+		},
+		{
+			name: "skipped deprovision leaves foreign finalizer",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testDeletedClusterDeployment()
+				cd.Finalizers = []string{hivev1.FinalizerDeprovision, foreignFinalizer}
+				cd.Status.InfraID = ""
+				return cd
+			}(),
+			expectedFinalizers: []string{foreignFinalizer},
+		},
+		{
+			name: "wait for foreign finalizer after cleanup",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testDeletedClusterDeployment()
+				cd.Finalizers = []string{foreignFinalizer}
+				cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: "missing-imageset"}
+				return cd
+			}(),
+			expectedFinalizers: []string{foreignFinalizer},
+			expectUnchanged:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := append(test.existing,
+				test.cd,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+			namespacedName := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			if _, err := rcd.Reconcile(reconcile.Request{NamespacedName: namespacedName}); !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			if assert.NoError(t, fakeClient.Get(context.TODO(), namespacedName, cd)) {
+				assert.Equal(t, test.expectedFinalizers, cd.Finalizers, "unexpected finalizers")
+				if test.expectUnchanged {
+					assert.Equal(t, test.cd.Status, cd.Status, "status should not change")
+				}
+			}
+			request := &hivev1.ClusterDeprovisionRequest{}
+			err := fakeClient.Get(context.TODO(), namespacedName, request)
+			if test.expectRequest {
+				assert.NoError(t, err, "expected deprovision request")
+			} else {
+				assert.True(t, errors.IsNotFound(err), "unexpected deprovision request")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentNamespaceTerminating(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name              string
+		terminating       bool
+		manageDNS         bool
+		existing          []runtime.Object
+		expectFinalizer   bool
+		expectRequest     bool
+		expectInstallJob  bool
+		expectEventReason string
+	}{
+		{
+			name:              "terminating namespace skips deprovision",
+			terminating:       true,
+			manageDNS:         true,
+			existing:          []runtime.Object{testDNSZone(), testInstallJob()},
+			expectInstallJob:  true,
+			expectEventReason: namespaceTerminatingReason,
+		},
+		{
+			name:        "terminating namespace with deprovision underway",
+			terminating: true,
+			existing: []runtime.Object{
+				&hivev1.ClusterDeprovisionRequest{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      testName,
+						Namespace: testNamespace,
+					},
+				},
+			},
+			expectRequest:     true,
+			expectEventReason: namespaceTerminatingReason,
+		},
+		{
+			name:            "active namespace deprovisions",
+			expectFinalizer: true,
+			expectRequest:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testDeletedClusterDeployment()
+			cd.Spec.ManageDNS = test.manageDNS
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+			if test.terminating {
+				now := metav1.Now()
+				ns.DeletionTimestamp = &now
+			}
+			existing := append(test.existing,
+				cd,
+				ns,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			fakeClient := fake.NewFakeClient(existing...)
+			recorder := record.NewFakeRecorder(10)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 recorder,
+			}
+			namespacedName := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			if _, err := rcd.Reconcile(reconcile.Request{NamespacedName: namespacedName}); !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd = &hivev1.ClusterDeployment{}
+			if assert.NoError(t, fakeClient.Get(context.TODO(), namespacedName, cd)) {
+				assert.Equal(t, test.expectFinalizer, controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision), "unexpected finalizer state")
+			}
+			request := &hivev1.ClusterDeprovisionRequest{}
+			err := fakeClient.Get(context.TODO(), namespacedName, request)
+			if test.expectRequest {
+				assert.NoError(t, err, "expected deprovision request")
+			} else {
+				assert.True(t, errors.IsNotFound(err), "unexpected deprovision request")
+			}
+			if test.expectInstallJob {
+				assert.NotNil(t, getInstallJob(fakeClient), "install job should be left for the namespace deletion")
+			}
+			if test.expectEventReason != "" {
+				select {
+				case event := <-recorder.Events:
+					assert.Contains(t, event, test.expectEventReason, "unexpected event")
+				default:
+					t.Errorf("expected %s event", test.expectEventReason)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentDeprovisionSkipped(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name           string
+		cd             func() *hivev1.ClusterDeployment
+		terminating    bool
+		expectedReason string
+	}{
+		{
+			name: "preserve on delete",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testDeletedClusterDeployment()
+				cd.Spec.PreserveOnDelete = true
+				cd.Status.Installed = true
+				return cd
+			},
+			expectedReason: preserveOnDeleteReason,
+		},
+		{
+			name: "no infra ID",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testDeletedClusterDeployment()
+				cd.Status.InfraID = ""
+				return cd
+			},
+			expectedReason: noInfraIDReason,
+		},
+		{
+			name: "unsupported platform",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testDeletedClusterDeployment()
+				cd.Spec.Platform = hivev1.Platform{Libvirt: &hivev1.LibvirtPlatform{}}
+				return cd
+			},
+			expectedReason: unsupportedPlatformReason,
+		},
+		{
+			name:           "terminating namespace",
+			cd:             testDeletedClusterDeployment,
+			terminating:    true,
+			expectedReason: namespaceTerminatingReason,
+		},
+		{
+			name: "preserve on delete before install",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testDeletedClusterDeployment()
+				cd.Spec.PreserveOnDelete = true
+				return cd
+			},
+		},
+		{
+			name: "deprovisioned",
+			cd:   testDeletedClusterDeployment,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+			if test.terminating {
+				now := metav1.Now()
+				ns.DeletionTimestamp = &now
+			}
+			fakeClient := fake.NewFakeClient(
+				test.cd(),
+				ns,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			recorder := record.NewFakeRecorder(10)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 recorder,
+			}
+			namespacedName := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			if _, err := rcd.Reconcile(reconcile.Request{NamespacedName: namespacedName}); !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			if !assert.NoError(t, fakeClient.Get(context.TODO(), namespacedName, cd)) {
+				return
+			}
+			condition := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.DeprovisionSkippedCondition)
+			request := &hivev1.ClusterDeprovisionRequest{}
+			requestErr := fakeClient.Get(context.TODO(), namespacedName, request)
+			if test.expectedReason == "" {
+				assert.Nil(t, condition, "unexpected DeprovisionSkipped condition")
+				assert.NoError(t, requestErr, "expected deprovision request")
+				assert.True(t, controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision), "finalizer should be kept until deprovisioned")
+				return
+			}
+			if assert.NotNil(t, condition, "missing DeprovisionSkipped condition") {
+				assert.Equal(t, corev1.ConditionTrue, condition.Status, "unexpected condition status")
+				assert.Equal(t, test.expectedReason, condition.Reason, "unexpected condition reason")
+			}
+			assert.True(t, errors.IsNotFound(requestErr), "unexpected deprovision request")
+			assert.False(t, controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision), "finalizer should be removed")
+			select {
+			case event := <-recorder.Events:
+				assert.Contains(t, event, test.expectedReason, "unexpected event")
+			default:
+				t.Errorf("expected %s event", test.expectedReason)
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentDeprovisionProgress(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cd := testDeletedClusterDeployment()
+	cd.Spec.ManageDNS = true
+	fakeClient := fake.NewFakeClient(
+		cd,
+		testDNSZone(),
+		testInstallJob(),
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+	)
+	namespacedName := types.NamespacedName{Name: testName, Namespace: testNamespace}
+
+	// Each step is reconciled by a new reconciler, as would happen after a restart of the controller.
+	reconcileAfterRestart := func() {
+		rcd := &ReconcileClusterDeployment{
+			Client:                        fakeClient,
+			scheme:                        scheme.Scheme,
+			remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+			eventRecorder:                 record.NewFakeRecorder(10),
+		}
+		if _, err := rcd.Reconcile(reconcile.Request{NamespacedName: namespacedName}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	getCD := func() *hivev1.ClusterDeployment {
+		cd := &hivev1.ClusterDeployment{}
+		if err := fakeClient.Get(context.TODO(), namespacedName, cd); err != nil {
+			t.Fatalf("unexpected error getting clusterdeployment: %v", err)
+		}
+		return cd
+	}
+	getRequest := func() *hivev1.ClusterDeprovisionRequest {
+		request := &hivev1.ClusterDeprovisionRequest{}
+		if err := fakeClient.Get(context.TODO(), namespacedName, request); err != nil {
+			return nil
+		}
+		return request
+	}
+
+	reconcileAfterRestart()
+	assert.Equal(t, hivev1.DeprovisionProgressDeletingDNSZone, getCD().Status.DeprovisionProgress, "unexpected progress after dnszone step")
+	assert.NotNil(t, getInstallJob(fakeClient), "install job should not be deleted before the dnszone")
+
+	reconcileAfterRestart()
+	assert.Equal(t, hivev1.DeprovisionProgressDeletingInstallJob, getCD().Status.DeprovisionProgress, "unexpected progress after install job step")
+	assert.Nil(t, getInstallJob(fakeClient), "install job should be deleted")
+	assert.Nil(t, getRequest(), "deprovision request should not be created before the install job is deleted")
+
+	reconcileAfterRestart()
+	assert.Equal(t, hivev1.DeprovisionProgressDeprovisioning, getCD().Status.DeprovisionProgress, "unexpected progress after deprovision request step")
+	assert.NotNil(t, getRequest(), "deprovision request should be created")
+
+	reconcileAfterRestart()
+	assert.Equal(t, hivev1.DeprovisionProgressDeprovisioning, getCD().Status.DeprovisionProgress, "progress should not change while deprovisioning")
+	assert.True(t, controllerutils.HasFinalizer(getCD(), hivev1.FinalizerDeprovision), "finalizer should remain while deprovisioning")
+
+	request := getRequest()
+	request.Status.Completed = true
+	if err := fakeClient.Status().Update(context.TODO(), request); err != nil {
+		t.Fatalf("unexpected error updating deprovision request: %v", err)
+	}
+	reconcileAfterRestart()
+	cd = getCD()
+	assert.Equal(t, hivev1.DeprovisionProgressCompleted, cd.Status.DeprovisionProgress, "unexpected progress after deprovision completed")
+	assert.False(t, controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision), "finalizer should be removed")
+}
+
+// staleDeprovisionRequestClient reports deprovision requests as missing, like a cache which has not yet caught
+// up with a request created before a restart.
+type staleDeprovisionRequestClient struct {
+	client.Client
+}
+
+func (c *staleDeprovisionRequestClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if _, ok := obj.(*hivev1.ClusterDeprovisionRequest); ok {
+		return errors.NewNotFound(hivev1.Resource("clusterdeprovisionrequest"), key.Name)
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func TestClusterDeploymentDeprovisionRequestStaleCache(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cd := testDeletedClusterDeployment()
+	cd.Status.DeprovisionProgress = hivev1.DeprovisionProgressDeprovisioning
+	fakeClient := fake.NewFakeClient(
+		cd,
+		testDeprovisionRequest(t, cd),
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        &staleDeprovisionRequestClient{Client: fakeClient},
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 record.NewFakeRecorder(10),
+	}
+	result, err := rcd.Reconcile(reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: testName, Namespace: testNamespace},
+	})
+	if assert.NoError(t, err, "an existing deprovision request should not be an error") {
+		assert.Equal(t, defaultRequeueTime, result.RequeueAfter, "expected requeue to wait for the cache")
+	}
+}
+
+// entryRecorder is a logrus hook which records the entries logged with the given message.
+type entryRecorder struct {
+	message string
+	entries []*log.Entry
+}
+
+func (h *entryRecorder) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *entryRecorder) Fire(entry *log.Entry) error {
+	if entry.Message == h.message {
+		h.entries = append(h.entries, entry)
+	}
+	return nil
+}
+
+func TestClusterDeploymentDeprovisionDeletedResources(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cd := testDeletedClusterDeployment()
+	cd.Status.DeprovisionProgress = hivev1.DeprovisionProgressDeprovisioning
+	request := testDeprovisionRequest(t, cd)
+	request.Status.Completed = true
+	request.Status.DeletedResources = map[string]int{"ec2:instance": 3, "s3": 1}
+	fakeClient := fake.NewFakeClient(
+		cd,
+		request,
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+	)
+	recorder := record.NewFakeRecorder(10)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        fakeClient,
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 recorder,
+	}
+	entries := &entryRecorder{message: "deprovision deleted cloud resources"}
+	hooks := log.StandardLogger().ReplaceHooks(make(log.LevelHooks))
+	defer log.StandardLogger().ReplaceHooks(hooks)
+	log.AddHook(entries)
+
+	_, err := rcd.Reconcile(reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: testName, Namespace: testNamespace},
+	})
+	if !assert.NoError(t, err, "unexpected error") {
+		return
+	}
+
+	if assert.Len(t, entries.entries, 1, "expected the deleted resources to be logged once") {
+		data := entries.entries[0].Data
+		assert.Equal(t, 3, data["ec2:instance"], "unexpected ec2:instance count")
+		assert.Equal(t, 1, data["s3"], "unexpected s3 count")
+		assert.Equal(t, 4, data["total"], "unexpected total count")
+	}
+	select {
+	case event := <-recorder.Events:
+		assert.Equal(t, "Normal DeprovisionCompleted deprovision deleted 4 cloud resources: ec2:instance=3, s3=1", event, "unexpected event")
+	default:
+		t.Error("expected an event with the deleted resources")
+	}
+}
+
+func TestClusterDeploymentDeprovisionRetry(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cd := testDeletedClusterDeployment()
+	fakeClient := fake.NewFakeClient(
+		cd,
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        fakeClient,
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 record.NewFakeRecorder(10),
+	}
+	reconcileRequest := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+	}
+	reconcileCD := func() {
+		if _, err := rcd.Reconcile(reconcileRequest); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	getCD := func() *hivev1.ClusterDeployment {
+		cd := &hivev1.ClusterDeployment{}
+		if err := fakeClient.Get(context.TODO(), reconcileRequest.NamespacedName, cd); err != nil {
+			t.Fatalf("unexpected error getting clusterdeployment: %v", err)
+		}
+		return cd
+	}
+	getRequest := func() *hivev1.ClusterDeprovisionRequest {
+		request := &hivev1.ClusterDeprovisionRequest{}
+		if err := fakeClient.Get(context.TODO(), reconcileRequest.NamespacedName, request); err != nil {
+			return nil
+		}
+		return request
+	}
+	failRequest := func() {
+		request := getRequest()
+		if request == nil {
+			t.Fatalf("missing deprovision request")
+		}
+		request.Status.Failed = true
+		if err := fakeClient.Status().Update(context.TODO(), request); err != nil {
+			t.Fatalf("unexpected error updating deprovision request: %v", err)
+		}
+	}
+
+	reconcileCD()
+	assert.NotNil(t, getRequest(), "deprovision request should be created")
+
+	for attempt := 2; attempt <= maxDeprovisionAttempts; attempt++ {
+		failRequest()
+		reconcileCD()
+		assert.Nil(t, getRequest(), "failed deprovision request should be deleted")
+		assert.Equal(t, strconv.Itoa(attempt), getCD().Annotations[deprovisionAttemptsAnnotation], "unexpected deprovision attempts")
+
+		reconcileCD()
+		if request := getRequest(); assert.NotNil(t, request, "deprovision request should be recreated") {
+			assert.False(t, request.Status.Failed, "recreated deprovision request should not be failed")
+		}
+	}
+
+	failRequest()
+	reconcileCD()
+	assert.NotNil(t, getRequest(), "failed deprovision request should be kept once attempts are exhausted")
+	cd = getCD()
+	assert.Equal(t, strconv.Itoa(maxDeprovisionAttempts), cd.Annotations[deprovisionAttemptsAnnotation], "unexpected deprovision attempts")
+	assert.True(t, controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision), "finalizer should not be removed")
+	condition := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.DeprovisionFailedCondition)
+	if assert.NotNil(t, condition, "missing deprovision failed condition") {
+		assert.Equal(t, corev1.ConditionTrue, condition.Status, "unexpected condition status")
+		assert.Equal(t, deprovisionAttemptsExhaustedReason, condition.Reason, "unexpected condition reason")
+	}
+}
+
+func TestClusterDeploymentDeprovisionThrottle(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	otherRequest := func(name string, completed bool) *hivev1.ClusterDeprovisionRequest {
+		return &hivev1.ClusterDeprovisionRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "other-namespace",
+			},
+			Status: hivev1.ClusterDeprovisionRequestStatus{
+				Completed: completed,
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		max             int
+		existing        []runtime.Object
+		expectRequest   bool
+		expectedRequeue time.Duration
+	}{
+		{
+			name: "no limit",
+			existing: []runtime.Object{
+				otherRequest("first", false),
+				otherRequest("second", false),
+			},
+			expectRequest: true,
+		},
+		{
+			name: "below limit",
+			max:  2,
+			existing: []runtime.Object{
+				otherRequest("first", false),
+				otherRequest("second", true),
+			},
+			expectRequest: true,
+		},
+		{
+			name: "limit reached",
+			max:  2,
+			existing: []runtime.Object{
+				otherRequest("first", false),
+				otherRequest("second", false),
+			},
+			expectedRequeue: defaultRequeueTime,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := append([]runtime.Object{
+				testDeletedClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			}, test.existing...)
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				maxConcurrentDeprovisions:     test.max,
+			}
+
+			result, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+			assert.Equal(t, test.expectedRequeue, result.RequeueAfter, "unexpected requeue")
+
+			request := &hivev1.ClusterDeprovisionRequest{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, request)
+			if test.expectRequest {
+				assert.NoError(t, err, "expected deprovision request")
+			} else {
+				assert.True(t, errors.IsNotFound(err), "deprovision request should not be created")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentDefaultPullSecret(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	defaultPullSecret := testSecret(corev1.SecretTypeDockerConfigJson, "default-pull-secret", corev1.DockerConfigJsonKey, testPullSecretData)
+	defaultPullSecret.Namespace = hiveNamespace
+	copiedName := testName + "-default-pull-secret"
+
+	tests := []struct {
+		name               string
+		cd                 *hivev1.ClusterDeployment
+		expectedPullSecret string
+		expectCopy         bool
+	}{
+		{
+			name: "default pull secret",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Spec.PullSecret = corev1.LocalObjectReference{}
+				return cd
+			}(),
+			expectedPullSecret: copiedName,
+			expectCopy:         true,
+		},
+		{
+			name:               "namespace pull secret takes precedence",
+			cd:                 testClusterDeployment(),
+			expectedPullSecret: pullSecretSecret,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(
+				test.cd,
+				defaultPullSecret,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				defaultPullSecret:             defaultPullSecret.Name,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			copied := &corev1.Secret{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: copiedName, Namespace: testNamespace}, copied)
+			if test.expectCopy {
+				if assert.NoError(t, err, "missing copy of default pull secret") {
+					assert.Equal(t, defaultPullSecret.Data, copied.Data, "unexpected pull secret data")
+				}
+			} else {
+				assert.True(t, errors.IsNotFound(err), "default pull secret should not be copied")
+			}
+
+			job := &batchv1.Job{}
+			if assert.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Name: installJobName, Namespace: testNamespace}, job), "missing install job") {
+				assert.Equal(t, []corev1.LocalObjectReference{{Name: test.expectedPullSecret}}, job.Spec.Template.Spec.ImagePullSecrets, "unexpected image pull secrets")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentDefaultSSHKey(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	defaultSSHKey := testSecret(corev1.SecretTypeOpaque, "default-ssh-key", adminSSHKeySecretKey, "defaultsshkey")
+	defaultSSHKey.Namespace = hiveNamespace
+	copiedName := testName + "-default-ssh-key"
+
+	tests := []struct {
+		name           string
+		cd             *hivev1.ClusterDeployment
+		expectedSSHKey string
+		expectCopy     bool
+	}{
+		{
+			name: "default ssh key",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Spec.SSHKey = nil
+				return cd
+			}(),
+			expectedSSHKey: copiedName,
+			expectCopy:     true,
+		},
+		{
+			name:           "cluster ssh key takes precedence",
+			cd:             testClusterDeployment(),
+			expectedSSHKey: sshKeySecret,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(
+				test.cd,
+				defaultSSHKey,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				defaultSSHKey:                 defaultSSHKey.Name,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			copied := &corev1.Secret{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: copiedName, Namespace: testNamespace}, copied)
+			if test.expectCopy {
+				if assert.NoError(t, err, "missing copy of default ssh key") {
+					assert.Equal(t, defaultSSHKey.Data, copied.Data, "unexpected ssh key data")
+				}
+			} else {
+				assert.True(t, errors.IsNotFound(err), "default ssh key should not be copied")
+			}
+
+			job := getInstallJob(fakeClient)
+			if !assert.NotNil(t, job, "missing install job") {
+				return
+			}
+			var sshKeyVolume string
+			for _, v := range job.Spec.Template.Spec.Volumes {
+				if v.Name == "sshkeys" && v.Secret != nil {
+					sshKeyVolume = v.Secret.SecretName
+				}
+			}
+			assert.Equal(t, test.expectedSSHKey, sshKeyVolume, "unexpected ssh key secret")
+		})
+	}
+}
+
+func TestClusterDeploymentDefaultAWSCredentials(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	withoutCredentials := func(cd *hivev1.ClusterDeployment) *hivev1.ClusterDeployment {
+		cd.Spec.PlatformSecrets.AWS = nil
+		return cd
+	}
+	withManagedDNS := func(cd *hivev1.ClusterDeployment) *hivev1.ClusterDeployment {
+		cd.Spec.ManageDNS = true
+		return cd
+	}
+	installJobCredentials := func(c client.Client) (string, error) {
+		job := &batchv1.Job{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: installJobName, Namespace: testNamespace}, job); err != nil {
+			return "", err
+		}
+		for _, container := range job.Spec.Template.Spec.Containers {
+			for _, env := range container.Env {
+				if env.Name == "AWS_ACCESS_KEY_ID" && env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+					return env.ValueFrom.SecretKeyRef.Name, nil
+				}
+			}
+		}
+		return "", nil
+	}
+	dnsZoneCredentials := func(c client.Client) (string, error) {
+		zone := &hivev1.DNSZone{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: dnsZoneName(testClusterDeployment()), Namespace: testNamespace}, zone); err != nil {
+			return "", err
+		}
+		return zone.Spec.AWS.AccountSecret.Name, nil
+	}
+	deprovisionRequestCredentials := func(c client.Client) (string, error) {
+		request := &hivev1.ClusterDeprovisionRequest{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, request); err != nil {
+			return "", err
+		}
+		if request.Spec.Platform.AWS.Credentials == nil {
+			return "", nil
+		}
+		return request.Spec.Platform.AWS.Credentials.Name, nil
+	}
+
+	tests := []struct {
+		name               string
+		cd                 *hivev1.ClusterDeployment
+		existing           []runtime.Object
+		defaultCredentials bool
+		getCredentials     func(client.Client) (string, error)
+		expectedSecret     string
+	}{
+		{
+			name:               "install uses default credentials",
+			cd:                 withoutCredentials(testClusterDeployment()),
+			defaultCredentials: true,
+			getCredentials:     installJobCredentials,
+			expectedSecret:     hivev1.DefaultAWSCredentialsSecretName,
+		},
+		{
+			name:               "install prefers cluster credentials",
+			cd:                 testClusterDeployment(),
+			defaultCredentials: true,
+			getCredentials:     installJobCredentials,
+			expectedSecret:     "aws-credentials",
+		},
+		{
+			name:           "install without default credentials",
+			cd:             withoutCredentials(testClusterDeployment()),
+			getCredentials: installJobCredentials,
+		},
+		{
+			name:               "managed dns zone uses default credentials",
+			cd:                 withManagedDNS(withoutCredentials(testClusterDeployment())),
+			defaultCredentials: true,
+			getCredentials:     dnsZoneCredentials,
+			expectedSecret:     hivev1.DefaultAWSCredentialsSecretName,
+		},
+		{
+			name: "install with managed dns zone uses default credentials",
+			cd:   withManagedDNS(withoutCredentials(testClusterDeployment())),
+			existing: []runtime.Object{
+				func() *hivev1.DNSZone {
+					zone := testAvailableDNSZone()
+					zone.Spec.AWS.AccountSecret.Name = hivev1.DefaultAWSCredentialsSecretName
+					return zone
+				}(),
+			},
+			defaultCredentials: true,
+			getCredentials:     installJobCredentials,
+			expectedSecret:     hivev1.DefaultAWSCredentialsSecretName,
+		},
+		{
+			name:               "managed dns zone prefers cluster credentials",
+			cd:                 withManagedDNS(testClusterDeployment()),
+			defaultCredentials: true,
+			getCredentials:     dnsZoneCredentials,
+			expectedSecret:     "aws-credentials",
+		},
+		{
+			name:               "deprovision uses default credentials",
+			cd:                 withoutCredentials(testDeletedClusterDeployment()),
+			defaultCredentials: true,
+			getCredentials:     deprovisionRequestCredentials,
+			expectedSecret:     hivev1.DefaultAWSCredentialsSecretName,
+		},
+		{
+			name:               "deprovision prefers cluster credentials",
+			cd:                 testDeletedClusterDeployment(),
+			defaultCredentials: true,
+			getCredentials:     deprovisionRequestCredentials,
+			expectedSecret:     "aws-credentials",
+		},
+		{
+			name:           "deprovision without default credentials",
+			cd:             withoutCredentials(testDeletedClusterDeployment()),
+			getCredentials: deprovisionRequestCredentials,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := append([]runtime.Object{
+				test.cd.DeepCopy(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			}, test.existing...)
+			if test.defaultCredentials {
+				existing = append(existing, testSecret(corev1.SecretTypeOpaque, hivev1.DefaultAWSCredentialsSecretName, "aws_access_key_id", "fakekeyid"))
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			secretName, err := test.getCredentials(fakeClient)
+			if assert.NoError(t, err, "unexpected error getting created object") {
+				assert.Equal(t, test.expectedSecret, secretName, "unexpected AWS credentials secret")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			if err := fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd); err == nil {
+				assert.Equal(t, test.cd.Spec.PlatformSecrets, cd.Spec.PlatformSecrets, "default credentials should not be persisted")
+			}
+		})
+	}
+}
+
+func TestClusterImageSetWatchHandler(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cdWithImageSet := func(name, imageSetName string, installed bool) *hivev1.ClusterDeployment {
+		cd := testClusterDeployment()
+		cd.Name = name
+		cd.Status.Installed = installed
+		if imageSetName != "" {
+			cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: imageSetName}
+		}
+		return cd
+	}
+	fakeClient := fake.NewFakeClient(
+		cdWithImageSet("installing", testClusterImageSetName, false),
+		cdWithImageSet("installed", testClusterImageSetName, true),
+		cdWithImageSet("other-imageset", "other-imageset", false),
+		cdWithImageSet("no-imageset", "", false),
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client: fakeClient,
+		scheme: scheme.Scheme,
+	}
+
+	imageSet := testClusterImageSet()
+	requests := rcd.clusterImageSetWatchHandler(handler.MapObject{Meta: imageSet, Object: imageSet})
+	assert.Equal(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "installing", Namespace: testNamespace}},
+	}, requests, "unexpected requests")
+}
+
+func TestClusterDeploymentImageSetChange(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cd := testClusterDeployment()
+	cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+	oldJob, _, err := install.GenerateInstallerJob(cd, images.DefaultHiveImage, "old-release-image:latest",
+		serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+	if !assert.NoError(t, err, "unexpected error generating install job") {
+		return
+	}
+	controllerutil.SetControllerReference(cd, oldJob, scheme.Scheme)
+	hash, err := calculateJobSpecHash(oldJob)
+	if !assert.NoError(t, err, "unexpected error calculating job hash") {
+		return
+	}
+	oldJob.Annotations[jobHashAnnotation] = hash
+
+	imageSet := testClusterImageSet()
+	imageSet.Spec.ReleaseImage = strPtr("new-release-image:latest")
+	fakeClient := fake.NewFakeClient(
+		cd,
+		imageSet,
+		oldJob,
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        fakeClient,
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 record.NewFakeRecorder(10),
+	}
+	reconcileRequest := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+	}
+
+	_, err = rcd.Reconcile(reconcileRequest)
+	if !assert.NoError(t, err, "unexpected error") {
+		return
+	}
+	assert.Nil(t, getInstallJob(fakeClient), "install job for the old release image should be deleted")
+
+	_, err = rcd.Reconcile(reconcileRequest)
+	if !assert.NoError(t, err, "unexpected error") {
+		return
+	}
+	job := getInstallJob(fakeClient)
+	if assert.NotNil(t, job, "install job should be recreated") {
+		assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE",
+			Value: "new-release-image:latest",
+		}, "install job should use the new release image")
+	}
+}
+
+func TestClusterDeploymentImageSetHiveImageChange(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	openshiftapiv1.Install(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	tests := []struct {
+		name             string
+		installed        bool
+		expectRecreation bool
+	}{
+		{
+			name:             "installing cluster recreates install job",
+			expectRecreation: true,
+		},
+		{
+			name:      "installed cluster is left alone",
+			installed: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+			if test.installed {
+				cd.Status.Installed = true
+				cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+			}
+			oldJob, _, err := install.GenerateInstallerJob(cd, "old-hive-image:latest", "test-release-image:latest",
+				serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+			if !assert.NoError(t, err, "unexpected error generating install job") {
+				return
+			}
+			controllerutil.SetControllerReference(cd, oldJob, scheme.Scheme)
+			hash, err := calculateJobSpecHash(oldJob)
+			if !assert.NoError(t, err, "unexpected error calculating job hash") {
+				return
+			}
+			oldJob.Annotations[jobHashAnnotation] = hash
+			if test.installed {
+				oldJob.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+			}
+
+			imageSet := testClusterImageSet()
+			imageSet.Spec.HiveImage = strPtr("new-hive-image:latest")
+			imageSet.Spec.InstallerImage = strPtr("installer-image:latest")
+			fakeClient := fake.NewFakeClient(
+				cd,
+				imageSet,
+				oldJob,
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				testMetadataConfigMap(),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+			reconcileRequest := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			}
+
+			for i := 0; i < 2; i++ {
+				if _, err := rcd.Reconcile(reconcileRequest); !assert.NoError(t, err, "unexpected error") {
+					return
+				}
+			}
+
+			job := getInstallJob(fakeClient)
+			if assert.NotNil(t, job, "install job should exist") {
+				expectedImage := "old-hive-image:latest"
+				if test.expectRecreation {
+					expectedImage = "new-hive-image:latest"
+				}
+				for _, container := range job.Spec.Template.Spec.Containers {
+					if container.Name == "hive" {
+						assert.Equal(t, expectedImage, container.Image, "unexpected hive image in install job")
+					}
+				}
+			}
+			cd = &hivev1.ClusterDeployment{}
+			if assert.NoError(t, fakeClient.Get(context.TODO(), reconcileRequest.NamespacedName, cd)) {
+				assert.Equal(t, "installer-image:latest", *cd.Status.InstallerImage, "installer image should not be resolved again")
+			}
+			jobs := &batchv1.JobList{}
+			if assert.NoError(t, fakeClient.List(context.TODO(), &client.ListOptions{Namespace: testNamespace}, jobs)) {
+				assert.Len(t, jobs.Items, 1, "only the install job should exist")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentImageSetNotFoundCondition(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cd := testClusterDeployment()
+	cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{}
+	fakeClient := fake.NewFakeClient(
+		cd,
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        fakeClient,
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 record.NewFakeRecorder(10),
+	}
+	reconcileRequest := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+	}
+	getCondition := func() *hivev1.ClusterDeploymentCondition {
+		cd := &hivev1.ClusterDeployment{}
+		if err := fakeClient.Get(context.TODO(), reconcileRequest.NamespacedName, cd); err != nil {
+			t.Fatalf("unexpected error getting clusterdeployment: %v", err)
+		}
+		return controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ClusterImageSetNotFoundCondition)
+	}
+	updateImageSetName := func(name string) {
+		cd := &hivev1.ClusterDeployment{}
+		if err := fakeClient.Get(context.TODO(), reconcileRequest.NamespacedName, cd); err != nil {
+			t.Fatalf("unexpected error getting clusterdeployment: %v", err)
+		}
+		cd.Spec.ImageSet.Name = name
+		if err := fakeClient.Update(context.TODO(), cd); err != nil {
+			t.Fatalf("unexpected error updating clusterdeployment: %v", err)
+		}
+	}
+
+	_, err := rcd.Reconcile(reconcileRequest)
+	assert.NoError(t, err, "unexpected error")
+	if condition := getCondition(); assert.NotNil(t, condition, "missing condition for empty imageset name") {
+		assert.Equal(t, corev1.ConditionTrue, condition.Status, "unexpected condition status for empty imageset name")
+		assert.Equal(t, clusterImageSetNameEmptyReason, condition.Reason, "unexpected condition reason for empty imageset name")
+	}
+
+	updateImageSetName(testClusterImageSetName)
+	_, err = rcd.Reconcile(reconcileRequest)
+	assert.NoError(t, err, "unexpected error")
+	if condition := getCondition(); assert.NotNil(t, condition, "missing condition for missing imageset") {
+		assert.Equal(t, corev1.ConditionTrue, condition.Status, "unexpected condition status for missing imageset")
+		assert.Equal(t, clusterImageSetNotFoundReason, condition.Reason, "unexpected condition reason for missing imageset")
+	}
+
+	if err := fakeClient.Create(context.TODO(), testClusterImageSet()); err != nil {
+		t.Fatalf("unexpected error creating clusterimageset: %v", err)
+	}
+	_, err = rcd.Reconcile(reconcileRequest)
+	assert.NoError(t, err, "unexpected error")
+	if condition := getCondition(); assert.NotNil(t, condition, "missing condition for created imageset") {
+		assert.Equal(t, corev1.ConditionFalse, condition.Status, "unexpected condition status for created imageset")
+		assert.Equal(t, clusterImageSetFoundReason, condition.Reason, "unexpected condition reason for created imageset")
+	}
+}
+
+func TestClusterDeploymentImageSetInstallerImageChange(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                   string
+		installed              bool
+		expectedInstallerImage string
+	}{
+		{
+			name:                   "installing",
+			expectedInstallerImage: "new-installer-image:latest",
+		},
+		{
+			name:                   "installed",
+			installed:              true,
+			expectedInstallerImage: "old-installer-image:latest",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+			cd.Status.InstallerImage = strPtr("old-installer-image:latest")
+			cd.Status.Installed = test.installed
+			imageSet := testClusterImageSet()
+			imageSet.Spec.InstallerImage = strPtr("new-installer-image:latest")
+			fakeClient := fake.NewFakeClient(
+				cd,
+				imageSet,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd = &hivev1.ClusterDeployment{}
+			if assert.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)) &&
+				assert.NotNil(t, cd.Status.InstallerImage, "missing installer image") {
+				assert.Equal(t, test.expectedInstallerImage, *cd.Status.InstallerImage, "unexpected installer image")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentImageSetReleaseImageChange(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                 string
+		installed            bool
+		resolvedFrom         string
+		expectInstallerImage bool
+		expectImageSetJob    bool
+	}{
+		{
+			name:              "installing",
+			resolvedFrom:      "old-release-image:latest",
+			expectImageSetJob: true,
+		},
+		{
+			name:                 "installing with unchanged release image",
+			resolvedFrom:         "test-release-image:latest",
+			expectInstallerImage: true,
+		},
+		{
+			name:                 "installed",
+			installed:            true,
+			resolvedFrom:         "old-release-image:latest",
+			expectInstallerImage: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Spec.Images.InstallerImage = ""
+			cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+			cd.Status.InstallerImage = strPtr("old-installer-image:latest")
+			cd.Status.InstallerImageReleaseImage = test.resolvedFrom
+			cd.Status.Installed = test.installed
+			fakeClient := fake.NewFakeClient(
+				cd,
+				testClusterImageSet(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd = &hivev1.ClusterDeployment{}
+			if !assert.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)) {
+				return
+			}
+			if test.expectInstallerImage {
+				assert.NotNil(t, cd.Status.InstallerImage, "missing installer image")
+			} else {
+				assert.Nil(t, cd.Status.InstallerImage, "outdated installer image should be cleared")
+			}
+			job := getJob(fakeClient, imageset.GetImageSetJobName(testName))
+			if test.expectImageSetJob {
+				if assert.NotNil(t, job, "missing imageset job") {
+					assert.Equal(t, "test-release-image:latest", imageset.GetJobReleaseImage(job), "unexpected release image")
+					assert.Equal(t, "test-release-image:latest", cd.Status.InstallerImageReleaseImage, "unexpected recorded release image")
+				}
+			} else {
+				assert.Nil(t, job, "unexpected imageset job")
+			}
+		})
+	}
+}
+
+func TestParseInstallMetadata(t *testing.T) {
+	tests := []struct {
+		name      string
+		metadata  string
+		expected  *hivev1.InstallMetadata
+		expectErr bool
+	}{
+		{
+			name:     "aws",
+			metadata: `{"clusterName":"bar","clusterID":"test-cluster-id","infraID":"test-infra-id","aws":{"region":"us-east-1","identifier":[{"openshiftClusterID":"test-cluster-id"}]}}`,
+			expected: &hivev1.InstallMetadata{
+				InfraID:   "test-infra-id",
+				ClusterID: "test-cluster-id",
+				Region:    "us-east-1",
+			},
+		},
+		{
+			name:     "no platform",
+			metadata: `{"clusterName":"bar","clusterID":"test-cluster-id","infraID":"test-infra-id"}`,
+			expected: &hivev1.InstallMetadata{
+				InfraID:   "test-infra-id",
+				ClusterID: "test-cluster-id",
+			},
+		},
+		{
+			name:      "invalid",
+			metadata:  "not json",
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata, err := parseInstallMetadata(test.metadata)
+			if test.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			if assert.NoError(t, err, "unexpected error") {
+				assert.Equal(t, test.expected, metadata, "unexpected install metadata")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentInstallMetadataStatus(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	tests := []struct {
+		name                  string
+		reportInstallMetadata bool
+		expected              *hivev1.InstallMetadata
+	}{
+		{
+			name:                  "enabled",
+			reportInstallMetadata: true,
+			expected: &hivev1.InstallMetadata{
+				InfraID:   testInfraID,
+				ClusterID: testClusterID,
+				Region:    "us-east-1",
+			},
+		},
+		{
+			name: "disabled",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Status.Installed = true
+			cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+			metadataCfgMap := testMetadataConfigMap()
+			metadataCfgMap.Data["metadata.json"] = fmt.Sprintf(`{"clusterName":%q,"clusterID":%q,"infraID":%q,"aws":{"region":"us-east-1"}}`,
+				testClusterName, testClusterID, testInfraID)
+			fakeClient := fake.NewFakeClient(
+				cd,
+				testCompletedInstallJob(),
+				metadataCfgMap,
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				reportInstallMetadata:         test.reportInstallMetadata,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd = &hivev1.ClusterDeployment{}
+			if assert.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)) {
+				assert.Equal(t, test.expected, cd.Status.InstallMetadata, "unexpected install metadata")
+			}
+		})
+	}
+}
+
+func TestRouteURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		path      string
+		tls       *routev1.TLSConfig
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "host",
+			host:     "console.apps.example.com",
+			tls:      &routev1.TLSConfig{Termination: routev1.TLSTerminationReencrypt},
+			expected: "https://console.apps.example.com",
+		},
+		{
+			name:     "host with port",
+			host:     "console.apps.example.com:8443",
+			tls:      &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge},
+			expected: "https://console.apps.example.com:8443",
+		},
+		{
+			name:     "host with port and path",
+			host:     "console.apps.example.com:8443/console",
+			tls:      &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough},
+			expected: "https://console.apps.example.com:8443/console",
+		},
+		{
+			name:     "route path",
+			host:     "apps.example.com",
+			path:     "/console",
+			tls:      &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge},
+			expected: "https://apps.example.com/console",
+		},
+		{
+			name:     "host with scheme",
+			host:     "https://console.apps.example.com/",
+			expected: "https://console.apps.example.com/",
+		},
+		{
+			name:     "no tls",
+			host:     "console.apps.example.com",
+			expected: "http://console.apps.example.com",
+		},
+		{
+			name:      "no host",
+			expectErr: true,
+		},
+		{
+			name:      "invalid host",
+			host:      "console.apps.example.com:port",
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := &routev1.Route{}
+			route.Spec.Host = test.host
+			route.Spec.Path = test.path
+			route.Spec.TLS = test.tls
+			actual, err := routeURL(route)
+			if test.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			if assert.NoError(t, err, "unexpected error") {
+				assert.Equal(t, test.expected, actual, "unexpected URL")
+			}
+		})
+	}
+}
+
+func TestAdminKubeconfigCluster(t *testing.T) {
+	tests := []struct {
+		name           string
+		clusters       []string
+		expectedServer string
+		expectErr      bool
+	}{
+		{
+			name:           "matched",
+			clusters:       []string{testClusterName, "other"},
+			expectedServer: "https://" + testClusterName + ".example.com:6443",
+		},
+		{
+			name:           "single cluster fallback",
+			clusters:       []string{"renamed"},
+			expectedServer: "https://renamed.example.com:6443",
+		},
+		{
+			name:      "ambiguous",
+			clusters:  []string{"renamed", "other"},
+			expectErr: true,
+		},
+		{
+			name:      "no clusters",
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := clientcmdapi.NewConfig()
+			for _, name := range test.clusters {
+				config.Clusters[name] = &clientcmdapi.Cluster{Server: fmt.Sprintf("https://%s.example.com:6443", name)}
+			}
+			cluster, err := adminKubeconfigCluster(config, testClusterName, log.WithField("test", t.Name()))
+			if test.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			if assert.NoError(t, err, "unexpected error") {
+				assert.Equal(t, test.expectedServer, cluster.Server, "unexpected cluster")
+			}
+		})
+	}
+}
+
+func TestGetHiveImage(t *testing.T) {
+	tests := []struct {
+		name            string
+		cdImage         string
+		imageSetImage   string
+		envImage        string
+		expectedImage   string
+		expectedDefault bool
+	}{
+		{
+			name:          "cluster deployment image",
+			cdImage:       "cd-hive-image",
+			imageSetImage: "imageset-hive-image",
+			envImage:      "env-hive-image",
+			expectedImage: "cd-hive-image",
+		},
+		{
+			name:          "imageset image",
+			imageSetImage: "imageset-hive-image",
+			envImage:      "env-hive-image",
+			expectedImage: "imageset-hive-image",
+		},
+		{
+			name:          "environment image",
+			envImage:      "env-hive-image",
+			expectedImage: "env-hive-image",
+		},
+		{
+			name:            "default image",
+			expectedImage:   images.DefaultHiveImage,
+			expectedDefault: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.envImage != "" {
+				os.Setenv(images.HiveImageEnvVar, test.envImage)
+				defer os.Unsetenv(images.HiveImageEnvVar)
+			}
+			cd := testClusterDeployment()
+			cd.Spec.Images.HiveImage = test.cdImage
+			var imageSet *hivev1.ClusterImageSet
+			if test.imageSetImage != "" {
+				imageSet = testClusterImageSet()
+				imageSet.Spec.HiveImage = &test.imageSetImage
+			}
+			rcd := &ReconcileClusterDeployment{}
+			image, isDefault := rcd.getHiveImage(cd, imageSet, log.New())
+			assert.Equal(t, test.expectedImage, image, "unexpected hive image")
+			assert.Equal(t, test.expectedDefault, isDefault, "unexpected default hive image")
+		})
+	}
+}
+
+func TestClusterDeploymentHiveImageUnresolvedCondition(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name              string
+		cd                *hivev1.ClusterDeployment
+		expectedCondition bool
+		expectedStatus    corev1.ConditionStatus
+	}{
+		{
+			name:              "default hive image",
+			cd:                testClusterDeployment(),
+			expectedCondition: true,
+			expectedStatus:    corev1.ConditionTrue,
+		},
+		{
+			name: "configured hive image",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Spec.Images.HiveImage = "cd-hive-image"
+				return cd
+			}(),
+		},
+		{
+			name: "configured hive image after default",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Spec.Images.HiveImage = "cd-hive-image"
+				cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+					cd.Status.Conditions,
+					hivev1.HiveImageUnresolvedCondition,
+					corev1.ConditionTrue,
+					hiveImageDefaultReason,
+					"no hive image is configured",
+					controllerutils.UpdateConditionIfReasonOrMessageChange)
+				return cd
+			}(),
+			expectedCondition: true,
+			expectedStatus:    corev1.ConditionFalse,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(
+				test.cd,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			if !assert.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)) {
+				return
+			}
+			cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.HiveImageUnresolvedCondition)
+			if !test.expectedCondition {
+				assert.Nil(t, cond, "unexpected HiveImageUnresolved condition")
+				return
+			}
+			if assert.NotNil(t, cond, "missing HiveImageUnresolved condition") {
+				assert.Equal(t, test.expectedStatus, cond.Status, "unexpected condition status")
+			}
+		})
+	}
+}
+
+func testEmptyClusterDeployment() *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testName,
+			Namespace:   testNamespace,
+			Finalizers:  []string{hivev1.FinalizerDeprovision},
+			UID:         types.UID("1234"),
+			Annotations: map[string]string{},
+		},
+	}
+	return cd
+}
+
+func testClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testEmptyClusterDeployment()
+
+	cd.Spec = hivev1.ClusterDeploymentSpec{
+		ClusterName: testClusterName,
+		SSHKey: &corev1.LocalObjectReference{
+			Name: sshKeySecret,
+		},
+		ControlPlane: hivev1.MachinePool{},
+		Compute:      []hivev1.MachinePool{},
+		PullSecret: corev1.LocalObjectReference{
+			Name: pullSecretSecret,
+		},
+		Platform: hivev1.Platform{
+			AWS: &hivev1.AWSPlatform{
+				Region: "us-east-1",
+			},
+		},
+		Networking: hivev1.Networking{
+			Type: hivev1.NetworkTypeOpenshiftSDN,
+		},
+		PlatformSecrets: hivev1.PlatformSecrets{
+			AWS: &hivev1.AWSPlatformSecrets{
+				Credentials: corev1.LocalObjectReference{
+					Name: "aws-credentials",
+				},
+			},
+		},
+	}
+
+	cd.Status = hivev1.ClusterDeploymentStatus{
+		ClusterID:      testClusterID,
+		InfraID:        testInfraID,
+		InstallerImage: strPtr("installer-image:latest"),
+	}
+
+	controllerutils.FixupEmptyClusterVersionFields(&cd.Status.ClusterVersionStatus)
+	return cd
+}
+
+func testPostInstallManifests() []runtime.RawExtension {
+	return []runtime.RawExtension{
+		{
+			Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"default"}}`),
+		},
+	}
+}
+
+func testClusterDeploymentWithoutFinalizer() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Finalizers = []string{}
+	return cd
+}
+
+func testDeletedClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	now := metav1.Now()
+	cd.DeletionTimestamp = &now
+	return cd
+}
+
+func testDeletedClusterDeploymentWithoutFinalizer() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	now := metav1.Now()
+	cd.DeletionTimestamp = &now
+	cd.Finalizers = []string{}
+	return cd
+}
+
+func testExpiredClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.CreationTimestamp = metav1.Time{Time: metav1.Now().Add(-60 * time.Minute)}
+	cd.Annotations[deleteAfterAnnotation] = "5m"
+	return cd
+}
+
+func testInstallJob() *batchv1.Job {
+	cd := testClusterDeployment()
+	job, _, err := install.GenerateInstallerJob(cd,
+		images.DefaultHiveImage,
+		"",
+		serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+	if err != nil {
+		panic("should not error while generating test install job")
+	}
+
+	controllerutil.SetControllerReference(cd, job, scheme.Scheme)
+
+	hash, err := calculateJobSpecHash(job)
+	if err != nil {
+		panic("should never get error calculating job spec hash")
+	}
+
+	job.Annotations[jobHashAnnotation] = hash
+	return job
+}
+
+func testCompletedInstallJob() *batchv1.Job {
+	job := testInstallJob()
+	job.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:   batchv1.JobComplete,
+			Status: corev1.ConditionTrue,
+		},
+	}
+	return job
+}
+
+func testMetadataConfigMap() *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	cm.Name = metadataName
+	cm.Namespace = testNamespace
+	metadataJSON := `{
+		"aws": {
+			"identifier": [{"openshiftClusterID": "testFooClusterUUID"}]
+		}
+	}`
+	cm.Data = map[string]string{"metadata.json": metadataJSON}
+	return cm
+}
+
+func testSecret(secretType corev1.SecretType, name, key, value string) *corev1.Secret {
+	s := &corev1.Secret{
+		Type: secretType,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{
+			key: []byte(value),
+		},
+	}
+	return s
+}
+
+// testPullSecretData is a valid docker config json pull secret.
+const testPullSecretData = `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`
+
+func testPullSecret() *corev1.Secret {
+	return testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, testPullSecretData)
+}
+
+func testRemoteClusterAPIClientBuilder(secretData string) (client.Client, error) {
+	remoteClusterVersion := &openshiftapiv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: remoteClusterVersionObjectName,
+		},
+	}
+	remoteClusterVersion.Status = testRemoteClusterVersionStatus()
+
+	remoteClusterRouteObject := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      remoteClusterRouteObjectName,
+			Namespace: remoteClusterRouteObjectNamespace,
+		},
+	}
+	remoteClusterRouteObject.Spec.Host = "bar-api.clusters.example.com:6443/console"
+	remoteClusterRouteObject.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationReencrypt}
+
+	remoteClient := fake.NewFakeClient(remoteClusterVersion, remoteClusterRouteObject)
+	return remoteClient, nil
+}
+
+func testRemoteClusterVersionStatus() openshiftapiv1.ClusterVersionStatus {
+	status := openshiftapiv1.ClusterVersionStatus{
+		History: []openshiftapiv1.UpdateHistory{
+			{
+				State:   openshiftapiv1.CompletedUpdate,
+				Version: testRemoteClusterCurrentVersion,
+				Image:   "TESTIMAGE",
+			},
+		},
+		ObservedGeneration: 123456789,
+		VersionHash:        "TESTVERSIONHASH",
+	}
+	return status
+}
+
+func testClusterImageSet() *hivev1.ClusterImageSet {
+	cis := &hivev1.ClusterImageSet{}
+	cis.Name = testClusterImageSetName
+	cis.Spec.ReleaseImage = strPtr("test-release-image:latest")
+	return cis
+}
+
+func testDNSZone() *hivev1.DNSZone {
+	zone := &hivev1.DNSZone{}
+	zone.Name = testName + "-zone"
+	zone.Namespace = testNamespace
+	zone.Spec = managedDNSZoneSpec(testClusterDeployment())
+	controllerutil.SetControllerReference(testClusterDeployment(), zone, scheme.Scheme)
+	return zone
+}
+
+// testConflictingDNSZone returns an available DNSZone with the managed zone name of the test cluster deployment
+// which is controlled by another cluster deployment.
+func testConflictingDNSZone() *hivev1.DNSZone {
+	other := testClusterDeployment()
+	other.Name = "other"
+	other.UID = types.UID("5678")
+	zone := testAvailableDNSZone()
+	zone.OwnerReferences = nil
+	controllerutil.SetControllerReference(other, zone, scheme.Scheme)
+	return zone
+}
+
+func testAvailableDNSZone() *hivev1.DNSZone {
+	zone := testDNSZone()
+	zone.Status.Conditions = []hivev1.DNSZoneCondition{
+		{
+			Type:   hivev1.ZoneAvailableDNSZoneCondition,
+			Status: corev1.ConditionTrue,
+		},
+	}
+	return zone
+}
+
+func testClusterDeploymentWithIngress() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Spec.Ingress = []hivev1.ClusterIngress{
+		{
+			Name:   "default",
+			Domain: fmt.Sprintf("apps.%s.example.com", cd.Spec.ClusterName),
+		},
+	}
+
+	return cd
+}
+
+func TestClusterDeploymentWildcardDomainMigration(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name              string
+		existing          *hivev1.ClusterDeployment
+		migrationExpected bool
+		expectedDomains   []string // must be same length as the number of ingress entries for the test clusterDeployment
+	}{
+		{
+			name:              "No ingress, no migration",
+			existing:          testClusterDeployment(),
+			migrationExpected: false,
+		},
+		{
+			name:              "No wildcards, no migration",
+			existing:          testClusterDeploymentWithIngress(),
+			migrationExpected: false,
+			expectedDomains:   []string{fmt.Sprintf("apps.%s.example.com", testClusterName)},
+		},
+		{
+			name: "Migrate wildcard domain",
+			existing: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeploymentWithIngress()
+				cd.Spec.Ingress[0].Domain = fmt.Sprintf("*.apps.%s.example.com", cd.Spec.ClusterName)
+
+				return cd
+			}(),
+			migrationExpected: true,
+			expectedDomains:   []string{fmt.Sprintf("apps.%s.example.com", testClusterName)},
+		},
+		{
+			name: "Migrate when only one of two is wildcard",
+			existing: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeploymentWithIngress()
+				cd.Spec.Ingress = append(cd.Spec.Ingress, hivev1.ClusterIngress{
+					Name:   "extraingress",
+					Domain: fmt.Sprintf("*.moreingress.%s.example.com", cd.Spec.ClusterName),
+				})
+
+				return cd
+			}(),
+			migrationExpected: true,
+			expectedDomains: []string{
+				fmt.Sprintf("apps.%s.example.com", testClusterName),
+				fmt.Sprintf("moreingress.%s.example.com", testClusterName),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			result := migrateWildcardIngress(test.existing)
+
+			assert.Equal(t, test.migrationExpected, len(result) > 0)
+
+			for i, domain := range test.expectedDomains {
+				assert.Equal(t, domain, test.existing.Spec.Ingress[i].Domain)
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentJobHashing(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name           string
+		existingJob    *batchv1.Job
+		generatedJob   *batchv1.Job
+		expectedResult bool
+		expectedError  bool
+	}{
+		{
+			name: "No existing annotation",
+			existingJob: func() *batchv1.Job {
+				job := testInstallJob()
+				delete(job.Annotations, jobHashAnnotation)
+				return job
+			}(),
+			generatedJob:   testInstallJob(),
+			expectedResult: true,
+		},
+		{
+			name: "Different hash",
+			existingJob: func() *batchv1.Job {
+				job := testInstallJob()
+				job.Annotations[jobHashAnnotation] = "DIFFERENTHASH"
+				return job
+			}(),
+			generatedJob:   testInstallJob(),
+			expectedResult: true,
+		},
+		{
+			name:           "Same hash",
+			existingJob:    testInstallJob(),
+			generatedJob:   testInstallJob(),
+			expectedResult: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(test.existingJob)
+			rcd := &ReconcileClusterDeployment{
+				Client: fakeClient,
+			}
+			tLogger := log.New()
+
+			result, err := rcd.deleteJobOnHashChange(test.existingJob, test.generatedJob, tLogger)
+
+			if test.expectedError {
+				assert.Error(t, err, "expected error during test case")
+			} else {
+				assert.Equal(t, test.expectedResult, result)
+
+				if test.expectedResult { //if job was deleted
+					job := getInstallJob(fakeClient)
+					assert.Nil(t, job, "previous install job should have been deleted")
+				}
+			}
+
+		})
+	}
+}
+
+func TestInstallJobResourceVersionAnnotation(t *testing.T) {
+	generateJob := func(resourceVersion string) *batchv1.Job {
+		cd := testClusterDeployment()
+		cd.ResourceVersion = resourceVersion
+		job, _, err := install.GenerateInstallerJob(cd, images.DefaultHiveImage, "", serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+		if err != nil {
+			t.Fatalf("unexpected error generating install job: %v", err)
+		}
+		return job
+	}
+
+	job := generateJob("100")
+	assert.Equal(t, "100", job.Annotations[install.ClusterDeploymentResourceVersionAnnotation], "unexpected resource version annotation")
+
+	updatedJob := generateJob("200")
+	assert.Equal(t, "200", updatedJob.Annotations[install.ClusterDeploymentResourceVersionAnnotation], "unexpected resource version annotation")
+
+	hash, err := calculateJobSpecHash(job)
+	if !assert.NoError(t, err) {
+		return
+	}
+	updatedHash, err := calculateJobSpecHash(updatedJob)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, hash, updatedHash, "resource version should not change the job hash")
+}
+
+func TestInstallJobPriorityClassHash(t *testing.T) {
+	generateJob := func(priorityClassName string) *batchv1.Job {
+		cd := testClusterDeployment()
+		cd.Spec.InstallJobPriorityClassName = priorityClassName
+		job, _, err := install.GenerateInstallerJob(cd, images.DefaultHiveImage, "", serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+		if err != nil {
+			t.Fatalf("unexpected error generating install job: %v", err)
+		}
+		return job
+	}
+
+	hash, err := calculateJobSpecHash(generateJob(""))
+	if !assert.NoError(t, err) {
+		return
+	}
+	updatedHash, err := calculateJobSpecHash(generateJob("high-priority"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, hash, updatedHash, "priority class should change the job hash")
+}
+
+func TestInstallJobPodAnnotationsHash(t *testing.T) {
+	generateJob := func(annotations map[string]string) *batchv1.Job {
+		cd := testClusterDeployment()
+		cd.Spec.InstallJobPodAnnotations = annotations
+		job, _, err := install.GenerateInstallerJob(cd, images.DefaultHiveImage, "", serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+		if err != nil {
+			t.Fatalf("unexpected error generating install job: %v", err)
+		}
+		return job
+	}
+
+	hash, err := calculateJobSpecHash(generateJob(nil))
+	if !assert.NoError(t, err) {
+		return
+	}
+	updatedHash, err := calculateJobSpecHash(generateJob(map[string]string{"sidecar.istio.io/inject": "false"}))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, hash, updatedHash, "pod annotations should change the job hash")
+}
+
+func TestClusterDeploymentInstallConfigValidation(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                  string
+		existing              []runtime.Object
+		validateInstallConfig bool
+		expectInstallJob      bool
+		expectedCondition     *corev1.ConditionStatus
+	}{
+		{
+			name: "invalid install config without validation",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			expectInstallJob: true,
+		},
+		{
+			name: "invalid install config",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validateInstallConfig: true,
+			expectInstallJob:      false,
+			expectedCondition:     conditionStatusPtr(corev1.ConditionTrue),
+		},
+		{
+			name: "invalid zone for region",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.BaseDomain = "example.com"
+					cd.Spec.ControlPlane.Platform.AWS = &hivev1.AWSMachinePoolPlatform{
+						Zones: []string{"us-west-2a"},
+					}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validateInstallConfig: true,
+			expectInstallJob:      false,
+			expectedCondition:     conditionStatusPtr(corev1.ConditionTrue),
+		},
+		{
+			name: "valid install config",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.BaseDomain = "example.com"
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validateInstallConfig: true,
+			expectInstallJob:      true,
+		},
+		{
+			name: "clear invalid condition once install config is fixed",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.BaseDomain = "example.com"
+					cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+						{
+							Type:   hivev1.InstallConfigInvalidCondition,
+							Status: corev1.ConditionTrue,
+							Reason: installConfigInvalidReason,
+						},
+					}
+					return cd
+				}(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			validateInstallConfig: true,
+			expectInstallJob:      false,
+			expectedCondition:     conditionStatusPtr(corev1.ConditionFalse),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(test.existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				validateInstallConfig:         test.validateInstallConfig,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			assert.NoError(t, err, "unexpected error")
+
+			if test.expectInstallJob {
+				assert.NotNil(t, getInstallJob(fakeClient), "expected install job to be created")
+			} else {
+				assert.Nil(t, getInstallJob(fakeClient), "install job should not be created")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)
+			if assert.NoError(t, err, "unexpected error getting clusterdeployment") {
+				cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.InstallConfigInvalidCondition)
+				if test.expectedCondition == nil {
+					assert.Nil(t, cond, "unexpected InstallConfigInvalid condition")
+				} else if assert.NotNil(t, cond, "missing InstallConfigInvalid condition") {
+					assert.Equal(t, *test.expectedCondition, cond.Status, "unexpected InstallConfigInvalid condition status")
+				}
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentIngressDomainValidation(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	withIngress := func(baseDomain string, domains ...string) *hivev1.ClusterDeployment {
+		cd := testClusterDeployment()
+		cd.Spec.BaseDomain = baseDomain
+		for i, domain := range domains {
+			cd.Spec.Ingress = append(cd.Spec.Ingress, hivev1.ClusterIngress{
+				Name:   fmt.Sprintf("ingress%d", i),
+				Domain: domain,
+			})
+		}
+		return cd
+	}
+
+	tests := []struct {
+		name              string
+		cd                *hivev1.ClusterDeployment
+		expectInstallJob  bool
+		expectedCondition *corev1.ConditionStatus
+	}{
+		{
+			name:             "ingress domain within base domain",
+			cd:               withIngress("example.com", "apps.foo.example.com"),
+			expectInstallJob: true,
+		},
+		{
+			name:             "ingress domain matching base domain case insensitively",
+			cd:               withIngress("Example.com.", "apps.foo.EXAMPLE.com"),
+			expectInstallJob: true,
+		},
+		{
+			name:              "ingress domain outside base domain",
+			cd:                withIngress("example.com", "apps.foo.example.com", "apps.foo.example.org"),
+			expectInstallJob:  false,
+			expectedCondition: conditionStatusPtr(corev1.ConditionTrue),
+		},
+		{
+			name:              "ingress domain with base domain as a partial suffix",
+			cd:                withIngress("example.com", "apps.fooexample.com"),
+			expectInstallJob:  false,
+			expectedCondition: conditionStatusPtr(corev1.ConditionTrue),
+		},
+		{
+			name: "clear invalid condition once ingress domains are fixed",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := withIngress("example.com", "apps.foo.example.com")
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+					{
+						Type:   hivev1.IngressDomainInvalidCondition,
+						Status: corev1.ConditionTrue,
+						Reason: ingressDomainInvalidReason,
+					},
+				}
+				return cd
+			}(),
+			expectInstallJob:  false,
+			expectedCondition: conditionStatusPtr(corev1.ConditionFalse),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(
+				test.cd,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			assert.NoError(t, err, "unexpected error")
+
+			if test.expectInstallJob {
+				assert.NotNil(t, getInstallJob(fakeClient), "expected install job to be created")
+			} else {
+				assert.Nil(t, getInstallJob(fakeClient), "install job should not be created")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)
+			if assert.NoError(t, err, "unexpected error getting clusterdeployment") {
+				cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.IngressDomainInvalidCondition)
+				if test.expectedCondition == nil {
+					assert.Nil(t, cond, "unexpected IngressDomainInvalid condition")
+				} else if assert.NotNil(t, cond, "missing IngressDomainInvalid condition") {
+					assert.Equal(t, *test.expectedCondition, cond.Status, "unexpected IngressDomainInvalid condition status")
+				}
+			}
+		})
+	}
+}
+
+// staleJobCacheClient simulates a cache that has not yet observed the install job: gets for jobs
+// report not found even though the job exists.
+type staleJobCacheClient struct {
+	client.Client
+}
+
+func (c *staleJobCacheClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if _, ok := obj.(*batchv1.Job); ok {
+		return errors.NewNotFound(batchv1.Resource("jobs"), key.Name)
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func TestClusterDeploymentInstallJobAlreadyExists(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	job := testCompletedInstallJob()
+	created := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	job.CreationTimestamp = created
+	fakeClient := fake.NewFakeClient(
+		testClusterDeployment(),
+		job,
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        &staleJobCacheClient{Client: fakeClient},
+		apiReader:                     fakeClient,
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 record.NewFakeRecorder(10),
+	}
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+	}
+	_, err := rcd.Reconcile(request)
+	if !assert.NoError(t, err, "install job that already exists should not fail reconcile") {
+		return
+	}
+	assert.NotNil(t, getInstallJob(fakeClient), "expected install job to still exist")
+
+	cd := &hivev1.ClusterDeployment{}
+	if err := fakeClient.Get(context.TODO(), request.NamespacedName, cd); err != nil {
+		t.Fatalf("unexpected error getting cluster deployment: %v", err)
+	}
+	assert.True(t, cd.Status.Installed, "status should reflect the completed job read from the API server")
+	if assert.NotNil(t, cd.Status.InstallStartedTimestamp, "install start should be taken from the existing job") {
+		assert.True(t, created.Equal(cd.Status.InstallStartedTimestamp), "unexpected install start %v", cd.Status.InstallStartedTimestamp)
+	}
+}
+
+func TestClusterDeploymentMaintenanceMode(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name              string
+		existing          []runtime.Object
+		maintenanceMode   bool
+		expectInstallJob  bool
+		expectImageSetJob bool
+		expectInstalled   bool
+		expectRequeue     bool
+	}{
+		{
+			name: "install job created without maintenance mode",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			expectInstallJob: true,
+		},
+		{
+			name: "install job not created in maintenance mode",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			maintenanceMode: true,
+			expectRequeue:   true,
+		},
+		{
+			name: "imageset job created without maintenance mode",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.InstallerImage = nil
+					cd.Spec.Images.InstallerImage = ""
+					cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					return cd
+				}(),
+				testClusterImageSet(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			expectImageSetJob: true,
+		},
+		{
+			name: "imageset job not created in maintenance mode",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.InstallerImage = nil
+					cd.Spec.Images.InstallerImage = ""
+					cd.Spec.ImageSet = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					return cd
+				}(),
+				testClusterImageSet(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			maintenanceMode: true,
+			expectRequeue:   true,
+		},
+		{
+			name: "existing install job completes in maintenance mode",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testCompletedInstallJob(),
+				testMetadataConfigMap(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			},
+			maintenanceMode:  true,
+			expectInstallJob: true,
+			expectInstalled:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(test.existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				maintenanceMode:               test.maintenanceMode,
+			}
+
+			result, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			assert.NoError(t, err, "unexpected error")
+
+			if test.expectRequeue {
+				assert.NotZero(t, result.RequeueAfter, "expected requeue")
+			}
+			if test.expectInstallJob {
+				assert.NotNil(t, getInstallJob(fakeClient), "expected install job")
+			} else {
+				assert.Nil(t, getInstallJob(fakeClient), "install job should not be created")
+			}
+			if test.expectImageSetJob {
+				assert.NotNil(t, getJob(fakeClient, imageSetJobName), "expected imageset job")
+			} else {
+				assert.Nil(t, getJob(fakeClient, imageSetJobName), "imageset job should not be created")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)
+			if assert.NoError(t, err, "unexpected error getting clusterdeployment") {
+				assert.Equal(t, test.expectInstalled, cd.Status.Installed, "unexpected installed status")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentInstallPodAntiAffinity(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                   string
+		installPodAntiAffinity bool
+		existingJob            *batchv1.Job
+		expectAntiAffinity     bool
+		expectJobDeleted       bool
+	}{
+		{
+			name: "anti-affinity disabled",
+		},
+		{
+			name:                   "anti-affinity enabled",
+			installPodAntiAffinity: true,
+			expectAntiAffinity:     true,
+		},
+		{
+			name:                   "enabling anti-affinity replaces existing install job",
+			installPodAntiAffinity: true,
+			existingJob:            testInstallJob(),
+			expectJobDeleted:       true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			}
+			if test.existingJob != nil {
+				existing = append(existing, test.existingJob)
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				installPodAntiAffinity:        test.installPodAntiAffinity,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			job := getInstallJob(fakeClient)
+			if test.expectJobDeleted {
+				assert.Nil(t, job, "install job without anti-affinity should be deleted")
+				return
+			}
+			if !assert.NotNil(t, job, "expected install job") {
+				return
+			}
+			affinity := job.Spec.Template.Spec.Affinity
+			if test.expectAntiAffinity {
+				if assert.NotNil(t, affinity, "missing affinity") {
+					assert.NotNil(t, affinity.PodAntiAffinity, "missing pod anti-affinity")
+				}
+			} else {
+				assert.Nil(t, affinity, "unexpected affinity")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentInstallPodServiceAccountToken(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name             string
+		disableToken     bool
+		existingJob      *batchv1.Job
+		expectJobDeleted bool
+	}{
+		{
+			name: "token mounted by default",
+		},
+		{
+			name:         "token disabled",
+			disableToken: true,
+		},
+		{
+			name:             "disabling the token replaces existing install job",
+			disableToken:     true,
+			existingJob:      testInstallJob(),
+			expectJobDeleted: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			}
+			if test.existingJob != nil {
+				existing = append(existing, test.existingJob)
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				disableInstallPodSAToken:      test.disableToken,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			job := getInstallJob(fakeClient)
+			if test.expectJobDeleted {
+				assert.Nil(t, job, "install job mounting the token should be deleted")
+				return
+			}
+			if !assert.NotNil(t, job, "expected install job") {
+				return
+			}
+			assert.Nil(t, job.Spec.Template.Spec.AutomountServiceAccountToken,
+				"the install manager needs the token, so it should not be disabled for the whole pod")
+			for _, container := range job.Spec.Template.Spec.Containers {
+				tokenHidden := false
+				for _, mount := range container.VolumeMounts {
+					if mount.MountPath == "/var/run/secrets/kubernetes.io/serviceaccount" {
+						tokenHidden = true
+					}
+				}
+				expectHidden := test.disableToken && container.Name == "installer"
+				assert.Equal(t, expectHidden, tokenHidden, "unexpected token mount for %s container", container.Name)
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentPullSecretValidation(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name               string
+		pullSecret         *corev1.Secret
+		expectInvalid      bool
+		expectedPullSecret string
+	}{
+		{
+			name:               "valid dockerconfigjson",
+			pullSecret:         testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`),
+			expectedPullSecret: `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`,
+		},
+		{
+			name:               "legacy dockercfg",
+			pullSecret:         testSecret(corev1.SecretTypeDockercfg, pullSecretSecret, corev1.DockerConfigKey, `{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}`),
+			expectedPullSecret: `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`,
+		},
+		{
+			name:          "malformed dockerconfigjson",
+			pullSecret:    testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "not json"),
+			expectInvalid: true,
+		},
+		{
+			name:          "malformed dockercfg",
+			pullSecret:    testSecret(corev1.SecretTypeDockercfg, pullSecretSecret, corev1.DockerConfigKey, "not json"),
+			expectInvalid: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(
+				testClusterDeployment(),
+				test.pullSecret,
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			if !assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, cd)) {
+				return
+			}
+			cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.PullSecretInvalidCondition)
+			if test.expectInvalid {
+				assert.Nil(t, getInstallJob(fakeClient), "install job should not be created for an invalid pull secret")
+				if assert.NotNil(t, cond, "missing PullSecretInvalid condition") {
+					assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+				}
+				return
+			}
+			assert.Nil(t, cond, "unexpected PullSecretInvalid condition")
+			assert.NotNil(t, getInstallJob(fakeClient), "expected install job")
+			cfgMap := &corev1.ConfigMap{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName + "-installconfig", Namespace: testNamespace}, cfgMap)
+			if assert.NoError(t, err, "did not find install config map") {
+				assert.Contains(t, cfgMap.Data["install-config.yaml"], test.expectedPullSecret, "unexpected pull secret in install config")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentCustomManifests(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	validManifests := map[string]string{"99-config.yaml": "kind: ConfigMap"}
+	tests := []struct {
+		name             string
+		manifests        map[string]string
+		existingJob      *batchv1.Job
+		expectJob        bool
+		expectJobDeleted bool
+		expectInvalid    bool
+	}{
+		{
+			name:      "manifests mounted",
+			manifests: validManifests,
+			expectJob: true,
+		},
+		{
+			name:      "unchanged manifests keep existing install job",
+			manifests: validManifests,
+			existingJob: func() *batchv1.Job {
+				job := testInstallJob()
+				install.SetCustomManifests(job, "custom-manifests", validManifests)
+				job.Annotations[jobHashAnnotation], _ = calculateJobSpecHash(job)
+				return job
+			}(),
+			expectJob: true,
+		},
+		{
+			name:      "changed manifests replace existing install job",
+			manifests: map[string]string{"99-config.yaml": "kind: Secret"},
+			existingJob: func() *batchv1.Job {
+				job := testInstallJob()
+				install.SetCustomManifests(job, "custom-manifests", validManifests)
+				job.Annotations[jobHashAnnotation], _ = calculateJobSpecHash(job)
+				return job
+			}(),
+			expectJobDeleted: true,
+		},
+		{
+			name:          "invalid manifest file name",
+			manifests:     map[string]string{"99-config.txt": "kind: ConfigMap"},
+			expectInvalid: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Spec.ManifestsConfigMapRef = &corev1.LocalObjectReference{Name: "custom-manifests"}
+			existing := []runtime.Object{
+				cd,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "custom-manifests",
+						Namespace: testNamespace,
+					},
+					Data: test.manifests,
+				},
+			}
+			if test.existingJob != nil {
+				existing = append(existing, test.existingJob)
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			job := getInstallJob(fakeClient)
+			switch {
+			case test.expectJobDeleted:
+				assert.Nil(t, job, "install job with outdated manifests should be deleted")
+			case test.expectInvalid:
+				assert.Nil(t, job, "install job should not be created for invalid manifests")
+				cd := &hivev1.ClusterDeployment{}
+				if assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, cd)) {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ManifestsInvalidCondition)
+					if assert.NotNil(t, cond, "missing ManifestsInvalid condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+					}
+				}
+			case test.expectJob:
+				if !assert.NotNil(t, job, "expected install job") {
+					return
+				}
+				found := false
+				for _, v := range job.Spec.Template.Spec.Volumes {
+					if v.Name == "custommanifests" && v.ConfigMap != nil {
+						found = true
+						assert.Equal(t, "custom-manifests", v.ConfigMap.Name, "unexpected config map name")
+					}
+				}
+				assert.True(t, found, "custom manifests volume not found")
+				if test.existingJob != nil {
+					assert.Equal(t, test.existingJob.Annotations[jobHashAnnotation], job.Annotations[jobHashAnnotation], "install job should not be replaced")
+				}
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentInstallPodTerminationGracePeriod(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                string
+		gracePeriod         int64
+		existingJob         *batchv1.Job
+		expectedGracePeriod int64
+		expectJobDeleted    bool
+	}{
+		{
+			name:                "default grace period",
+			expectedGracePeriod: install.DefaultInstallPodTerminationGracePeriodSeconds,
+		},
+		{
+			name:                "configured grace period",
+			gracePeriod:         1800,
+			expectedGracePeriod: 1800,
+		},
+		{
+			name:             "changing grace period replaces existing install job",
+			gracePeriod:      1800,
+			existingJob:      testInstallJob(),
+			expectJobDeleted: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := []runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			}
+			if test.existingJob != nil {
+				existing = append(existing, test.existingJob)
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				installPodGracePeriod:         test.gracePeriod,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			job := getInstallJob(fakeClient)
+			if test.expectJobDeleted {
+				assert.Nil(t, job, "install job with the old grace period should be deleted")
+				return
+			}
+			if !assert.NotNil(t, job, "expected install job") {
+				return
+			}
+			if assert.NotNil(t, job.Spec.Template.Spec.TerminationGracePeriodSeconds, "missing termination grace period") {
+				assert.Equal(t, test.expectedGracePeriod, *job.Spec.Template.Spec.TerminationGracePeriodSeconds,
+					"unexpected termination grace period")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentAdopt(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	openshiftapiv1.Install(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	tests := []struct {
+		name            string
+		mutate          func(cd *hivev1.ClusterDeployment)
+		expectInstalled bool
+		expectedEvent   string
+		expectRequeue   bool
+	}{
+		{
+			name:            "adopt cluster",
+			expectInstalled: true,
+			expectedEvent:   clusterAdoptedReason,
+		},
+		{
+			name: "adopt cluster with expiry",
+			mutate: func(cd *hivev1.ClusterDeployment) {
+				cd.CreationTimestamp = metav1.NewTime(time.Now().Truncate(time.Second))
+				cd.Annotations[deleteAfterAnnotation] = "8h"
+			},
+			expectInstalled: true,
+			expectedEvent:   clusterAdoptedReason,
+			expectRequeue:   true,
+		},
+		{
+			name: "adopt cluster without finalizer",
+			mutate: func(cd *hivev1.ClusterDeployment) {
+				cd.Finalizers = []string{}
+			},
+			expectInstalled: true,
+			expectedEvent:   clusterAdoptedReason,
+		},
+		{
+			name: "adopt cluster missing infra ID",
+			mutate: func(cd *hivev1.ClusterDeployment) {
+				cd.Status.InfraID = ""
+			},
+			expectedEvent: adoptionIncompleteReason,
+		},
+		{
+			name: "adopt cluster missing admin kubeconfig secret",
+			mutate: func(cd *hivev1.ClusterDeployment) {
+				cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{}
+			},
+			expectedEvent: adoptionIncompleteReason,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Annotations[adoptAnnotation] = "true"
+			cd.Status.InstallerImage = nil
+			cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+			if test.mutate != nil {
+				test.mutate(cd)
+			}
+			fakeClient := fake.NewFakeClient(
+				cd,
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			fakeRecorder := record.NewFakeRecorder(10)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 fakeRecorder,
+			}
+
+			// Reconcile a few times to make sure no install is launched once the cluster has been adopted.
+			for i := 0; i < 3; i++ {
+				result, err := rcd.Reconcile(reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      testName,
+						Namespace: testNamespace,
+					},
+				})
+				if !assert.NoError(t, err, "unexpected error") {
+					return
+				}
+				if i == 0 && test.expectRequeue {
+					assert.NotZero(t, result.RequeueAfter, "adopted cluster should be requeued for its expiry")
+				}
+			}
+
+			cd = &hivev1.ClusterDeployment{}
+			if err := fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd); !assert.NoError(t, err, "missing cluster deployment") {
+				return
+			}
+			assert.Equal(t, test.expectInstalled, cd.Status.Installed, "unexpected installed status")
+			if test.expectInstalled {
+				assert.NotNil(t, cd.Status.InstalledTimestamp, "missing installed timestamp")
+			}
+			assert.True(t, controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision), "expected deprovision finalizer")
+			assert.Nil(t, getInstallJob(fakeClient), "install job should not be created for an adopted cluster")
+			assert.Nil(t, getJob(fakeClient, imageSetJobName), "imageset job should not be created for an adopted cluster")
+
+			var events []string
+			for len(fakeRecorder.Events) > 0 {
+				events = append(events, <-fakeRecorder.Events)
+			}
+			if assert.NotEmpty(t, events, "expected events") {
+				assert.Contains(t, events[0], test.expectedEvent, "unexpected event")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentDeleteInstallConfig(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	openshiftapiv1.Install(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	installConfigName := testName + "-installconfig"
+	tests := []struct {
+		name                 string
+		installed            bool
+		installConfigSecret  bool
+		cleanupInstallConfig bool
+		expectInstallConfig  bool
+	}{
+		{
+			name:                "installed cluster keeps install config by default",
+			installed:           true,
+			expectInstallConfig: true,
+		},
+		{
+			name:                 "installed cluster install config deleted",
+			installed:            true,
+			cleanupInstallConfig: true,
+		},
+		{
+			name:                 "installed cluster install config secret deleted",
+			installed:            true,
+			installConfigSecret:  true,
+			cleanupInstallConfig: true,
+		},
+		{
+			name:                 "install config recreated for reinstall",
+			cleanupInstallConfig: true,
+			expectInstallConfig:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Spec.InstallConfigSecret = test.installConfigSecret
+			existing := []runtime.Object{
+				cd,
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			}
+			if test.installed {
+				cd.Status.Installed = true
+				cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
+				existing = append(existing,
+					testCompletedInstallJob(),
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: installConfigName, Namespace: testNamespace}},
+					testSecret(corev1.SecretTypeOpaque, installConfigName, "install-config.yaml", "fakeinstallconfig"),
+				)
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				cleanupInstallConfig:          test.cleanupInstallConfig,
+			}
+
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			cfgMap := &corev1.ConfigMap{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: installConfigName, Namespace: testNamespace}, cfgMap)
+			if test.expectInstallConfig {
+				assert.NoError(t, err, "expected install config map")
+			} else {
+				assert.True(t, errors.IsNotFound(err), "install config map should be deleted")
+				cfgSecret := &corev1.Secret{}
+				err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: installConfigName, Namespace: testNamespace}, cfgSecret)
+				assert.True(t, errors.IsNotFound(err), "install config secret should be deleted")
+			}
+		})
+	}
+}
+
+func TestClusterDeploymentRegionValidation(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                string
+		region              string
+		regions             []string
+		describeErr         error
+		expectedCheckRegion string
+		expectUnavailable   bool
+		expectInstallJob    bool
+	}{
+		{
+			name:             "region available",
+			regions:          []string{"us-west-2", "us-east-1"},
+			expectInstallJob: true,
+		},
+		{
+			name:              "region unavailable",
+			regions:           []string{"us-west-2", "eu-west-1"},
+			expectUnavailable: true,
+		},
+		{
+			name:             "regions cannot be listed",
+			describeErr:      fmt.Errorf("UnauthorizedOperation"),
+			expectInstallJob: true,
+		},
+		{
+			name:                "checked from the partition of the region",
+			region:              "us-gov-east-1",
+			regions:             []string{"us-gov-west-1", "us-gov-east-1"},
+			expectedCheckRegion: "us-gov-west-1",
+			expectInstallJob:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAWSClient := mockaws.NewMockClient(mockCtrl)
+			output := &ec2.DescribeRegionsOutput{}
+			for _, region := range test.regions {
+				output.Regions = append(output.Regions, &ec2.Region{RegionName: aws.String(region)})
+			}
+			mockAWSClient.EXPECT().DescribeRegions(gomock.Any()).Return(output, test.describeErr).AnyTimes()
+
+			cd := testClusterDeployment()
+			if test.region != "" {
+				cd.Spec.AWS.Region = test.region
+			}
+			expectedCheckRegion := test.expectedCheckRegion
+			if expectedCheckRegion == "" {
+				expectedCheckRegion = "us-east-1"
+			}
+			fakeClient := fake.NewFakeClient(
+				cd,
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			)
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				validateRegion:                true,
+				awsClientBuilder: func(c client.Client, secretName, namespace, region string) (awsclient.Client, error) {
+					assert.Equal(t, "aws-credentials", secretName, "unexpected credentials secret")
+					assert.Equal(t, expectedCheckRegion, region, "unexpected region used to list the regions")
+					return mockAWSClient, nil
+				},
+			}
+			request := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			}
+
+			// The first reconcile sets the condition, the second acts on it.
+			for i := 0; i < 2; i++ {
+				if _, err := rcd.Reconcile(request); !assert.NoError(t, err, "unexpected error") {
+					return
+				}
+			}
+
+			cd = &hivev1.ClusterDeployment{}
+			if assert.NoError(t, fakeClient.Get(context.TODO(), request.NamespacedName, cd), "missing clusterdeployment") {
+				cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.RegionUnavailableCondition)
+				if test.expectUnavailable {
+					if assert.NotNil(t, cond, "missing RegionUnavailable condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+					}
+				} else {
+					assert.Nil(t, cond, "unexpected RegionUnavailable condition")
+				}
+			}
+			if test.expectInstallJob {
+				assert.NotNil(t, getInstallJob(fakeClient), "expected install job")
+			} else {
+				assert.Nil(t, getInstallJob(fakeClient), "install job should not be created in an unavailable region")
+			}
+		})
+	}
+}
+
+func TestGetClusterExpiry(t *testing.T) {
+	created := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	installed := created.Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		installed      bool
+		expectedExpiry *time.Time
+		expectedError  bool
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name:           "delete after creation",
+			annotations:    map[string]string{deleteAfterAnnotation: "8h"},
+			expectedExpiry: timePtr(created.Add(8 * time.Hour)),
+		},
+		{
+			name:        "delete after install before installed",
+			annotations: map[string]string{deleteAfterInstalledAnnotation: "2h"},
+		},
+		{
+			name:           "delete after install once installed",
+			annotations:    map[string]string{deleteAfterInstalledAnnotation: "2h"},
+			installed:      true,
+			expectedExpiry: timePtr(installed.Add(2 * time.Hour)),
+		},
+		{
+			name: "both annotations uses earliest expiry",
+			annotations: map[string]string{
+				deleteAfterAnnotation:          "8h",
+				deleteAfterInstalledAnnotation: "2h",
+			},
+			installed:      true,
+			expectedExpiry: timePtr(installed.Add(2 * time.Hour)),
+		},
+		{
+			name: "both annotations before installed",
+			annotations: map[string]string{
+				deleteAfterAnnotation:          "8h",
+				deleteAfterInstalledAnnotation: "2h",
+			},
+			expectedExpiry: timePtr(created.Add(8 * time.Hour)),
+		},
+		{
+			name:          "invalid delete after install",
+			annotations:   map[string]string{deleteAfterInstalledAnnotation: "soon"},
+			installed:     true,
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.CreationTimestamp = metav1.NewTime(created)
+			cd.Annotations = test.annotations
+			if test.installed {
+				cd.Status.Installed = true
+				cd.Status.InstalledTimestamp = &metav1.Time{Time: installed}
+			}
+			expiry, err := getClusterExpiry(cd)
+			if test.expectedError {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			if assert.NoError(t, err, "unexpected error") {
+				assert.Equal(t, test.expectedExpiry, expiry, "unexpected expiry")
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func conditionStatusPtr(status corev1.ConditionStatus) *corev1.ConditionStatus {
+	return &status
+}
+
+func TestInstallDuration(t *testing.T) {
+	now := time.Now()
+	started := metav1.NewTime(now.Add(-42*time.Minute - 17*time.Second))
+
+	tests := []struct {
+		name      string
+		started   *metav1.Time
+		installed *metav1.Time
+		expected  string
+	}{
+		{
+			name:     "not started",
+			expected: "",
+		},
+		{
+			name:     "running",
+			started:  &started,
+			expected: "42m0s",
+		},
+		{
+			name:    "completed",
+			started: &started,
+			installed: func() *metav1.Time {
+				installed := metav1.NewTime(started.Add(35*time.Minute + 12*time.Second))
+				return &installed
+			}(),
+			expected: "35m12s",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Status.InstallStartedTimestamp = test.started
+			cd.Status.InstalledTimestamp = test.installed
+			cd.Status.Installed = test.installed != nil
+			assert.Equal(t, test.expected, installDuration(cd, now), "unexpected install duration")
+		})
+	}
+}
+
+func TestSetProvisionCompletedCondition(t *testing.T) {
+	failedJob := func(reason, message string) *batchv1.Job {
+		job := testInstallJob()
+		job.Status.Conditions = []batchv1.JobCondition{
+			{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  reason,
+				Message: message,
+			},
+		}
+		return job
+	}
+
+	tests := []struct {
+		name            string
+		cd              *hivev1.ClusterDeployment
+		job             *batchv1.Job
+		expectCondition bool
+		expectedStatus  corev1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "installed",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Status.Installed = true
+				return cd
+			}(),
+			job:             testCompletedInstallJob(),
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionTrue,
+			expectedReason:  provisionSucceededReason,
+		},
+		{
+			name:            "attempts exhausted",
+			cd:              testClusterDeployment(),
+			job:             failedJob("BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  provisionAttemptsExhaustedReason,
+			expectedMessage: "Job has reached the specified backoff limit",
+		},
+		{
+			name:            "deadline exceeded",
+			cd:              testClusterDeployment(),
+			job:             failedJob("DeadlineExceeded", "Job was active longer than specified deadline"),
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  provisionDeadlineExceededReason,
+			expectedMessage: "Job was active longer than specified deadline",
+		},
+		{
+			name:            "other failure",
+			cd:              testClusterDeployment(),
+			job:             failedJob("SomethingElse", ""),
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  provisionFailedReason,
+			expectedMessage: "install job has failed",
+		},
+		{
+			name:            "install in progress",
+			cd:              testClusterDeployment(),
+			job:             testInstallJob(),
+			expectCondition: false,
+		},
+		{
+			name:            "no install job",
+			cd:              testClusterDeployment(),
+			expectCondition: false,
+		},
+		{
+			name: "retried install in progress clears failure",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+					{
+						Type:   hivev1.ProvisionCompletedCondition,
+						Status: corev1.ConditionFalse,
+						Reason: provisionAttemptsExhaustedReason,
+					},
+				}
+				return cd
+			}(),
+			job:             testInstallJob(),
+			expectCondition: false,
+		},
+		{
+			name: "failure after success is not possible while installed",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Status.Installed = true
+				return cd
+			}(),
+			job:             failedJob("BackoffLimitExceeded", ""),
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionTrue,
+			expectedReason:  provisionSucceededReason,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setProvisionCompletedCondition(test.cd, test.job)
+			cond := controllerutils.FindClusterDeploymentCondition(test.cd.Status.Conditions, hivev1.ProvisionCompletedCondition)
+			if !test.expectCondition {
+				assert.Nil(t, cond, "unexpected ProvisionCompleted condition")
+				return
+			}
+			if assert.NotNil(t, cond, "missing ProvisionCompleted condition") {
+				assert.Equal(t, test.expectedStatus, cond.Status, "unexpected condition status")
+				assert.Equal(t, test.expectedReason, cond.Reason, "unexpected condition reason")
+				if test.expectedMessage != "" {
+					assert.Equal(t, test.expectedMessage, cond.Message, "unexpected condition message")
+				}
+			}
+		})
+	}
+}
+
+// conflictingClient returns a conflict error for the first conflicts calls to Update.
+type conflictingClient struct {
+	client.Client
+	conflicts int
+	updates   int
+}
+
+func (c *conflictingClient) Update(ctx context.Context, obj runtime.Object) error {
+	c.updates++
+	if c.updates <= c.conflicts {
+		return errors.NewConflict(hivev1.Resource("clusterdeployments"), testName, fmt.Errorf("conflict"))
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+func TestFixupAdminKubeconfigSecretConflict(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name            string
+		fixedUp         bool
+		conflicts       int
+		expectErr       bool
+		expectedUpdates int
+	}{
+		{
+			name:            "no conflict",
+			expectedUpdates: 1,
+		},
+		{
+			name:            "conflict then success",
+			conflicts:       1,
+			expectedUpdates: 2,
+		},
+		{
+			name:            "persistent conflicts",
+			conflicts:       100,
+			expectErr:       true,
+			expectedUpdates: retry.DefaultBackoff.Steps,
+		},
+		{
+			name:    "already fixed up",
+			fixedUp: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			secret := testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, adminKubeconfigKey, adminKubeconfig)
+			if test.fixedUp {
+				fixed, err := controllerutils.FixupKubeconfig([]byte(adminKubeconfig))
+				if err != nil {
+					t.Fatalf("unexpected error fixing up kubeconfig: %v", err)
+				}
+				secret.Data[rawAdminKubeconfigKey] = []byte(adminKubeconfig)
+				secret.Data[adminKubeconfigKey] = fixed
+			}
+			fakeClient := &conflictingClient{
+				Client:    fake.NewFakeClient(secret.DeepCopy()),
+				conflicts: test.conflicts,
+			}
+			rcd := &ReconcileClusterDeployment{
+				Client: fakeClient,
+				scheme: scheme.Scheme,
+			}
+
+			err := rcd.fixupAdminKubeconfigSecret(secret, log.WithField("test", test.name))
+			assert.Equal(t, test.expectedUpdates, fakeClient.updates, "unexpected number of updates")
+			if test.expectErr {
+				assert.True(t, errors.IsConflict(err), "expected conflict error, got %v", err)
+				return
+			}
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			stored := &corev1.Secret{}
+			if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: adminKubeconfigSecret}, stored); err != nil {
+				t.Fatalf("unexpected error getting secret: %v", err)
+			}
+			assert.Equal(t, adminKubeconfig, string(stored.Data[rawAdminKubeconfigKey]), "raw kubeconfig should be kept")
+			assert.Equal(t, stored.Data, secret.Data, "caller's secret should match the stored secret")
+		})
+	}
+}
+
+func TestUpdateOutdatedConfigurations(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cd := testClusterDeployment()
+	cd.Generation = 2
+	job, cfgMap, err := install.GenerateInstallerJob(cd, images.DefaultHiveImage, "", serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+	if !assert.NoError(t, err, "unexpected error generating install job") {
+		return
+	}
+
+	outdatedCfgMap := cfgMap.DeepCopy()
+	outdatedCfgMap.Annotations = map[string]string{
+		clusterDeploymentGenerationAnnotation: "1",
+	}
+	outdatedCfgMap.Data = map[string]string{
+		"install-config.yaml": "outdated",
+	}
+
+	fakeClient := fake.NewFakeClient(outdatedCfgMap, job)
+	rcd := &ReconcileClusterDeployment{
+		Client: fakeClient,
+		scheme: scheme.Scheme,
+	}
+
+	changed, err := rcd.updateOutdatedConfigurations(cd.Generation, job, cfgMap, nil, log.New())
+	assert.NoError(t, err, "unexpected error updating outdated configurations")
+	assert.True(t, changed, "expected outdated config map to be updated")
+
+	updatedCfgMap := &corev1.ConfigMap{}
+	if assert.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Name: cfgMap.Name, Namespace: cfgMap.Namespace}, updatedCfgMap)) {
+		assert.Equal(t, cfgMap.Data, updatedCfgMap.Data, "config map data should have been updated")
+		assert.Equal(t, "2", updatedCfgMap.Annotations[clusterDeploymentGenerationAnnotation], "config map generation should have been updated")
+	}
+
+	changed, err = rcd.updateOutdatedConfigurations(cd.Generation, job, cfgMap, nil, log.New())
+	assert.NoError(t, err, "unexpected error updating outdated configurations")
+	assert.False(t, changed, "up to date config map should not be updated again")
+}
+
+func TestUpdateOutdatedInstallConfigSecret(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	cd := testClusterDeployment()
+	cd.Generation = 2
+	cd.Spec.InstallConfigSecret = true
+	job, cfgMap, err := install.GenerateInstallerJob(cd, images.DefaultHiveImage, "", serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+	if !assert.NoError(t, err, "unexpected error generating install job") {
+		return
+	}
+	cfgSecret := install.GenerateInstallConfigSecret(cfgMap)
+
+	outdatedSecret := cfgSecret.DeepCopy()
+	outdatedSecret.Annotations = map[string]string{
+		clusterDeploymentGenerationAnnotation: "1",
+	}
+	outdatedSecret.Data = map[string][]byte{
+		"install-config.yaml": []byte("outdated"),
+	}
+
+	fakeClient := fake.NewFakeClient(outdatedSecret, job)
+	rcd := &ReconcileClusterDeployment{
+		Client: fakeClient,
+		scheme: scheme.Scheme,
+	}
+
+	changed, err := rcd.updateOutdatedConfigurations(cd.Generation, job, cfgMap, cfgSecret, log.New())
+	assert.NoError(t, err, "unexpected error updating outdated configurations")
+	assert.True(t, changed, "expected outdated secret to be updated")
+
+	updatedSecret := &corev1.Secret{}
+	if assert.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Name: cfgSecret.Name, Namespace: cfgSecret.Namespace}, updatedSecret)) {
+		assert.Equal(t, cfgSecret.Data, updatedSecret.Data, "secret data should have been updated")
+		assert.Equal(t, "2", updatedSecret.Annotations[clusterDeploymentGenerationAnnotation], "secret generation should have been updated")
+	}
+
+	changed, err = rcd.updateOutdatedConfigurations(cd.Generation, job, cfgMap, cfgSecret, log.New())
+	assert.NoError(t, err, "unexpected error updating outdated configurations")
+	assert.False(t, changed, "up to date secret should not be updated again")
+}
+
+func TestAddClusterDeploymentFinalizer(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name            string
+		conflicts       int
+		expectErr       bool
+		expectFinalizer bool
+	}{
+		{
+			name:            "no conflict",
+			expectFinalizer: true,
+		},
+		{
+			name:            "conflict then success",
+			conflicts:       1,
+			expectFinalizer: true,
+		},
+		{
+			name:      "persistent conflict",
+			conflicts: 100,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := &conflictingClient{
+				Client:    fake.NewFakeClient(testClusterDeploymentWithoutFinalizer()),
+				conflicts: test.conflicts,
+			}
+			rcd := &ReconcileClusterDeployment{
+				Client: fakeClient,
+				scheme: scheme.Scheme,
+			}
+
+			err := rcd.addClusterDeploymentFinalizer(testClusterDeploymentWithoutFinalizer())
+			if test.expectErr {
+				assert.True(t, errors.IsConflict(err), "expected conflict error")
+			} else {
+				assert.NoError(t, err, "unexpected error")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)
+			if assert.NoError(t, err, "unexpected error getting clusterdeployment") {
+				assert.Equal(t, test.expectFinalizer, controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision), "unexpected finalizer state")
+			}
+		})
+	}
+}
+
+func TestCalcInstallPodRestarts(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	installPod := func(name string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+				Labels: map[string]string{
+					install.ClusterDeploymentNameLabel: testName,
+					install.InstallJobLabel:            "true",
+				},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: statuses,
+			},
+		}
+	}
+	containerStatus := func(restarts int32, reason string) corev1.ContainerStatus {
+		cs := corev1.ContainerStatus{RestartCount: restarts}
+		if reason != "" {
+			cs.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{Reason: reason}
+		}
+		return cs
+	}
+
+	tests := []struct {
+		name               string
+		existing           []runtime.Object
+		expectedRestarts   int
+		expectedTermReason string
+	}{
+		{
+			name:     "no pods",
+			existing: []runtime.Object{},
+		},
+		{
+			name: "no terminations",
 			existing: []runtime.Object{
-				testClusterDeployment(),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
-				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
-				func() *batchv1.Job {
-					job := testInstallJob()
-					job.Annotations[jobHashAnnotation] = "DIFFERENTHASH"
-					return job
-				}(),
+				installPod("pod1", containerStatus(0, ""), containerStatus(0, "")),
 			},
-			validate: func(c client.Client, t *testing.T) {
-				installJob := getInstallJob(c)
-				assert.Nil(t, installJob, "install job should not exist")
+		},
+		{
+			name: "oom killed",
+			existing: []runtime.Object{
+				installPod("pod1", containerStatus(0, ""), containerStatus(2, "OOMKilled")),
 			},
+			expectedRestarts:   2,
+			expectedTermReason: "OOMKilled",
 		},
 		{
-			name: "Ignore old install job hash difference if cluster already installed",
+			name: "dominant reason across pods",
 			existing: []runtime.Object{
-				func() *hivev1.ClusterDeployment {
-					cd := testClusterDeployment()
-					cd.Status.Installed = true
-					cd.Status.AdminKubeconfigSecret = corev1.LocalObjectReference{Name: adminKubeconfigSecret}
-					return cd
-				}(),
-				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
-				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
-				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
-				func() *batchv1.Job {
-					job := testInstallJob()
-					job.Annotations[jobHashAnnotation] = "DIFFERENTHASH"
-					return job
-				}(),
+				installPod("pod1", containerStatus(1, "Error"), containerStatus(1, "OOMKilled")),
+				installPod("pod2", containerStatus(0, ""), containerStatus(3, "OOMKilled")),
 			},
-			validate: func(c client.Client, t *testing.T) {
-				installJob := getInstallJob(c)
-				assert.NotNil(t, installJob, "install job should not be touched after the clusterdeployment is installed")
+			expectedRestarts:   5,
+			expectedTermReason: "OOMKilled",
+		},
+		{
+			name: "tie prefers first reason alphabetically",
+			existing: []runtime.Object{
+				installPod("pod1", containerStatus(1, "OOMKilled"), containerStatus(1, "Error")),
 			},
+			expectedRestarts:   2,
+			expectedTermReason: "Error",
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			fakeClient := fake.NewFakeClient(test.existing...)
 			rcd := &ReconcileClusterDeployment{
-				Client:                        fakeClient,
-				scheme:                        scheme.Scheme,
-				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
-			}
-
-			_, err := rcd.Reconcile(reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      testName,
-					Namespace: testNamespace,
-				},
-			})
-
-			if test.validate != nil {
-				test.validate(fakeClient, t)
+				Client: fake.NewFakeClient(test.existing...),
+				scheme: scheme.Scheme,
 			}
 
-			if err != nil && !test.expectErr {
-				t.Errorf("Unexpected error: %v", err)
-			}
-			if err == nil && test.expectErr {
-				t.Errorf("Expected error but got none")
+			pods, err := rcd.listInstallPods(testClusterDeployment())
+			if assert.NoError(t, err, "unexpected error") {
+				restarts, reason := calcInstallPodRestarts(pods, log.New())
+				assert.Equal(t, test.expectedRestarts, restarts, "unexpected restart count")
+				assert.Equal(t, test.expectedTermReason, reason, "unexpected termination reason")
 			}
 		})
 	}
 }
 
-func TestClusterDeploymentReconcileResults(t *testing.T) {
+func TestSetInstallPodDuplicationCondition(t *testing.T) {
 	apis.AddToScheme(scheme.Scheme)
 
+	installPod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+				Labels: map[string]string{
+					install.ClusterDeploymentNameLabel: testName,
+					install.InstallJobLabel:            "true",
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: phase,
+			},
+		}
+	}
+
 	tests := []struct {
-		name                     string
-		existing                 []runtime.Object
-		exptectedReconcileResult reconcile.Result
+		name              string
+		cd                *hivev1.ClusterDeployment
+		existing          []runtime.Object
+		expectedCondition *corev1.ConditionStatus
+		expectEvent       bool
 	}{
 		{
-			name: "Requeue after adding finalizer",
+			name: "one active pod",
+			cd:   testClusterDeployment(),
 			existing: []runtime.Object{
-				testClusterDeploymentWithoutFinalizer(),
+				installPod("pod1", corev1.PodRunning),
 			},
-			exptectedReconcileResult: reconcile.Result{},
+		},
+		{
+			name: "two active pods",
+			cd:   testClusterDeployment(),
+			existing: []runtime.Object{
+				installPod("pod1", corev1.PodRunning),
+				installPod("pod2", corev1.PodPending),
+			},
+			expectedCondition: conditionStatusPtr(corev1.ConditionTrue),
+			expectEvent:       true,
+		},
+		{
+			name: "completed pod is not active",
+			cd:   testClusterDeployment(),
+			existing: []runtime.Object{
+				installPod("pod1", corev1.PodFailed),
+				installPod("pod2", corev1.PodRunning),
+			},
+		},
+		{
+			name: "no event when duplication already reported",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+					{
+						Type:   hivev1.InstallPodDuplicationCondition,
+						Status: corev1.ConditionTrue,
+						Reason: installPodDuplicationReason,
+					},
+				}
+				return cd
+			}(),
+			existing: []runtime.Object{
+				installPod("pod1", corev1.PodRunning),
+				installPod("pod2", corev1.PodRunning),
+			},
+			expectedCondition: conditionStatusPtr(corev1.ConditionTrue),
+		},
+		{
+			name: "duplication resolved",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+					{
+						Type:   hivev1.InstallPodDuplicationCondition,
+						Status: corev1.ConditionTrue,
+						Reason: installPodDuplicationReason,
+					},
+				}
+				return cd
+			}(),
+			existing: []runtime.Object{
+				installPod("pod1", corev1.PodRunning),
+			},
+			expectedCondition: conditionStatusPtr(corev1.ConditionFalse),
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			fakeClient := fake.NewFakeClient(test.existing...)
+			recorder := record.NewFakeRecorder(10)
 			rcd := &ReconcileClusterDeployment{
-				Client:                        fakeClient,
-				scheme:                        scheme.Scheme,
-				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				Client:        fake.NewFakeClient(test.existing...),
+				scheme:        scheme.Scheme,
+				eventRecorder: recorder,
 			}
 
-			reconcileResult, err := rcd.Reconcile(reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      testName,
-					Namespace: testNamespace,
-				},
-			})
+			pods, err := rcd.listInstallPods(test.cd)
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+			rcd.setInstallPodDuplicationCondition(test.cd, pods, log.New())
 
-			assert.NoError(t, err, "unexpected error")
+			cond := controllerutils.FindClusterDeploymentCondition(test.cd.Status.Conditions, hivev1.InstallPodDuplicationCondition)
+			if test.expectedCondition == nil {
+				assert.Nil(t, cond, "unexpected InstallPodDuplication condition")
+			} else if assert.NotNil(t, cond, "missing InstallPodDuplication condition") {
+				assert.Equal(t, *test.expectedCondition, cond.Status, "unexpected InstallPodDuplication condition status")
+			}
 
-			assert.Equal(t, test.exptectedReconcileResult, reconcileResult, "unexpected reconcile result")
+			if test.expectEvent {
+				assert.Len(t, recorder.Events, 1, "expected an event")
+			} else {
+				assert.Empty(t, recorder.Events, "unexpected event")
+			}
 		})
 	}
 }
 
-func testEmptyClusterDeployment() *hivev1.ClusterDeployment {
-	cd := &hivev1.ClusterDeployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        testName,
-			Namespace:   testNamespace,
-			Finalizers:  []string{hivev1.FinalizerDeprovision},
-			UID:         types.UID("1234"),
-			Annotations: map[string]string{},
+func TestClusterDeploymentInstallPhaseCleared(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	openshiftapiv1.Install(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	cd := testClusterDeployment()
+	cd.Status.InstallPhase = "DestroyingBootstrap"
+	fakeClient := fake.NewFakeClient(
+		cd,
+		testCompletedInstallJob(),
+		testMetadataConfigMap(),
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+		testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        fakeClient,
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 record.NewFakeRecorder(10),
+	}
+
+	_, err := rcd.Reconcile(reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testName,
+			Namespace: testNamespace,
 		},
+	})
+	if !assert.NoError(t, err, "unexpected error") {
+		return
+	}
+
+	cd = &hivev1.ClusterDeployment{}
+	err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, cd)
+	if assert.NoError(t, err, "missing cluster deployment") && assert.True(t, cd.Status.Installed, "cluster should be installed") {
+		assert.Empty(t, cd.Status.InstallPhase, "install phase should be cleared once installed")
 	}
-	return cd
 }
 
-func testClusterDeployment() *hivev1.ClusterDeployment {
-	cd := testEmptyClusterDeployment()
+func TestSelectorPodWatchHandler(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
 
-	cd.Spec = hivev1.ClusterDeploymentSpec{
-		ClusterName: testClusterName,
-		SSHKey: &corev1.LocalObjectReference{
-			Name: sshKeySecret,
-		},
-		ControlPlane: hivev1.MachinePool{},
-		Compute:      []hivev1.MachinePool{},
-		PullSecret: corev1.LocalObjectReference{
-			Name: pullSecretSecret,
-		},
-		Platform: hivev1.Platform{
-			AWS: &hivev1.AWSPlatform{
-				Region: "us-east-1",
-			},
+	longName := strings.Repeat("a", 100)
+	longCD := testClusterDeployment()
+	longCD.Name = longName
+	tests := []struct {
+		name         string
+		labelValue   string
+		expectedName string
+	}{
+		{
+			name:         "short name",
+			labelValue:   install.ClusterDeploymentNameLabelValue(testName),
+			expectedName: testName,
 		},
-		Networking: hivev1.Networking{
-			Type: hivev1.NetworkTypeOpenshiftSDN,
+		{
+			name:         "long name",
+			labelValue:   install.ClusterDeploymentNameLabelValue(longName),
+			expectedName: longName,
 		},
-		PlatformSecrets: hivev1.PlatformSecrets{
-			AWS: &hivev1.AWSPlatformSecrets{
-				Credentials: corev1.LocalObjectReference{
-					Name: "aws-credentials",
-				},
-			},
+		{
+			name:       "long name without cluster deployment",
+			labelValue: install.ClusterDeploymentNameLabelValue(strings.Repeat("b", 100)),
 		},
 	}
-
-	cd.Status = hivev1.ClusterDeploymentStatus{
-		ClusterID:      testClusterID,
-		InfraID:        testInfraID,
-		InstallerImage: strPtr("installer-image:latest"),
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rcd := &ReconcileClusterDeployment{
+				Client: fake.NewFakeClient(testClusterDeployment(), longCD),
+				scheme: scheme.Scheme,
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "install-pod",
+					Namespace: testNamespace,
+					Labels:    map[string]string{install.ClusterDeploymentNameLabel: test.labelValue},
+				},
+			}
+			requests := rcd.selectorPodWatchHandler(handler.MapObject{Meta: pod, Object: pod})
+			if test.expectedName == "" {
+				assert.Empty(t, requests, "unexpected requests")
+				return
+			}
+			assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: test.expectedName}}}, requests, "unexpected requests")
+		})
 	}
-
-	controllerutils.FixupEmptyClusterVersionFields(&cd.Status.ClusterVersionStatus)
-	return cd
 }
 
-func testClusterDeploymentWithoutFinalizer() *hivev1.ClusterDeployment {
-	cd := testClusterDeployment()
-	cd.Finalizers = []string{}
-	return cd
+func getJob(c client.Client, name string) *batchv1.Job {
+	job := &batchv1.Job{}
+	err := c.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: testNamespace}, job)
+	if err == nil {
+		return job
+	}
+	return nil
 }
 
-func testDeletedClusterDeployment() *hivev1.ClusterDeployment {
-	cd := testClusterDeployment()
-	now := metav1.Now()
-	cd.DeletionTimestamp = &now
-	return cd
+func getInstallJob(c client.Client) *batchv1.Job {
+	return getJob(c, installJobName)
 }
 
-func testDeletedClusterDeploymentWithoutFinalizer() *hivev1.ClusterDeployment {
-	cd := testClusterDeployment()
-	now := metav1.Now()
-	cd.DeletionTimestamp = &now
-	cd.Finalizers = []string{}
-	return cd
-}
+func TestCaptureFailedInstallLog(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
 
-func testExpiredClusterDeployment() *hivev1.ClusterDeployment {
-	cd := testClusterDeployment()
-	cd.CreationTimestamp = metav1.Time{Time: metav1.Now().Add(-60 * time.Minute)}
-	cd.Annotations[deleteAfterAnnotation] = "5m"
-	return cd
-}
+	failedJob := func() *batchv1.Job {
+		job := testInstallJob()
+		job.Status.Conditions = []batchv1.JobCondition{
+			{
+				Type:   batchv1.JobFailed,
+				Status: corev1.ConditionTrue,
+				Reason: "BackoffLimitExceeded",
+			},
+		}
+		return job
+	}
+	installPod := func(name string, created time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         testNamespace,
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					install.ClusterDeploymentNameLabel: testName,
+					install.InstallJobLabel:            "true",
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+			},
+		}
+	}
+	existingLog := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      install.GetInstallLogConfigMapName(testClusterDeployment()),
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{
+			installLogConfigMapKey: "previous log",
+		},
+	}
+	now := time.Now()
 
-func testInstallJob() *batchv1.Job {
-	cd := testClusterDeployment()
-	job, _, err := install.GenerateInstallerJob(cd,
-		images.DefaultHiveImage,
-		"",
-		serviceAccountName, "testSSHKey", "testPullSecret")
-	if err != nil {
-		panic("should not error while generating test install job")
+	tests := []struct {
+		name                  string
+		existing              []runtime.Object
+		failedInstallLogBytes int64
+		expectedLog           string
+		expectedPod           string
+	}{
+		{
+			name: "failed install log captured",
+			existing: []runtime.Object{
+				failedJob(),
+				installPod("pod1", now),
+			},
+			failedInstallLogBytes: 1024,
+			expectedLog:           "log for pod1",
+			expectedPod:           "pod1",
+		},
+		{
+			name: "failed install log truncated",
+			existing: []runtime.Object{
+				failedJob(),
+				installPod("pod1", now),
+			},
+			failedInstallLogBytes: 4,
+			expectedLog:           "pod1",
+			expectedPod:           "pod1",
+		},
+		{
+			name: "log captured from newest pod",
+			existing: []runtime.Object{
+				failedJob(),
+				installPod("pod1", now.Add(-time.Hour)),
+				installPod("pod2", now),
+			},
+			failedInstallLogBytes: 1024,
+			expectedLog:           "log for pod2",
+			expectedPod:           "pod2",
+		},
+		{
+			name: "log capture disabled",
+			existing: []runtime.Object{
+				failedJob(),
+				installPod("pod1", now),
+			},
+		},
+		{
+			name: "install not failed",
+			existing: []runtime.Object{
+				testInstallJob(),
+				installPod("pod1", now),
+			},
+			failedInstallLogBytes: 1024,
+		},
+		{
+			name: "log already captured",
+			existing: []runtime.Object{
+				failedJob(),
+				installPod("pod1", now),
+				existingLog,
+			},
+			failedInstallLogBytes: 1024,
+			expectedLog:           "previous log",
+		},
 	}
 
-	controllerutil.SetControllerReference(cd, job, scheme.Scheme)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := append([]runtime.Object{
+				testClusterDeployment(),
+				testPullSecret(),
+				testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+			}, test.existing...)
+			fakeClient := fake.NewFakeClient(existing...)
+			readPods := []string{}
+			rcd := &ReconcileClusterDeployment{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				eventRecorder:                 record.NewFakeRecorder(10),
+				failedInstallLogBytes:         test.failedInstallLogBytes,
+				installPodLogReader: func(namespace, podName string, limitBytes int64) ([]byte, error) {
+					readPods = append(readPods, podName)
+					return readTail(strings.NewReader("log for "+podName), limitBytes)
+				},
+			}
 
-	hash, err := calculateJobSpecHash(job)
-	if err != nil {
-		panic("should never get error calculating job spec hash")
-	}
+			_, err := rcd.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      testName,
+					Namespace: testNamespace,
+				},
+			})
+			assert.NoError(t, err, "unexpected error")
 
-	job.Annotations[jobHashAnnotation] = hash
-	return job
-}
+			if test.expectedPod != "" {
+				assert.Equal(t, []string{test.expectedPod}, readPods, "unexpected pod logs read")
+			} else {
+				assert.Empty(t, readPods, "no pod logs should be read")
+			}
 
-func testCompletedInstallJob() *batchv1.Job {
-	job := testInstallJob()
-	job.Status.Conditions = []batchv1.JobCondition{
-		{
-			Type:   batchv1.JobComplete,
-			Status: corev1.ConditionTrue,
-		},
+			cm := &corev1.ConfigMap{}
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Name: install.GetInstallLogConfigMapName(testClusterDeployment()), Namespace: testNamespace}, cm)
+			if test.expectedLog == "" {
+				assert.True(t, errors.IsNotFound(err), "install log configmap should not exist")
+				return
+			}
+			if assert.NoError(t, err, "expected install log configmap") {
+				assert.Equal(t, test.expectedLog, cm.Data[installLogConfigMapKey], "unexpected install log")
+			}
+		})
 	}
-	return job
-}
-
-func testMetadataConfigMap() *corev1.ConfigMap {
-	cm := &corev1.ConfigMap{}
-	cm.Name = metadataName
-	cm.Namespace = testNamespace
-	metadataJSON := `{
-		"aws": {
-			"identifier": [{"openshiftClusterID": "testFooClusterUUID"}]
-		}
-	}`
-	cm.Data = map[string]string{"metadata.json": metadataJSON}
-	return cm
 }
 
-func testSecret(secretType corev1.SecretType, name, key, value string) *corev1.Secret {
-	s := &corev1.Secret{
-		Type: secretType,
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: testNamespace,
+func TestReadTail(t *testing.T) {
+	installLog := strings.Repeat("0123456789", 10000)
+	tests := []struct {
+		name       string
+		limitBytes int64
+		expected   string
+	}{
+		{
+			name:       "shorter than limit",
+			limitBytes: 200000,
+			expected:   installLog,
 		},
-		Data: map[string][]byte{
-			key: []byte(value),
+		{
+			name:       "truncated within a chunk",
+			limitBytes: 15,
+			expected:   "567890123456789",
+		},
+		{
+			name:       "truncated across chunks",
+			limitBytes: 50000,
+			expected:   installLog[50000:],
 		},
 	}
-	return s
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tail, err := readTail(strings.NewReader(installLog), test.limitBytes)
+			if assert.NoError(t, err, "unexpected error") {
+				assert.Equal(t, test.expected, string(tail), "unexpected log tail")
+			}
+		})
+	}
 }
 
-func testRemoteClusterAPIClientBuilder(secretData string) (client.Client, error) {
-	remoteClusterVersion := &openshiftapiv1.ClusterVersion{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: remoteClusterVersionObjectName,
-		},
+func TestSanitizeForLog(t *testing.T) {
+	route := &routev1.Route{}
+	route.Spec.Host = "console.apps.example.com"
+	route.Spec.TLS = &routev1.TLSConfig{
+		Termination:              routev1.TLSTerminationReencrypt,
+		Key:                      "secret-private-key",
+		Certificate:              "secret-certificate",
+		CACertificate:            "secret-ca-certificate",
+		DestinationCACertificate: "secret-destination-ca-certificate",
 	}
-	remoteClusterVersion.Status = testRemoteClusterVersionStatus()
+	sanitized := sanitizeForLog(route)
+	assert.Contains(t, sanitized, "console.apps.example.com", "non-sensitive fields should be kept")
+	assert.Contains(t, sanitized, redactedLogValue, "sensitive fields should be redacted")
+	assert.NotContains(t, sanitized, "secret-", "sensitive values should not be logged")
 
-	remoteClusterRouteObject := &routev1.Route{
+	cd := testClusterDeployment()
+	cd.Annotations[corev1.LastAppliedConfigAnnotation] = `{"secret-applied-config":true}`
+	cd.Spec.Compute = []hivev1.MachinePool{{
+		Name:   "worker",
+		Taints: []corev1.Taint{{Key: "dedicated-taint-key", Effect: corev1.TaintEffectNoSchedule}},
+	}}
+	sanitized = sanitizeForLog(cd)
+	assert.Contains(t, sanitized, testName, "non-sensitive fields should be kept")
+	assert.Contains(t, sanitized, "dedicated-taint-key", "fields named like sensitive fields elsewhere should be kept")
+	assert.NotContains(t, sanitized, "secret-applied-config", "last applied configuration should not be logged")
+
+	secret := testSecret(corev1.SecretTypeOpaque, "test-secret", "password", "secret-password")
+	sanitized = sanitizeForLog(secret)
+	assert.NotContains(t, sanitized, base64.StdEncoding.EncodeToString([]byte("secret-password")), "secret data should not be logged")
+}
+
+func TestSetAdminKubeconfigStatusRedactsRoute(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	route := &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      remoteClusterRouteObjectName,
 			Namespace: remoteClusterRouteObjectNamespace,
 		},
 	}
-	remoteClusterRouteObject.Spec.Host = "bar-api.clusters.example.com:6443/console"
-
-	remoteClient := fake.NewFakeClient(remoteClusterVersion, remoteClusterRouteObject)
-	return remoteClient, nil
-}
-
-func testRemoteClusterVersionStatus() openshiftapiv1.ClusterVersionStatus {
-	status := openshiftapiv1.ClusterVersionStatus{
-		History: []openshiftapiv1.UpdateHistory{
-			{
-				State:   openshiftapiv1.CompletedUpdate,
-				Version: testRemoteClusterCurrentVersion,
-				Image:   "TESTIMAGE",
-			},
+	route.Spec.Host = "console.apps.example.com"
+	route.Spec.TLS = &routev1.TLSConfig{
+		Termination: routev1.TLSTerminationReencrypt,
+		Key:         "secret-private-key",
+	}
+	rcd := &ReconcileClusterDeployment{
+		Client: fake.NewFakeClient(),
+		scheme: scheme.Scheme,
+		remoteClusterAPIClientBuilder: func(string) (client.Client, error) {
+			return fake.NewFakeClient(route), nil
 		},
-		ObservedGeneration: 123456789,
-		VersionHash:        "TESTVERSIONHASH",
 	}
-	return status
-}
 
-func testClusterImageSet() *hivev1.ClusterImageSet {
-	cis := &hivev1.ClusterImageSet{}
-	cis.Name = testClusterImageSetName
-	cis.Spec.ReleaseImage = strPtr("test-release-image:latest")
-	return cis
-}
+	out := &bytes.Buffer{}
+	logger := log.New()
+	logger.Out = out
+	logger.SetLevel(log.DebugLevel)
 
-func testDNSZone() *hivev1.DNSZone {
-	zone := &hivev1.DNSZone{}
-	zone.Name = testName + "-zone"
-	zone.Namespace = testNamespace
-	return zone
+	cd := testClusterDeployment()
+	err := rcd.setAdminKubeconfigStatus(cd, testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig), logger.WithField("clusterDeployment", testName))
+	if assert.NoError(t, err, "unexpected error") {
+		assert.Equal(t, "https://console.apps.example.com", cd.Status.WebConsoleURL, "unexpected web console URL")
+		assert.Contains(t, out.String(), "read remote route object", "expected route to be logged")
+		assert.NotContains(t, out.String(), "secret-private-key", "route TLS key should not be logged")
+	}
 }
 
-func testAvailableDNSZone() *hivev1.DNSZone {
-	zone := testDNSZone()
-	zone.Status.Conditions = []hivev1.DNSZoneCondition{
-		{
-			Type:   hivev1.ZoneAvailableDNSZoneCondition,
-			Status: corev1.ConditionTrue,
+func TestSetAdminKubeconfigStatusConsoleRouteOverride(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	routev1.Install(scheme.Scheme)
+
+	defaultRoute := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      remoteClusterRouteObjectName,
+			Namespace: remoteClusterRouteObjectNamespace,
 		},
 	}
-	return zone
-}
-
-func testClusterDeploymentWithIngress() *hivev1.ClusterDeployment {
-	cd := testClusterDeployment()
-	cd.Spec.Ingress = []hivev1.ClusterIngress{
-		{
-			Name:   "default",
-			Domain: fmt.Sprintf("apps.%s.example.com", cd.Spec.ClusterName),
+	defaultRoute.Spec.Host = "console.apps.example.com"
+	customRoute := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "custom-console",
+			Namespace: "custom-console-namespace",
 		},
 	}
-
-	return cd
-}
-
-func TestClusterDeploymentWildcardDomainMigration(t *testing.T) {
-	apis.AddToScheme(scheme.Scheme)
+	customRoute.Spec.Host = "custom-console.apps.example.com"
+	customRoute.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
 
 	tests := []struct {
-		name              string
-		existing          *hivev1.ClusterDeployment
-		migrationExpected bool
-		expectedDomains   []string // must be same length as the number of ingress entries for the test clusterDeployment
+		name        string
+		route       *hivev1.ConsoleRouteReference
+		expectedURL string
 	}{
 		{
-			name:              "No ingress, no migration",
-			existing:          testClusterDeployment(),
-			migrationExpected: false,
-		},
-		{
-			name:              "No wildcards, no migration",
-			existing:          testClusterDeploymentWithIngress(),
-			migrationExpected: false,
-			expectedDomains:   []string{fmt.Sprintf("apps.%s.example.com", testClusterName)},
+			name:        "default console route",
+			expectedURL: "http://console.apps.example.com",
 		},
 		{
-			name: "Migrate wildcard domain",
-			existing: func() *hivev1.ClusterDeployment {
-				cd := testClusterDeploymentWithIngress()
-				cd.Spec.Ingress[0].Domain = fmt.Sprintf("*.apps.%s.example.com", cd.Spec.ClusterName)
-
-				return cd
-			}(),
-			migrationExpected: true,
-			expectedDomains:   []string{fmt.Sprintf("apps.%s.example.com", testClusterName)},
+			name:        "console route override",
+			route:       &hivev1.ConsoleRouteReference{Namespace: "custom-console-namespace", Name: "custom-console"},
+			expectedURL: "https://custom-console.apps.example.com",
 		},
 		{
-			name: "Migrate when only one of two is wildcard",
-			existing: func() *hivev1.ClusterDeployment {
-				cd := testClusterDeploymentWithIngress()
-				cd.Spec.Ingress = append(cd.Spec.Ingress, hivev1.ClusterIngress{
-					Name:   "extraingress",
-					Domain: fmt.Sprintf("*.moreingress.%s.example.com", cd.Spec.ClusterName),
-				})
-
-				return cd
-			}(),
-			migrationExpected: true,
-			expectedDomains: []string{
-				fmt.Sprintf("apps.%s.example.com", testClusterName),
-				fmt.Sprintf("moreingress.%s.example.com", testClusterName),
-			},
+			name:        "incomplete console route override",
+			route:       &hivev1.ConsoleRouteReference{Name: "custom-console"},
+			expectedURL: "http://console.apps.example.com",
 		},
 	}
-
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-
-			result := migrateWildcardIngress(test.existing)
-
-			assert.Equal(t, test.migrationExpected, result)
-
-			for i, domain := range test.expectedDomains {
-				assert.Equal(t, domain, test.existing.Spec.Ingress[i].Domain)
+			rcd := &ReconcileClusterDeployment{
+				Client: fake.NewFakeClient(),
+				scheme: scheme.Scheme,
+				remoteClusterAPIClientBuilder: func(string) (client.Client, error) {
+					return fake.NewFakeClient(defaultRoute.DeepCopy(), customRoute.DeepCopy()), nil
+				},
+			}
+			cd := testClusterDeployment()
+			cd.Spec.ConsoleRoute = test.route
+			err := rcd.setAdminKubeconfigStatus(cd, testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig), log.WithField("clusterDeployment", testName))
+			if assert.NoError(t, err, "unexpected error") {
+				assert.Equal(t, test.expectedURL, cd.Status.WebConsoleURL, "unexpected web console URL")
 			}
 		})
 	}
 }
 
-func TestClusterDeploymentJobHashing(t *testing.T) {
-	apis.AddToScheme(scheme.Scheme)
-
+func TestLoggerForClusterDeployment(t *testing.T) {
 	tests := []struct {
-		name           string
-		existingJob    *batchv1.Job
-		generatedJob   *batchv1.Job
-		expectedResult bool
-		expectedError  bool
+		name          string
+		annotation    *string
+		expectedLevel log.Level
 	}{
 		{
-			name: "No existing annotation",
-			existingJob: func() *batchv1.Job {
-				job := testInstallJob()
-				delete(job.Annotations, jobHashAnnotation)
-				return job
-			}(),
-			generatedJob:   testInstallJob(),
-			expectedResult: true,
+			name:          "no annotation",
+			expectedLevel: log.InfoLevel,
 		},
 		{
-			name: "Different hash",
-			existingJob: func() *batchv1.Job {
-				job := testInstallJob()
-				job.Annotations[jobHashAnnotation] = "DIFFERENTHASH"
-				return job
-			}(),
-			generatedJob:   testInstallJob(),
-			expectedResult: true,
+			name:          "debug annotation",
+			annotation:    strPtr("debug"),
+			expectedLevel: log.DebugLevel,
 		},
 		{
-			name:           "Same hash",
-			existingJob:    testInstallJob(),
-			generatedJob:   testInstallJob(),
-			expectedResult: false,
+			name:          "less verbose annotation",
+			annotation:    strPtr("error"),
+			expectedLevel: log.InfoLevel,
+		},
+		{
+			name:          "invalid annotation",
+			annotation:    strPtr("chatty"),
+			expectedLevel: log.InfoLevel,
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			fakeClient := fake.NewFakeClient(test.existingJob)
-			rcd := &ReconcileClusterDeployment{
-				Client: fakeClient,
+			out := &bytes.Buffer{}
+			logger := log.New()
+			logger.Out = out
+			logger.SetLevel(log.InfoLevel)
+			cdLog := logger.WithField("clusterDeployment", testName)
+
+			cd := testClusterDeployment()
+			if test.annotation != nil {
+				cd.Annotations[logLevelAnnotation] = *test.annotation
 			}
-			tLogger := log.New()
 
-			result, err := rcd.deleteJobOnHashChange(test.existingJob, test.generatedJob, tLogger)
+			result := loggerForClusterDeployment(cd, cdLog)
+			assert.Equal(t, test.expectedLevel, result.Logger.GetLevel(), "unexpected log level")
+			assert.Equal(t, log.InfoLevel, logger.GetLevel(), "controller log level should not change")
 
-			if test.expectedError {
-				assert.Error(t, err, "expected error during test case")
+			out.Reset()
+			result.Debug("debug message")
+			if test.expectedLevel == log.DebugLevel {
+				assert.Contains(t, out.String(), "debug message", "expected debug message to be logged")
+				assert.Contains(t, out.String(), "clusterDeployment="+testName, "expected logger fields to be kept")
 			} else {
-				assert.Equal(t, test.expectedResult, result)
-
-				if test.expectedResult { //if job was deleted
-					job := getInstallJob(fakeClient)
-					assert.Nil(t, job, "previous install job should have been deleted")
-				}
+				assert.NotContains(t, out.String(), "debug message", "debug message should not be logged")
 			}
-
 		})
 	}
 }
-
-func getJob(c client.Client, name string) *batchv1.Job {
-	job := &batchv1.Job{}
-	err := c.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: testNamespace}, job)
-	if err == nil {
-		return job
-	}
-	return nil
-}
-
-func getInstallJob(c client.Client) *batchv1.Job {
-	return getJob(c, installJobName)
-}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	awsPlatformName     = "aws"
+	libvirtPlatformName = "libvirt"
+)
+
+// deprovisionRequestBuilder fills in the platform-specific settings of the deprovision request for a cluster
+// deployment.
+type deprovisionRequestBuilder func(c client.Client, cd *hivev1.ClusterDeployment, req *hivev1.ClusterDeprovisionRequest) error
+
+// deprovisionRequestBuilders are the deprovision request builders of the platforms hive can deprovision, keyed
+// by platform name.
+var deprovisionRequestBuilders = map[string]deprovisionRequestBuilder{
+	awsPlatformName: buildAWSDeprovisionRequest,
+}
+
+// getPlatformName returns the name of the platform the cluster deployment is installed on, or an empty string
+// if no platform is set.
+func getPlatformName(cd *hivev1.ClusterDeployment) string {
+	switch {
+	case cd.Spec.Platform.AWS != nil:
+		return awsPlatformName
+	case cd.Spec.Platform.Libvirt != nil:
+		return libvirtPlatformName
+	default:
+		return ""
+	}
+}
+
+// isDeprovisionSupported returns true when a deprovision request builder is registered for the platform of the
+// cluster deployment.
+func isDeprovisionSupported(cd *hivev1.ClusterDeployment) bool {
+	_, ok := deprovisionRequestBuilders[getPlatformName(cd)]
+	return ok
+}
+
+// generateDeprovisionRequest returns the deprovision request for the cluster deployment, with the platform
+// settings filled in by the builder registered for its platform.
+func generateDeprovisionRequest(c client.Client, cd *hivev1.ClusterDeployment) (*hivev1.ClusterDeprovisionRequest, error) {
+	platform := getPlatformName(cd)
+	build, ok := deprovisionRequestBuilders[platform]
+	if !ok {
+		return nil, fmt.Errorf("deprovisioning is not supported for platform %q", platform)
+	}
+
+	req := &hivev1.ClusterDeprovisionRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cd.Name,
+			Namespace: cd.Namespace,
+		},
+		Spec: hivev1.ClusterDeprovisionRequestSpec{
+			InfraID:   cd.Status.InfraID,
+			ClusterID: cd.Status.ClusterID,
+		},
+	}
+	if err := build(c, cd, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// buildAWSDeprovisionRequest fills in the AWS region, credentials and user tags of a deprovision request. The
+// default AWS credentials of the namespace are used when the cluster deployment does not reference any.
+func buildAWSDeprovisionRequest(c client.Client, cd *hivev1.ClusterDeployment, req *hivev1.ClusterDeprovisionRequest) error {
+	aws := &hivev1.AWSClusterDeprovisionRequest{
+		Region:   cd.Spec.Platform.AWS.Region,
+		UserTags: cd.Spec.Platform.AWS.UserTags,
+	}
+	if cd.Spec.PlatformSecrets.AWS != nil {
+		aws.Credentials = &cd.Spec.PlatformSecrets.AWS.Credentials
+	}
+	defaultCredentials, err := controllerutils.DefaultAWSCredentialsSecret(c, cd)
+	if err != nil {
+		return fmt.Errorf("error getting default AWS credentials secret: %v", err)
+	}
+	if defaultCredentials != nil {
+		aws.Credentials = defaultCredentials
+	}
+	req.Spec.Platform.AWS = aws
+	return nil
+}
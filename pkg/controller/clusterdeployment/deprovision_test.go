@@ -0,0 +1,128 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+)
+
+func testDeprovisionRequest(t *testing.T, cd *hivev1.ClusterDeployment) *hivev1.ClusterDeprovisionRequest {
+	req, err := generateDeprovisionRequest(fake.NewFakeClient(), cd)
+	if err != nil {
+		t.Fatalf("unexpected error generating deprovision request: %v", err)
+	}
+	return req
+}
+
+func TestGenerateDeprovisionRequest(t *testing.T) {
+	libvirtClusterDeployment := func() *hivev1.ClusterDeployment {
+		cd := testClusterDeployment()
+		cd.Spec.Platform.AWS = nil
+		cd.Spec.Platform.Libvirt = &hivev1.LibvirtPlatform{}
+		cd.Spec.PlatformSecrets.AWS = nil
+		return cd
+	}
+	noPlatformClusterDeployment := func() *hivev1.ClusterDeployment {
+		cd := testClusterDeployment()
+		cd.Spec.Platform.AWS = nil
+		return cd
+	}
+	// testLibvirtBuilder stands in for a libvirt builder so that the dispatch to it can be observed.
+	testLibvirtBuilder := func(c client.Client, cd *hivev1.ClusterDeployment, req *hivev1.ClusterDeprovisionRequest) error {
+		req.Spec.Platform.AWS = &hivev1.AWSClusterDeprovisionRequest{Region: "libvirt"}
+		return nil
+	}
+
+	tests := []struct {
+		name            string
+		cd              *hivev1.ClusterDeployment
+		builders        map[string]deprovisionRequestBuilder
+		expectErr       bool
+		validateRequest func(*testing.T, *hivev1.ClusterDeprovisionRequest)
+	}{
+		{
+			name: "aws",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Spec.AWS.UserTags = map[string]string{"owner": "hive"}
+				return cd
+			}(),
+			validateRequest: func(t *testing.T, req *hivev1.ClusterDeprovisionRequest) {
+				assert.Equal(t, testName, req.Name, "unexpected name")
+				assert.Equal(t, testNamespace, req.Namespace, "unexpected namespace")
+				assert.Equal(t, testInfraID, req.Spec.InfraID, "unexpected infra ID")
+				assert.Equal(t, testClusterID, req.Spec.ClusterID, "unexpected cluster ID")
+				if assert.NotNil(t, req.Spec.Platform.AWS, "missing AWS platform") {
+					assert.Equal(t, "us-east-1", req.Spec.Platform.AWS.Region, "unexpected region")
+					if assert.NotNil(t, req.Spec.Platform.AWS.Credentials, "missing credentials") {
+						assert.Equal(t, "aws-credentials", req.Spec.Platform.AWS.Credentials.Name, "unexpected credentials")
+					}
+					assert.Equal(t, map[string]string{"owner": "hive"}, req.Spec.Platform.AWS.UserTags, "unexpected user tags")
+				}
+			},
+		},
+		{
+			name: "builder selected by platform",
+			cd:   libvirtClusterDeployment(),
+			builders: map[string]deprovisionRequestBuilder{
+				awsPlatformName:     buildAWSDeprovisionRequest,
+				libvirtPlatformName: testLibvirtBuilder,
+			},
+			validateRequest: func(t *testing.T, req *hivev1.ClusterDeprovisionRequest) {
+				if assert.NotNil(t, req.Spec.Platform.AWS, "libvirt builder not called") {
+					assert.Equal(t, "libvirt", req.Spec.Platform.AWS.Region, "unexpected builder called")
+				}
+			},
+		},
+		{
+			name:      "unsupported platform",
+			cd:        libvirtClusterDeployment(),
+			expectErr: true,
+		},
+		{
+			name:      "no platform",
+			cd:        noPlatformClusterDeployment(),
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.builders != nil {
+				defer func(builders map[string]deprovisionRequestBuilder) {
+					deprovisionRequestBuilders = builders
+				}(deprovisionRequestBuilders)
+				deprovisionRequestBuilders = test.builders
+			}
+
+			req, err := generateDeprovisionRequest(fake.NewFakeClient(), test.cd)
+			if test.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			if assert.NoError(t, err, "unexpected error") {
+				test.validateRequest(t, req)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	defaultCircuitBreakerMinimumInstalls = 5
+	defaultCircuitBreakerWindow          = time.Hour
+	defaultCircuitBreakerCooldown        = 30 * time.Minute
+
+	hiveConfigName = "hive"
+
+	installFailureRateExceededReason       = "InstallFailureRateExceeded"
+	installFailureRateBelowThresholdReason = "InstallFailureRateBelowThreshold"
+)
+
+// installCircuitBreaker tracks the outcome of recent install jobs across all cluster deployments, and pauses
+// the creation of new install jobs for a cooldown period when too many of them have failed.
+type installCircuitBreaker struct {
+	// failurePercent is the percentage of recent installs which must have failed to trip the breaker.
+	failurePercent int
+	// minimumInstalls is the number of recent installs required before the breaker can trip.
+	minimumInstalls int
+	// window is how far back install outcomes are counted.
+	window time.Duration
+	// cooldown is how long new installs are paused once the breaker trips.
+	cooldown time.Duration
+	// now returns the current time, and is replaced in tests.
+	now func() time.Time
+
+	mu sync.Mutex
+	// outcomes holds whether each recently finished install job failed, keyed by job UID.
+	outcomes map[types.UID]installOutcome
+	// openUntil is the end of the cooldown while the breaker is tripped.
+	openUntil time.Time
+	// resetAt is when the breaker last reset. Installs which finished before then are not counted again.
+	resetAt time.Time
+}
+
+type installOutcome struct {
+	failed   bool
+	finished time.Time
+}
+
+// newInstallCircuitBreaker returns an install circuit breaker configured from the environment, or nil when
+// the circuit breaker is not enabled.
+func newInstallCircuitBreaker() *installCircuitBreaker {
+	value := os.Getenv(constants.InstallCircuitBreakerFailurePercentEnvVar)
+	if value == "" {
+		return nil
+	}
+	failurePercent, err := strconv.Atoi(value)
+	if err != nil || failurePercent <= 0 || failurePercent > 100 {
+		log.WithField("value", value).Warn("invalid install circuit breaker failure percent, circuit breaker disabled")
+		return nil
+	}
+	return &installCircuitBreaker{
+		failurePercent:  failurePercent,
+		minimumInstalls: getCircuitBreakerMinimumInstalls(),
+		window:          getCircuitBreakerDuration(constants.InstallCircuitBreakerWindowEnvVar, defaultCircuitBreakerWindow),
+		cooldown:        getCircuitBreakerDuration(constants.InstallCircuitBreakerCooldownEnvVar, defaultCircuitBreakerCooldown),
+		now:             time.Now,
+		outcomes:        map[types.UID]installOutcome{},
+	}
+}
+
+func getCircuitBreakerMinimumInstalls() int {
+	value := os.Getenv(constants.InstallCircuitBreakerMinimumInstallsEnvVar)
+	if value == "" {
+		return defaultCircuitBreakerMinimumInstalls
+	}
+	minimum, err := strconv.Atoi(value)
+	if err != nil || minimum <= 0 {
+		log.WithField("value", value).Warn("invalid install circuit breaker minimum installs, using default")
+		return defaultCircuitBreakerMinimumInstalls
+	}
+	return minimum
+}
+
+func getCircuitBreakerDuration(envVar string, defaultDuration time.Duration) time.Duration {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultDuration
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.WithError(err).WithField(envVar, value).Warn("invalid install circuit breaker duration, using default")
+		return defaultDuration
+	}
+	return duration
+}
+
+// recordInstallJob records the outcome of the given install job if it has finished. Each job is only counted
+// once no matter how often it is recorded.
+func (b *installCircuitBreaker) recordInstallJob(job *batchv1.Job) {
+	var failed bool
+	var finished time.Time
+	switch {
+	case controllerutils.IsFailed(job):
+		failed = true
+		finished = jobConditionTime(job, batchv1.JobFailed)
+	case controllerutils.IsSuccessful(job):
+		finished = jobConditionTime(job, batchv1.JobComplete)
+		if job.Status.CompletionTime != nil {
+			finished = job.Status.CompletionTime.Time
+		}
+	default:
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if finished.IsZero() {
+		finished = now
+	}
+	b.pruneOutcomes(now)
+	if finished.Before(now.Add(-b.window)) || finished.Before(b.resetAt) {
+		return
+	}
+	if _, ok := b.outcomes[job.UID]; !ok {
+		b.outcomes[job.UID] = installOutcome{failed: failed, finished: finished}
+	}
+}
+
+// check returns how much longer new installs are paused, and a message describing the state of the breaker.
+// Zero is returned when installs may proceed.
+func (b *installCircuitBreaker) check() (time.Duration, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+
+	if !b.openUntil.IsZero() {
+		if now.Before(b.openUntil) {
+			return b.openUntil.Sub(now), fmt.Sprintf("new installs are paused until %s after too many install failures",
+				b.openUntil.UTC().Format(time.RFC3339))
+		}
+		// The cooldown has passed, so start counting afresh.
+		b.openUntil = time.Time{}
+		b.resetAt = now
+		b.outcomes = map[types.UID]installOutcome{}
+	}
+
+	b.pruneOutcomes(now)
+	failures := 0
+	for _, outcome := range b.outcomes {
+		if outcome.failed {
+			failures++
+		}
+	}
+	total := len(b.outcomes)
+	if total < b.minimumInstalls || failures*100 < b.failurePercent*total {
+		return 0, fmt.Sprintf("%d of the last %d installs failed", failures, total)
+	}
+
+	b.openUntil = now.Add(b.cooldown)
+	return b.cooldown, fmt.Sprintf("%d of the last %d installs failed, new installs are paused until %s",
+		failures, total, b.openUntil.UTC().Format(time.RFC3339))
+}
+
+// pruneOutcomes forgets installs which finished before the window. Callers must hold the lock.
+func (b *installCircuitBreaker) pruneOutcomes(now time.Time) {
+	cutoff := now.Add(-b.window)
+	for uid, outcome := range b.outcomes {
+		if outcome.finished.Before(cutoff) {
+			delete(b.outcomes, uid)
+		}
+	}
+}
+
+func jobConditionTime(job *batchv1.Job, conditionType batchv1.JobConditionType) time.Time {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
+// setInstallCircuitBreakerCondition reflects the state of the install circuit breaker in the
+// InstallCircuitBreakerOpen condition of the HiveConfig.
+func (r *ReconcileClusterDeployment) setInstallCircuitBreakerCondition(open bool, message string, cdLog log.FieldLogger) error {
+	hiveConfig := &hivev1.HiveConfig{}
+	err := r.Get(context.TODO(), types.NamespacedName{Name: hiveConfigName}, hiveConfig)
+	if errors.IsNotFound(err) {
+		cdLog.Debug("no hiveconfig found, not setting install circuit breaker condition")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	status := corev1.ConditionFalse
+	reason := installFailureRateBelowThresholdReason
+	if open {
+		status = corev1.ConditionTrue
+		reason = installFailureRateExceededReason
+	}
+	original := hiveConfig.Status.Conditions
+	hiveConfig.Status.Conditions = controllerutils.SetHiveConfigCondition(
+		append([]hivev1.HiveConfigCondition(nil), original...),
+		hivev1.InstallCircuitBreakerOpenCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	existing := controllerutils.FindHiveConfigCondition(original, hivev1.InstallCircuitBreakerOpenCondition)
+	updated := controllerutils.FindHiveConfigCondition(hiveConfig.Status.Conditions, hivev1.InstallCircuitBreakerOpenCondition)
+	if (existing == nil && updated == nil) || (existing != nil && updated != nil && existing.Status == updated.Status) {
+		return nil
+	}
+	cdLog.WithField("open", open).Info("install circuit breaker changed state")
+	return r.Status().Update(context.TODO(), hiveConfig)
+}
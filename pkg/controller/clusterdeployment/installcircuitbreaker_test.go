@@ -0,0 +1,230 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+func testInstallCircuitBreaker(now *time.Time) *installCircuitBreaker {
+	return &installCircuitBreaker{
+		failurePercent:  50,
+		minimumInstalls: 4,
+		window:          time.Hour,
+		cooldown:        30 * time.Minute,
+		now:             func() time.Time { return *now },
+		outcomes:        map[types.UID]installOutcome{},
+	}
+}
+
+func testFinishedInstallJob(uid string, failed bool, finished time.Time) *batchv1.Job {
+	conditionType := batchv1.JobComplete
+	if failed {
+		conditionType = batchv1.JobFailed
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: types.UID(uid),
+		},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{
+					Type:               conditionType,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(finished),
+				},
+			},
+		},
+	}
+}
+
+func TestInstallCircuitBreaker(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		outcomes []bool
+		finished time.Duration
+		expected bool
+	}{
+		{
+			name:     "no installs",
+			expected: false,
+		},
+		{
+			name:     "too few installs",
+			outcomes: []bool{true, true, true},
+			expected: false,
+		},
+		{
+			name:     "failure rate below threshold",
+			outcomes: []bool{true, false, false, false},
+			expected: false,
+		},
+		{
+			name:     "failure rate at threshold",
+			outcomes: []bool{true, true, false, false},
+			expected: true,
+		},
+		{
+			name:     "failures outside the window",
+			outcomes: []bool{true, true, true, true},
+			finished: -2 * time.Hour,
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := start
+			breaker := testInstallCircuitBreaker(&now)
+			for i, failed := range test.outcomes {
+				breaker.recordInstallJob(testFinishedInstallJob(fmt.Sprintf("job-%d", i), failed, start.Add(test.finished)))
+			}
+			pausedFor, _ := breaker.check()
+			if test.expected {
+				assert.Equal(t, breaker.cooldown, pausedFor, "expected installs to be paused for the cooldown")
+			} else {
+				assert.Zero(t, pausedFor, "expected installs to proceed")
+			}
+		})
+	}
+}
+
+func TestInstallCircuitBreakerCountsJobsOnce(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	breaker := testInstallCircuitBreaker(&now)
+	job := testFinishedInstallJob("job", true, now)
+	for i := 0; i < 5; i++ {
+		breaker.recordInstallJob(job)
+	}
+	breaker.recordInstallJob(testInstallJob())
+	assert.Len(t, breaker.outcomes, 1, "each finished job should be counted once")
+	pausedFor, _ := breaker.check()
+	assert.Zero(t, pausedFor, "a single repeatedly observed failure should not trip the breaker")
+}
+
+func TestInstallCircuitBreakerReset(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	breaker := testInstallCircuitBreaker(&now)
+	for i := 0; i < 4; i++ {
+		breaker.recordInstallJob(testFinishedInstallJob(fmt.Sprintf("job-%d", i), true, start))
+	}
+
+	pausedFor, _ := breaker.check()
+	assert.Equal(t, 30*time.Minute, pausedFor, "breaker should trip")
+
+	now = start.Add(10 * time.Minute)
+	pausedFor, _ = breaker.check()
+	assert.Equal(t, 20*time.Minute, pausedFor, "breaker should stay open during the cooldown")
+
+	now = start.Add(30 * time.Minute)
+	pausedFor, _ = breaker.check()
+	assert.Zero(t, pausedFor, "breaker should reset after the cooldown")
+
+	// Failures from before the reset are not counted again.
+	for i := 0; i < 4; i++ {
+		breaker.recordInstallJob(testFinishedInstallJob(fmt.Sprintf("job-%d", i), true, start))
+	}
+	pausedFor, _ = breaker.check()
+	assert.Zero(t, pausedFor, "failures from before the reset should not trip the breaker")
+
+	for i := 4; i < 8; i++ {
+		breaker.recordInstallJob(testFinishedInstallJob(fmt.Sprintf("job-%d", i), true, now))
+	}
+	pausedFor, _ = breaker.check()
+	assert.Equal(t, 30*time.Minute, pausedFor, "new failures should trip the breaker again")
+}
+
+func TestClusterDeploymentInstallCircuitBreaker(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	breaker := testInstallCircuitBreaker(&now)
+	for i := 0; i < 4; i++ {
+		breaker.recordInstallJob(testFinishedInstallJob(fmt.Sprintf("job-%d", i), true, start))
+	}
+
+	fakeClient := fake.NewFakeClient(
+		testClusterDeployment(),
+		testPullSecret(),
+		testSecret(corev1.SecretTypeOpaque, sshKeySecret, adminSSHKeySecretKey, "fakesshkey"),
+		&hivev1.HiveConfig{ObjectMeta: metav1.ObjectMeta{Name: hiveConfigName}},
+	)
+	rcd := &ReconcileClusterDeployment{
+		Client:                        fakeClient,
+		scheme:                        scheme.Scheme,
+		remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+		eventRecorder:                 record.NewFakeRecorder(10),
+		installCircuitBreaker:         breaker,
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+	}
+	getCondition := func() *hivev1.HiveConfigCondition {
+		hiveConfig := &hivev1.HiveConfig{}
+		if err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: hiveConfigName}, hiveConfig); err != nil {
+			t.Fatalf("unexpected error getting hiveconfig: %v", err)
+		}
+		return controllerutils.FindHiveConfigCondition(hiveConfig.Status.Conditions, hivev1.InstallCircuitBreakerOpenCondition)
+	}
+
+	result, err := rcd.Reconcile(request)
+	if !assert.NoError(t, err, "unexpected error") {
+		return
+	}
+	assert.Nil(t, getInstallJob(fakeClient), "install job should not be created while the breaker is open")
+	assert.Equal(t, breaker.cooldown, result.RequeueAfter, "expected requeue after the cooldown")
+	if condition := getCondition(); assert.NotNil(t, condition, "missing circuit breaker condition") {
+		assert.Equal(t, corev1.ConditionTrue, condition.Status, "unexpected condition status")
+		assert.Equal(t, installFailureRateExceededReason, condition.Reason, "unexpected condition reason")
+	}
+
+	now = start.Add(breaker.cooldown)
+	_, err = rcd.Reconcile(request)
+	if !assert.NoError(t, err, "unexpected error") {
+		return
+	}
+	assert.NotNil(t, getInstallJob(fakeClient), "install job should be created once the breaker resets")
+	if condition := getCondition(); assert.NotNil(t, condition, "missing circuit breaker condition") {
+		assert.Equal(t, corev1.ConditionFalse, condition.Status, "unexpected condition status")
+		assert.Equal(t, installFailureRateBelowThresholdReason, condition.Reason, "unexpected condition reason")
+	}
+}
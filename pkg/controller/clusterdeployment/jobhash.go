@@ -0,0 +1,44 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// calculateJobSpecHash returns a hash of the job spec. It is only ever calculated for the job hive generates, never
+// for the job read back from the API server, so fields filled in by the API server or admission plugins do not
+// reach the hash.
+func calculateJobSpecHash(job *batchv1.Job) (string, error) {
+	hasher := md5.New()
+	jobSpecBytes, err := job.Spec.Marshal()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = hasher.Write(jobSpecBytes)
+	if err != nil {
+		return "", err
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+
+	return sum, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/hive/pkg/install"
+)
+
+func testGeneratedInstallJob(t *testing.T) *batchv1.Job {
+	cd := testClusterDeployment()
+	job, _, err := install.GenerateInstallerJob(cd, "example.com/hive:latest", "", serviceAccountName, "testSSHKey", "testPullSecret", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error generating install job: %v", err)
+	}
+	return job
+}
+
+func TestCalculateJobSpecHash(t *testing.T) {
+	gracePeriod := int64(30)
+
+	tests := []struct {
+		name   string
+		mutate func(job *batchv1.Job)
+	}{
+		{
+			name: "container image",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.Template.Spec.Containers[0].Image = "example.com/other:latest"
+			},
+		},
+		{
+			name: "environment",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+					Name:  "OPENSHIFT_INSTALL_OS_IMAGE_OVERRIDE",
+					Value: "ami-0123456789",
+				})
+			},
+		},
+		{
+			name: "termination grace period",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.Template.Spec.TerminationGracePeriodSeconds = &gracePeriod
+			},
+		},
+		{
+			name: "automount service account token",
+			mutate: func(job *batchv1.Job) {
+				automount := false
+				job.Spec.Template.Spec.AutomountServiceAccountToken = &automount
+			},
+		},
+		{
+			name:   "service account token hidden from installer",
+			mutate: install.DisableInstallPodServiceAccountToken,
+		},
+		{
+			name: "dns policy",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.Template.Spec.DNSPolicy = corev1.DNSDefault
+			},
+		},
+		{
+			name: "other volume",
+			mutate: func(job *batchv1.Job) {
+				job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
+					Name: "extra",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				})
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job := testGeneratedInstallJob(t)
+			hash, err := calculateJobSpecHash(job)
+			if !assert.NoError(t, err) {
+				return
+			}
+			test.mutate(job)
+			mutatedHash, err := calculateJobSpecHash(job)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NotEqual(t, hash, mutatedHash, "expected the job hash to change")
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	defaultReconcileFailureBaseDelay = time.Second
+	defaultReconcileFailureMaxDelay  = 10 * time.Minute
+)
+
+// rateLimitedReconciler retries failed reconciles of a cluster deployment after a delay from its own rate
+// limiter rather than the default rate limiter of the controller, which retries a failing object within
+// milliseconds. Each cluster deployment backs off separately, so one which fails on every reconcile does not
+// starve the others.
+type rateLimitedReconciler struct {
+	reconcile.Reconciler
+	rateLimiter workqueue.RateLimiter
+}
+
+// newRateLimitedReconciler wraps the given reconciler with a per cluster deployment exponential backoff
+// configured from the environment.
+func newRateLimitedReconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	baseDelay := getReconcileFailureDelay(constants.ReconcileFailureBaseDelayEnvVar, defaultReconcileFailureBaseDelay)
+	maxDelay := getReconcileFailureDelay(constants.ReconcileFailureMaxDelayEnvVar, defaultReconcileFailureMaxDelay)
+	if maxDelay < baseDelay {
+		log.WithField("baseDelay", baseDelay).WithField("maxDelay", maxDelay).Warn("reconcile failure max delay is below the base delay, using the base delay")
+		maxDelay = baseDelay
+	}
+	return &rateLimitedReconciler{
+		Reconciler:  r,
+		rateLimiter: workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+	}
+}
+
+// Reconcile reconciles the request, and turns a failure or an immediate requeue into a requeue after the
+// backoff of the request. The backoff is reset once a reconcile succeeds. The controller never sees the errors,
+// so they are logged and counted here.
+func (r *rateLimitedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	result, err := r.Reconciler.Reconcile(request)
+	if err == nil && (!result.Requeue || result.RequeueAfter > 0) {
+		r.rateLimiter.Forget(request)
+		return result, nil
+	}
+	delay := r.rateLimiter.When(request)
+	logger := log.WithField("controller", controllerName).WithField("clusterDeployment", request.NamespacedName).
+		WithField("failures", r.rateLimiter.NumRequeues(request)).WithField("delay", delay)
+	if err != nil {
+		metricReconcileErrors.Inc()
+		logger.WithError(err).Error("reconcile failed, backing off")
+	} else {
+		logger.Debug("requeue requested, backing off")
+	}
+	return reconcile.Result{RequeueAfter: delay}, nil
+}
+
+func getReconcileFailureDelay(envVar string, defaultDelay time.Duration) time.Duration {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultDelay
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay <= 0 {
+		log.WithError(err).WithField(envVar, value).Warn("invalid reconcile failure delay, using default")
+		return defaultDelay
+	}
+	return delay
+}
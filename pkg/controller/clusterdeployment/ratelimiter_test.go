@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeployment
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+// fakeReconciler fails the reconcile of the cluster deployments in failing.
+type fakeReconciler struct {
+	failing map[types.NamespacedName]bool
+}
+
+func (r *fakeReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if r.failing[request.NamespacedName] {
+		return reconcile.Result{}, fmt.Errorf("reconcile failed")
+	}
+	return reconcile.Result{}, nil
+}
+
+func TestRateLimitedReconciler(t *testing.T) {
+	os.Setenv(constants.ReconcileFailureBaseDelayEnvVar, "1s")
+	os.Setenv(constants.ReconcileFailureMaxDelayEnvVar, "10s")
+	defer os.Unsetenv(constants.ReconcileFailureBaseDelayEnvVar)
+	defer os.Unsetenv(constants.ReconcileFailureMaxDelayEnvVar)
+
+	failing := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "failing"}}
+	healthy := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: "healthy"}}
+	inner := &fakeReconciler{failing: map[types.NamespacedName]bool{failing.NamespacedName: true}}
+	r := newRateLimitedReconciler(inner)
+	reconcileErrors := func() float64 {
+		m := &dto.Metric{}
+		if err := metricReconcileErrors.Write(m); err != nil {
+			t.Fatalf("unexpected error reading counter: %v", err)
+		}
+		return m.GetCounter().GetValue()
+	}
+	initialErrors := reconcileErrors()
+
+	expectedDelays := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, expected := range expectedDelays {
+		result, err := r.Reconcile(failing)
+		assert.NoError(t, err, "failures should be retried through the backoff rather than the controller's rate limiter")
+		assert.Equal(t, expected, result.RequeueAfter, "unexpected delay after failure %d", i+1)
+
+		result, err = r.Reconcile(healthy)
+		assert.NoError(t, err, "unexpected error")
+		assert.Equal(t, reconcile.Result{}, result, "healthy cluster deployment should not be delayed")
+	}
+	assert.Equal(t, initialErrors+float64(len(expectedDelays)), reconcileErrors(), "every failure should be counted")
+
+	inner.failing = nil
+	result, err := r.Reconcile(failing)
+	assert.NoError(t, err, "unexpected error")
+	assert.Equal(t, reconcile.Result{}, result, "unexpected result once the reconcile succeeds")
+
+	inner.failing = map[types.NamespacedName]bool{failing.NamespacedName: true}
+	result, _ = r.Reconcile(failing)
+	assert.Equal(t, time.Second, result.RequeueAfter, "backoff should be reset by a successful reconcile")
+}
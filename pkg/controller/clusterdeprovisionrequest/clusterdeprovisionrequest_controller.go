@@ -24,6 +24,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -38,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/controller/heartbeat"
 	"github.com/openshift/hive/pkg/controller/images"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
@@ -76,7 +78,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("clusterdeprovisionrequest-controller", mgr, controller.Options{Reconciler: r})
+	c, err := heartbeat.NewController("clusterdeprovisionrequest-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -108,6 +110,7 @@ type ReconcileClusterDeprovisionRequest struct {
 // and what is in the ClusterDeprovisionRequest.Spec
 // +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeprovisionrequests;clusterdeprovisionrequests/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeprovisionrequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 func (r *ReconcileClusterDeprovisionRequest) Reconcile(request reconcile.Request) (reconcile.Result, error) {
 	start := time.Now()
 	rLog := log.WithFields(log.Fields{
@@ -145,6 +148,11 @@ func (r *ReconcileClusterDeprovisionRequest) Reconcile(request reconcile.Request
 		return reconcile.Result{}, nil
 	}
 
+	if instance.Status.Failed {
+		rLog.Debug("clusterdeprovisionrequest has failed, skipping")
+		return reconcile.Result{}, nil
+	}
+
 	// Generate an uninstall job
 	hiveImage := images.GetHiveImage(rLog)
 	rLog.Debug("generating uninstall job")
@@ -190,6 +198,7 @@ func (r *ReconcileClusterDeprovisionRequest) Reconcile(request reconcile.Request
 		jobDuration := existingJob.Status.CompletionTime.Time.Sub(existingJob.Status.StartTime.Time)
 		rLog.WithField("duration", jobDuration.Seconds()).Debug("uninstall job completed")
 		instance.Status.Completed = true
+		instance.Status.DeletedResources = r.getDeletedResources(existingJob, rLog)
 		err = r.Status().Update(context.TODO(), instance)
 		if err != nil {
 			rLog.WithError(err).Error("error updating request status")
@@ -198,6 +207,44 @@ func (r *ReconcileClusterDeprovisionRequest) Reconcile(request reconcile.Request
 		metricUninstallJobDuration.Observe(float64(jobDuration.Seconds()))
 		return reconcile.Result{}, nil
 	}
+	if controllerutils.IsFailed(existingJob) {
+		rLog.Warn("uninstall job failed, setting failed status")
+		instance.Status.Failed = true
+		err = r.Status().Update(context.TODO(), instance)
+		if err != nil {
+			rLog.WithError(err).Error("error updating request status")
+		}
+		return reconcile.Result{}, err
+	}
 	rLog.Infof("uninstall job not yet successful")
 	return reconcile.Result{}, nil
 }
+
+// getDeletedResources returns the counts of the resources deleted by a successful uninstall job, which the
+// deprovision container reports in its termination message. Nil is returned when no counts were reported,
+// for example by uninstall jobs of an older hive image.
+func (r *ReconcileClusterDeprovisionRequest) getDeletedResources(job *batchv1.Job, rLog log.FieldLogger) map[string]int {
+	pods := &corev1.PodList{}
+	err := r.List(context.TODO(), client.MatchingLabels(map[string]string{"job-name": job.Name}).InNamespace(job.Namespace), pods)
+	if err != nil {
+		rLog.WithError(err).Warn("cannot list uninstall pods, deleted resources not reported")
+		return nil
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != "deprovision" || status.State.Terminated == nil || status.State.Terminated.Message == "" {
+				continue
+			}
+			counts, err := install.ParseDeprovisionReport(status.State.Terminated.Message)
+			if err != nil {
+				rLog.WithError(err).WithField("pod", pod.Name).Warn("cannot parse deleted resources reported by uninstall pod")
+				continue
+			}
+			return counts
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package clusterdeprovisionrequest
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
@@ -124,6 +125,64 @@ func TestClusterDeprovisionRequestReconcile(t *testing.T) {
 				validateCompleted(t, c)
 			},
 		},
+		{
+			name: "deleted resources reported by uninstall pod",
+			existing: []runtime.Object{
+				testClusterDeprovisionRequest(),
+				testSuccessfulUninstallJob(),
+				testUninstallPod(corev1.PodFailed, ""),
+				testUninstallPod(corev1.PodSucceeded, `{"ec2:instance":3,"s3":1}`),
+			},
+			validate: func(t *testing.T, c client.Client) {
+				validateCompleted(t, c)
+				validateDeletedResources(t, c, map[string]int{"ec2:instance": 3, "s3": 1})
+			},
+		},
+		{
+			name: "completed without deleted resources from older uninstall pod",
+			existing: []runtime.Object{
+				testClusterDeprovisionRequest(),
+				testSuccessfulUninstallJob(),
+				testUninstallPod(corev1.PodSucceeded, ""),
+			},
+			validate: func(t *testing.T, c client.Client) {
+				validateCompleted(t, c)
+				validateDeletedResources(t, c, nil)
+			},
+		},
+		{
+			name: "failed when job has failed",
+			existing: []runtime.Object{
+				testClusterDeprovisionRequest(),
+				func() runtime.Object {
+					job := testUninstallJob()
+					job.Status.Conditions = []batchv1.JobCondition{
+						{
+							Type:   batchv1.JobFailed,
+							Status: corev1.ConditionTrue,
+						},
+					}
+					return job
+				}(),
+			},
+			validate: func(t *testing.T, c client.Client) {
+				validateNotCompleted(t, c)
+				validateFailed(t, c)
+			},
+		},
+		{
+			name: "no-op failed",
+			existing: []runtime.Object{
+				func() runtime.Object {
+					req := testClusterDeprovisionRequest()
+					req.Status.Failed = true
+					return req
+				}(),
+			},
+			validate: func(t *testing.T, c client.Client) {
+				validateNoJobExists(t, c)
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -178,6 +237,53 @@ func testUninstallJob() *batchv1.Job {
 	return uninstallJob
 }
 
+func testSuccessfulUninstallJob() *batchv1.Job {
+	job := testUninstallJob()
+	job.Status.Conditions = []batchv1.JobCondition{
+		{
+			Type:   batchv1.JobComplete,
+			Status: corev1.ConditionTrue,
+		},
+	}
+	now := metav1.Now()
+	job.Status.CompletionTime = &now
+	job.Status.StartTime = &now
+	return job
+}
+
+func testUninstallPod(phase corev1.PodPhase, message string) *corev1.Pod {
+	job := testUninstallJob()
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name + "-" + string(phase),
+			Namespace: job.Namespace,
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "deprovision",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{Message: message},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateDeletedResources(t *testing.T, c client.Client, expected map[string]int) {
+	req := &hivev1.ClusterDeprovisionRequest{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, req)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(expected, req.Status.DeletedResources) {
+		t.Errorf("unexpected deleted resources: expected %v, got %v", expected, req.Status.DeletedResources)
+	}
+}
+
 func validateNoJobExists(t *testing.T, c client.Client) {
 	job := &batchv1.Job{}
 	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName + "-uninstall"}, job)
@@ -219,3 +325,14 @@ func validateCompleted(t *testing.T, c client.Client) {
 		t.Errorf("request is expected to be in completed state")
 	}
 }
+
+func validateFailed(t *testing.T, c client.Client) {
+	req := &hivev1.ClusterDeprovisionRequest{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, req)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !req.Status.Failed {
+		t.Errorf("request is expected to be in failed state")
+	}
+}
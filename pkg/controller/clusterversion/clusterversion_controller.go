@@ -19,12 +19,15 @@ package clusterversion
 import (
 	"context"
 	"fmt"
+	"os"
 	"reflect"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -37,6 +40,8 @@ import (
 
 	openshiftapiv1 "github.com/openshift/api/config/v1"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/heartbeat"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 )
@@ -45,6 +50,10 @@ const (
 	clusterVersionObjectName = "version"
 	clusterVersionUnknown    = "undef"
 	controllerName           = "clusterversion"
+
+	// defaultPollInterval is the default interval at which the remote ClusterVersion of an installed cluster
+	// is re-fetched.
+	defaultPollInterval = 30 * time.Minute
 )
 
 // Add creates a new ClusterDeployment Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -59,13 +68,29 @@ func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 		Client:                        hivemetrics.NewClientWithMetricsOrDie(mgr, controllerName),
 		scheme:                        mgr.GetScheme(),
 		remoteClusterAPIClientBuilder: controllerutils.BuildClusterAPIClientFromKubeconfig,
+		pollInterval:                  getPollInterval(),
+	}
+}
+
+// getPollInterval returns the cluster version poll interval from the environment, falling back to the
+// default if it is unset or invalid.
+func getPollInterval() time.Duration {
+	value := os.Getenv(constants.ClusterVersionPollIntervalEnvVar)
+	if value == "" {
+		return defaultPollInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.WithError(err).WithField("interval", value).Warn("invalid cluster version poll interval, using default")
+		return defaultPollInterval
 	}
+	return interval
 }
 
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
 func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("clusterversion-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	c, err := heartbeat.NewController("clusterversion-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
 	if err != nil {
 		return err
 	}
@@ -88,6 +113,9 @@ type ReconcileClusterVersion struct {
 	// remoteClusterAPIClientBuilder is a function pointer to the function that builds a client for the
 	// remote cluster's cluster-api
 	remoteClusterAPIClientBuilder func(string) (client.Client, error)
+	// pollInterval is the interval at which the remote ClusterVersion of an installed cluster is re-fetched.
+	// Zero disables periodic polling.
+	pollInterval time.Duration
 }
 
 // Reconcile reads that state of the cluster for a ClusterDeployment object and syncs the remote ClusterVersion status
@@ -120,6 +148,15 @@ func (r *ReconcileClusterVersion) Reconcile(request reconcile.Request) (reconcil
 		cdLog.Debug("skipping cluster with unreachable condition")
 		return reconcile.Result{}, nil
 	}
+
+	// Avoid fetching the remote cluster version more often than the poll interval for installed clusters.
+	if r.pollInterval > 0 && cd.Status.Installed && cd.Status.ClusterVersionLastCheckTime != nil {
+		nextCheck := cd.Status.ClusterVersionLastCheckTime.Add(r.pollInterval)
+		if wait := time.Until(nextCheck); wait > 0 {
+			cdLog.WithField("nextCheck", nextCheck).Debug("cluster version checked recently, skipping")
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
+	}
 	cdLog.Info("reconciling cluster version")
 
 	if len(cd.Status.AdminKubeconfigSecret.Name) == 0 {
@@ -156,6 +193,9 @@ func (r *ReconcileClusterVersion) Reconcile(request reconcile.Request) (reconcil
 	}
 
 	cdLog.Debug("reconcile complete")
+	if r.pollInterval > 0 && cd.Status.Installed {
+		return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -164,6 +204,12 @@ func (r *ReconcileClusterVersion) updateClusterVersionStatus(cd *hivev1.ClusterD
 	cdLog.WithField("clusterversion.status", clusterVersion.Status).Debug("remote cluster version status")
 	controllerutils.FixupEmptyClusterVersionFields(&clusterVersion.Status)
 	clusterVersion.Status.DeepCopyInto(&cd.Status.ClusterVersionStatus)
+	// Only record the check time when polling, otherwise every reconcile would update the status
+	// and trigger yet another reconcile.
+	if r.pollInterval > 0 && cd.Status.Installed {
+		now := metav1.Now()
+		cd.Status.ClusterVersionLastCheckTime = &now
+	}
 
 	if reflect.DeepEqual(cd.Status, origCD.Status) {
 		cdLog.Debug("status has not changed, nothing to update")
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -150,6 +151,119 @@ func TestClusterVersionReconcile(t *testing.T) {
 	}
 }
 
+func TestClusterVersionPolling(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	configv1.Install(scheme.Scheme)
+
+	pollInterval := 30 * time.Minute
+	installedClusterDeployment := func(lastCheck *time.Time) *hivev1.ClusterDeployment {
+		cd := testClusterDeployment()
+		cd.Status.Installed = true
+		cd.Status.ClusterVersionStatus.VersionHash = "OLDVERSIONHASH"
+		if lastCheck != nil {
+			checkTime := metav1.NewTime(*lastCheck)
+			cd.Status.ClusterVersionLastCheckTime = &checkTime
+		}
+		return cd
+	}
+	timePtr := func(d time.Duration) *time.Time {
+		checkTime := time.Now().Add(d)
+		return &checkTime
+	}
+
+	tests := []struct {
+		name                string
+		cd                  *hivev1.ClusterDeployment
+		pollInterval        time.Duration
+		expectUpdated       bool
+		expectedRequeueMin  time.Duration
+		expectedRequeueMax  time.Duration
+		expectLastCheckTime bool
+	}{
+		{
+			name:                "never checked",
+			cd:                  installedClusterDeployment(nil),
+			pollInterval:        pollInterval,
+			expectUpdated:       true,
+			expectedRequeueMin:  pollInterval,
+			expectedRequeueMax:  pollInterval,
+			expectLastCheckTime: true,
+		},
+		{
+			name:                "poll interval elapsed",
+			cd:                  installedClusterDeployment(timePtr(-2 * pollInterval)),
+			pollInterval:        pollInterval,
+			expectUpdated:       true,
+			expectedRequeueMin:  pollInterval,
+			expectedRequeueMax:  pollInterval,
+			expectLastCheckTime: true,
+		},
+		{
+			name:                "recently checked",
+			cd:                  installedClusterDeployment(timePtr(-10 * time.Minute)),
+			pollInterval:        pollInterval,
+			expectUpdated:       false,
+			expectedRequeueMin:  19 * time.Minute,
+			expectedRequeueMax:  20 * time.Minute,
+			expectLastCheckTime: true,
+		},
+		{
+			name:          "polling disabled",
+			cd:            installedClusterDeployment(nil),
+			expectUpdated: true,
+		},
+		{
+			name: "not installed",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := installedClusterDeployment(nil)
+				cd.Status.Installed = false
+				return cd
+			}(),
+			pollInterval:  pollInterval,
+			expectUpdated: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(test.cd, testKubeconfigSecret())
+			rcd := &ReconcileClusterVersion{
+				Client:                        fakeClient,
+				scheme:                        scheme.Scheme,
+				remoteClusterAPIClientBuilder: testRemoteClusterAPIClientBuilder,
+				pollInterval:                  test.pollInterval,
+			}
+
+			namespacedName := types.NamespacedName{
+				Name:      testName,
+				Namespace: testNamespace,
+			}
+			result, err := rcd.Reconcile(reconcile.Request{NamespacedName: namespacedName})
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			assert.True(t, result.RequeueAfter >= test.expectedRequeueMin && result.RequeueAfter <= test.expectedRequeueMax,
+				"unexpected requeue after %v", result.RequeueAfter)
+
+			cd := &hivev1.ClusterDeployment{}
+			if !assert.NoError(t, fakeClient.Get(context.TODO(), namespacedName, cd), "unexpected error getting clusterdeployment") {
+				return
+			}
+			if test.expectUpdated {
+				assert.Equal(t, "TESTVERSIONHASH", cd.Status.ClusterVersionStatus.VersionHash, "expected cluster version status to be updated")
+			} else {
+				assert.Equal(t, "OLDVERSIONHASH", cd.Status.ClusterVersionStatus.VersionHash, "cluster version status should not be updated")
+			}
+			if test.expectLastCheckTime {
+				assert.NotNil(t, cd.Status.ClusterVersionLastCheckTime, "expected last check time")
+			} else {
+				assert.Nil(t, cd.Status.ClusterVersionLastCheckTime, "unexpected last check time")
+			}
+		})
+	}
+}
+
 func testClusterDeployment() *hivev1.ClusterDeployment {
 	cd := &hivev1.ClusterDeployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -30,6 +30,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go/service/route53"
 
@@ -159,7 +160,7 @@ func (zr *ZoneReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
-	if zr.dnsZone.Spec.LinkToParentDomain {
+	if zr.dnsZone.Spec.LinkToParentDomain && !zr.isPrivateZone() {
 		err = zr.syncParentDomainLink(nameServers)
 		if err != nil {
 			zr.logger.WithError(err).Error("failed syncing parent domain link")
@@ -167,9 +168,21 @@ func (zr *ZoneReconciler) Reconcile() (reconcile.Result, error) {
 		}
 	}
 
-	isZoneSOAAvailable, err := zr.soaLookup(zr.dnsZone.Spec.Zone, zr.logger)
+	err = zr.syncAdditionalRecords()
 	if err != nil {
-		zr.logger.WithError(err).Error("error looking up SOA record for zone")
+		zr.logger.WithError(err).Error("failed syncing additional records")
+		return reconcile.Result{}, err
+	}
+
+	isZoneSOAAvailable := true
+	if zr.isPrivateZone() {
+		// The records of a private zone only resolve from within its VPC, so there is nothing to look up.
+		zr.logger.Debug("zone is private, not looking up SOA record")
+	} else {
+		isZoneSOAAvailable, err = zr.soaLookup(zr.dnsZone.Spec.Zone, zr.logger)
+		if err != nil {
+			zr.logger.WithError(err).Error("error looking up SOA record for zone")
+		}
 	}
 
 	reconcileResult := reconcile.Result{}
@@ -226,6 +239,10 @@ func (zr *ZoneReconciler) parentLinkRecord(nameServers []string) (*hivev1.DNSEnd
 		// dot at the end. If a dot is present, an update happens every sync.
 		targets = append(targets, strings.TrimSuffix(nameServer, "."))
 	}
+	recordTTL := defaultNSRecordTTL
+	if zr.dnsZone.Spec.RecordTTL > 0 {
+		recordTTL = zr.dnsZone.Spec.RecordTTL
+	}
 	endpoint := &hivev1.DNSEndpoint{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      parentLinkRecordName(zr.dnsZone.Name),
@@ -237,7 +254,7 @@ func (zr *ZoneReconciler) parentLinkRecord(nameServers []string) (*hivev1.DNSEnd
 					DNSName:    zr.dnsZone.Spec.Zone,
 					Targets:    targets,
 					RecordType: "NS",
-					RecordTTL:  defaultNSRecordTTL,
+					RecordTTL:  recordTTL,
 				},
 			},
 		},
@@ -248,6 +265,78 @@ func (zr *ZoneReconciler) parentLinkRecord(nameServers []string) (*hivev1.DNSEnd
 	return endpoint, nil
 }
 
+// syncAdditionalRecords ensures a DNSEndpoint exists holding the additional records of the zone, or that
+// none exists when the zone has no additional records.
+func (zr *ZoneReconciler) syncAdditionalRecords() error {
+	existingRecords := &hivev1.DNSEndpoint{}
+	existingRecordsName := types.NamespacedName{
+		Namespace: zr.dnsZone.Namespace,
+		Name:      additionalRecordsName(zr.dnsZone.Name),
+	}
+	err := zr.kubeClient.Get(context.TODO(), existingRecordsName, existingRecords)
+	if err != nil && !errors.IsNotFound(err) {
+		zr.logger.WithError(err).Error("failed retrieving existing additional records DNSEndpoint")
+		return err
+	}
+	dnsEndpointNotFound := err != nil
+
+	if len(zr.dnsZone.Spec.AdditionalRecords) == 0 {
+		if dnsEndpointNotFound {
+			return nil
+		}
+		zr.logger.Info("deleting additional records DNSEndpoint")
+		if err = zr.kubeClient.Delete(context.TODO(), existingRecords); err != nil && !errors.IsNotFound(err) {
+			zr.logger.WithError(err).Error("failed deleting additional records DNSEndpoint")
+			return err
+		}
+		return nil
+	}
+
+	records, err := zr.additionalRecords()
+	if err != nil {
+		zr.logger.WithError(err).Error("failed to create additional records DNSEndpoint")
+		return err
+	}
+
+	if dnsEndpointNotFound {
+		if err = zr.kubeClient.Create(context.TODO(), records); err != nil {
+			zr.logger.WithError(err).Error("failed creating additional records DNSEndpoint")
+			return err
+		}
+		return nil
+	}
+
+	if !reflect.DeepEqual(existingRecords.Spec, records.Spec) {
+		existingRecords.Spec = records.Spec
+		if err = zr.kubeClient.Update(context.TODO(), existingRecords); err != nil {
+			zr.logger.WithError(err).Error("failed to update existing additional records DNSEndpoint")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (zr *ZoneReconciler) additionalRecords() (*hivev1.DNSEndpoint, error) {
+	endpoint := &hivev1.DNSEndpoint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      additionalRecordsName(zr.dnsZone.Name),
+			Namespace: zr.dnsZone.Namespace,
+		},
+	}
+	for i := range zr.dnsZone.Spec.AdditionalRecords {
+		record := zr.dnsZone.Spec.AdditionalRecords[i].DeepCopy()
+		if record.RecordTTL == 0 {
+			record.RecordTTL = zr.dnsZone.Spec.RecordTTL
+		}
+		endpoint.Spec.Endpoints = append(endpoint.Spec.Endpoints, record)
+	}
+	if err := controllerutil.SetControllerReference(zr.dnsZone, endpoint, zr.scheme); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
 // syncTags determines if there are changes that need to happen to match tags in the spec
 func (zr *ZoneReconciler) syncTags(zoneID *string, existingTags []*route53.Tag) error {
 	expected := zr.expectedTags()
@@ -454,14 +543,30 @@ func (zr *ZoneReconciler) createRoute53HostedZone() (*route53.HostedZone, error)
 	logger := zr.logger.WithField("zone", zr.dnsZone.Spec.Zone)
 	logger.Info("Creating route53 hostedzone")
 	var hostedZone *route53.HostedZone
-	resp, err := zr.awsClient.CreateHostedZone(&route53.CreateHostedZoneInput{
+	input := &route53.CreateHostedZoneInput{
 		Name: aws.String(zr.dnsZone.Spec.Zone),
 		// We use the UID of the HostedZone resource as the caller reference so that if
 		// we fail to update the status of the HostedZone with the ID of the recently
 		// created zone, we don't attempt to recreate it. Same if communication fails on
 		// the response from AWS.
 		CallerReference: aws.String(string(zr.dnsZone.UID)),
-	})
+	}
+	if zr.isPrivateZone() {
+		vpcID, err := zr.privateZoneVPC()
+		if err != nil {
+			logger.WithError(err).Error("Failed to find VPC for private hosted zone")
+			return nil, err
+		}
+		logger.WithField("vpc", vpcID).Info("Hosted zone will be private")
+		input.HostedZoneConfig = &route53.HostedZoneConfig{
+			PrivateZone: aws.Bool(true),
+		}
+		input.VPC = &route53.VPC{
+			VPCId:     aws.String(vpcID),
+			VPCRegion: aws.String(zr.dnsZone.Spec.AWS.Region),
+		}
+	}
+	resp, err := zr.awsClient.CreateHostedZone(input)
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == route53.ErrCodeHostedZoneAlreadyExists {
 			// If the zone was already created, we need to find its ID
@@ -501,6 +606,31 @@ func (zr *ZoneReconciler) createRoute53HostedZone() (*route53.HostedZone, error)
 	return hostedZone, err
 }
 
+// isPrivateZone returns true when the hosted zone should be a private hosted zone.
+func (zr *ZoneReconciler) isPrivateZone() bool {
+	return zr.dnsZone.Spec.AWS != nil && zr.dnsZone.Spec.AWS.PrivateZone != nil
+}
+
+// privateZoneVPC returns the ID of the VPC holding the subnets of the private zone.
+func (zr *ZoneReconciler) privateZoneVPC() (string, error) {
+	subnets := zr.dnsZone.Spec.AWS.PrivateZone.Subnets
+	if len(subnets) == 0 {
+		return "", fmt.Errorf("private zone has no subnets to find its VPC from")
+	}
+	resp, err := zr.awsClient.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnets),
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, subnet := range resp.Subnets {
+		if vpcID := aws.StringValue(subnet.VpcId); vpcID != "" {
+			return vpcID, nil
+		}
+	}
+	return "", fmt.Errorf("no VPC found for subnets %v", subnets)
+}
+
 func (zr *ZoneReconciler) findZoneByCallerReference(domain, callerRef string) (*route53.HostedZone, error) {
 	logger := zr.logger.WithField("domain", domain).WithField("callerRef", callerRef)
 	logger.Debug("Searching for zone by domain and callerRef")
@@ -631,6 +761,10 @@ func parentLinkRecordName(dnsZoneName string) string {
 	return apihelpers.GetResourceName(dnsZoneName, "ns")
 }
 
+func additionalRecordsName(dnsZoneName string) string {
+	return apihelpers.GetResourceName(dnsZoneName, "records")
+}
+
 func lookupSOARecord(zone string, logger log.FieldLogger) (bool, error) {
 	// TODO: determine if there's a better way to obtain resolver endpoints
 	clientConfig, _ := dns.ClientConfigFromFile(resolverConfigFile)
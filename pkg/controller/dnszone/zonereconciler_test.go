@@ -23,6 +23,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/golang/mock/gomock"
@@ -30,6 +31,8 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 
@@ -101,14 +104,16 @@ func TestReconcile(t *testing.T) {
 	log.SetLevel(log.DebugLevel)
 
 	cases := []struct {
-		name                string
-		dnsZone             *hivev1.DNSZone
-		dnsEndpoint         *hivev1.DNSEndpoint
-		setupAWSMock        func(*mock.MockClientMockRecorder)
-		validateZone        func(*testing.T, *hivev1.DNSZone)
-		validateDNSEndpoint func(*testing.T, *hivev1.DNSEndpoint)
-		errorExpected       bool
-		soaLookupResult     bool
+		name                      string
+		dnsZone                   *hivev1.DNSZone
+		dnsEndpoint               *hivev1.DNSEndpoint
+		additionalRecords         *hivev1.DNSEndpoint
+		setupAWSMock              func(*mock.MockClientMockRecorder)
+		validateZone              func(*testing.T, *hivev1.DNSZone)
+		validateDNSEndpoint       func(*testing.T, *hivev1.DNSEndpoint)
+		validateAdditionalRecords func(*testing.T, *hivev1.DNSEndpoint)
+		errorExpected             bool
+		soaLookupResult           bool
 	}{
 		{
 			name:    "DNSZone without finalizer",
@@ -215,7 +220,79 @@ func TestReconcile(t *testing.T) {
 				mockGetNSRecord(expect)
 			},
 			validateDNSEndpoint: func(t *testing.T, endpoint *hivev1.DNSEndpoint) {
-				assert.NotNil(t, endpoint, "endpoint record should exist")
+				if assert.NotNil(t, endpoint, "endpoint record should exist") {
+					assert.Equal(t, defaultNSRecordTTL, endpoint.Spec.Endpoints[0].RecordTTL, "endpoint record should use the default TTL")
+				}
+			},
+		},
+		{
+			name: "Existing zone, link to parent, create DNSEndpoint with record TTL",
+			dnsZone: func() *hivev1.DNSZone {
+				zone := validDNSZoneWithLinkToParent()
+				zone.Spec.RecordTTL = hivev1.TTL(30)
+				return zone
+			}(),
+			setupAWSMock: func(expect *mock.MockClientMockRecorder) {
+				mockZoneExists(expect, validDNSZoneWithAdditionalTags())
+				mockExistingTags(expect)
+				mockGetNSRecord(expect)
+			},
+			validateDNSEndpoint: func(t *testing.T, endpoint *hivev1.DNSEndpoint) {
+				if assert.NotNil(t, endpoint, "endpoint record should exist") {
+					assert.Equal(t, hivev1.TTL(30), endpoint.Spec.Endpoints[0].RecordTTL, "endpoint record should use the zone's TTL")
+				}
+			},
+		},
+		{
+			name: "Existing zone, create additional records",
+			dnsZone: func() *hivev1.DNSZone {
+				zone := validDNSZoneWithLinkToParent()
+				zone.Spec.RecordTTL = hivev1.TTL(30)
+				zone.Spec.AdditionalRecords = []hivev1.Endpoint{
+					{
+						DNSName:    "www.blah.example.com",
+						Targets:    hivev1.Targets{"console.apps.blah.example.com"},
+						RecordType: "CNAME",
+					},
+					{
+						DNSName:    "mail.blah.example.com",
+						Targets:    hivev1.Targets{"10 mx.example.com"},
+						RecordType: "MX",
+						RecordTTL:  hivev1.TTL(300),
+					},
+				}
+				return zone
+			}(),
+			setupAWSMock: func(expect *mock.MockClientMockRecorder) {
+				mockZoneExists(expect, validDNSZoneWithAdditionalTags())
+				mockExistingTags(expect)
+				mockGetNSRecord(expect)
+			},
+			validateAdditionalRecords: func(t *testing.T, endpoint *hivev1.DNSEndpoint) {
+				if assert.NotNil(t, endpoint, "additional records should exist") && assert.Len(t, endpoint.Spec.Endpoints, 2, "unexpected number of records") {
+					assert.Equal(t, "www.blah.example.com", endpoint.Spec.Endpoints[0].DNSName, "unexpected record name")
+					assert.Equal(t, "CNAME", endpoint.Spec.Endpoints[0].RecordType, "unexpected record type")
+					assert.Equal(t, hivev1.TTL(30), endpoint.Spec.Endpoints[0].RecordTTL, "record without TTL should use the zone's TTL")
+					assert.Equal(t, hivev1.TTL(300), endpoint.Spec.Endpoints[1].RecordTTL, "record should keep its own TTL")
+				}
+			},
+		},
+		{
+			name:    "Existing zone, delete removed additional records",
+			dnsZone: validDNSZoneWithLinkToParent(),
+			additionalRecords: &hivev1.DNSEndpoint{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      additionalRecordsName(validDNSZoneWithLinkToParent().Name),
+					Namespace: validDNSZoneWithLinkToParent().Namespace,
+				},
+			},
+			setupAWSMock: func(expect *mock.MockClientMockRecorder) {
+				mockZoneExists(expect, validDNSZoneWithAdditionalTags())
+				mockExistingTags(expect)
+				mockGetNSRecord(expect)
+			},
+			validateAdditionalRecords: func(t *testing.T, endpoint *hivev1.DNSEndpoint) {
+				assert.Nil(t, endpoint, "additional records should be deleted")
 			},
 		},
 		{
@@ -233,6 +310,34 @@ func TestReconcile(t *testing.T) {
 				assert.NotNil(t, condition, "zone available condition should be set on dnszone")
 			},
 		},
+		{
+			name: "Create private hosted zone",
+			dnsZone: func() *hivev1.DNSZone {
+				zone := validDNSZoneWithoutID()
+				zone.Spec.LinkToParentDomain = true
+				zone.Spec.AWS.PrivateZone = &hivev1.AWSPrivateZone{
+					Subnets: []string{"subnet-a", "subnet-b"},
+				}
+				return zone
+			}(),
+			setupAWSMock: func(expect *mock.MockClientMockRecorder) {
+				mockZoneDoesntExist(expect, validDNSZoneWithoutID())
+				mockDescribeSubnets(expect, "vpc-1234")
+				mockCreatePrivateZone(expect, "vpc-1234")
+				mockNoExistingTags(expect)
+				mockSyncTags(expect)
+				mockGetNSRecord(expect)
+			},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				condition := controllerutils.FindDNSZoneCondition(zone.Status.Conditions, hivev1.ZoneAvailableDNSZoneCondition)
+				if assert.NotNil(t, condition, "zone available condition should be set on dnszone") {
+					assert.Equal(t, corev1.ConditionTrue, condition.Status, "private zone should be available without an SOA lookup")
+				}
+			},
+			validateDNSEndpoint: func(t *testing.T, endpoint *hivev1.DNSEndpoint) {
+				assert.Nil(t, endpoint, "private zone should not be linked to its parent domain")
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -258,6 +363,9 @@ func TestReconcile(t *testing.T) {
 			if tc.dnsEndpoint != nil {
 				setFakeDNSEndpointInKube(mocks, tc.dnsEndpoint)
 			}
+			if tc.additionalRecords != nil {
+				setFakeDNSEndpointInKube(mocks, tc.additionalRecords)
+			}
 
 			if tc.setupAWSMock != nil {
 				tc.setupAWSMock(mocks.mockAWSClient.EXPECT())
@@ -290,6 +398,14 @@ func TestReconcile(t *testing.T) {
 				}
 				tc.validateDNSEndpoint(t, endpoint)
 			}
+			if tc.validateAdditionalRecords != nil {
+				endpoint := &hivev1.DNSEndpoint{}
+				err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: tc.dnsZone.Namespace, Name: additionalRecordsName(tc.dnsZone.Name)}, endpoint)
+				if err != nil {
+					endpoint = nil
+				}
+				tc.validateAdditionalRecords(t, endpoint)
+			}
 		})
 	}
 }
@@ -334,6 +450,43 @@ func mockCreateZone(expect *mock.MockClientMockRecorder) {
 	}, nil).Times(1)
 }
 
+func mockDescribeSubnets(expect *mock.MockClientMockRecorder, vpcID string) {
+	expect.DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+		Subnets: []*ec2.Subnet{
+			{
+				SubnetId: aws.String("subnet-a"),
+				VpcId:    aws.String(vpcID),
+			},
+		},
+	}, nil).Times(1)
+}
+
+func mockCreatePrivateZone(expect *mock.MockClientMockRecorder, vpcID string) {
+	expect.CreateHostedZone(privateZoneInputMatcher{vpcID: vpcID}).Return(&route53.CreateHostedZoneOutput{
+		HostedZone: &route53.HostedZone{
+			Id:   aws.String("1234"),
+			Name: aws.String("blah.example.com"),
+		},
+	}, nil).Times(1)
+}
+
+// privateZoneInputMatcher matches requests to create a private hosted zone associated with the VPC.
+type privateZoneInputMatcher struct {
+	vpcID string
+}
+
+func (m privateZoneInputMatcher) Matches(x interface{}) bool {
+	input, ok := x.(*route53.CreateHostedZoneInput)
+	if !ok || input.HostedZoneConfig == nil || input.VPC == nil {
+		return false
+	}
+	return aws.BoolValue(input.HostedZoneConfig.PrivateZone) && aws.StringValue(input.VPC.VPCId) == m.vpcID
+}
+
+func (m privateZoneInputMatcher) String() string {
+	return fmt.Sprintf("is a private hosted zone in VPC %s", m.vpcID)
+}
+
 func mockCreateZoneDuplicateFailure(expect *mock.MockClientMockRecorder) {
 	expect.CreateHostedZone(gomock.Any()).Return(nil, awserr.New(route53.ErrCodeHostedZoneAlreadyExists, "already exists", fmt.Errorf("already exists"))).Times(1)
 }
@@ -0,0 +1,300 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// DefaultTimeout is how long a controller may go without completing a reconcile before it is reported as
+	// unhealthy. It is much longer than the probe interval so that a controller working through a long queue is
+	// not reported.
+	DefaultTimeout = time.Hour
+
+	// reportInterval is how often the heartbeats are written to the heartbeat config map.
+	reportInterval = 5 * time.Minute
+
+	// probeInterval is how often a probe request is added to the queue of each controller.
+	probeInterval = 5 * time.Minute
+
+	hiveConfigName = "hive"
+	hiveNamespace  = "hive"
+
+	// configMapName is the name of the config map in the hive namespace holding the time each controller last
+	// completed a reconcile. The heartbeats change on every report, so they are kept out of the HiveConfig,
+	// whose changes redeploy hive.
+	configMapName = "hive-controller-heartbeats"
+
+	heartbeatStaleReason   = "HeartbeatStale"
+	heartbeatCurrentReason = "HeartbeatsCurrent"
+)
+
+// defaultRecorder holds the heartbeats of the controllers created with NewController.
+var defaultRecorder = NewRecorder()
+
+// Recorder keeps the time each controller last completed a reconcile.
+type Recorder struct {
+	// now returns the current time, and is replaced in tests.
+	now func() time.Time
+
+	mu         sync.Mutex
+	heartbeats map[string]time.Time
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		now:        time.Now,
+		heartbeats: map[string]time.Time{},
+	}
+}
+
+// Register starts tracking the named controller. Its heartbeat starts at the time it is registered so that a
+// controller which has not reconciled anything yet is not reported as unhealthy straight away.
+func (h *Recorder) Register(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.heartbeats[name]; !ok {
+		h.heartbeats[name] = h.now()
+	}
+}
+
+// Beat records that the named controller has completed a reconcile.
+func (h *Recorder) Beat(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.heartbeats[name] = h.now()
+}
+
+// Heartbeats returns the last heartbeat of every tracked controller by controller name.
+func (h *Recorder) Heartbeats() map[string]time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	heartbeats := map[string]time.Time{}
+	for name, last := range h.heartbeats {
+		heartbeats[name] = last.Truncate(time.Second)
+	}
+	return heartbeats
+}
+
+// Wrap returns a reconciler which records a heartbeat for the named controller each time r completes a
+// reconcile, whether or not it succeeded. Probe requests record a heartbeat without being passed to r.
+func (h *Recorder) Wrap(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	h.Register(name)
+	return &reconciler{name: name, recorder: h, Reconciler: r}
+}
+
+type reconciler struct {
+	reconcile.Reconciler
+	name     string
+	recorder *Recorder
+}
+
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	defer r.recorder.Beat(r.name)
+	if request == probeRequest {
+		return reconcile.Result{}, nil
+	}
+	return r.Reconciler.Reconcile(request)
+}
+
+// probeRequest is added to the queue of each controller to record a heartbeat when the controller has nothing
+// else to reconcile. Its name is not a valid object name, so it never names a real object.
+var probeRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: "~heartbeat-probe"}}
+
+// probeSource adds the probe request to the queue of a controller every interval, until the queue shuts down.
+// A controller whose heartbeat goes stale has therefore stopped working through its queue, rather than merely
+// having no objects to reconcile.
+type probeSource struct {
+	interval time.Duration
+}
+
+func (s *probeSource) Start(_ handler.EventHandler, queue workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+	go func() {
+		for !queue.ShuttingDown() {
+			queue.Add(probeRequest)
+			time.Sleep(s.interval)
+		}
+	}()
+	return nil
+}
+
+// NewController creates a controller like controller.New, recording a heartbeat each time it completes a
+// reconcile and probing it periodically so that an idle controller keeps its heartbeat current. The controller
+// ignores objects outside the target namespaces of hive, when any are configured.
+func NewController(name string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
+	options.Reconciler = controllerutils.NewNamespaceRestrictedReconciler(controllerutils.TargetNamespacesFromEnv(), options.Reconciler)
+	options.Reconciler = defaultRecorder.Wrap(name, options.Reconciler)
+	c, err := controller.New(name, mgr, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Watch(&probeSource{interval: probeInterval}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Add creates a new heartbeat Reporter and adds it to the Manager.
+func Add(mgr manager.Manager) error {
+	return mgr.Add(&Reporter{
+		Client:   mgr.GetClient(),
+		Recorder: defaultRecorder,
+		Interval: reportInterval,
+		Timeout:  getTimeout(),
+	})
+}
+
+func getTimeout() time.Duration {
+	value := os.Getenv(constants.ControllerHeartbeatTimeoutEnvVar)
+	if value == "" {
+		return DefaultTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.WithError(err).WithField(constants.ControllerHeartbeatTimeoutEnvVar, value).Warn("invalid controller heartbeat timeout, using default")
+		return DefaultTimeout
+	}
+	return timeout
+}
+
+// Reporter runs in a goroutine and periodically writes the heartbeats of the controllers to the heartbeat config
+// map, setting the HiveControllersUnhealthy condition of the HiveConfig when any of them is older than the timeout.
+type Reporter struct {
+	Client   client.Client
+	Recorder *Recorder
+
+	// Interval is the length of time we sleep between reports.
+	Interval time.Duration
+	// Timeout is how old a heartbeat may be before its controller is reported as unhealthy.
+	Timeout time.Duration
+}
+
+// Start begins the heartbeat reporting loop.
+func (hr *Reporter) Start(stopCh <-chan struct{}) error {
+	log.Info("started controller heartbeat reporter goroutine")
+	wait.Until(func() {
+		if err := hr.Report(); err != nil {
+			log.WithError(err).Error("error reporting controller heartbeats")
+		}
+	}, hr.Interval, stopCh)
+	return nil
+}
+
+// Report writes the current heartbeats to the heartbeat config map, and the HiveControllersUnhealthy condition to
+// the HiveConfig status. The HiveConfig is only updated when the condition changes.
+func (hr *Reporter) Report() error {
+	heartbeats := hr.Recorder.Heartbeats()
+	if err := hr.writeHeartbeats(heartbeats); err != nil {
+		return err
+	}
+
+	hiveConfig := &hivev1.HiveConfig{}
+	err := hr.Client.Get(context.TODO(), types.NamespacedName{Name: hiveConfigName}, hiveConfig)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	now := hr.Recorder.now()
+	for name, last := range heartbeats {
+		if now.Sub(last) > hr.Timeout {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	status, reason, message := corev1.ConditionFalse, heartbeatCurrentReason, "All controllers have reconciled recently"
+	if len(stale) > 0 {
+		status, reason = corev1.ConditionTrue, heartbeatStaleReason
+		message = fmt.Sprintf("No reconcile completed within %s by controllers: %s", hr.Timeout, strings.Join(stale, ", "))
+	}
+	original := hiveConfig.Status.DeepCopy()
+	hiveConfig.Status.Conditions = controllerutils.SetHiveConfigCondition(
+		hiveConfig.Status.Conditions,
+		hivev1.HiveControllersUnhealthyCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if equality.Semantic.DeepEqual(original, &hiveConfig.Status) {
+		return nil
+	}
+	if len(stale) > 0 {
+		log.WithField("controllers", stale).Warn("controller heartbeats are stale")
+	}
+	return hr.Client.Status().Update(context.TODO(), hiveConfig)
+}
+
+// writeHeartbeats writes the heartbeats to the heartbeat config map, creating it if needed.
+func (hr *Reporter) writeHeartbeats(heartbeats map[string]time.Time) error {
+	data := map[string]string{}
+	for name, last := range heartbeats {
+		data[name] = last.UTC().Format(time.RFC3339)
+	}
+	cm := &corev1.ConfigMap{}
+	err := hr.Client.Get(context.TODO(), types.NamespacedName{Namespace: hiveNamespace, Name: configMapName}, cm)
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: hiveNamespace,
+				Name:      configMapName,
+			},
+			Data: data,
+		}
+		return hr.Client.Create(context.TODO(), cm)
+	}
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(cm.Data, data) {
+		return nil
+	}
+	cm.Data = data
+	return hr.Client.Update(context.TODO(), cm)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const testTimeout = time.Hour
+
+type testReconciler struct {
+	err   error
+	calls int
+}
+
+func (r *testReconciler) Reconcile(reconcile.Request) (reconcile.Result, error) {
+	r.calls++
+	return reconcile.Result{}, r.err
+}
+
+func testRecorder(now *time.Time) *Recorder {
+	recorder := NewRecorder()
+	recorder.now = func() time.Time { return *now }
+	return recorder
+}
+
+func testReporter(c client.Client, recorder *Recorder) *Reporter {
+	return &Reporter{
+		Client:   c,
+		Recorder: recorder,
+		Interval: time.Minute,
+		Timeout:  testTimeout,
+	}
+}
+
+func getHiveConfig(t *testing.T, c client.Client) *hivev1.HiveConfig {
+	hiveConfig := &hivev1.HiveConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: hiveConfigName}, hiveConfig); err != nil {
+		t.Fatalf("error getting HiveConfig: %v", err)
+	}
+	return hiveConfig
+}
+
+func getHeartbeats(t *testing.T, c client.Client) map[string]string {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: hiveNamespace, Name: configMapName}, cm); err != nil {
+		t.Fatalf("error getting heartbeat config map: %v", err)
+	}
+	return cm.Data
+}
+
+func TestHeartbeatUpdatedOnReconcile(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	recorder := testRecorder(&now)
+	succeeding := recorder.Wrap("succeeding-controller", &testReconciler{})
+	failing := recorder.Wrap("failing-controller", &testReconciler{err: fmt.Errorf("reconcile failed")})
+
+	assert.Equal(t, map[string]time.Time{
+		"failing-controller":    start,
+		"succeeding-controller": start,
+	}, recorder.Heartbeats(), "controllers should start with a heartbeat when registered")
+
+	now = start.Add(10 * time.Minute)
+	_, err := succeeding.Reconcile(reconcile.Request{})
+	assert.NoError(t, err)
+	now = start.Add(20 * time.Minute)
+	_, err = failing.Reconcile(reconcile.Request{})
+	assert.Error(t, err, "the error of the wrapped reconciler should be returned")
+
+	assert.Equal(t, map[string]time.Time{
+		"failing-controller":    start.Add(20 * time.Minute),
+		"succeeding-controller": start.Add(10 * time.Minute),
+	}, recorder.Heartbeats(), "heartbeats should be updated when a reconcile completes")
+}
+
+func TestHeartbeatUpdatedOnProbe(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	recorder := testRecorder(&now)
+	inner := &testReconciler{}
+	idle := recorder.Wrap("idle-controller", inner)
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	assert.NoError(t, (&probeSource{interval: time.Hour}).Start(nil, queue))
+	item, _ := queue.Get()
+	request, ok := item.(reconcile.Request)
+	if !assert.True(t, ok, "expected a reconcile request in the queue") {
+		return
+	}
+
+	now = start.Add(10 * time.Minute)
+	_, err := idle.Reconcile(request)
+	assert.NoError(t, err)
+	assert.Zero(t, inner.calls, "the probe should not be passed to the wrapped reconciler")
+	assert.Equal(t, map[string]time.Time{
+		"idle-controller": now,
+	}, recorder.Heartbeats(), "the probe should update the heartbeat")
+}
+
+func TestReporter(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	recorder := testRecorder(&now)
+	active := recorder.Wrap("active-controller", &testReconciler{})
+	recorder.Wrap("stopped-controller", &testReconciler{})
+
+	c := fake.NewFakeClient(&hivev1.HiveConfig{ObjectMeta: metav1.ObjectMeta{Name: hiveConfigName}})
+	reporter := testReporter(c, recorder)
+
+	assert.NoError(t, reporter.Report())
+	assert.Equal(t, map[string]string{
+		"active-controller":  "2019-06-01T12:00:00Z",
+		"stopped-controller": "2019-06-01T12:00:00Z",
+	}, getHeartbeats(t, c), "heartbeats should be written to the config map")
+	hiveConfig := getHiveConfig(t, c)
+	assert.Nil(t, controllerutils.FindHiveConfigCondition(hiveConfig.Status.Conditions, hivev1.HiveControllersUnhealthyCondition),
+		"no condition should be set while the controllers are healthy")
+
+	now = start.Add(10 * time.Minute)
+	_, err := active.Reconcile(reconcile.Request{})
+	assert.NoError(t, err)
+	assert.NoError(t, reporter.Report())
+	assert.Equal(t, "2019-06-01T12:10:00Z", getHeartbeats(t, c)["active-controller"], "heartbeat of the active controller should be updated")
+	assert.Equal(t, hiveConfig.ResourceVersion, getHiveConfig(t, c).ResourceVersion,
+		"the HiveConfig should not be updated while the condition is unchanged")
+
+	now = start.Add(2 * testTimeout)
+	_, err = active.Reconcile(reconcile.Request{})
+	assert.NoError(t, err)
+	assert.NoError(t, reporter.Report())
+	assert.Equal(t, map[string]string{
+		"active-controller":  "2019-06-01T14:00:00Z",
+		"stopped-controller": "2019-06-01T12:00:00Z",
+	}, getHeartbeats(t, c), "only the heartbeat of the active controller should be updated")
+	hiveConfig = getHiveConfig(t, c)
+	condition := controllerutils.FindHiveConfigCondition(hiveConfig.Status.Conditions, hivev1.HiveControllersUnhealthyCondition)
+	if assert.NotNil(t, condition, "a stale heartbeat should set the unhealthy condition") {
+		assert.Equal(t, corev1.ConditionTrue, condition.Status)
+		assert.Equal(t, heartbeatStaleReason, condition.Reason)
+		assert.Contains(t, condition.Message, "stopped-controller")
+		assert.NotContains(t, condition.Message, "active-controller")
+	}
+
+	now = now.Add(time.Minute)
+	for _, name := range []string{"active-controller", "stopped-controller"} {
+		recorder.Beat(name)
+	}
+	assert.NoError(t, reporter.Report())
+	hiveConfig = getHiveConfig(t, c)
+	condition = controllerutils.FindHiveConfigCondition(hiveConfig.Status.Conditions, hivev1.HiveControllersUnhealthyCondition)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, corev1.ConditionFalse, condition.Status, "the condition should clear once every controller reconciles")
+		assert.Equal(t, heartbeatCurrentReason, condition.Reason)
+	}
+}
+
+func TestReporterNoHiveConfig(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	now := time.Now()
+	recorder := testRecorder(&now)
+	recorder.Register("test-controller")
+	assert.NoError(t, testReporter(fake.NewFakeClient(), recorder).Report(), "a missing HiveConfig should be ignored")
+}
@@ -3,6 +3,8 @@ package installlogmonitor
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -25,6 +28,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/heartbeat"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 )
@@ -38,6 +43,14 @@ const (
 	unknownMessage      = "Cluster install failed but no known errors found in logs"
 	successReason       = "ClusterInstalled"
 	successMessage      = "Cluster install completed successfully"
+
+	// quotaExceededReason is the reason reported for install failures caused by cloud account quotas. These
+	// are also recorded as events so that they can be routed to a quota increase workflow.
+	quotaExceededReason = "QuotaExceeded"
+
+	// defaultScanLines is the number of trailing install log lines scanned for known errors when not
+	// otherwise configured.
+	defaultScanLines = 1000
 )
 
 var (
@@ -61,13 +74,33 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileInstallLog{Client: hivemetrics.NewClientWithMetricsOrDie(mgr, controllerName), scheme: mgr.GetScheme()}
+	return &ReconcileInstallLog{
+		Client:        hivemetrics.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:        mgr.GetScheme(),
+		scanLines:     getScanLines(),
+		eventRecorder: mgr.GetRecorder(controllerName),
+	}
+}
+
+// getScanLines returns the number of trailing install log lines to scan from the environment, falling back
+// to the default if it is unset or invalid.
+func getScanLines() int {
+	value := os.Getenv(constants.InstallLogScanLinesEnvVar)
+	if value == "" {
+		return defaultScanLines
+	}
+	lines, err := strconv.Atoi(value)
+	if err != nil || lines <= 0 {
+		log.WithField("value", value).Warn("invalid number of install log lines to scan, using default")
+		return defaultScanLines
+	}
+	return lines
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("installlogmonitor-controller", mgr, controller.Options{Reconciler: r})
+	c, err := heartbeat.NewController("installlogmonitor-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
@@ -111,6 +144,13 @@ var _ reconcile.Reconciler = &ReconcileInstallLog{}
 type ReconcileInstallLog struct {
 	client.Client
 	scheme *runtime.Scheme
+
+	// scanLines is the number of trailing lines of an install log which are scanned for known errors.
+	// Zero scans the entire log.
+	scanLines int
+
+	// eventRecorder is used to record events on cluster deployments.
+	eventRecorder record.EventRecorder
 }
 
 func (r *ReconcileInstallLog) isHiveInstallLog(cm *corev1.ConfigMap) (isInstallLog bool, needsMigration bool) {
@@ -259,15 +299,20 @@ func (r *ReconcileInstallLog) Reconcile(request reconcile.Request) (reconcile.Re
 			iLog.WithField("line", l).Info("install log line")
 		}
 
-		// Scan log contents for known errors if the install was not reported as a success:
+		// Scan log contents for known errors if the install was not reported as a success. Failures are
+		// reported near the end of the log so only the trailing lines are scanned.
+		scanned := []byte(lastLines(log, r.scanLines))
 		for _, ilr := range ilRegexes {
 			for _, re := range ilr.SearchRegexes {
-				if re.Match([]byte(log)) {
+				if re.Match(scanned) {
 					iLog.WithField("reason", ilr.InstallFailingReason).Info("found known install failure string")
 					cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(cd.Status.Conditions, hivev1.InstallFailingCondition,
 						corev1.ConditionTrue, ilr.InstallFailingReason, ilr.InstallFailingMessage, controllerutils.UpdateConditionAlways)
 					// Increment a counter metric for this cluster type and error reason:
 					metricInstallErrors.WithLabelValues(hivemetrics.GetClusterDeploymentType(cd), ilr.InstallFailingReason).Inc()
+					if ilr.InstallFailingReason == quotaExceededReason {
+						r.eventRecorder.Event(cd, corev1.EventTypeWarning, quotaExceededReason, ilr.InstallFailingMessage)
+					}
 					foundError = true
 					break
 				}
@@ -301,3 +346,18 @@ func (r *ReconcileInstallLog) Reconcile(request reconcile.Request) (reconcile.Re
 	iLog.Info("reconcile complete")
 	return reconcile.Result{}, nil
 }
+
+// lastLines returns the last n lines of s, or all of s if it has n lines or fewer or n is not positive.
+func lastLines(s string, n int) string {
+	if n <= 0 {
+		return s
+	}
+	end := len(strings.TrimSuffix(s, "\n"))
+	for i := 0; i < n; i++ {
+		end = strings.LastIndex(s[:end], "\n")
+		if end < 0 {
+			return s
+		}
+	}
+	return s[end+1:]
+}
@@ -15,6 +15,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -89,6 +90,9 @@ func TestIsHiveInstallLogAndMigration(t *testing.T) {
 const (
 	dnsAlreadyExistsLog    = "blahblah\naws_route53_record.api_external: [ERR]: Error building changeset: InvalidChangeBatch: [Tried to create resource record set [name='api.jh-stg-2405-2.n6b3.s1.devshift.org.'type='A'] but it already exists]\n\nblahblah"
 	pendingVerificationLog = "blahblah\naws_instance.master.2: Error launching source instance: PendingVerification: Your request for accessing resources in this region is being validated, and you will not be able to launch additional resources in this region until the validation is complete. We will notify you by email once your request has been validated. While normally resolved within minutes, please allow up to 4 hours for this process to complete. If the issue still persists, please let us know by writing to awsa\n\nblahblah"
+	quotaExceededLog       = "blahblah\naws_instance.master.0: Error launching source instance: VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit of 32 allows for the instance bucket that the specified instance type belongs to.\n\nblahblah"
+	requestLimitLog        = "blahblah\naws_instance.master.0: Error launching source instance: RequestLimitExceeded: Request limit exceeded.\n\nblahblah"
+	bootstrapFailedLog     = "blahblah\nlevel=error msg=\"Bootstrap failed to complete: timed out waiting for the condition\"\nlevel=error msg=\"Failed to wait for bootstrapping to complete\"\nblahblah"
 )
 
 func TestInstallLogProcessing(t *testing.T) {
@@ -96,8 +100,10 @@ func TestInstallLogProcessing(t *testing.T) {
 	tests := []struct {
 		name                    string
 		existing                []runtime.Object // NOTE: configmap will implicitly be added
+		scanLines               int
 		expectedConditionStatus corev1.ConditionStatus
 		expectedConditionReason string
+		expectedEvent           string
 	}{
 		{
 			name: "process new install log error DNS already exists",
@@ -119,6 +125,71 @@ func TestInstallLogProcessing(t *testing.T) {
 			expectedConditionStatus: corev1.ConditionTrue,
 			expectedConditionReason: "PendingVerification",
 		},
+		{
+			name: "process new install log error QuotaExceeded",
+			existing: []runtime.Object{
+				buildRegexConfigMap(),
+				buildInstallLogConfigMap(testName, "log", quotaExceededLog, "false"),
+				testClusterDeployment(),
+			},
+			expectedConditionStatus: corev1.ConditionTrue,
+			expectedConditionReason: quotaExceededReason,
+			expectedEvent:           "Warning QuotaExceeded Cloud account quota exceeded",
+		},
+		{
+			name: "process new install log throttling is not QuotaExceeded",
+			existing: []runtime.Object{
+				buildRegexConfigMap(),
+				buildInstallLogConfigMap(testName, "log", requestLimitLog, "false"),
+				testClusterDeployment(),
+			},
+			expectedConditionStatus: corev1.ConditionTrue,
+			expectedConditionReason: unknownReason,
+		},
+		{
+			name: "process new install log error within scanned lines",
+			existing: []runtime.Object{
+				buildRegexConfigMap(),
+				buildInstallLogConfigMap(testName, "log", dnsAlreadyExistsLog, "false"),
+				testClusterDeployment(),
+			},
+			scanLines:               3,
+			expectedConditionStatus: corev1.ConditionTrue,
+			expectedConditionReason: "DNSAlreadyExists",
+		},
+		{
+			name: "process new install log error beyond scanned lines",
+			existing: []runtime.Object{
+				buildRegexConfigMap(),
+				buildInstallLogConfigMap(testName, "log", dnsAlreadyExistsLog, "false"),
+				testClusterDeployment(),
+			},
+			scanLines:               2,
+			expectedConditionStatus: corev1.ConditionTrue,
+			expectedConditionReason: unknownReason,
+		},
+		{
+			name: "process new install log multi-line error within scanned lines",
+			existing: []runtime.Object{
+				buildRegexConfigMap(),
+				buildInstallLogConfigMap(testName, "log", bootstrapFailedLog, "false"),
+				testClusterDeployment(),
+			},
+			scanLines:               3,
+			expectedConditionStatus: corev1.ConditionTrue,
+			expectedConditionReason: "BootstrapFailed",
+		},
+		{
+			name: "process new install log multi-line error partially beyond scanned lines",
+			existing: []runtime.Object{
+				buildRegexConfigMap(),
+				buildInstallLogConfigMap(testName, "log", bootstrapFailedLog, "false"),
+				testClusterDeployment(),
+			},
+			scanLines:               2,
+			expectedConditionStatus: corev1.ConditionTrue,
+			expectedConditionReason: unknownReason,
+		},
 		{
 			name: "process new install log success",
 			existing: []runtime.Object{
@@ -160,9 +231,12 @@ func TestInstallLogProcessing(t *testing.T) {
 		}
 		t.Run(test.name, func(t *testing.T) {
 			fakeClient := fake.NewFakeClient(test.existing...)
+			fakeRecorder := record.NewFakeRecorder(10)
 			r := &ReconcileInstallLog{
-				Client: fakeClient,
-				scheme: scheme.Scheme,
+				Client:        fakeClient,
+				scheme:        scheme.Scheme,
+				scanLines:     test.scanLines,
+				eventRecorder: fakeRecorder,
 			}
 			_, err := r.Reconcile(reconcile.Request{
 				NamespacedName: types.NamespacedName{
@@ -192,11 +266,70 @@ func TestInstallLogProcessing(t *testing.T) {
 				assert.Nil(t, cond, "cluster has InstallFailing condition")
 			}
 
+			var events []string
+			for len(fakeRecorder.Events) > 0 {
+				events = append(events, <-fakeRecorder.Events)
+			}
+			if test.expectedEvent != "" {
+				assert.Equal(t, []string{test.expectedEvent}, events, "unexpected events")
+			} else {
+				assert.Empty(t, events, "unexpected events")
+			}
+
 		})
 	}
 
 }
 
+func TestLastLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      string
+		lines    int
+		expected string
+	}{
+		{
+			name:     "no limit",
+			log:      "a\nb\nc",
+			expected: "a\nb\nc",
+		},
+		{
+			name:     "fewer lines than limit",
+			log:      "a\nb\nc",
+			lines:    5,
+			expected: "a\nb\nc",
+		},
+		{
+			name:     "exactly limit",
+			log:      "a\nb\nc",
+			lines:    3,
+			expected: "a\nb\nc",
+		},
+		{
+			name:     "more lines than limit",
+			log:      "a\nb\nc",
+			lines:    2,
+			expected: "b\nc",
+		},
+		{
+			name:     "trailing newline",
+			log:      "a\nb\nc\n",
+			lines:    1,
+			expected: "c\n",
+		},
+		{
+			name:     "empty",
+			lines:    1,
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, lastLines(test.log, test.lines))
+		})
+	}
+}
+
 // buildInstallLogConfigMap builds an install log configmap.
 func buildInstallLogConfigMap(name, key, contents, successStr string) *corev1.ConfigMap {
 	cm := &corev1.ConfigMap{
@@ -243,6 +376,19 @@ searchRegexStrings:
 - "PendingVerification: Your request for accessing resources in this region is being validated"
 installFailingReason: PendingVerification
 installFailingMessage: Account pending verification for region
+`,
+			"BootstrapFailed": `
+searchRegexStrings:
+- 'Bootstrap failed to complete.*\n.*Failed to wait for bootstrapping'
+installFailingReason: BootstrapFailed
+installFailingMessage: Bootstrap failed to complete
+`,
+			"QuotaExceeded": `
+searchRegexStrings:
+- "(Address|Instance|InternetGateway|NatGateway|NetworkInterface|Route|RouteTable|RulesPerSecurityGroup|SecurityGroup|Vcpu|Volume|Vpc|VpcEndpoint)LimitExceeded: "
+- "Quota '[A-Z_]+' exceeded"
+installFailingReason: QuotaExceeded
+installFailingMessage: Cloud account quota exceeded
 `,
 		},
 	}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// NewHistogram creates a histogram whose buckets are overridden by the comma separated bucket boundaries in the
+// given environment variable. The buckets of the options are kept when the variable is unset or invalid.
+func NewHistogram(opts prometheus.HistogramOpts, bucketsEnvVar string) prometheus.Histogram {
+	if value := os.Getenv(bucketsEnvVar); value != "" {
+		buckets, err := ParseBuckets(value)
+		if err != nil {
+			log.WithError(err).WithField("metric", opts.Name).Warn("invalid histogram buckets, using defaults")
+		} else {
+			opts.Buckets = buckets
+		}
+	}
+	return prometheus.NewHistogram(opts)
+}
+
+// ParseBuckets parses comma separated histogram bucket boundaries, which must be strictly increasing.
+func ParseBuckets(value string) ([]float64, error) {
+	var buckets []float64
+	for _, s := range strings.Split(value, ",") {
+		bound, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket boundary %q: %v", s, err)
+		}
+		if len(buckets) > 0 && bound <= buckets[len(buckets)-1] {
+			return nil, fmt.Errorf("bucket boundaries are not strictly increasing: %v", value)
+		}
+		buckets = append(buckets, bound)
+	}
+	return buckets, nil
+}
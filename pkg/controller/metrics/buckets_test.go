@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"os"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHistogram(t *testing.T) {
+	const bucketsEnvVar = "TEST_HISTOGRAM_BUCKETS"
+	defaultBuckets := []float64{30, 60, 120}
+
+	tests := []struct {
+		name            string
+		buckets         string
+		expectedBuckets []float64
+	}{
+		{
+			name:            "defaults",
+			expectedBuckets: defaultBuckets,
+		},
+		{
+			name:            "configured buckets",
+			buckets:         "600,1800,3600,7200",
+			expectedBuckets: []float64{600, 1800, 3600, 7200},
+		},
+		{
+			name:            "fractional buckets",
+			buckets:         "0.5, 1, 2.5",
+			expectedBuckets: []float64{0.5, 1, 2.5},
+		},
+		{
+			name:            "invalid boundary",
+			buckets:         "60,abc",
+			expectedBuckets: defaultBuckets,
+		},
+		{
+			name:            "decreasing boundaries",
+			buckets:         "600,300",
+			expectedBuckets: defaultBuckets,
+		},
+		{
+			name:            "duplicate boundaries",
+			buckets:         "300,300",
+			expectedBuckets: defaultBuckets,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			os.Setenv(bucketsEnvVar, test.buckets)
+			defer os.Unsetenv(bucketsEnvVar)
+
+			histogram := NewHistogram(prometheus.HistogramOpts{
+				Name:    "test_histogram_seconds",
+				Buckets: defaultBuckets,
+			}, bucketsEnvVar)
+
+			m := &dto.Metric{}
+			if err := histogram.Write(m); err != nil {
+				t.Fatalf("unexpected error reading metric: %v", err)
+			}
+			var buckets []float64
+			for _, b := range m.GetHistogram().GetBucket() {
+				buckets = append(buckets, b.GetUpperBound())
+			}
+			assert.Equal(t, test.expectedBuckets, buckets, "unexpected histogram buckets")
+		})
+	}
+}
@@ -1,18 +1,22 @@
 package metrics
 
 import (
-	"fmt"
 	"net/http"
+	"os"
 	"strings"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openshift/hive/pkg/constants"
 )
 
 var (
@@ -22,6 +26,18 @@ var (
 	},
 		[]string{"controller", "method", "resource"},
 	)
+
+	// parsedPaths memoizes the results of parsePath. Paths are keyed by the prefix which determines the
+	// result, so the number of entries is small, but it is bounded as namespaced paths include the namespace.
+	parsedPaths = newParsedPathCache()
+)
+
+const (
+	parsedPathCacheSize = 1024
+
+	// otherControllerLabel is the controller label used for requests from controllers excluded from
+	// client metrics.
+	otherControllerLabel = "other"
 )
 
 func init() {
@@ -32,13 +48,22 @@ func init() {
 // metrics for requests by controller name, HTTP method, and URL path. The client will re-use the
 // managers cache. This should be used in all Hive controllers.
 func NewClientWithMetricsOrDie(mgr manager.Manager, ctrlrName string) client.Client {
+	c, err := NewClientWithMetrics(mgr, ctrlrName, excludedControllersFromEnv())
+	if err != nil {
+		log.WithError(err).Fatal("unable to initialize metrics wrapped client")
+	}
+	return c
+}
+
+// NewClientWithMetrics creates a new controller-runtime client with a wrapper which increments
+// metrics for requests by controller name, HTTP method, and URL path, returning an error if the
+// client cannot be built. Requests from controllers in excludedControllers are counted under the
+// "other" controller label.
+func NewClientWithMetrics(mgr manager.Manager, ctrlrName string, excludedControllers sets.String) (client.Client, error) {
 	// Copy the rest config as we want our round trippers to be controller specific.
 	cfg := rest.CopyConfig(mgr.GetConfig())
 	cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
-		return &ControllerMetricsTripper{
-			RoundTripper: rt,
-			controller:   ctrlrName,
-		}
+		return newControllerMetricsTripper(rt, ctrlrName, excludedControllers)
 	}
 
 	options := client.Options{
@@ -47,7 +72,7 @@ func NewClientWithMetricsOrDie(mgr manager.Manager, ctrlrName string) client.Cli
 	}
 	c, err := client.New(cfg, options)
 	if err != nil {
-		log.WithError(err).Fatal("unable to initialize metrics wrapped client")
+		return nil, err
 	}
 
 	return &client.DelegatingClient{
@@ -57,7 +82,19 @@ func NewClientWithMetricsOrDie(mgr manager.Manager, ctrlrName string) client.Cli
 		},
 		Writer:       c,
 		StatusClient: c,
+	}, nil
+}
+
+// excludedControllersFromEnv returns the set of controllers excluded from client metrics, as configured
+// by the hive operator.
+func excludedControllersFromEnv() sets.String {
+	excluded := sets.NewString()
+	for _, name := range strings.Split(os.Getenv(constants.ClientMetricsExcludedControllersEnvVar), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			excluded.Insert(name)
+		}
 	}
+	return excluded
 }
 
 // ControllerMetricsTripper is a RoundTripper implementation which tracks our metrics for client requests.
@@ -66,19 +103,68 @@ type ControllerMetricsTripper struct {
 	controller string
 }
 
+func newControllerMetricsTripper(rt http.RoundTripper, ctrlrName string, excludedControllers sets.String) *ControllerMetricsTripper {
+	controller := ctrlrName
+	if excludedControllers.Has(ctrlrName) {
+		controller = otherControllerLabel
+	}
+	return &ControllerMetricsTripper{
+		RoundTripper: rt,
+		controller:   controller,
+	}
+}
+
 // RoundTrip implements the http RoundTripper interface.
 func (cmt *ControllerMetricsTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	metricKubeClientRequests.WithLabelValues(cmt.controller, req.Method, parsePath(req.URL.Path)).Inc()
+	metricKubeClientRequests.WithLabelValues(cmt.controller, req.Method, cachedParsePath(req.URL.Path)).Inc()
 	// Call the nested RoundTripper.
 	resp, err := cmt.RoundTripper.RoundTrip(req)
 	return resp, err
 }
 
+func newParsedPathCache() *lru.Cache {
+	cache, err := lru.New(parsedPathCacheSize)
+	if err != nil {
+		log.WithError(err).Fatal("unable to create parsed path cache")
+	}
+	return cache
+}
+
+// cachedParsePath returns the result of parsePath for the given path, re-using a previous result for any
+// path with the same cache key. Safe for concurrent use.
+func cachedParsePath(path string) string {
+	key := parsePathCacheKey(path)
+	if resource, ok := parsedPaths.Get(key); ok {
+		return resource.(string)
+	}
+	resource := parsePath(key)
+	parsedPaths.Add(key, resource)
+	return resource
+}
+
+// parsePathCacheKey returns the prefix of the path which determines the result of parsePath. Anything
+// following the resource of a namespaced request, such as the object name or subresource, is dropped.
+func parsePathCacheKey(path string) string {
+	segments := 5
+	if strings.HasPrefix(path, "/apis/") {
+		segments = 6
+	}
+	count := 0
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' {
+			count++
+			if count > segments {
+				return path[:i]
+			}
+		}
+	}
+	return path
+}
+
 // parsePath returns a group/version/resource string from the given path. Used to avoid per cluster metrics
 // for cardinality reasons.
 func parsePath(path string) string {
 	tokens := strings.Split(path[1:], "/")
-	fmt.Printf("tokens: %v\n", tokens)
 	if tokens[0] == "api" {
 		// Handle core resources:
 		if len(tokens) == 3 || len(tokens) == 4 {
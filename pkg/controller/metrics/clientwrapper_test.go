@@ -1,11 +1,79 @@
 package metrics
 
 import (
+	"net/http"
 	"testing"
 
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
+// fakeManager provides just enough of a manager to build a client.
+type fakeManager struct {
+	manager.Manager
+	config *rest.Config
+}
+
+func (m *fakeManager) GetConfig() *rest.Config {
+	return m.config
+}
+
+func (m *fakeManager) GetScheme() *runtime.Scheme {
+	return scheme.Scheme
+}
+
+func (m *fakeManager) GetRESTMapper() meta.RESTMapper {
+	return meta.NewDefaultRESTMapper(nil)
+}
+
+func (m *fakeManager) GetCache() cache.Cache {
+	return nil
+}
+
+func TestNewClientWithMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *rest.Config
+		expectErr bool
+	}{
+		{
+			name:   "valid config",
+			config: &rest.Config{Host: "https://example.com:6443"},
+		},
+		{
+			name: "missing CA file",
+			config: &rest.Config{
+				Host: "https://example.com:6443",
+				TLSClientConfig: rest.TLSClientConfig{
+					CAFile: "/does/not/exist/ca.crt",
+				},
+			},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := NewClientWithMetrics(&fakeManager{config: test.config}, "test", sets.NewString())
+			if test.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, c)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, c)
+			}
+		})
+	}
+}
+
 func TestPathParse(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -76,3 +144,128 @@ func TestPathParse(t *testing.T) {
 	}
 
 }
+
+func TestCachedPathParse(t *testing.T) {
+	paths := []string{
+		"/api/v1/pods",
+		"/api/v1/nodes/nodename",
+		"/api/v1/nodes/nodename/status",
+		"/api/v1/namespaces/hive",
+		"/api/v1/namespaces/hive/configmaps",
+		"/api/v1/namespaces/hive/configmaps/dgoodwin-del-install-log",
+		"/apis/batch/v1/jobs",
+		"/apis/batch/v1/namespaces/hive/jobs",
+		"/apis/batch/v1/namespaces/hive/jobs/dgoodwin-del-install",
+		"/apis/hive.openshift.io/v1alpha1/selectorsyncidentityproviders/ssname",
+		"/apis/hive.openshift.io/v1alpha1/namespaces/hive/clusterdeployments/dgoodwin-del",
+		"/apis/hive.openshift.io/v1alpha1/namespaces/hive/clusterdeployments/dgoodwin-del/status",
+		"/apis/hive.openshift.io/v1alpha1/namespaces/hive/clusterdeployments/other/status",
+		"/version",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			expected := parsePath(path)
+			// The first call may populate the cache, the second must be served from it.
+			assert.Equal(t, expected, cachedParsePath(path), "unexpected result populating cache")
+			_, cached := parsedPaths.Get(parsePathCacheKey(path))
+			assert.True(t, cached, "expected path to be cached")
+			assert.Equal(t, expected, cachedParsePath(path), "unexpected result from cache")
+		})
+	}
+}
+
+func TestParsePathCacheKey(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{
+			path:     "/api/v1/pods",
+			expected: "/api/v1/pods",
+		},
+		{
+			path:     "/api/v1/namespaces/hive/configmaps/dgoodwin-del-install-log",
+			expected: "/api/v1/namespaces/hive/configmaps",
+		},
+		{
+			path:     "/apis/hive.openshift.io/v1alpha1/namespaces/hive/clusterdeployments/dgoodwin-del/status",
+			expected: "/apis/hive.openshift.io/v1alpha1/namespaces/hive/clusterdeployments",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			assert.Equal(t, test.expected, parsePathCacheKey(test.path))
+		})
+	}
+}
+
+func BenchmarkParsePath(b *testing.B) {
+	path := "/apis/hive.openshift.io/v1alpha1/namespaces/hive/clusterdeployments/dgoodwin-del/status"
+	for i := 0; i < b.N; i++ {
+		parsePath(path)
+	}
+}
+
+func BenchmarkCachedParsePath(b *testing.B) {
+	path := "/apis/hive.openshift.io/v1alpha1/namespaces/hive/clusterdeployments/dgoodwin-del/status"
+	for i := 0; i < b.N; i++ {
+		cachedParsePath(path)
+	}
+}
+
+// roundTripFunc allows a function to be used as a http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestControllerMetricsTripperExcludedControllers(t *testing.T) {
+	requestCount := func(controller string) float64 {
+		m := &dto.Metric{}
+		if err := metricKubeClientRequests.WithLabelValues(controller, http.MethodGet, "core/v1/pods").Write(m); err != nil {
+			t.Fatalf("unexpected error reading metric: %v", err)
+		}
+		return m.GetCounter().GetValue()
+	}
+	rt := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	excluded := sets.NewString("noisy")
+
+	tests := []struct {
+		name            string
+		controller      string
+		expectedCounted string
+		expectedIgnored string
+	}{
+		{
+			name:            "included controller",
+			controller:      "quiet",
+			expectedCounted: "quiet",
+			expectedIgnored: otherControllerLabel,
+		},
+		{
+			name:            "excluded controller",
+			controller:      "noisy",
+			expectedCounted: otherControllerLabel,
+			expectedIgnored: "noisy",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			countedBefore := requestCount(test.expectedCounted)
+			ignoredBefore := requestCount(test.expectedIgnored)
+
+			req, err := http.NewRequest(http.MethodGet, "https://example.com/api/v1/pods", nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, err = newControllerMetricsTripper(rt, test.controller, excluded).RoundTrip(req)
+			assert.NoError(t, err)
+
+			assert.Equal(t, countedBefore+1, requestCount(test.expectedCounted), "expected request to be counted")
+			assert.Equal(t, ignoredBefore, requestCount(test.expectedIgnored), "unexpected request count")
+		})
+	}
+}
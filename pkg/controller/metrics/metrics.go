@@ -26,6 +26,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	"github.com/openshift/hive/pkg/install"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -60,6 +61,10 @@ var (
 		Name: "hive_cluster_deployments_conditions",
 		Help: "Total number of cluster deployments by type with conditions.",
 	}, []string{"cluster_type", "age_lt", "condition"})
+	metricClusterDeploymentsByPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_cluster_deployments_by_phase",
+		Help: "Total number of cluster deployments by phase and cluster type.",
+	}, []string{"phase", "cluster_type"})
 	metricInstallJobsTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "hive_install_jobs",
 		Help: "Total number of install jobs running by cluster type and state.",
@@ -100,6 +105,7 @@ func init() {
 	metrics.Registry.MustRegister(metricClusterDeploymentsUninstalledTotal)
 	metrics.Registry.MustRegister(metricClusterDeploymentsDeprovisioningTotal)
 	metrics.Registry.MustRegister(metricClusterDeploymentsWithConditionTotal)
+	metrics.Registry.MustRegister(metricClusterDeploymentsByPhase)
 	metrics.Registry.MustRegister(metricInstallJobsTotal)
 	metrics.Registry.MustRegister(metricUninstallJobsTotal)
 	metrics.Registry.MustRegister(metricImagesetJobsTotal)
@@ -181,6 +187,8 @@ func (mc *Calculator) Start(stopCh <-chan struct{}) error {
 				metricClusterDeploymentsDeprovisioningTotal,
 				metricClusterDeploymentsWithConditionTotal,
 				mcLog)
+			// Phase metrics have no age label, so they are only set for the unfiltered accumulator.
+			accumulator.setPhaseMetrics(metricClusterDeploymentsByPhase, mcLog)
 
 			// Also add metrics only for clusters created in last 48h
 			accumulator, err = newClusterAccumulator("48h", []string{"0h", "1h", "2h", "8h", "24h"})
@@ -310,10 +318,10 @@ func processJobs(jobs []batchv1.Job) (runningTotal, succeededTotal, failedTotal
 	for _, job := range jobs {
 		clusterType := GetClusterDeploymentTypeForJob(job)
 
-		// Sort the jobs:
-		if job.Status.Failed > 0 {
+		// Sort the jobs by their conditions, as the pod counts in the job status can lag behind them:
+		if controllerutils.IsFailed(&job) {
 			failed[clusterType]++
-		} else if job.Status.Succeeded > 0 {
+		} else if controllerutils.IsSuccessful(&job) {
 			succeeded[clusterType]++
 		} else {
 			running[clusterType]++
@@ -348,6 +356,9 @@ type clusterAccumulator struct {
 	// conditions maps conditions to cluster type to counter.
 	conditions map[hivev1.ClusterDeploymentConditionType]map[string]int
 
+	// phases maps phase to cluster type to counter.
+	phases map[string]map[string]int
+
 	// clusterTypesSet will contain every cluster type we encounter during processing.
 	// Used to zero out some values which may no longer exist when setting the final metrics.
 	// Maps cluster type to a meaningless bool.
@@ -359,8 +370,15 @@ const (
 	stateRunning   = "running"
 	stateSucceeded = "succeeded"
 	stateFailed    = "failed"
+
+	phaseInstalling     = "installing"
+	phaseInstalled      = "installed"
+	phaseFailed         = "failed"
+	phaseDeprovisioning = "deprovisioning"
 )
 
+var allPhases = []string{phaseInstalling, phaseInstalled, phaseFailed, phaseDeprovisioning}
+
 // newClusterAccumulator initializes a new cluster accumulator.
 // ageFilter can be used to exclude clusters older than a certain duration. Use "0h" to include all clusters.
 // durationBuckets are used to sort uninstalled, or deleted clusters into buckets based on how long they have been in that state.
@@ -372,6 +390,7 @@ func newClusterAccumulator(ageFilter string, durationBuckets []string) (*cluster
 		deprovisioning:  map[string]map[string]int{},
 		uninstalled:     map[string]map[string]int{},
 		conditions:      map[hivev1.ClusterDeploymentConditionType]map[string]int{},
+		phases:          map[string]map[string]int{},
 		clusterTypesSet: map[string]bool{},
 	}
 	var err error
@@ -395,6 +414,9 @@ func newClusterAccumulator(ageFilter string, durationBuckets []string) (*cluster
 	for _, cdct := range hivev1.AllClusterDeploymentConditions {
 		ca.conditions[cdct] = map[string]int{}
 	}
+	for _, phase := range allPhases {
+		ca.phases[phase] = map[string]int{}
+	}
 	return ca, nil
 }
 
@@ -430,6 +452,12 @@ func (ca *clusterAccumulator) ensureClusterTypeBuckets(clusterType string) {
 			ca.conditions[k][clusterType] = 0
 		}
 	}
+	for k, v := range ca.phases {
+		_, ok := v[clusterType]
+		if !ok {
+			ca.phases[k][clusterType] = 0
+		}
+	}
 }
 
 func (ca *clusterAccumulator) processCluster(cd *hivev1.ClusterDeployment) {
@@ -479,6 +507,38 @@ func (ca *clusterAccumulator) processCluster(cd *hivev1.ClusterDeployment) {
 			ca.conditions[cond.Type][clusterType]++
 		}
 	}
+
+	ca.phases[clusterDeploymentPhase(cd)][clusterType]++
+}
+
+// clusterDeploymentPhase returns the phase of the cluster deployment derived from its deletion timestamp,
+// installed status and ProvisionCompleted condition.
+func clusterDeploymentPhase(cd *hivev1.ClusterDeployment) string {
+	if cd.DeletionTimestamp != nil {
+		return phaseDeprovisioning
+	}
+	if cd.Status.Installed {
+		return phaseInstalled
+	}
+	for _, cond := range cd.Status.Conditions {
+		if cond.Type == hivev1.ProvisionCompletedCondition && cond.Status == corev1.ConditionFalse {
+			return phaseFailed
+		}
+	}
+	return phaseInstalling
+}
+
+func (ca *clusterAccumulator) setPhaseMetrics(phases *prometheus.GaugeVec, mcLog log.FieldLogger) {
+	for k, v := range ca.phases {
+		for k1, v1 := range v {
+			phases.WithLabelValues(k, k1).Set(float64(v1))
+			mcLog.WithFields(log.Fields{
+				"clusterType": k1,
+				"phase":       k,
+				"total":       v1,
+			}).Debug("calculated total cluster deployments by phase metric")
+		}
+	}
 }
 
 func (ca *clusterAccumulator) setMetrics(total, installed, uninstalled, deprovisioning, conditions *prometheus.GaugeVec, mcLog log.FieldLogger) {
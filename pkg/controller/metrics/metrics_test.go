@@ -4,6 +4,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
@@ -117,6 +120,55 @@ func TestClusterAccumulator(t *testing.T) {
 	assert.Equal(t, 0, accumulator.uninstalled["72h"]["managed"])
 }
 
+func TestClusterDeploymentPhases(t *testing.T) {
+	tenMinsAgo := metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
+	fiveMinsAgo := metav1.Time{Time: time.Now().Add(-5 * time.Minute)}
+
+	failed := testClusterDeployment("failed", "managed", tenMinsAgo, false)
+	failed.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+		{
+			Type:   hivev1.ProvisionCompletedCondition,
+			Status: corev1.ConditionFalse,
+			Reason: "InstallAttemptsExhausted",
+		},
+	}
+
+	clusters := []hivev1.ClusterDeployment{
+		testClusterDeployment("i1", "managed", tenMinsAgo, true),
+		testClusterDeployment("i2", "managed", tenMinsAgo, true),
+		testClusterDeployment("a", "managed", fiveMinsAgo, false),
+		failed,
+		testClusterDeployment("unmanaged1", "unmanaged", fiveMinsAgo, false),
+		testDeletedClusterDeployment("unmanaged2", "unmanaged", tenMinsAgo, fiveMinsAgo, true),
+	}
+
+	accumulator, _ := newClusterAccumulator(infinity, []string{"0h"})
+	for _, cd := range clusters {
+		accumulator.processCluster(&cd)
+	}
+
+	assert.Equal(t, 2, accumulator.phases[phaseInstalled]["managed"])
+	assert.Equal(t, 1, accumulator.phases[phaseInstalling]["managed"])
+	assert.Equal(t, 1, accumulator.phases[phaseFailed]["managed"])
+	assert.Equal(t, 0, accumulator.phases[phaseDeprovisioning]["managed"])
+	assert.Equal(t, 0, accumulator.phases[phaseInstalled]["unmanaged"])
+	assert.Equal(t, 1, accumulator.phases[phaseInstalling]["unmanaged"])
+	assert.Equal(t, 0, accumulator.phases[phaseFailed]["unmanaged"])
+	assert.Equal(t, 1, accumulator.phases[phaseDeprovisioning]["unmanaged"])
+
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_phases"}, []string{"phase", "cluster_type"})
+	accumulator.setPhaseMetrics(gauge, log.WithField("test", t.Name()))
+	for _, phase := range allPhases {
+		for _, clusterType := range []string{"managed", "unmanaged"} {
+			m := &dto.Metric{}
+			if assert.NoError(t, gauge.WithLabelValues(phase, clusterType).Write(m)) {
+				assert.Equal(t, float64(accumulator.phases[phase][clusterType]), m.GetGauge().GetValue(),
+					"unexpected gauge value for phase %s and cluster type %s", phase, clusterType)
+			}
+		}
+	}
+}
+
 func TestInstallJobs(t *testing.T) {
 	oneHourAgo := &metav1.Time{Time: time.Now().Add(-1 * time.Hour)}
 	fiveMinsAgo := &metav1.Time{Time: time.Now().Add(-5 * time.Minute)}
@@ -126,6 +178,22 @@ func TestInstallJobs(t *testing.T) {
 				StartTime:      oneHourAgo,
 				CompletionTime: fiveMinsAgo,
 				Succeeded:      1,
+				Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			},
+		},
+		{
+			// Job that has completed before its succeeded count was updated:
+			Status: batchv1.JobStatus{
+				StartTime:      oneHourAgo,
+				CompletionTime: fiveMinsAgo,
+				Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			},
+		},
+		{
+			// Job with a failed pod which is still being retried:
+			Status: batchv1.JobStatus{
+				StartTime: oneHourAgo,
+				Failed:    1,
 			},
 		},
 		{
@@ -146,12 +214,13 @@ func TestInstallJobs(t *testing.T) {
 				StartTime:      oneHourAgo,
 				CompletionTime: fiveMinsAgo,
 				Failed:         1,
+				Conditions:     []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}},
 			},
 		},
 	}
 	running, succeeded, failed := processJobs(jobs)
-	assert.Equal(t, 2, running[hivev1.DefaultClusterType])
-	assert.Equal(t, 1, succeeded[hivev1.DefaultClusterType])
+	assert.Equal(t, 3, running[hivev1.DefaultClusterType])
+	assert.Equal(t, 2, succeeded[hivev1.DefaultClusterType])
 	assert.Equal(t, 1, failed[hivev1.DefaultClusterType])
 }
 
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	// DefaultBindAddress is the address the hive controllers serve metrics on when not otherwise configured.
+	DefaultBindAddress = ":2112"
+
+	// disabledBindAddress stops the manager from serving metrics itself.
+	disabledBindAddress = "0"
+)
+
+// ServerOptions configures the endpoint serving the metrics of the hive controllers.
+type ServerOptions struct {
+	// BindAddress is the address metrics are served on.
+	BindAddress string
+	// TLSCertDir is the directory holding the tls.crt and tls.key with which metrics are served over HTTPS.
+	// Metrics are served over plain HTTP when it is empty.
+	TLSCertDir string
+}
+
+// ServerOptionsFromEnv returns the metrics server options set in the environment by the hive operator.
+func ServerOptionsFromEnv() ServerOptions {
+	opts := ServerOptions{
+		BindAddress: os.Getenv(constants.MetricsBindAddressEnvVar),
+		TLSCertDir:  os.Getenv(constants.MetricsTLSCertDirEnvVar),
+	}
+	if opts.BindAddress == "" {
+		opts.BindAddress = DefaultBindAddress
+	}
+	return opts
+}
+
+// ManagerBindAddress returns the metrics bind address to give the controller manager. The manager can only
+// serve plain HTTP, so its endpoint is disabled when metrics are served over TLS by TLSServer instead.
+func (o ServerOptions) ManagerBindAddress() string {
+	if o.TLSCertDir != "" {
+		return disabledBindAddress
+	}
+	return o.BindAddress
+}
+
+// TLSServer returns a runnable which serves the metrics of the controller manager over HTTPS, or nil when TLS
+// is not configured.
+func (o ServerOptions) TLSServer() manager.Runnable {
+	if o.TLSCertDir == "" {
+		return nil
+	}
+	return &tlsServer{
+		addr:     o.BindAddress,
+		certFile: filepath.Join(o.TLSCertDir, corev1.TLSCertKey),
+		keyFile:  filepath.Join(o.TLSCertDir, corev1.TLSPrivateKeyKey),
+	}
+}
+
+type tlsServer struct {
+	addr     string
+	certFile string
+	keyFile  string
+}
+
+// Start serves metrics until the stop channel is closed.
+func (s *tlsServer) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.HTTPErrorOnError,
+	}))
+	server := &http.Server{
+		Addr:    s.addr,
+		Handler: mux,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.WithField("address", s.addr).Info("serving metrics over TLS")
+		errCh <- server.ListenAndServeTLS(s.certFile, s.keyFile)
+	}()
+
+	select {
+	case <-stop:
+		return server.Shutdown(context.Background())
+	case err := <-errCh:
+		return err
+	}
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+func TestServerOptionsFromEnv(t *testing.T) {
+	tests := []struct {
+		name                   string
+		bindAddress            string
+		tlsCertDir             string
+		expectedManagerAddress string
+		expectTLSServer        bool
+	}{
+		{
+			name:                   "defaults",
+			expectedManagerAddress: DefaultBindAddress,
+		},
+		{
+			name:                   "bind address",
+			bindAddress:            ":8443",
+			expectedManagerAddress: ":8443",
+		},
+		{
+			name:                   "tls",
+			bindAddress:            ":8443",
+			tlsCertDir:             "/etc/metrics-tls",
+			expectedManagerAddress: "0",
+			expectTLSServer:        true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			os.Setenv(constants.MetricsBindAddressEnvVar, test.bindAddress)
+			defer os.Unsetenv(constants.MetricsBindAddressEnvVar)
+			os.Setenv(constants.MetricsTLSCertDirEnvVar, test.tlsCertDir)
+			defer os.Unsetenv(constants.MetricsTLSCertDirEnvVar)
+
+			opts := ServerOptionsFromEnv()
+			assert.Equal(t, test.expectedManagerAddress, opts.ManagerBindAddress(), "unexpected manager bind address")
+			server := opts.TLSServer()
+			if !test.expectTLSServer {
+				assert.Nil(t, server, "unexpected TLS server")
+				return
+			}
+			if assert.NotNil(t, server, "expected TLS server") {
+				s := server.(*tlsServer)
+				assert.Equal(t, test.bindAddress, s.addr, "unexpected TLS server address")
+				assert.Equal(t, "/etc/metrics-tls/tls.crt", s.certFile, "unexpected certificate file")
+				assert.Equal(t, "/etc/metrics-tls/tls.key", s.keyFile, "unexpected key file")
+			}
+		})
+	}
+}
+
+func TestTLSServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metrics-tls")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeTestCertificate(t, dir)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error finding a free port: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	server := ServerOptions{BindAddress: addr, TLSCertDir: dir}.TLSServer()
+	stop := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Start(stop) }()
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = client.Get("https://" + addr + "/metrics")
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if assert.NoError(t, err, "metrics should be served over TLS") {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "unexpected status")
+		assert.NotNil(t, resp.TLS, "expected a TLS connection")
+	}
+
+	close(stop)
+	assert.NoError(t, <-errCh, "unexpected error stopping server")
+}
+
+func writeTestCertificate(t *testing.T, dir string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "hive-controllers"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error creating certificate: %v", err)
+	}
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling key: %v", err)
+	}
+	files := map[string]*pem.Block{
+		"tls.crt": {Type: "CERTIFICATE", Bytes: cert},
+		"tls.key": {Type: "EC PRIVATE KEY", Bytes: keyBytes},
+	}
+	for name, block := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(block), 0600); err != nil {
+			t.Fatalf("unexpected error writing %s: %v", name, err)
+		}
+	}
+}
@@ -53,6 +53,7 @@ import (
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
 	"github.com/openshift/hive/pkg/awsclient"
+	"github.com/openshift/hive/pkg/controller/heartbeat"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	"github.com/openshift/hive/pkg/install"
@@ -89,7 +90,7 @@ func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
 func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("remotemachineset-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	c, err := heartbeat.NewController("remotemachineset-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
 	if err != nil {
 		return err
 	}
@@ -445,12 +446,18 @@ func (r *ReconcileRemoteMachineSet) generateInstallConfigFromClusterDeployment(c
 	})
 	cd = cd.DeepCopy()
 
-	cdLog.Debug("loading SSH key secret")
-	sshKey, err := controllerutils.LoadSecretData(r, cd.Spec.SSHKey.Name,
-		cd.Namespace, adminSSHKeySecretKey)
-	if err != nil {
-		cdLog.WithError(err).Error("unable to load ssh key from secret")
-		return nil, err
+	// Clusters using the default SSH key of hive do not reference an SSH key. The key does not affect the
+	// machine pools, so it is left out.
+	sshKey := ""
+	if cd.Spec.SSHKey != nil {
+		cdLog.Debug("loading SSH key secret")
+		var err error
+		sshKey, err = controllerutils.LoadSecretData(r, cd.Spec.SSHKey.Name,
+			cd.Namespace, adminSSHKeySecretKey)
+		if err != nil {
+			cdLog.WithError(err).Error("unable to load ssh key from secret")
+			return nil, err
+		}
 	}
 
 	// Using a fake pull secret here, we don't need the pull secret given our use of
@@ -472,6 +479,15 @@ func (r *ReconcileRemoteMachineSet) getAWSClient(cd *hivev1.ClusterDeployment) (
 	if cd != nil && cd.Spec.AWS != nil && cd.Spec.PlatformSecrets.AWS != nil {
 		secretName = cd.Spec.PlatformSecrets.AWS.Credentials.Name
 		regionName = cd.Spec.AWS.Region
+	} else if cd != nil && cd.Spec.AWS != nil {
+		defaultCredentials, err := controllerutils.DefaultAWSCredentialsSecret(r.Client, cd)
+		if err != nil {
+			return nil, err
+		}
+		if defaultCredentials != nil {
+			secretName = defaultCredentials.Name
+			regionName = cd.Spec.AWS.Region
+		}
 	}
 
 	awsClient, err := r.awsClientBuilder(r.Client, secretName, cd.Namespace, regionName)
@@ -186,6 +186,49 @@ func SetDNSZoneCondition(
 	return conditions
 }
 
+// SetHiveConfigCondition sets a condition on a HiveConfig resource's status
+func SetHiveConfigCondition(
+	conditions []hivev1.HiveConfigCondition,
+	conditionType hivev1.HiveConfigConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string,
+	updateConditionCheck UpdateConditionCheck,
+) []hivev1.HiveConfigCondition {
+	now := metav1.Now()
+	existingCondition := FindHiveConfigCondition(conditions, conditionType)
+	if existingCondition == nil {
+		if status == corev1.ConditionTrue {
+			conditions = append(
+				conditions,
+				hivev1.HiveConfigCondition{
+					Type:               conditionType,
+					Status:             status,
+					Reason:             reason,
+					Message:            message,
+					LastTransitionTime: now,
+					LastProbeTime:      now,
+				},
+			)
+		}
+	} else {
+		if shouldUpdateCondition(
+			existingCondition.Status, existingCondition.Reason, existingCondition.Message,
+			status, reason, message,
+			updateConditionCheck,
+		) {
+			if existingCondition.Status != status {
+				existingCondition.LastTransitionTime = now
+			}
+			existingCondition.Status = status
+			existingCondition.Reason = reason
+			existingCondition.Message = message
+			existingCondition.LastProbeTime = now
+		}
+	}
+	return conditions
+}
+
 // FindClusterDeploymentCondition finds in the condition that has the
 // specified condition type in the given list. If none exists, then returns nil.
 func FindClusterDeploymentCondition(conditions []hivev1.ClusterDeploymentCondition, conditionType hivev1.ClusterDeploymentConditionType) *hivev1.ClusterDeploymentCondition {
@@ -218,3 +261,14 @@ func FindDNSZoneCondition(conditions []hivev1.DNSZoneCondition, conditionType hi
 	}
 	return nil
 }
+
+// FindHiveConfigCondition finds in the condition that has the
+// specified condition type in the given list. If none exists, then returns nil.
+func FindHiveConfigCondition(conditions []hivev1.HiveConfigCondition, conditionType hivev1.HiveConfigConditionType) *hivev1.HiveConfigCondition {
+	for i, condition := range conditions {
+		if condition.Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+// TargetNamespacesFromEnv returns the namespaces the hive controllers are restricted to, or nil when they
+// reconcile every namespace.
+func TargetNamespacesFromEnv() []string {
+	value := os.Getenv(constants.TargetNamespacesEnvVar)
+	if value == "" {
+		return nil
+	}
+	namespaces := []string{}
+	for _, ns := range strings.Split(value, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// NewNamespaceRestrictedReconciler returns a reconciler which ignores the requests for namespaced objects outside
+// the given namespaces, including the requests mapped from watches of other objects and requeued requests. r is
+// returned unchanged when no namespaces are given.
+func NewNamespaceRestrictedReconciler(namespaces []string, r reconcile.Reconciler) reconcile.Reconciler {
+	if len(namespaces) == 0 {
+		return r
+	}
+	return &namespaceRestrictedReconciler{Reconciler: r, namespaces: sets.NewString(namespaces...)}
+}
+
+type namespaceRestrictedReconciler struct {
+	reconcile.Reconciler
+	namespaces sets.String
+}
+
+func (r *namespaceRestrictedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if request.Namespace != "" && !r.namespaces.Has(request.Namespace) {
+		return reconcile.Result{}, nil
+	}
+	return r.Reconciler.Reconcile(request)
+}
+
+// NewMultiNamespaceCache returns a function creating a manager cache which only watches namespaced objects in the
+// given namespaces, with one informer per namespace, so that objects in any other namespace are neither cached nor
+// reconciled. Cluster-scoped objects are watched cluster-wide. Reads of namespaced objects in any other
+// namespace, such as the shared configuration in the namespace hive runs in, go straight to the API server.
+func NewMultiNamespaceCache(namespaces []string) manager.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		if opts.Scheme == nil {
+			opts.Scheme = scheme.Scheme
+		}
+		if opts.Mapper == nil {
+			mapper, err := apiutil.NewDiscoveryRESTMapper(config)
+			if err != nil {
+				return nil, fmt.Errorf("could not create RESTMapper from config: %v", err)
+			}
+			opts.Mapper = mapper
+		}
+		reader, err := client.New(config, client.Options{Scheme: opts.Scheme, Mapper: opts.Mapper})
+		if err != nil {
+			return nil, err
+		}
+		c := &multiNamespaceCache{
+			namespaceCaches: map[string]cache.Cache{},
+			reader:          reader,
+			scheme:          opts.Scheme,
+			mapper:          opts.Mapper,
+		}
+		if c.clusterCache, err = cache.New(config, opts); err != nil {
+			return nil, err
+		}
+		for _, ns := range namespaces {
+			nsOpts := opts
+			nsOpts.Namespace = ns
+			if c.namespaceCaches[ns], err = cache.New(config, nsOpts); err != nil {
+				return nil, err
+			}
+		}
+		return c, nil
+	}
+}
+
+// multiNamespaceCache dispatches namespaced objects to the cache of their namespace, and cluster-scoped objects to
+// a cluster-wide cache.
+type multiNamespaceCache struct {
+	namespaceCaches map[string]cache.Cache
+	clusterCache    cache.Cache
+	// reader reads the namespaced objects outside the cached namespaces.
+	reader client.Reader
+	scheme *runtime.Scheme
+	mapper meta.RESTMapper
+}
+
+var _ cache.Cache = &multiNamespaceCache{}
+
+// isNamespaced returns true when the kind of obj, or of the items of obj when it is a list, is namespaced.
+func (c *multiNamespaceCache) isNamespaced(obj runtime.Object) (bool, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return false, err
+	}
+	return c.isKindNamespaced(gvk, meta.IsListType(obj))
+}
+
+func (c *multiNamespaceCache) isKindNamespaced(gvk schema.GroupVersionKind, list bool) (bool, error) {
+	if list {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if key.Namespace == "" {
+		return c.clusterCache.Get(ctx, key, obj)
+	}
+	if nsCache, ok := c.namespaceCaches[key.Namespace]; ok {
+		return nsCache.Get(ctx, key, obj)
+	}
+	return c.reader.Get(ctx, key, obj)
+}
+
+func (c *multiNamespaceCache) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	if opts != nil && opts.Namespace != "" {
+		if nsCache, ok := c.namespaceCaches[opts.Namespace]; ok {
+			return nsCache.List(ctx, opts, list)
+		}
+		return c.reader.List(ctx, opts, list)
+	}
+	namespaced, err := c.isNamespaced(list)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.clusterCache.List(ctx, opts, list)
+	}
+
+	var items []runtime.Object
+	for _, nsCache := range c.namespaceCaches {
+		nsList := list.DeepCopyObject()
+		if err := nsCache.List(ctx, opts, nsList); err != nil {
+			return err
+		}
+		nsItems, err := meta.ExtractList(nsList)
+		if err != nil {
+			return err
+		}
+		items = append(items, nsItems...)
+	}
+	return meta.SetList(list, items)
+}
+
+func (c *multiNamespaceCache) GetInformer(obj runtime.Object) (toolscache.SharedIndexInformer, error) {
+	namespaced, err := c.isNamespaced(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !namespaced {
+		return c.clusterCache.GetInformer(obj)
+	}
+	return c.namespaceInformers(func(nsCache cache.Cache) (toolscache.SharedIndexInformer, error) {
+		return nsCache.GetInformer(obj)
+	})
+}
+
+func (c *multiNamespaceCache) GetInformerForKind(gvk schema.GroupVersionKind) (toolscache.SharedIndexInformer, error) {
+	namespaced, err := c.isKindNamespaced(gvk, false)
+	if err != nil {
+		return nil, err
+	}
+	if !namespaced {
+		return c.clusterCache.GetInformerForKind(gvk)
+	}
+	return c.namespaceInformers(func(nsCache cache.Cache) (toolscache.SharedIndexInformer, error) {
+		return nsCache.GetInformerForKind(gvk)
+	})
+}
+
+// namespaceInformers returns an informer combining the informers of every namespace.
+func (c *multiNamespaceCache) namespaceInformers(get func(cache.Cache) (toolscache.SharedIndexInformer, error)) (toolscache.SharedIndexInformer, error) {
+	informers := []toolscache.SharedIndexInformer{}
+	for _, nsCache := range c.namespaceCaches {
+		informer, err := get(nsCache)
+		if err != nil {
+			return nil, err
+		}
+		informers = append(informers, informer)
+	}
+	if len(informers) == 0 {
+		return nil, fmt.Errorf("no namespaces to watch")
+	}
+	return &multiNamespaceInformer{SharedIndexInformer: informers[0], informers: informers}, nil
+}
+
+func (c *multiNamespaceCache) Start(stopCh <-chan struct{}) error {
+	for _, nsCache := range c.namespaceCaches {
+		go nsCache.Start(stopCh)
+	}
+	return c.clusterCache.Start(stopCh)
+}
+
+func (c *multiNamespaceCache) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	synced := c.clusterCache.WaitForCacheSync(stopCh)
+	for _, nsCache := range c.namespaceCaches {
+		synced = nsCache.WaitForCacheSync(stopCh) && synced
+	}
+	return synced
+}
+
+func (c *multiNamespaceCache) IndexField(obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	namespaced, err := c.isNamespaced(obj)
+	if err != nil {
+		return err
+	}
+	if !namespaced {
+		return c.clusterCache.IndexField(obj, field, extractValue)
+	}
+	for _, nsCache := range c.namespaceCaches {
+		if err := nsCache.IndexField(obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// multiNamespaceInformer adds its event handlers to the informer of every namespace. Its other methods are those
+// of the informer of the first namespace.
+type multiNamespaceInformer struct {
+	toolscache.SharedIndexInformer
+	informers []toolscache.SharedIndexInformer
+}
+
+func (i *multiNamespaceInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	for _, informer := range i.informers {
+		informer.AddEventHandler(handler)
+	}
+}
+
+func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, informer := range i.informers {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+func (i *multiNamespaceInformer) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
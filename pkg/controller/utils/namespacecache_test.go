@@ -0,0 +1,239 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fakeInformer counts the event handlers added to it.
+type fakeInformer struct {
+	toolscache.SharedIndexInformer
+	handlers int
+}
+
+func (i *fakeInformer) AddEventHandler(toolscache.ResourceEventHandler) {
+	i.handlers++
+}
+
+func (i *fakeInformer) AddEventHandlerWithResyncPeriod(toolscache.ResourceEventHandler, time.Duration) {
+	i.handlers++
+}
+
+func (i *fakeInformer) HasSynced() bool {
+	return true
+}
+
+// fakeCache reads its objects from a fake client, and has a single informer for every kind.
+type fakeCache struct {
+	client.Reader
+	informer *fakeInformer
+}
+
+func newFakeCache(objs ...runtime.Object) *fakeCache {
+	return &fakeCache{Reader: fake.NewFakeClient(objs...), informer: &fakeInformer{}}
+}
+
+func (c *fakeCache) GetInformer(runtime.Object) (toolscache.SharedIndexInformer, error) {
+	return c.informer, nil
+}
+
+func (c *fakeCache) GetInformerForKind(schema.GroupVersionKind) (toolscache.SharedIndexInformer, error) {
+	return c.informer, nil
+}
+
+func (c *fakeCache) Start(<-chan struct{}) error {
+	return nil
+}
+
+func (c *fakeCache) WaitForCacheSync(<-chan struct{}) bool {
+	return true
+}
+
+func (c *fakeCache) IndexField(runtime.Object, string, client.IndexerFunc) error {
+	return nil
+}
+
+func testSecret(namespace, name string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+func testMultiNamespaceCache() (*multiNamespaceCache, map[string]*fakeCache, *fakeCache) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+
+	namespaceCaches := map[string]*fakeCache{
+		"team-a": newFakeCache(testSecret("team-a", "secret-a")),
+		"team-b": newFakeCache(testSecret("team-b", "secret-b")),
+	}
+	clusterCache := newFakeCache(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+	c := &multiNamespaceCache{
+		namespaceCaches: map[string]cache.Cache{},
+		clusterCache:    clusterCache,
+		reader:          fake.NewFakeClient(testSecret("hive", "default-secret")),
+		scheme:          scheme.Scheme,
+		mapper:          mapper,
+	}
+	for ns, nsCache := range namespaceCaches {
+		c.namespaceCaches[ns] = nsCache
+	}
+	return c, namespaceCaches, clusterCache
+}
+
+func TestMultiNamespaceCacheGet(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       types.NamespacedName
+		obj       runtime.Object
+		expectErr bool
+	}{
+		{
+			name: "target namespace",
+			key:  types.NamespacedName{Namespace: "team-b", Name: "secret-b"},
+			obj:  &corev1.Secret{},
+		},
+		{
+			name:      "object of another target namespace",
+			key:       types.NamespacedName{Namespace: "team-a", Name: "secret-b"},
+			obj:       &corev1.Secret{},
+			expectErr: true,
+		},
+		{
+			name: "other namespace read from the API",
+			key:  types.NamespacedName{Namespace: "hive", Name: "default-secret"},
+			obj:  &corev1.Secret{},
+		},
+		{
+			name: "cluster-scoped",
+			key:  types.NamespacedName{Name: "team-a"},
+			obj:  &corev1.Namespace{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, _, _ := testMultiNamespaceCache()
+			err := c.Get(context.TODO(), test.key, test.obj)
+			if test.expectErr {
+				assert.True(t, errors.IsNotFound(err), "expected not found error, got %v", err)
+			} else {
+				assert.NoError(t, err, "unexpected error")
+			}
+		})
+	}
+}
+
+func TestMultiNamespaceCacheList(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespace     string
+		expectedNames []string
+	}{
+		{
+			name:          "all namespaces",
+			expectedNames: []string{"secret-a", "secret-b"},
+		},
+		{
+			name:          "target namespace",
+			namespace:     "team-a",
+			expectedNames: []string{"secret-a"},
+		},
+		{
+			name:          "other namespace read from the API",
+			namespace:     "hive",
+			expectedNames: []string{"default-secret"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, _, _ := testMultiNamespaceCache()
+			secrets := &corev1.SecretList{}
+			if !assert.NoError(t, c.List(context.TODO(), &client.ListOptions{Namespace: test.namespace}, secrets), "unexpected error") {
+				return
+			}
+			names := []string{}
+			for _, secret := range secrets.Items {
+				names = append(names, secret.Name)
+			}
+			assert.ElementsMatch(t, test.expectedNames, names, "unexpected secrets listed")
+		})
+	}
+}
+
+func TestMultiNamespaceCacheGetInformer(t *testing.T) {
+	c, namespaceCaches, clusterCache := testMultiNamespaceCache()
+
+	informer, err := c.GetInformer(&corev1.Secret{})
+	if assert.NoError(t, err, "unexpected error") {
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{})
+		for ns, nsCache := range namespaceCaches {
+			assert.Equal(t, 1, nsCache.informer.handlers, "handler not added to the informer of namespace %s", ns)
+		}
+		assert.Zero(t, clusterCache.informer.handlers, "namespaced objects should not be watched cluster-wide")
+	}
+
+	informer, err = c.GetInformer(&corev1.Namespace{})
+	if assert.NoError(t, err, "unexpected error") {
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{})
+		assert.Equal(t, 1, clusterCache.informer.handlers, "cluster-scoped objects should be watched cluster-wide")
+	}
+}
+
+type countingReconciler struct {
+	requests []reconcile.Request
+}
+
+func (r *countingReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	r.requests = append(r.requests, request)
+	return reconcile.Result{}, nil
+}
+
+func TestNamespaceRestrictedReconciler(t *testing.T) {
+	inner := &countingReconciler{}
+	r := NewNamespaceRestrictedReconciler([]string{"team-a"}, inner)
+	requests := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "cd"}},
+		{NamespacedName: types.NamespacedName{Namespace: "team-b", Name: "cd"}},
+		{NamespacedName: types.NamespacedName{Name: "cluster-scoped"}},
+	}
+	for _, request := range requests {
+		_, err := r.Reconcile(request)
+		assert.NoError(t, err, "unexpected error")
+	}
+	assert.Equal(t, []reconcile.Request{requests[0], requests[2]}, inner.requests, "unexpected requests reconciled")
+
+	assert.Equal(t, inner, NewNamespaceRestrictedReconciler(nil, inner), "reconciler should not be wrapped without namespaces")
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewReadOnlyClient returns a client which reads through the given client but only logs the writes it is
+// asked to make. Writes report success so that callers carry on as they would if the write had been made.
+func NewReadOnlyClient(c client.Client, scheme *runtime.Scheme, logger log.FieldLogger) client.Client {
+	return &readOnlyClient{
+		Client: c,
+		scheme: scheme,
+		logger: logger,
+	}
+}
+
+type readOnlyClient struct {
+	client.Client
+	scheme *runtime.Scheme
+	logger log.FieldLogger
+}
+
+// Create logs the object which would have been created.
+func (c *readOnlyClient) Create(ctx context.Context, obj runtime.Object) error {
+	c.logWrite("create", obj)
+	return nil
+}
+
+// Update logs the object which would have been updated.
+func (c *readOnlyClient) Update(ctx context.Context, obj runtime.Object) error {
+	c.logWrite("update", obj)
+	return nil
+}
+
+// Delete logs the object which would have been deleted.
+func (c *readOnlyClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOptionFunc) error {
+	c.logWrite("delete", obj)
+	return nil
+}
+
+// Status returns a status writer which logs the status updates it is asked to make.
+func (c *readOnlyClient) Status() client.StatusWriter {
+	return &readOnlyStatusWriter{client: c}
+}
+
+type readOnlyStatusWriter struct {
+	client *readOnlyClient
+}
+
+// Update logs the object whose status would have been updated.
+func (w *readOnlyStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	w.client.logWrite("update status", obj)
+	return nil
+}
+
+func (c *readOnlyClient) logWrite(action string, obj runtime.Object) {
+	c.logWriteWithFields(action, obj, nil)
+}
+
+func (c *readOnlyClient) logWriteWithFields(action string, obj runtime.Object, extra log.Fields) {
+	fields := log.Fields{"action": action}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	if gvk, err := apiutil.GVKForObject(obj, c.scheme); err == nil {
+		fields["kind"] = gvk.Kind
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		fields["namespace"] = accessor.GetNamespace()
+		fields["name"] = accessor.GetName()
+	}
+	c.logger.WithFields(fields).Info("read-only mode, skipping write")
+}
+
+// NewReadOnlyEventRecorder returns an event recorder which only logs the events it is asked to record, as
+// recording an event creates an Event object.
+func NewReadOnlyEventRecorder(scheme *runtime.Scheme, logger log.FieldLogger) record.EventRecorder {
+	return &readOnlyEventRecorder{client: &readOnlyClient{scheme: scheme, logger: logger}}
+}
+
+type readOnlyEventRecorder struct {
+	client *readOnlyClient
+}
+
+func (r *readOnlyEventRecorder) Event(obj runtime.Object, eventtype, reason, message string) {
+	r.logEvent(obj, eventtype, reason, message)
+}
+
+func (r *readOnlyEventRecorder) Eventf(obj runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.logEvent(obj, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *readOnlyEventRecorder) PastEventf(obj runtime.Object, timestamp metav1.Time, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.logEvent(obj, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *readOnlyEventRecorder) AnnotatedEventf(obj runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.logEvent(obj, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *readOnlyEventRecorder) logEvent(obj runtime.Object, eventtype, reason, message string) {
+	r.client.logWriteWithFields("record event", obj, log.Fields{
+		"eventType": eventtype,
+		"reason":    reason,
+		"message":   message,
+	})
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestReadOnlyEventRecorder(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := log.New()
+	logger.Out = out
+	recorder := NewReadOnlyEventRecorder(scheme.Scheme, logger)
+
+	recorder.Eventf(testSecret("team-a", "secret-a"), corev1.EventTypeNormal, "Reason", "message %d", 1)
+
+	assert.Contains(t, out.String(), `action="record event"`, "event should be logged")
+	assert.Contains(t, out.String(), "kind=Secret", "unexpected kind")
+	assert.Contains(t, out.String(), "name=secret-a", "unexpected name")
+	assert.Contains(t, out.String(), "eventType=Normal", "unexpected event type")
+	assert.Contains(t, out.String(), "reason=Reason", "unexpected reason")
+	assert.Contains(t, out.String(), `message="message 1"`, "unexpected message")
+}
@@ -24,6 +24,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -94,6 +95,14 @@ func SetupClusterInstallServiceAccount(c client.Client, namespace string, logger
 			return nil, fmt.Errorf("error creating role: %v", err)
 		}
 		logger.WithField("name", roleName).Info("created role")
+	} else if !equality.Semantic.DeepEqual(currentRole.Rules, expectedRole.Rules) {
+		// Roles created by older versions of hive are missing permissions added since.
+		currentRole.Rules = expectedRole.Rules
+		err = c.Update(context.Background(), currentRole)
+		if err != nil {
+			return nil, fmt.Errorf("error updating role: %v", err)
+		}
+		logger.WithField("name", roleName).Info("updated role")
 	} else {
 		logger.WithField("name", roleName).Debug("role already exists")
 	}
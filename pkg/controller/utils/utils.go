@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -124,11 +125,15 @@ func AddFinalizer(object metav1.Object, finalizer string) {
 	object.SetFinalizers(finalizers.List())
 }
 
-// DeleteFinalizer removes a finalizer from the given object
+// DeleteFinalizer removes a finalizer from the given object, keeping the order of any other finalizers
 func DeleteFinalizer(object metav1.Object, finalizer string) {
-	finalizers := sets.NewString(object.GetFinalizers()...)
-	finalizers.Delete(finalizer)
-	object.SetFinalizers(finalizers.List())
+	finalizers := []string{}
+	for _, f := range object.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	object.SetFinalizers(finalizers)
 }
 
 // GetKubeClient creates a new Kubernetes dynamic client.
@@ -162,6 +167,23 @@ func LoadSecretData(c client.Client, secretName, namespace, dataKey string) (str
 	return string(retStr), nil
 }
 
+// DefaultAWSCredentialsSecret returns a reference to the default AWS credentials secret in the namespace of the
+// cluster deployment when the cluster deployment is on AWS, does not name its own AWS credentials secret, and the
+// default secret exists. Otherwise nil is returned.
+func DefaultAWSCredentialsSecret(c client.Client, cd *hivev1.ClusterDeployment) (*corev1.LocalObjectReference, error) {
+	if cd.Spec.AWS == nil || cd.Spec.PlatformSecrets.AWS != nil {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: hivev1.DefaultAWSCredentialsSecretName}, secret)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &corev1.LocalObjectReference{Name: secret.Name}, nil
+}
+
 const (
 	concurrentControllerReconciles = 5
 )
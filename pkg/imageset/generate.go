@@ -28,6 +28,7 @@ import (
 
 	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/install"
 )
 
 // ImageSpec specifies an image reference and associated pull policy
@@ -54,11 +55,21 @@ fi
 
 	// ClusterDeploymentNameLabel is the label that is used to identify the imageset pod of a particular cluster deployment
 	ClusterDeploymentNameLabel = "hive.openshift.io/cluster-deployment-name"
+
+	// DefaultCPURequest is the CPU requested by each container of the imageset job when not otherwise configured
+	DefaultCPURequest = "10m"
+
+	// DefaultMemoryRequest is the memory requested by each container of the imageset job when not otherwise configured
+	DefaultMemoryRequest = "64Mi"
+
+	// DefaultJobTTLSecondsAfterFinished is how long a finished imageset job is kept before it is garbage
+	// collected when not otherwise configured
+	DefaultJobTTLSecondsAfterFinished = 3600
 )
 
 // GenerateImageSetJob creates a job to determine the installer image for a ClusterImageSet
-// given a release image
-func GenerateImageSetJob(cd *hivev1.ClusterDeployment, releaseImage, serviceAccountName string, cli, hive ImageSpec) *batchv1.Job {
+// given a release image. The resources are applied to each container of the job.
+func GenerateImageSetJob(cd *hivev1.ClusterDeployment, releaseImage, serviceAccountName string, cli, hive ImageSpec, resources corev1.ResourceRequirements) *batchv1.Job {
 
 	logger := log.WithFields(log.Fields{
 		"clusterdeployment": types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}.String(),
@@ -116,6 +127,7 @@ func GenerateImageSetJob(cd *hivev1.ClusterDeployment, releaseImage, serviceAcco
 			Command:         []string{"/bin/sh", "-c"},
 			Args:            []string{extractImageScript},
 			VolumeMounts:    volumeMounts,
+			Resources:       resources,
 		},
 		{
 			Name:            "hiveutil",
@@ -135,6 +147,7 @@ func GenerateImageSetJob(cd *hivev1.ClusterDeployment, releaseImage, serviceAcco
 				cd.Namespace,
 			},
 			VolumeMounts: volumeMounts,
+			Resources:    resources,
 		},
 	}
 
@@ -150,9 +163,10 @@ func GenerateImageSetJob(cd *hivev1.ClusterDeployment, releaseImage, serviceAcco
 	completions := int32(1)
 	deadline := int64((24 * time.Hour).Seconds())
 	backoffLimit := int32(123456)
+	ttl := int32(DefaultJobTTLSecondsAfterFinished)
 	labels := map[string]string{
 		ImagesetJobLabel:           "true",
-		ClusterDeploymentNameLabel: cd.Name,
+		ClusterDeploymentNameLabel: install.ClusterDeploymentNameLabelValue(cd.Name),
 	}
 	if cd.Labels != nil {
 		typeStr, ok := cd.Labels[hivev1.HiveClusterTypeLabel]
@@ -168,9 +182,10 @@ func GenerateImageSetJob(cd *hivev1.ClusterDeployment, releaseImage, serviceAcco
 			Labels:    labels,
 		},
 		Spec: batchv1.JobSpec{
-			Completions:           &completions,
-			ActiveDeadlineSeconds: &deadline,
-			BackoffLimit:          &backoffLimit,
+			Completions:             &completions,
+			ActiveDeadlineSeconds:   &deadline,
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: labels,
@@ -183,7 +198,28 @@ func GenerateImageSetJob(cd *hivev1.ClusterDeployment, releaseImage, serviceAcco
 	return job
 }
 
-// GetImageSetJobName returns the expected name of the imageset job for a ClusterImageSet.
+// SetJobTTLSecondsAfterFinished sets how long the imageset job is kept once it has finished before Kubernetes
+// garbage collects it. This cleans up failed jobs even while the controller which would otherwise delete them
+// is down. It has no effect on clusters where the TTLAfterFinished feature is disabled.
+func SetJobTTLSecondsAfterFinished(job *batchv1.Job, seconds int32) {
+	job.Spec.TTLSecondsAfterFinished = &seconds
+}
+
+// GetJobReleaseImage returns the release image whose installer image is resolved by the imageset job.
+func GetJobReleaseImage(job *batchv1.Job) string {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == "RELEASE_IMAGE" {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// GetImageSetJobName returns the expected name of the imageset job for a ClusterDeployment. Names which would
+// be too long for a job are truncated and given a hash of the ClusterDeployment name, so the job name is always
+// valid and the same for a given ClusterDeployment. Lookups of the imageset job must use this name.
 func GetImageSetJobName(cdName string) string {
 	return apihelpers.GetResourceName(cdName, "imageset")
 }
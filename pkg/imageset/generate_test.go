@@ -1,10 +1,13 @@
 package imageset
 
 import (
+	"strings"
 	"testing"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
 )
@@ -29,10 +32,73 @@ var (
 )
 
 func TestGenerateImageSetJob(t *testing.T) {
-	job := GenerateImageSetJob(testClusterDeployment(), *testImageSet().Spec.ReleaseImage, "test-service-account", testCLIImageSpec, testHiveImageSpec)
+	job := GenerateImageSetJob(testClusterDeployment(), *testImageSet().Spec.ReleaseImage, "test-service-account", testCLIImageSpec, testHiveImageSpec, corev1.ResourceRequirements{})
 	validateJob(t, job)
 }
 
+func TestGenerateImageSetJobTTL(t *testing.T) {
+	job := GenerateImageSetJob(testClusterDeployment(), *testImageSet().Spec.ReleaseImage, "test-service-account", testCLIImageSpec, testHiveImageSpec, corev1.ResourceRequirements{})
+	if ttl := job.Spec.TTLSecondsAfterFinished; ttl == nil || *ttl != DefaultJobTTLSecondsAfterFinished {
+		t.Errorf("unexpected default ttlSecondsAfterFinished: %v", ttl)
+	}
+	SetJobTTLSecondsAfterFinished(job, 600)
+	if ttl := job.Spec.TTLSecondsAfterFinished; ttl == nil || *ttl != 600 {
+		t.Errorf("unexpected ttlSecondsAfterFinished: %v", ttl)
+	}
+}
+
+func TestGetJobReleaseImage(t *testing.T) {
+	job := GenerateImageSetJob(testClusterDeployment(), *testImageSet().Spec.ReleaseImage, "test-service-account", testCLIImageSpec, testHiveImageSpec, corev1.ResourceRequirements{})
+	if releaseImage := GetJobReleaseImage(job); releaseImage != *testImageSet().Spec.ReleaseImage {
+		t.Errorf("unexpected release image: %q", releaseImage)
+	}
+}
+
+func TestGenerateImageSetJobResources(t *testing.T) {
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("20m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+	}
+	job := GenerateImageSetJob(testClusterDeployment(), *testImageSet().Spec.ReleaseImage, "test-service-account", testCLIImageSpec, testHiveImageSpec, resources)
+	validateJob(t, job)
+	for _, c := range job.Spec.Template.Spec.Containers {
+		cpu := c.Resources.Requests[corev1.ResourceCPU]
+		if cpu.String() != "20m" {
+			t.Errorf("unexpected cpu request for container %s: %s", c.Name, cpu.String())
+		}
+		memory := c.Resources.Requests[corev1.ResourceMemory]
+		if memory.String() != "128Mi" {
+			t.Errorf("unexpected memory request for container %s: %s", c.Name, memory.String())
+		}
+	}
+}
+
+func TestGenerateImageSetJobLongName(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Name = strings.Repeat("long-cluster-deployment-name-", 8) + "a"
+	job := GenerateImageSetJob(cd, *testImageSet().Spec.ReleaseImage, "test-service-account", testCLIImageSpec, testHiveImageSpec, corev1.ResourceRequirements{})
+
+	if errs := validation.IsDNS1123Label(job.Name); len(errs) > 0 {
+		t.Errorf("invalid job name %q: %v", job.Name, errs)
+	}
+	if job.Name != GetImageSetJobName(cd.Name) {
+		t.Errorf("job name %q does not match the name used for lookups %q", job.Name, GetImageSetJobName(cd.Name))
+	}
+	if name := GenerateImageSetJob(cd, *testImageSet().Spec.ReleaseImage, "test-service-account", testCLIImageSpec, testHiveImageSpec, corev1.ResourceRequirements{}).Name; name != job.Name {
+		t.Errorf("job name is not stable: %q != %q", name, job.Name)
+	}
+	if other := GetImageSetJobName(strings.TrimSuffix(cd.Name, "a") + "b"); other == job.Name {
+		t.Errorf("long names differing only at the end should not share a job name: %q", other)
+	}
+	for k, v := range job.Spec.Template.Labels {
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			t.Errorf("invalid value %q for label %s: %v", v, k, errs)
+		}
+	}
+}
+
 func testClusterDeployment() *hivev1.ClusterDeployment {
 	cd := &hivev1.ClusterDeployment{}
 	cd.Name = "test-cluster-deployment"
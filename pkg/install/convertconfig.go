@@ -17,6 +17,10 @@ limitations under the License.
 package install
 
 import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,6 +31,97 @@ import (
 	installeraws "github.com/openshift/installer/pkg/types/aws"
 )
 
+// InstallConfig wraps the installer's InstallConfig with fields that are not yet present in the vendored
+// installer types. The embedded InstallConfig is inlined when serialized, so the result can be written out
+// as install-config.yaml directly.
+type InstallConfig struct {
+	*types.InstallConfig
+
+	// AdditionalTrustBundle is a PEM-encoded X.509 certificate bundle that will be added to the nodes'
+	// trusted certificate store.
+	AdditionalTrustBundle string `json:"additionalTrustBundle,omitempty"`
+
+	// ImageContentSources lists sources/repositories for the release-image content.
+	ImageContentSources []ImageContentSource `json:"imageContentSources,omitempty"`
+
+	// Publish controls how the user facing endpoints of the cluster, such as the API and ingress, are
+	// published.
+	Publish string `json:"publish,omitempty"`
+
+	// Platform shadows the embedded InstallConfig's platform to add platform settings which are not yet
+	// present in the vendored installer types.
+	Platform Platform `json:"platform"`
+}
+
+// Platform wraps the installer's Platform with settings not yet present in the vendored installer types.
+type Platform struct {
+	types.Platform
+
+	// AWS shadows the embedded AWS platform.
+	AWS *AWSPlatform `json:"aws,omitempty"`
+}
+
+// AWSPlatform wraps the installer's AWS Platform with settings not yet present in the vendored installer types.
+type AWSPlatform struct {
+	*installeraws.Platform
+
+	// Subnets specifies existing subnets (by ID) where cluster resources will be created.
+	Subnets []string `json:"subnets,omitempty"`
+}
+
+// ImageContentSource defines a list of sources/repositories that can be used to pull content.
+type ImageContentSource struct {
+	Source  string   `json:"source"`
+	Mirrors []string `json:"mirrors,omitempty"`
+}
+
+// NewInstallConfig wraps an InstallConfig generated for the given ClusterDeployment with the settings that
+// are not yet supported by the vendored installer types.
+func NewInstallConfig(cd *hivev1.ClusterDeployment, ic *types.InstallConfig, additionalTrustBundle string) *InstallConfig {
+	wrapped := &InstallConfig{
+		InstallConfig:         ic,
+		AdditionalTrustBundle: additionalTrustBundle,
+		Platform: Platform{
+			Platform: ic.Platform,
+		},
+	}
+	if ic.Platform.AWS != nil {
+		wrapped.Platform.AWS = &AWSPlatform{
+			Platform: ic.Platform.AWS,
+		}
+		if cd.Spec.Platform.AWS != nil {
+			wrapped.Platform.AWS.Subnets = cd.Spec.Platform.AWS.Subnets
+			wrapped.Publish = string(cd.Spec.Platform.AWS.Publish)
+		}
+	}
+	for _, source := range cd.Spec.ImageContentSources {
+		wrapped.ImageContentSources = append(wrapped.ImageContentSources, ImageContentSource{
+			Source:  source.Source,
+			Mirrors: source.Mirrors,
+		})
+	}
+	return wrapped
+}
+
+// GenerateInstallConfigYAML renders the install-config.yaml which the installer is given for the
+// ClusterDeployment. The install job and the install manager both use it, so it can also be called on its own
+// to see exactly what would be used for an install without launching one.
+func GenerateInstallConfigYAML(cd *hivev1.ClusterDeployment, sshKey, pullSecret, additionalTrustBundle string) (string, error) {
+	ic, err := GenerateInstallConfig(cd, sshKey, pullSecret, true)
+	if err != nil {
+		return "", err
+	}
+	return marshalInstallConfig(cd, ic, additionalTrustBundle)
+}
+
+func marshalInstallConfig(cd *hivev1.ClusterDeployment, ic *types.InstallConfig, additionalTrustBundle string) (string, error) {
+	d, err := yaml.Marshal(NewInstallConfig(cd, ic, additionalTrustBundle))
+	if err != nil {
+		return "", err
+	}
+	return string(d), nil
+}
+
 // GenerateInstallConfig builds an InstallConfig for the installer from our ClusterDeploymentSpec.
 // The two types are extremely similar, but have different goals and in some cases deviation was required
 // as ClusterDeployment is used as a CRD API.
@@ -72,6 +167,11 @@ func GenerateInstallConfig(cd *hivev1.ClusterDeployment, sshKey, pullSecret stri
 		computeMachinePools = convertMachinePools(computeWorkerPool...)
 	}
 
+	networking, err := convertNetworking(spec.Networking)
+	if err != nil {
+		return nil, err
+	}
+
 	ic := &types.InstallConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: spec.ClusterName,
@@ -79,17 +179,9 @@ func GenerateInstallConfig(cd *hivev1.ClusterDeployment, sshKey, pullSecret stri
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: types.InstallConfigVersion,
 		},
-		SSHKey:     sshKey,
-		BaseDomain: spec.BaseDomain,
-		Networking: &types.Networking{
-			// TODO: deviation from installer API here
-			NetworkType: string(spec.Networking.Type),
-			// TODO: deviation from installer API here
-			ServiceNetwork: []ipnet.IPNet{*parseCIDR(spec.Networking.ServiceCIDR)},
-			// TODO: deviation from installer API here
-			ClusterNetwork: convertClusterNetworks(spec.Networking.ClusterNetworks),
-			MachineCIDR:    parseCIDR(spec.Networking.MachineCIDR),
-		},
+		SSHKey:       sshKey,
+		BaseDomain:   spec.BaseDomain,
+		Networking:   networking,
 		PullSecret:   pullSecret,
 		Platform:     platform,
 		ControlPlane: &controlPlaneMachinePool,
@@ -124,21 +216,56 @@ func convertMachinePools(pools ...hivev1.MachinePool) []types.MachinePool {
 	return machinePools
 }
 
-func parseCIDR(s string) *ipnet.IPNet {
+// convertNetworking renders the network plugin and address ranges from the ClusterDeployment into the
+// installer's networking section. An empty network type is left for the installer to default.
+func convertNetworking(networking hivev1.Networking) (*types.Networking, error) {
+	switch networking.Type {
+	case "", hivev1.NetworkTypeOpenshiftSDN, hivev1.NetworkTypeOpenshiftOVN:
+	default:
+		return nil, fmt.Errorf("unsupported network type %q", networking.Type)
+	}
+	serviceNetwork, err := parseCIDR(networking.ServiceCIDR)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service CIDR: %v", err)
+	}
+	machineCIDR, err := parseCIDR(networking.MachineCIDR)
+	if err != nil {
+		return nil, fmt.Errorf("invalid machine CIDR: %v", err)
+	}
+	clusterNetworks, err := convertClusterNetworks(networking.ClusterNetworks)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Networking{
+		// TODO: deviation from installer API here
+		NetworkType: string(networking.Type),
+		// TODO: deviation from installer API here
+		ServiceNetwork: []ipnet.IPNet{*serviceNetwork},
+		// TODO: deviation from installer API here
+		ClusterNetwork: clusterNetworks,
+		MachineCIDR:    machineCIDR,
+	}, nil
+}
+
+func parseCIDR(s string) (*ipnet.IPNet, error) {
 	if s == "" {
-		return &ipnet.IPNet{}
+		return &ipnet.IPNet{}, nil
 	}
-	return ipnet.MustParseCIDR(s)
+	return ipnet.ParseCIDR(s)
 }
 
-func convertClusterNetworks(networks []networkv1.ClusterNetwork) []types.ClusterNetworkEntry {
+func convertClusterNetworks(networks []networkv1.ClusterNetwork) ([]types.ClusterNetworkEntry, error) {
 	output := make([]types.ClusterNetworkEntry, 0, len(networks))
 	for _, network := range networks {
+		cidr, err := parseCIDR(network.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster network CIDR: %v", err)
+		}
 		output = append(output, types.ClusterNetworkEntry{
-			CIDR: *parseCIDR(network.CIDR),
+			CIDR: *cidr,
 			// TODO: deviation from installer API here
 			HostPrefix: int32(network.HostSubnetLength),
 		})
 	}
-	return output
+	return output, nil
 }
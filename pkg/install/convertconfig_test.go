@@ -214,6 +214,7 @@ func TestConvert(t *testing.T) {
 		cd                       *hivev1.ClusterDeployment
 		expectedInstallConfig    *installtypes.InstallConfig
 		generateConfigForInstall bool
+		expectedErr              bool
 	}{
 		{
 			name:                     "full copy",
@@ -267,6 +268,82 @@ func TestConvert(t *testing.T) {
 			}(),
 			generateConfigForInstall: true,
 		},
+		{
+			name: "SDN networking",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := buildValidClusterDeployment()
+				cd.Spec.Networking.Type = hivev1.NetworkTypeOpenshiftSDN
+				return cd
+			}(),
+			expectedInstallConfig: func() *installtypes.InstallConfig {
+				ic := buildBaseExpectedInstallConfig()
+				ic.Networking.NetworkType = "OpenShiftSDN"
+				return ic
+			}(),
+			generateConfigForInstall: true,
+		},
+		{
+			name: "OVN networking",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := buildValidClusterDeployment()
+				cd.Spec.Networking.Type = hivev1.NetworkTypeOpenshiftOVN
+				cd.Spec.Networking.ServiceCIDR = "172.31.0.0/16"
+				cd.Spec.Networking.ClusterNetworks = []netopv1.ClusterNetwork{
+					{
+						CIDR:             "10.132.0.0/14",
+						HostSubnetLength: 23,
+					},
+				}
+				cd.Spec.Networking.MachineCIDR = "10.1.0.0/16"
+				return cd
+			}(),
+			expectedInstallConfig: func() *installtypes.InstallConfig {
+				ic := buildBaseExpectedInstallConfig()
+				ic.Networking = &installtypes.Networking{
+					NetworkType:    "OVNKubernetes",
+					ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("172.31.0.0/16")},
+					ClusterNetwork: []installtypes.ClusterNetworkEntry{
+						{
+							CIDR:       *ipnet.MustParseCIDR("10.132.0.0/14"),
+							HostPrefix: 23,
+						},
+					},
+					MachineCIDR: ipnet.MustParseCIDR("10.1.0.0/16"),
+				}
+				return ic
+			}(),
+			generateConfigForInstall: true,
+		},
+		{
+			name: "unsupported network type",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := buildValidClusterDeployment()
+				cd.Spec.Networking.Type = "Calico"
+				return cd
+			}(),
+			generateConfigForInstall: true,
+			expectedErr:              true,
+		},
+		{
+			name: "invalid service CIDR",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := buildValidClusterDeployment()
+				cd.Spec.Networking.ServiceCIDR = "172.30.0.0"
+				return cd
+			}(),
+			generateConfigForInstall: true,
+			expectedErr:              true,
+		},
+		{
+			name: "invalid cluster network CIDR",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := buildValidClusterDeployment()
+				cd.Spec.Networking.ClusterNetworks[0].CIDR = "not-a-cidr"
+				return cd
+			}(),
+			generateConfigForInstall: true,
+			expectedErr:              true,
+		},
 		{
 			name: "control plane pool name not master",
 			cd: func() *hivev1.ClusterDeployment {
@@ -346,10 +423,70 @@ func TestConvert(t *testing.T) {
 			}(),
 			generateConfigForInstall: false,
 		},
+		{
+			name: "distinct instance types per compute pool",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := buildValidClusterDeployment()
+				replicas := int64(2)
+				infraPool := hivev1.MachinePool{
+					Name:     "infra",
+					Replicas: &replicas,
+					Platform: hivev1.MachinePoolPlatform{
+						AWS: &hivev1.AWSMachinePoolPlatform{
+							InstanceType: "r5.xlarge",
+							EC2RootVolume: hivev1.EC2RootVolume{
+								Size: 250,
+								Type: "gp2",
+							},
+						},
+					},
+				}
+				cd.Spec.Compute = append(cd.Spec.Compute, infraPool)
+				return cd
+			}(),
+			expectedInstallConfig: func() *installtypes.InstallConfig {
+				ic := buildBaseExpectedInstallConfig()
+				replicas := int64(2)
+				infraPool := installtypes.MachinePool{
+					Name:     "infra",
+					Replicas: &replicas,
+					Platform: installtypes.MachinePoolPlatform{
+						AWS: &installawstypes.MachinePool{
+							InstanceType: "r5.xlarge",
+							EC2RootVolume: installawstypes.EC2RootVolume{
+								Size: 250,
+								Type: "gp2",
+							},
+						},
+					},
+				}
+				ic.Compute = append(ic.Compute, infraPool)
+				return ic
+			}(),
+			generateConfigForInstall: false,
+		},
+		{
+			name: "compute pool without platform left to installer defaults",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := buildValidClusterDeployment()
+				cd.Spec.Compute[0].Platform.AWS = nil
+				return cd
+			}(),
+			expectedInstallConfig: func() *installtypes.InstallConfig {
+				ic := buildBaseExpectedInstallConfig()
+				ic.Compute[0].Platform.AWS = nil
+				return ic
+			}(),
+			generateConfigForInstall: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			ic, err := GenerateInstallConfig(test.cd, adminSSHKey, pullSecret, test.generateConfigForInstall)
+			if test.expectedErr {
+				assert.Error(t, err, "expected error generating install config")
+				return
+			}
 			if assert.NoError(t, err) {
 				assert.Equal(t, test.expectedInstallConfig, ic)
 			}
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DeprovisionReportPath is where the deprovision container writes the counts of the resources it deleted.
+	// It is the termination message path of the container, so the counts can be read from the pod status
+	// once the container has finished.
+	DeprovisionReportPath = "/dev/termination-log"
+
+	// otherResourceType is the type under which deleted resources without an ARN are counted.
+	otherResourceType = "other"
+)
+
+// nestedResourceFields maps the log fields naming a resource deleted as part of another resource, such as the
+// policies of an IAM role, to the suffix added to the resource type of the parent.
+var nestedResourceFields = map[string]string{
+	"policy":     "policy",
+	"record set": "record-set",
+}
+
+// DeletionCounter is a logrus hook which counts the resources deleted by the AWS uninstaller by resource type,
+// such as "ec2:instance", from the "Deleted" entries it logs.
+type DeletionCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewDeletionCounter returns a DeletionCounter which has not counted any resources.
+func NewDeletionCounter() *DeletionCounter {
+	return &DeletionCounter{counts: map[string]int{}}
+}
+
+// Levels implements logrus.Hook.
+func (c *DeletionCounter) Levels() []log.Level {
+	return []log.Level{log.InfoLevel}
+}
+
+// Fire implements logrus.Hook.
+func (c *DeletionCounter) Fire(entry *log.Entry) error {
+	if entry.Message != "Deleted" {
+		return nil
+	}
+	resourceType := otherResourceType
+	if value, ok := entry.Data["arn"].(string); ok {
+		resourceType = arnResourceType(value)
+	}
+	for field, suffix := range nestedResourceFields {
+		if _, ok := entry.Data[field]; ok {
+			resourceType = resourceType + "-" + suffix
+			break
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[resourceType]++
+	return nil
+}
+
+// Report returns the counts of the deleted resources in the format read by ParseDeprovisionReport.
+func (c *DeletionCounter) Report() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return json.Marshal(c.counts)
+}
+
+// ParseDeprovisionReport returns the counts of deleted resources by resource type from a report written by
+// the deprovision container.
+func ParseDeprovisionReport(report string) (map[string]int, error) {
+	counts := map[string]int{}
+	if err := json.Unmarshal([]byte(report), &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// arnResourceType returns the service and the type of resource named by an ARN, for example "ec2:instance".
+// Only the service is returned for ARNs whose resource has no type, such as S3 buckets.
+func arnResourceType(value string) string {
+	parsed, err := arn.Parse(value)
+	if err != nil {
+		return otherResourceType
+	}
+	if i := strings.IndexAny(parsed.Resource, "/:"); i > 0 {
+		return parsed.Service + ":" + parsed.Resource[:i]
+	}
+	return parsed.Service
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"io/ioutil"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeletionCounter(t *testing.T) {
+	counter := NewDeletionCounter()
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	logger.AddHook(counter)
+
+	instanceLogger := logger.WithField("arn", "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0")
+	instanceLogger.Info("Deleted")
+	logger.WithField("arn", "arn:aws:ec2:us-east-1:123456789012:instance/i-0fedcba9876543210").Info("Deleted")
+	logger.WithField("arn", "arn:aws:s3:::test-cluster-image-registry").Info("Deleted")
+	roleLogger := logger.WithField("arn", "arn:aws:iam::123456789012:role/test-cluster-master-role")
+	roleLogger.WithField("policy", "test-cluster-master-policy").Info("Deleted")
+	roleLogger.Info("Deleted")
+	logger.WithField("id", "sg-0123456789abcdef0").Info("Deleted")
+	instanceLogger.Info("Terminating")
+	instanceLogger.Debug("Deleted")
+
+	report, err := counter.Report()
+	if !assert.NoError(t, err) {
+		return
+	}
+	counts, err := ParseDeprovisionReport(string(report))
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]int{
+			"ec2:instance":    2,
+			"s3":              1,
+			"iam:role":        1,
+			"iam:role-policy": 1,
+			"other":           1,
+		}, counts)
+	}
+}
+
+func TestParseDeprovisionReportInvalid(t *testing.T) {
+	_, err := ParseDeprovisionReport("not json")
+	assert.Error(t, err)
+}
@@ -17,15 +17,18 @@ limitations under the License.
 package install
 
 import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 
-	"github.com/ghodss/yaml"
 	log "github.com/sirupsen/logrus"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
@@ -39,6 +42,21 @@ const (
 	defaultInstallerImagePullPolicy = corev1.PullAlways
 	defaultHiveImagePullPolicy      = corev1.PullAlways
 
+	// hostnameTopologyKey is the node label used to spread install pods across nodes.
+	hostnameTopologyKey = "kubernetes.io/hostname"
+
+	// installManagerContainerName is the name of the install job container running the install manager.
+	installManagerContainerName = "hive"
+
+	// serviceAccountTokenPath is where the service account token is mounted into containers.
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// serviceAccountTokenMaskVolume is the empty volume mounted over the service account token.
+	serviceAccountTokenMaskVolume = "serviceaccounttokenmask"
+
+	// DefaultInstallPodTerminationGracePeriodSeconds is how long the install pod is given to clean up
+	// cloud resources when it is terminated, unless otherwise configured.
+	DefaultInstallPodTerminationGracePeriodSeconds int64 = 600
+
 	tryInstallOnceAnnotation              = "hive.openshift.io/try-install-once"
 	tryUninstallOnceAnnotation            = "hive.openshift.io/try-uninstall-once"
 	clusterDeploymentGenerationAnnotation = "hive.openshift.io/cluster-deployment-generation"
@@ -51,16 +69,50 @@ const (
 	// ClusterDeploymentNameLabel is the label that is used to identify the installer pod of a particular cluster deployment
 	ClusterDeploymentNameLabel = "hive.openshift.io/cluster-deployment-name"
 
+	// ClusterDeploymentResourceVersionAnnotation is set on the install job with the resource version of the cluster
+	// deployment the job was generated from. It is informational only and does not affect the job hash.
+	ClusterDeploymentResourceVersionAnnotation = "hive.openshift.io/cluster-deployment-resource-version"
+
 	// SSHPrivateKeyDir is the directory where the generated Job will mount the ssh secret to
 	SSHPrivateKeyDir = "/sshkeys"
 
 	// SSHSecretPrivateKeyName is the key name holding the private key in the SSH secret
 	SSHSecretPrivateKeyName = "ssh-privatekey"
+
+	// InstallTokenDir is the directory where the generated Job will mount the projected service account token to
+	InstallTokenDir = "/var/run/secrets/hive/install-token"
+
+	// installTokenFileName is the name of the projected service account token file
+	installTokenFileName = "token"
+
+	// AdditionalTrustBundleDir is the directory where the generated Job will mount the additional trust bundle secret to
+	AdditionalTrustBundleDir = "/additional-trust-bundle"
+
+	// BootstrapIgnitionOverrideDir is the directory where the generated Job will mount the bootstrap ignition override secret to
+	BootstrapIgnitionOverrideDir = "/bootstrap-ignition-override"
+
+	// CustomManifestsDir is the directory where the generated Job will mount the custom manifests config map to
+	CustomManifestsDir = "/custom-manifests"
+
+	// inputsHashAnnotation is set on the install pod template with a combined hash of the inputs of the install
+	// which are not otherwise part of the pod template, such as the contents of the additional trust bundle,
+	// the custom manifests and the sections rendered into the install config, so that a change to any of them
+	// results in a new install job.
+	inputsHashAnnotation = "hive.openshift.io/install-inputs-hash"
 )
 
 var (
 	// SSHPrivateKeyFilePath is the path to the private key contents (from the SSH secret)
 	SSHPrivateKeyFilePath = fmt.Sprintf("%s/%s", SSHPrivateKeyDir, SSHSecretPrivateKeyName)
+
+	// AdditionalTrustBundleFilePath is the path to the additional trust bundle contents (from the additional trust bundle secret)
+	AdditionalTrustBundleFilePath = fmt.Sprintf("%s/%s", AdditionalTrustBundleDir, hivev1.AdditionalTrustBundleSecretKey)
+
+	// InstallTokenFilePath is the path to the projected service account token for the install pod
+	InstallTokenFilePath = fmt.Sprintf("%s/%s", InstallTokenDir, installTokenFileName)
+
+	// BootstrapIgnitionOverrideFilePath is the path to the bootstrap ignition override contents (from the override secret)
+	BootstrapIgnitionOverrideFilePath = fmt.Sprintf("%s/%s", BootstrapIgnitionOverrideDir, hivev1.BootstrapIgnitionOverrideSecretKey)
 )
 
 // GenerateInstallerJob creates a job to install an OpenShift cluster
@@ -70,7 +122,9 @@ func GenerateInstallerJob(
 	hiveImage, releaseImage string,
 	serviceAccountName string,
 	sshKey string,
-	pullSecret string) (*batchv1.Job, *corev1.ConfigMap, error) {
+	pullSecret string,
+	additionalTrustBundle string,
+	bootstrapIgnitionOverride string) (*batchv1.Job, *corev1.ConfigMap, error) {
 
 	cdLog := log.WithFields(log.Fields{
 		"clusterDeployment": cd.Name,
@@ -80,7 +134,8 @@ func GenerateInstallerJob(
 	cdLog.Debug("generating installer job")
 	ic, err := GenerateInstallConfig(cd, sshKey, pullSecret, true)
 	annotations := map[string]string{
-		clusterDeploymentGenerationAnnotation: strconv.FormatInt(cd.Generation, 10),
+		clusterDeploymentGenerationAnnotation:      strconv.FormatInt(cd.Generation, 10),
+		ClusterDeploymentResourceVersionAnnotation: cd.ResourceVersion,
 	}
 	if err != nil {
 		return nil, nil, err
@@ -94,15 +149,14 @@ func GenerateInstallerJob(
 
 	// TODO: drop all generation of install config here ASAP. We generate this on the fly now
 	// in the install manager. This is only being kept for beta2 and beta3 ClusterImageSet compatability.
-	d, err := yaml.Marshal(ic)
+	installConfig, err := marshalInstallConfig(cd, ic, additionalTrustBundle)
 	if err != nil {
 		return nil, nil, err
 	}
-	installConfig := string(d)
 
 	cfgMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        apihelpers.GetResourceName(cd.Name, "installconfig"),
+			Name:        GetInstallConfigName(cd),
 			Namespace:   cd.Namespace,
 			Annotations: annotations,
 		},
@@ -173,7 +227,18 @@ func GenerateInstallerJob(
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
 		},
-		{
+	}
+	if cd.Spec.InstallConfigSecret {
+		volumes = append(volumes, corev1.Volume{
+			Name: "installconfig",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cfgMap.Name,
+				},
+			},
+		})
+	} else {
+		volumes = append(volumes, corev1.Volume{
 			Name: "installconfig",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
@@ -182,7 +247,7 @@ func GenerateInstallerJob(
 					},
 				},
 			},
-		},
+		})
 	}
 
 	if cd.Spec.SSHKey != nil {
@@ -196,6 +261,91 @@ func GenerateInstallerJob(
 		})
 	}
 
+	// Settings which do not otherwise appear in the pod spec have a hash of their contents folded into the
+	// inputs hash annotation of the pod template, so that changes to them roll the install job.
+	//
+	// Annotations requested in the ClusterDeployment are part of the pod template, and so of the job hash,
+	// on purpose: they can change how the pod is run, for example whether a sidecar is injected. A requested
+	// inputs hash annotation is dropped so that it cannot replace the hash set below.
+	podAnnotations := map[string]string{}
+	for k, v := range cd.Spec.InstallJobPodAnnotations {
+		podAnnotations[k] = v
+	}
+	delete(podAnnotations, inputsHashAnnotation)
+	if cd.Spec.AdditionalTrustBundle != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: "additionaltrustbundle",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cd.Spec.AdditionalTrustBundle.Name,
+				},
+			},
+		})
+		addInputHash(podAnnotations, "additionalTrustBundle", []byte(additionalTrustBundle))
+	}
+
+	if cd.Spec.BootstrapIgnitionOverrideRef != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: "bootstrapignitionoverride",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cd.Spec.BootstrapIgnitionOverrideRef.Name,
+				},
+			},
+		})
+		addInputHash(podAnnotations, "bootstrapIgnitionOverride", []byte(bootstrapIgnitionOverride))
+	}
+
+	if cd.Spec.InstallTokenAudience != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: "installtoken",
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience: cd.Spec.InstallTokenAudience,
+								Path:     installTokenFileName,
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	if len(cd.Spec.ImageContentSources) > 0 {
+		sources, err := json.Marshal(cd.Spec.ImageContentSources)
+		if err != nil {
+			return nil, nil, err
+		}
+		addInputHash(podAnnotations, "imageContentSources", sources)
+	}
+	if placement := controlPlanePlacement(cd); placement != nil {
+		data, err := json.Marshal(placement)
+		if err != nil {
+			return nil, nil, err
+		}
+		addInputHash(podAnnotations, "controlPlanePlacement", data)
+	}
+	if len(ic.Compute) > 0 {
+		data, err := json.Marshal(ic.Compute)
+		if err != nil {
+			return nil, nil, err
+		}
+		addInputHash(podAnnotations, "compute", data)
+	}
+	if ic.Networking != nil {
+		data, err := json.Marshal(ic.Networking)
+		if err != nil {
+			return nil, nil, err
+		}
+		addInputHash(podAnnotations, "networking", data)
+	}
+	if len(podAnnotations) == 0 {
+		podAnnotations = nil
+	}
+
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      "install",
@@ -221,6 +371,43 @@ func GenerateInstallerJob(
 		})
 	}
 
+	if cd.Spec.AdditionalTrustBundle != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "additionaltrustbundle",
+			MountPath: AdditionalTrustBundleDir,
+		})
+
+		env = append(env, corev1.EnvVar{
+			Name:  "ADDITIONAL_TRUST_BUNDLE_PATH",
+			Value: AdditionalTrustBundleFilePath,
+		})
+	}
+
+	if cd.Spec.BootstrapIgnitionOverrideRef != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "bootstrapignitionoverride",
+			MountPath: BootstrapIgnitionOverrideDir,
+		})
+
+		env = append(env, corev1.EnvVar{
+			Name:  "BOOTSTRAP_IGNITION_OVERRIDE_PATH",
+			Value: BootstrapIgnitionOverrideFilePath,
+		})
+	}
+
+	if cd.Spec.InstallTokenAudience != "" {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "installtoken",
+			MountPath: InstallTokenDir,
+			ReadOnly:  true,
+		})
+
+		env = append(env, corev1.EnvVar{
+			Name:  "INSTALL_TOKEN_PATH",
+			Value: InstallTokenFilePath,
+		})
+	}
+
 	if cd.Status.InstallerImage == nil {
 		return nil, nil, fmt.Errorf("installer image not resolved")
 	}
@@ -251,7 +438,7 @@ func GenerateInstallerJob(
 			VolumeMounts: volumeMounts,
 		},
 		{
-			Name:            "hive",
+			Name:            installManagerContainerName,
 			Image:           hiveImage,
 			ImagePullPolicy: hiveImagePullPolicy,
 			Env:             env,
@@ -271,10 +458,14 @@ func GenerateInstallerJob(
 	}
 
 	restartPolicy := corev1.RestartPolicyOnFailure
+	if cd.Spec.InstallPodRestartPolicy != "" {
+		restartPolicy = cd.Spec.InstallPodRestartPolicy
+	}
 	if tryOnce {
 		restartPolicy = corev1.RestartPolicyNever
 	}
 
+	terminationGracePeriod := DefaultInstallPodTerminationGracePeriodSeconds
 	podSpec := corev1.PodSpec{
 		DNSPolicy:          corev1.DNSClusterFirst,
 		RestartPolicy:      restartPolicy,
@@ -284,6 +475,8 @@ func GenerateInstallerJob(
 		ImagePullSecrets: []corev1.LocalObjectReference{
 			cd.Spec.PullSecret,
 		},
+		PriorityClassName:             cd.Spec.InstallJobPriorityClassName,
+		TerminationGracePeriodSeconds: &terminationGracePeriod,
 	}
 
 	completions := int32(1)
@@ -294,7 +487,7 @@ func GenerateInstallerJob(
 
 	labels := map[string]string{
 		InstallJobLabel:            "true",
-		ClusterDeploymentNameLabel: cd.Name,
+		ClusterDeploymentNameLabel: ClusterDeploymentNameLabelValue(cd.Name),
 	}
 	if cd.Labels != nil {
 		typeStr, ok := cd.Labels[hivev1.HiveClusterTypeLabel]
@@ -308,14 +501,15 @@ func GenerateInstallerJob(
 			Name:        GetInstallJobName(cd),
 			Namespace:   cd.Namespace,
 			Annotations: annotations,
-			Labels:      labels,
+			Labels:      installJobLabels(cd, labels),
 		},
 		Spec: batchv1.JobSpec{
 			Completions:  &completions,
 			BackoffLimit: &backoffLimit,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      labels,
+					Annotations: podAnnotations,
 				},
 				Spec: podSpec,
 			},
@@ -325,11 +519,189 @@ func GenerateInstallerJob(
 	return job, cfgMap, nil
 }
 
+// controlPlanePlacement returns the explicitly configured availability zones and subnets for the control
+// plane of an AWS cluster, or nil if placement is left to the installer.
+func controlPlanePlacement(cd *hivev1.ClusterDeployment) map[string][]string {
+	if cd.Spec.Platform.AWS == nil {
+		return nil
+	}
+	var zones []string
+	if cd.Spec.ControlPlane.Platform.AWS != nil {
+		zones = cd.Spec.ControlPlane.Platform.AWS.Zones
+	}
+	if len(zones) == 0 && cd.Spec.Platform.AWS.DefaultMachinePlatform != nil {
+		zones = cd.Spec.Platform.AWS.DefaultMachinePlatform.Zones
+	}
+	if len(zones) == 0 && len(cd.Spec.Platform.AWS.Subnets) == 0 {
+		return nil
+	}
+	return map[string][]string{
+		"zones":   zones,
+		"subnets": cd.Spec.Platform.AWS.Subnets,
+	}
+}
+
+// hashContents returns the hex encoded md5 hash of the given data.
+func hashContents(data []byte) string {
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// addInputHash folds the hash of the named input of the install into the inputs hash annotation.
+func addInputHash(annotations map[string]string, input string, data []byte) {
+	annotations[inputsHashAnnotation] = hashContents([]byte(annotations[inputsHashAnnotation] + "\n" + input + "=" + hashContents(data)))
+}
+
+// GenerateInstallConfigSecret returns a Secret holding the same install-config as the given ConfigMap, for
+// ClusterDeployments which keep their install-config in a Secret.
+func GenerateInstallConfigSecret(cfgMap *corev1.ConfigMap) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: *cfgMap.ObjectMeta.DeepCopy(),
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{},
+	}
+	for k, v := range cfgMap.Data {
+		secret.Data[k] = []byte(v)
+	}
+	return secret
+}
+
+// installJobLabels returns the labels of the install job: the given labels along with the AWS user tags of the
+// cluster deployment which are also valid labels. The user tags are left off the pod template so that changing
+// them does not replace the install job.
+func installJobLabels(cd *hivev1.ClusterDeployment, labels map[string]string) map[string]string {
+	if cd.Spec.AWS == nil || len(cd.Spec.AWS.UserTags) == 0 {
+		return labels
+	}
+	jobLabels := make(map[string]string, len(labels)+len(cd.Spec.AWS.UserTags))
+	for k, v := range cd.Spec.AWS.UserTags {
+		if len(validation.IsQualifiedName(k)) > 0 || len(validation.IsValidLabelValue(v)) > 0 {
+			continue
+		}
+		jobLabels[k] = v
+	}
+	for k, v := range labels {
+		jobLabels[k] = v
+	}
+	return jobLabels
+}
+
+// SetInstallPodAntiAffinity asks the scheduler to avoid placing the pod of the install job on a node which is
+// already running another install pod from one of the given namespaces, or from the namespace of the job when no
+// namespaces are given. Pod anti-affinity cannot select every namespace, so install pods in namespaces which are
+// not listed are not spread. The anti-affinity is preferred rather than required so that installs still start
+// when there are fewer nodes than installs.
+func SetInstallPodAntiAffinity(job *batchv1.Job, namespaces []string) {
+	if job.Spec.Template.Spec.Affinity == nil {
+		job.Spec.Template.Spec.Affinity = &corev1.Affinity{}
+	}
+	job.Spec.Template.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{InstallJobLabel: "true"},
+					},
+					Namespaces:  namespaces,
+					TopologyKey: hostnameTopologyKey,
+				},
+			},
+		},
+	}
+}
+
+// DisableInstallPodServiceAccountToken stops the token of the install service account from being mounted into
+// the containers of the install job which do not talk to the API server. The install manager container still
+// needs the token to load the cluster deployment and report progress, so the token is hidden from the other
+// containers by mounting an empty directory over the service account directory, which the service account
+// admission plugin leaves alone.
+func DisableInstallPodServiceAccountToken(job *batchv1.Job) {
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: serviceAccountTokenMaskVolume,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == installManagerContainerName {
+			continue
+		}
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      serviceAccountTokenMaskVolume,
+			MountPath: serviceAccountTokenPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// SetInstallPodTerminationGracePeriod sets how long the pod of the install job is given to clean up when it
+// is terminated, including when the install job is deleted.
+func SetInstallPodTerminationGracePeriod(job *batchv1.Job, seconds int64) {
+	job.Spec.Template.Spec.TerminationGracePeriodSeconds = &seconds
+}
+
+// SetCustomManifests mounts the config map holding the custom manifests of the cluster deployment into the
+// containers of the install job, so that the install manager adds them to the manifests generated by the
+// installer. A hash of the manifests is set on the pod template so that changes to them result in a new
+// install job.
+func SetCustomManifests(job *batchv1.Job, configMapName string, manifests map[string]string) error {
+	data, err := json.Marshal(manifests)
+	if err != nil {
+		return err
+	}
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "custommanifests",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+			},
+		},
+	})
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      "custommanifests",
+			MountPath: CustomManifestsDir,
+			ReadOnly:  true,
+		})
+		podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, corev1.EnvVar{
+			Name:  "CUSTOM_MANIFESTS_PATH",
+			Value: CustomManifestsDir,
+		})
+	}
+	if job.Spec.Template.Annotations == nil {
+		job.Spec.Template.Annotations = map[string]string{}
+	}
+	addInputHash(job.Spec.Template.Annotations, "customManifests", data)
+	return nil
+}
+
+// ClusterDeploymentNameLabelValue returns the value of the ClusterDeploymentNameLabel for the named cluster
+// deployment. Names longer than a label value allows are truncated and given a hash of the full name, so the
+// label of a long name only identifies the cluster deployment together with its namespace and a lookup.
+func ClusterDeploymentNameLabelValue(cdName string) string {
+	return apihelpers.GetLabelValue(cdName, "cd")
+}
+
 // GetInstallJobName returns the expected name of the install job for a cluster deployment.
 func GetInstallJobName(cd *hivev1.ClusterDeployment) string {
 	return apihelpers.GetResourceName(cd.Name, "install")
 }
 
+// GetInstallConfigName returns the expected name of the ConfigMap or Secret holding the install-config of a
+// cluster deployment.
+func GetInstallConfigName(cd *hivev1.ClusterDeployment) string {
+	return apihelpers.GetResourceName(cd.Name, "installconfig")
+}
+
+// GetInstallLogConfigMapName returns the expected name of the ConfigMap holding the captured install log
+// for a failed install of a cluster deployment.
+func GetInstallLogConfigMapName(cd *hivev1.ClusterDeployment) string {
+	return apihelpers.GetResourceName(cd.Name, "install-log")
+}
+
 // GetUninstallJobName returns the expected name of the deprovision job for a cluster deployment.
 func GetUninstallJobName(name string) string {
 	return apihelpers.GetResourceName(name, "uninstall")
@@ -464,6 +836,8 @@ func GenerateUninstallerJob(
 				"debug",
 				"--region",
 				region,
+				"--report-file",
+				DeprovisionReportPath,
 				fmt.Sprintf("kubernetes.io/cluster/%s=owned", infraID),
 			},
 		},
@@ -1,12 +1,14 @@
 package install
 
 import (
+	"github.com/ghodss/yaml"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"testing"
 )
@@ -32,6 +34,666 @@ func TestGenerate(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestGenerateInstallerJobAdditionalTrustBundle(t *testing.T) {
+	const bundle = "-----BEGIN CERTIFICATE-----\nfake\n-----END CERTIFICATE-----\n"
+
+	tests := []struct {
+		name                  string
+		additionalTrustBundle *corev1.LocalObjectReference
+	}{
+		{
+			name: "no additional trust bundle",
+		},
+		{
+			name:                  "additional trust bundle",
+			additionalTrustBundle: &corev1.LocalObjectReference{Name: "trust-bundle"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+			cd.Spec.AdditionalTrustBundle = test.additionalTrustBundle
+			renderedBundle := ""
+			if test.additionalTrustBundle != nil {
+				renderedBundle = bundle
+			}
+
+			job, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", renderedBundle, "")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			ic := map[string]interface{}{}
+			if assert.NoError(t, yaml.Unmarshal([]byte(cfgMap.Data["install-config.yaml"]), &ic)) {
+				if test.additionalTrustBundle != nil {
+					assert.Equal(t, bundle, ic["additionalTrustBundle"], "unexpected additionalTrustBundle in install-config")
+				} else {
+					assert.NotContains(t, ic, "additionalTrustBundle")
+				}
+				assert.Equal(t, testClusterName, ic["metadata"].(map[string]interface{})["name"], "embedded install-config fields should be inlined")
+			}
+
+			var volume *corev1.Volume
+			for i, v := range job.Spec.Template.Spec.Volumes {
+				if v.Name == "additionaltrustbundle" {
+					volume = &job.Spec.Template.Spec.Volumes[i]
+				}
+			}
+			if test.additionalTrustBundle == nil {
+				assert.Nil(t, volume, "unexpected additional trust bundle volume")
+				return
+			}
+			if assert.NotNil(t, volume, "missing additional trust bundle volume") {
+				assert.Equal(t, test.additionalTrustBundle.Name, volume.Secret.SecretName)
+			}
+			assert.NotEmpty(t, job.Spec.Template.Annotations[inputsHashAnnotation], "missing inputs hash")
+			for _, container := range job.Spec.Template.Spec.Containers {
+				assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{
+					Name:      "additionaltrustbundle",
+					MountPath: AdditionalTrustBundleDir,
+				}, "additional trust bundle not mounted in %s container", container.Name)
+				assert.Contains(t, container.Env, corev1.EnvVar{
+					Name:  "ADDITIONAL_TRUST_BUNDLE_PATH",
+					Value: AdditionalTrustBundleFilePath,
+				}, "additional trust bundle path not set in %s container", container.Name)
+			}
+
+			changedJob, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "changed", "")
+			if assert.NoError(t, err) {
+				assert.NotEqual(t, job.Spec.Template.Annotations, changedJob.Spec.Template.Annotations, "bundle changes should change the pod template")
+			}
+		})
+	}
+}
+
+func TestGenerateInstallerJobBootstrapIgnitionOverride(t *testing.T) {
+	const override = `{"ignition":{"version":"2.2.0"}}`
+
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+	cd.Spec.BootstrapIgnitionOverrideRef = &corev1.LocalObjectReference{Name: "bootstrap-override"}
+
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", override)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Contains(t, job.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "bootstrapignitionoverride",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: "bootstrap-override"},
+		},
+	}, "missing bootstrap ignition override volume")
+	for _, container := range job.Spec.Template.Spec.Containers {
+		assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{
+			Name:      "bootstrapignitionoverride",
+			MountPath: BootstrapIgnitionOverrideDir,
+		}, "bootstrap ignition override not mounted in %s container", container.Name)
+		assert.Contains(t, container.Env, corev1.EnvVar{
+			Name:  "BOOTSTRAP_IGNITION_OVERRIDE_PATH",
+			Value: BootstrapIgnitionOverrideFilePath,
+		}, "bootstrap ignition override path not set in %s container", container.Name)
+	}
+	assert.NotEmpty(t, job.Spec.Template.Annotations[inputsHashAnnotation], "missing inputs hash")
+
+	changedJob, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", `{"ignition":{"version":"2.1.0"}}`)
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, job.Spec.Template.Annotations, changedJob.Spec.Template.Annotations, "override changes should change the pod template")
+	}
+}
+
+func TestGenerateInstallerJobImageContentSources(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+
+	job, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, cfgMap.Data["install-config.yaml"], "imageContentSources", "image content sources should not be rendered by default")
+
+	cd.Spec.ImageContentSources = []hivev1.ImageContentSource{
+		{
+			Source:  "quay.io/openshift-release-dev/ocp-release",
+			Mirrors: []string{"mirror.example.com/ocp/release"},
+		},
+	}
+	mirroredJob, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ic := &InstallConfig{}
+	if assert.NoError(t, yaml.Unmarshal([]byte(cfgMap.Data["install-config.yaml"]), ic)) {
+		assert.Equal(t, []ImageContentSource{
+			{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []string{"mirror.example.com/ocp/release"},
+			},
+		}, ic.ImageContentSources, "unexpected image content sources in install-config")
+	}
+	assert.NotEqual(t, job.Spec.Template.Annotations[inputsHashAnnotation], mirroredJob.Spec.Template.Annotations[inputsHashAnnotation], "image content sources should change the inputs hash")
+}
+
+func TestGenerateInstallerJobControlPlanePlacement(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cd.Spec.ControlPlane.Platform.AWS = &hivev1.AWSMachinePoolPlatform{
+		Zones: []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+	}
+	cd.Spec.Platform.AWS.Subnets = []string{"subnet-a", "subnet-b", "subnet-c"}
+	placedJob, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ic := &InstallConfig{}
+	if assert.NoError(t, yaml.Unmarshal([]byte(cfgMap.Data["install-config.yaml"]), ic)) {
+		if assert.NotNil(t, ic.ControlPlane, "missing control plane") && assert.NotNil(t, ic.ControlPlane.Platform.AWS, "missing control plane AWS platform") {
+			assert.Equal(t, []string{"us-east-1a", "us-east-1b", "us-east-1c"}, ic.ControlPlane.Platform.AWS.Zones, "unexpected control plane zones")
+		}
+		if assert.NotNil(t, ic.Platform.AWS, "missing AWS platform") {
+			assert.Equal(t, []string{"subnet-a", "subnet-b", "subnet-c"}, ic.Platform.AWS.Subnets, "unexpected subnets")
+			assert.Equal(t, "us-east-1", ic.Platform.AWS.Region, "embedded AWS platform fields should be inlined")
+		}
+	}
+	assert.NotEqual(t, job.Spec.Template.Annotations[inputsHashAnnotation], placedJob.Spec.Template.Annotations[inputsHashAnnotation], "placement should change the inputs hash")
+
+	cd.Spec.ControlPlane.Platform.AWS.Zones = []string{"us-east-1a"}
+	movedJob, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, placedJob.Spec.Template.Annotations, movedJob.Spec.Template.Annotations, "placement changes should change the pod template")
+	}
+}
+
+func TestGenerateInstallerJobPublish(t *testing.T) {
+	tests := []struct {
+		name            string
+		publish         hivev1.PublishingStrategy
+		expectedPublish string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:            "external",
+			publish:         hivev1.ExternalPublishingStrategy,
+			expectedPublish: "External",
+		},
+		{
+			name:            "internal",
+			publish:         hivev1.InternalPublishingStrategy,
+			expectedPublish: "Internal",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+			cd.Spec.Platform.AWS.Subnets = []string{"subnet-a", "subnet-b", "subnet-c"}
+			cd.Spec.Platform.AWS.Publish = test.publish
+
+			_, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+			if !assert.NoError(t, err) {
+				return
+			}
+			ic := &InstallConfig{}
+			if assert.NoError(t, yaml.Unmarshal([]byte(cfgMap.Data["install-config.yaml"]), ic)) {
+				assert.Equal(t, test.expectedPublish, ic.Publish, "unexpected publish")
+			}
+			if test.publish == "" {
+				assert.NotContains(t, cfgMap.Data["install-config.yaml"], "publish:", "publish should be left to the installer by default")
+			}
+		})
+	}
+}
+
+func TestGenerateInstallerJobComputePools(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	replicas := int64(3)
+	cd.Spec.Compute = []hivev1.MachinePool{
+		{
+			Name:     "worker",
+			Replicas: &replicas,
+			Platform: hivev1.MachinePoolPlatform{
+				AWS: &hivev1.AWSMachinePoolPlatform{
+					InstanceType: "m5.large",
+				},
+			},
+		},
+	}
+	workerJob, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	ic := &InstallConfig{}
+	if assert.NoError(t, yaml.Unmarshal([]byte(cfgMap.Data["install-config.yaml"]), ic)) &&
+		assert.Len(t, ic.Compute, 1, "unexpected compute pools") &&
+		assert.NotNil(t, ic.Compute[0].Platform.AWS, "missing compute AWS platform") {
+		assert.Equal(t, "m5.large", ic.Compute[0].Platform.AWS.InstanceType, "unexpected compute instance type")
+	}
+	assert.NotEqual(t, job.Spec.Template.Annotations[inputsHashAnnotation], workerJob.Spec.Template.Annotations[inputsHashAnnotation], "compute pools should change the inputs hash")
+
+	cd.Spec.Compute[0].Platform.AWS.InstanceType = "m5.xlarge"
+	resizedJob, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, workerJob.Spec.Template.Annotations, resizedJob.Spec.Template.Annotations, "instance type changes should change the pod template")
+	}
+}
+
+func TestGenerateInstallerJobNetworking(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+
+	sdnJob, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	ic := &InstallConfig{}
+	if assert.NoError(t, yaml.Unmarshal([]byte(cfgMap.Data["install-config.yaml"]), ic)) &&
+		assert.NotNil(t, ic.Networking, "missing networking") {
+		assert.Equal(t, "OpenShiftSDN", ic.Networking.NetworkType, "unexpected network type")
+	}
+	assert.NotEmpty(t, sdnJob.Spec.Template.Annotations[inputsHashAnnotation], "missing inputs hash")
+
+	cd.Spec.Networking.Type = hivev1.NetworkTypeOpenshiftOVN
+	ovnJob, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	ic = &InstallConfig{}
+	if assert.NoError(t, yaml.Unmarshal([]byte(cfgMap.Data["install-config.yaml"]), ic)) &&
+		assert.NotNil(t, ic.Networking, "missing networking") {
+		assert.Equal(t, "OVNKubernetes", ic.Networking.NetworkType, "unexpected network type")
+	}
+	assert.NotEqual(t, sdnJob.Spec.Template.Annotations, ovnJob.Spec.Template.Annotations, "network type changes should change the pod template")
+
+	cd.Spec.Networking.ServiceCIDR = "172.31.0.0/16"
+	cidrJob, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, ovnJob.Spec.Template.Annotations, cidrJob.Spec.Template.Annotations, "CIDR changes should change the pod template")
+	}
+}
+
+func TestGenerateInstallConfigYAML(t *testing.T) {
+	const bundle = "-----BEGIN CERTIFICATE-----\nfake\n-----END CERTIFICATE-----\n"
+
+	tests := []struct {
+		name                  string
+		cd                    func() *hivev1.ClusterDeployment
+		additionalTrustBundle string
+	}{
+		{
+			name: "default cluster deployment",
+			cd:   testClusterDeployment,
+		},
+		{
+			name: "additional trust bundle",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Spec.AdditionalTrustBundle = &corev1.LocalObjectReference{Name: "trust-bundle"}
+				return cd
+			},
+			additionalTrustBundle: bundle,
+		},
+		{
+			name: "image content sources",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment()
+				cd.Spec.ImageContentSources = []hivev1.ImageContentSource{
+					{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"mirror.example.com/ocp-release"}},
+				}
+				return cd
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := test.cd()
+			cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+
+			_, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", test.additionalTrustBundle, "")
+			if !assert.NoError(t, err) {
+				return
+			}
+			installConfig, err := GenerateInstallConfigYAML(cd, "sshkey", "pullsecret", test.additionalTrustBundle)
+			if assert.NoError(t, err) {
+				assert.Equal(t, cfgMap.Data["install-config.yaml"], installConfig, "exported install-config should match the one given to the install job")
+			}
+		})
+	}
+}
+
+func TestGenerateInstallerJobInstallTokenAudience(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, volume := range job.Spec.Template.Spec.Volumes {
+		assert.Nil(t, volume.Projected, "unexpected projected volume %s", volume.Name)
+	}
+
+	cd.Spec.InstallTokenAudience = "sts.example.com"
+	tokenJob, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var tokenVolume *corev1.Volume
+	for i, volume := range tokenJob.Spec.Template.Spec.Volumes {
+		if volume.Name == "installtoken" {
+			tokenVolume = &tokenJob.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	if assert.NotNil(t, tokenVolume, "missing install token volume") &&
+		assert.NotNil(t, tokenVolume.Projected, "install token volume should be projected") &&
+		assert.Len(t, tokenVolume.Projected.Sources, 1) &&
+		assert.NotNil(t, tokenVolume.Projected.Sources[0].ServiceAccountToken, "missing service account token projection") {
+		assert.Equal(t, "sts.example.com", tokenVolume.Projected.Sources[0].ServiceAccountToken.Audience, "unexpected token audience")
+	}
+
+	for _, container := range tokenJob.Spec.Template.Spec.Containers {
+		found := false
+		for _, mount := range container.VolumeMounts {
+			if mount.Name == "installtoken" {
+				found = true
+				assert.Equal(t, InstallTokenDir, mount.MountPath, "unexpected install token mount path")
+			}
+		}
+		assert.True(t, found, "install token not mounted in container %s", container.Name)
+	}
+
+	cd.Spec.InstallTokenAudience = "other.example.com"
+	otherJob, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, tokenJob.Spec.Template.Spec.Volumes, otherJob.Spec.Template.Spec.Volumes, "audience changes should change the pod template")
+	}
+}
+
+func TestGenerateInstallerJobInstallConfigSecret(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+
+	job, cfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, volume := range job.Spec.Template.Spec.Volumes {
+		if volume.Name == "installconfig" {
+			assert.NotNil(t, volume.ConfigMap, "install config should be mounted from a config map by default")
+			assert.Nil(t, volume.Secret, "install config should not be mounted from a secret by default")
+		}
+	}
+
+	cd.Spec.InstallConfigSecret = true
+	secretJob, secretCfgMap, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var cfgVolume *corev1.Volume
+	for i, volume := range secretJob.Spec.Template.Spec.Volumes {
+		if volume.Name == "installconfig" {
+			cfgVolume = &secretJob.Spec.Template.Spec.Volumes[i]
+		}
+	}
+	if assert.NotNil(t, cfgVolume, "missing install config volume") &&
+		assert.NotNil(t, cfgVolume.Secret, "install config should be mounted from a secret") {
+		assert.Nil(t, cfgVolume.ConfigMap, "install config should not be mounted from a config map")
+		assert.Equal(t, cfgMap.Name, cfgVolume.Secret.SecretName, "unexpected install config secret name")
+	}
+	assert.NotEqual(t, job.Spec.Template.Spec.Volumes, secretJob.Spec.Template.Spec.Volumes, "storing the install config in a secret should change the pod template")
+
+	secret := GenerateInstallConfigSecret(secretCfgMap)
+	assert.Equal(t, secretCfgMap.Name, secret.Name, "unexpected secret name")
+	assert.Equal(t, secretCfgMap.Namespace, secret.Namespace, "unexpected secret namespace")
+	assert.Equal(t, secretCfgMap.Annotations, secret.Annotations, "unexpected secret annotations")
+	assert.Equal(t, secretCfgMap.Data["install-config.yaml"], string(secret.Data["install-config.yaml"]), "unexpected install config in secret")
+}
+
+func TestGenerateInstallerJobRestartPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		restartPolicy  corev1.RestartPolicy
+		tryOnce        bool
+		expectedPolicy corev1.RestartPolicy
+	}{
+		{
+			name:           "default",
+			expectedPolicy: corev1.RestartPolicyOnFailure,
+		},
+		{
+			name:           "never",
+			restartPolicy:  corev1.RestartPolicyNever,
+			expectedPolicy: corev1.RestartPolicyNever,
+		},
+		{
+			name:           "on failure",
+			restartPolicy:  corev1.RestartPolicyOnFailure,
+			expectedPolicy: corev1.RestartPolicyOnFailure,
+		},
+		{
+			name:           "try once",
+			restartPolicy:  corev1.RestartPolicyOnFailure,
+			tryOnce:        true,
+			expectedPolicy: corev1.RestartPolicyNever,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+			cd.Spec.InstallPodRestartPolicy = test.restartPolicy
+			if test.tryOnce {
+				cd.Annotations[tryInstallOnceAnnotation] = "true"
+			}
+			job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expectedPolicy, job.Spec.Template.Spec.RestartPolicy, "unexpected restart policy")
+			}
+		})
+	}
+}
+
+func TestGenerateInstallerJobPriorityClass(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if assert.NoError(t, err) {
+		assert.Empty(t, job.Spec.Template.Spec.PriorityClassName, "unexpected priority class")
+	}
+
+	cd.Spec.InstallJobPriorityClassName = "high-priority"
+	job, _, err = GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "high-priority", job.Spec.Template.Spec.PriorityClassName, "unexpected priority class")
+	}
+}
+
+func TestGenerateInstallerJobPodAnnotations(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if assert.NoError(t, err) {
+		assert.NotContains(t, job.Spec.Template.Annotations, "sidecar.istio.io/inject", "unexpected pod annotation")
+	}
+
+	cd.Spec.InstallJobPodAnnotations = map[string]string{
+		"sidecar.istio.io/inject": "false",
+		inputsHashAnnotation:      "overridden",
+	}
+	job, _, err = GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "false", job.Spec.Template.Annotations["sidecar.istio.io/inject"], "missing pod annotation")
+		assert.NotEqual(t, "overridden", job.Spec.Template.Annotations[inputsHashAnnotation], "hash annotation should not be replaced")
+		assert.Empty(t, job.Annotations["sidecar.istio.io/inject"], "pod annotations should not be set on the job")
+	}
+}
+
+func TestGenerateInstallerJobUserTagLabels(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+	cd.Spec.AWS.UserTags = map[string]string{
+		"owner":         "hive",
+		"cost center":   "1234",
+		InstallJobLabel: "false",
+	}
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "hive", job.Labels["owner"], "user tag should be a label of the install job")
+	assert.NotContains(t, job.Labels, "cost center", "user tags which are not valid labels should be skipped")
+	assert.Equal(t, "true", job.Labels[InstallJobLabel], "user tags should not replace hive labels")
+	assert.NotContains(t, job.Spec.Template.Labels, "owner", "user tags should not be pod labels")
+}
+
+func TestSetInstallPodAntiAffinity(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Nil(t, job.Spec.Template.Spec.Affinity, "install pods should have no affinity by default")
+
+	SetInstallPodAntiAffinity(job, []string{"team-a", "team-b"})
+	if assert.NotNil(t, job.Spec.Template.Spec.Affinity, "missing affinity") &&
+		assert.NotNil(t, job.Spec.Template.Spec.Affinity.PodAntiAffinity, "missing pod anti-affinity") {
+		terms := job.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		if assert.Len(t, terms, 1, "unexpected anti-affinity terms") {
+			assert.Equal(t, "kubernetes.io/hostname", terms[0].PodAffinityTerm.TopologyKey, "unexpected topology key")
+			assert.Equal(t, []string{"team-a", "team-b"}, terms[0].PodAffinityTerm.Namespaces, "install pods should be spread across the target namespaces")
+			selector, err := metav1.LabelSelectorAsSelector(terms[0].PodAffinityTerm.LabelSelector)
+			if assert.NoError(t, err) {
+				assert.True(t, selector.Matches(labels.Set(job.Spec.Template.Labels)), "anti-affinity should match install pods")
+			}
+		}
+		assert.Empty(t, job.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			"anti-affinity should not prevent installs from being scheduled")
+	}
+}
+
+func TestDisableInstallPodServiceAccountToken(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, container := range job.Spec.Template.Spec.Containers {
+		assert.False(t, masksServiceAccountToken(container), "%s container should mount the token by default", container.Name)
+	}
+
+	DisableInstallPodServiceAccountToken(job)
+	assert.Nil(t, job.Spec.Template.Spec.AutomountServiceAccountToken, "the token should still be mounted into the pod")
+	for _, container := range job.Spec.Template.Spec.Containers {
+		if container.Name == installManagerContainerName {
+			assert.False(t, masksServiceAccountToken(container), "install manager needs the token to run the install")
+		} else {
+			assert.True(t, masksServiceAccountToken(container), "token should be hidden from the %s container", container.Name)
+		}
+	}
+}
+
+func masksServiceAccountToken(container corev1.Container) bool {
+	for _, mount := range container.VolumeMounts {
+		if mount.MountPath == serviceAccountTokenPath {
+			return mount.Name == serviceAccountTokenMaskVolume
+		}
+	}
+	return false
+}
+
+func TestSetCustomManifests(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	manifests := map[string]string{"99-config.yaml": "kind: ConfigMap"}
+	if !assert.NoError(t, SetCustomManifests(job, "custom-manifests", manifests)) {
+		return
+	}
+
+	podSpec := job.Spec.Template.Spec
+	found := false
+	for _, v := range podSpec.Volumes {
+		if v.Name == "custommanifests" {
+			found = true
+			if assert.NotNil(t, v.ConfigMap, "custom manifests volume should be a config map") {
+				assert.Equal(t, "custom-manifests", v.ConfigMap.Name, "unexpected config map name")
+			}
+		}
+	}
+	assert.True(t, found, "custom manifests volume not found")
+	for _, c := range podSpec.Containers {
+		mounted := false
+		for _, m := range c.VolumeMounts {
+			if m.Name == "custommanifests" {
+				mounted = true
+				assert.Equal(t, CustomManifestsDir, m.MountPath, "unexpected mount path in container %s", c.Name)
+			}
+		}
+		assert.True(t, mounted, "custom manifests not mounted in container %s", c.Name)
+		env := map[string]string{}
+		for _, e := range c.Env {
+			env[e.Name] = e.Value
+		}
+		assert.Equal(t, CustomManifestsDir, env["CUSTOM_MANIFESTS_PATH"], "unexpected manifests path in container %s", c.Name)
+	}
+	hash := job.Spec.Template.Annotations[inputsHashAnnotation]
+	assert.NotEmpty(t, hash, "missing inputs hash annotation")
+
+	job, _, err = GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	manifests["99-config.yaml"] = "kind: Secret"
+	if assert.NoError(t, SetCustomManifests(job, "custom-manifests", manifests)) {
+		assert.NotEqual(t, hash, job.Spec.Template.Annotations[inputsHashAnnotation], "hash should change with the manifests")
+	}
+}
+
+func TestSetInstallPodTerminationGracePeriod(t *testing.T) {
+	cd := testClusterDeployment()
+	cd.Status.InstallerImage = strPtr("example.com/installer:latest")
+	job, _, err := GenerateInstallerJob(cd, "example.com/hive:latest", "", "serviceaccount", "sshkey", "pullsecret", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.NotNil(t, job.Spec.Template.Spec.TerminationGracePeriodSeconds, "missing termination grace period") {
+		assert.Equal(t, DefaultInstallPodTerminationGracePeriodSeconds, *job.Spec.Template.Spec.TerminationGracePeriodSeconds,
+			"unexpected default termination grace period")
+	}
+
+	SetInstallPodTerminationGracePeriod(job, 1800)
+	if assert.NotNil(t, job.Spec.Template.Spec.TerminationGracePeriodSeconds, "missing termination grace period") {
+		assert.Equal(t, int64(1800), *job.Spec.Template.Spec.TerminationGracePeriodSeconds, "unexpected termination grace period")
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
 func testClusterDeployment() *hivev1.ClusterDeployment {
 	cd := &hivev1.ClusterDeployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,201 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	awsvalidation "github.com/openshift/installer/pkg/types/aws/validation"
+)
+
+// ValidateInstallConfig checks a generated InstallConfig against the rules of the vendored installer
+// types, so that obviously invalid configurations can be reported before an install job is launched.
+// This is not a replacement for the installer's own validation, only a guard against spec combinations
+// we know the installer will reject.
+func ValidateInstallConfig(ic *types.InstallConfig) error {
+	allErrs := field.ErrorList{}
+
+	if ic.ObjectMeta.Name == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("metadata", "name"), "cluster name required"))
+	} else {
+		for _, msg := range validation.IsDNS1123Label(ic.ObjectMeta.Name) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), ic.ObjectMeta.Name, msg))
+		}
+	}
+
+	if ic.BaseDomain == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("baseDomain"), "base domain required"))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(ic.BaseDomain) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("baseDomain"), ic.BaseDomain, msg))
+		}
+	}
+
+	if ic.PullSecret == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("pullSecret"), "pull secret required"))
+	}
+
+	allErrs = append(allErrs, validatePlatform(&ic.Platform, field.NewPath("platform"))...)
+
+	if ic.ControlPlane == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("controlPlane"), "control plane machine pool required"))
+	} else {
+		allErrs = append(allErrs, validateMachinePool(&ic.Platform, ic.ControlPlane, field.NewPath("controlPlane"))...)
+	}
+
+	poolNames := sets.NewString()
+	for i := range ic.Compute {
+		poolPath := field.NewPath("compute").Index(i)
+		if poolNames.Has(ic.Compute[i].Name) {
+			allErrs = append(allErrs, field.Duplicate(poolPath.Child("name"), ic.Compute[i].Name))
+		}
+		poolNames.Insert(ic.Compute[i].Name)
+		allErrs = append(allErrs, validateMachinePool(&ic.Platform, &ic.Compute[i], poolPath)...)
+	}
+
+	return allErrs.ToAggregate()
+}
+
+func validatePlatform(platform *types.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch platform.Name() {
+	case "":
+		allErrs = append(allErrs, field.Required(fldPath, "a platform must be specified"))
+	default:
+		if platform.AWS != nil {
+			allErrs = append(allErrs, awsvalidation.ValidatePlatform(platform.AWS, fldPath.Child("aws"))...)
+		}
+	}
+	return allErrs
+}
+
+func validateMachinePool(platform *types.Platform, pool *types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if pool.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "machine pool name required"))
+	}
+	if pool.Replicas != nil && *pool.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), *pool.Replicas, "number of replicas must not be negative"))
+	}
+	if pool.Platform.AWS != nil {
+		if platform.AWS == nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("platform", "aws"), pool.Platform.AWS,
+				fmt.Sprintf("cannot specify aws machine pool for %q platform", platform.Name())))
+		} else {
+			allErrs = append(allErrs, awsvalidation.ValidateMachinePool(platform.AWS, pool.Platform.AWS, fldPath.Child("platform", "aws"))...)
+		}
+	}
+	return allErrs
+}
+
+// ignitionConfig holds the minimal fields shared by every ignition config spec version.
+type ignitionConfig struct {
+	Ignition struct {
+		Version string `json:"version"`
+	} `json:"ignition"`
+}
+
+// ValidateIgnitionConfig checks that the given data parses as an ignition config with a version set.
+func ValidateIgnitionConfig(data []byte) error {
+	cfg := &ignitionConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("cannot parse ignition config: %v", err)
+	}
+	if cfg.Ignition.Version == "" {
+		return fmt.Errorf("ignition config has no ignition.version")
+	}
+	return nil
+}
+
+// dockerConfigJSON holds the fields of a docker config json pull secret read by the installer.
+type dockerConfigJSON struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Email    string `json:"email"`
+	} `json:"auths"`
+}
+
+// ValidatePullSecret checks that the given data parses as a docker config json pull secret with credentials for
+// at least one registry. The credentials of each registry must be either a base64 encoded "user:password" auth,
+// or a username and password.
+func ValidatePullSecret(data []byte) error {
+	cfg := &dockerConfigJSON{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("cannot parse pull secret as a docker config: %v", err)
+	}
+	if len(cfg.Auths) == 0 {
+		return fmt.Errorf("pull secret has no registry credentials in auths")
+	}
+	registries := make([]string, 0, len(cfg.Auths))
+	for registry := range cfg.Auths {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+	for _, registry := range registries {
+		auth := cfg.Auths[registry]
+		if auth.Auth == "" {
+			if auth.Username == "" || auth.Password == "" {
+				return fmt.Errorf("pull secret has no credentials for registry %s", registry)
+			}
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return fmt.Errorf("cannot decode the auth of registry %s in pull secret: %v", registry, err)
+		}
+		if !strings.Contains(string(decoded), ":") {
+			return fmt.Errorf("the auth of registry %s in pull secret is not of the form user:password", registry)
+		}
+	}
+	return nil
+}
+
+// ValidateCustomManifests checks that the file names of the given custom manifests can be added to the
+// manifests generated by the installer: they must be plain file names with a yaml or json extension.
+func ValidateCustomManifests(manifests map[string]string) error {
+	if len(manifests) == 0 {
+		return fmt.Errorf("custom manifests config map has no manifests")
+	}
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "/\\") {
+			return fmt.Errorf("custom manifest %q is not a plain file name", name)
+		}
+		switch filepath.Ext(name) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return fmt.Errorf("custom manifest %q must have a .yaml, .yml or .json extension", name)
+		}
+	}
+	return nil
+}
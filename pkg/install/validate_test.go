@@ -0,0 +1,318 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	installtypes "github.com/openshift/installer/pkg/types"
+)
+
+func buildValidInstallConfig() *installtypes.InstallConfig {
+	ic := buildBaseExpectedInstallConfig()
+	ic.ObjectMeta.Name = testName
+	return ic
+}
+
+func TestValidateInstallConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		ic          *installtypes.InstallConfig
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			ic:   buildValidInstallConfig(),
+		},
+		{
+			name: "valid generated from cluster deployment",
+			ic: func() *installtypes.InstallConfig {
+				cd := buildValidClusterDeployment()
+				cd.Spec.ClusterName = testName
+				ic, _ := GenerateInstallConfig(cd, adminSSHKey, pullSecret, true)
+				return ic
+			}(),
+		},
+		{
+			name: "missing cluster name",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.ObjectMeta.Name = ""
+				return ic
+			}(),
+			expectedErr: "metadata.name: Required value",
+		},
+		{
+			name: "invalid cluster name",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.ObjectMeta.Name = "Not_A_Label"
+				return ic
+			}(),
+			expectedErr: "metadata.name: Invalid value",
+		},
+		{
+			name: "missing base domain",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.BaseDomain = ""
+				return ic
+			}(),
+			expectedErr: "baseDomain: Required value",
+		},
+		{
+			name: "missing pull secret",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.PullSecret = ""
+				return ic
+			}(),
+			expectedErr: "pullSecret: Required value",
+		},
+		{
+			name: "no platform",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.Platform.AWS = nil
+				ic.ControlPlane.Platform.AWS = nil
+				ic.Compute[0].Platform.AWS = nil
+				return ic
+			}(),
+			expectedErr: "platform: Required value",
+		},
+		{
+			name: "unknown region",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.Platform.AWS.Region = "not-a-region"
+				return ic
+			}(),
+			expectedErr: "platform.aws.region: Unsupported value",
+		},
+		{
+			name: "zone outside of region",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.ControlPlane.Platform.AWS.Zones = []string{"us-west-2a"}
+				return ic
+			}(),
+			expectedErr: "controlPlane.platform.aws.zones[0]: Invalid value",
+		},
+		{
+			name: "negative replicas",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				replicas := int64(-1)
+				ic.Compute[0].Replicas = &replicas
+				return ic
+			}(),
+			expectedErr: "compute[0].replicas: Invalid value",
+		},
+		{
+			name: "duplicate compute pools",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.Compute = append(ic.Compute, ic.Compute[0])
+				return ic
+			}(),
+			expectedErr: "compute[1].name: Duplicate value",
+		},
+		{
+			name: "missing control plane",
+			ic: func() *installtypes.InstallConfig {
+				ic := buildValidInstallConfig()
+				ic.ControlPlane = nil
+				return ic
+			}(),
+			expectedErr: "controlPlane: Required value",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateInstallConfig(test.ic)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestValidateIgnitionConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			data: `{"ignition":{"version":"2.2.0"},"storage":{"files":[]}}`,
+		},
+		{
+			name:        "not json",
+			data:        "not: json",
+			expectedErr: "cannot parse ignition config",
+		},
+		{
+			name:        "missing version",
+			data:        `{"storage":{"files":[]}}`,
+			expectedErr: "ignition config has no ignition.version",
+		},
+		{
+			name:        "empty",
+			data:        "",
+			expectedErr: "cannot parse ignition config",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateIgnitionConfig([]byte(test.data))
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestValidateCustomManifests(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifests   map[string]string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			manifests: map[string]string{
+				"99-config.yaml":  "kind: ConfigMap",
+				"99-machine.yml":  "kind: MachineConfig",
+				"99-network.json": `{"kind":"Network"}`,
+			},
+		},
+		{
+			name:        "empty",
+			manifests:   map[string]string{},
+			expectedErr: "has no manifests",
+		},
+		{
+			name:        "wrong extension",
+			manifests:   map[string]string{"99-config.txt": "kind: ConfigMap"},
+			expectedErr: `"99-config.txt" must have a .yaml, .yml or .json extension`,
+		},
+		{
+			name:        "no extension",
+			manifests:   map[string]string{"99-config": "kind: ConfigMap"},
+			expectedErr: "must have a .yaml, .yml or .json extension",
+		},
+		{
+			name:        "hidden file",
+			manifests:   map[string]string{".config.yaml": "kind: ConfigMap"},
+			expectedErr: `".config.yaml" is not a plain file name`,
+		},
+		{
+			name:        "path",
+			manifests:   map[string]string{"../config.yaml": "kind: ConfigMap"},
+			expectedErr: "is not a plain file name",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCustomManifests(test.manifests)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestValidatePullSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			data: `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz","email":"user@example.com"}}}`,
+		},
+		{
+			name: "username and password",
+			data: `{"auths":{"registry.example.com":{"username":"user","password":"pass"}}}`,
+		},
+		{
+			name:        "no auths",
+			data:        "{}",
+			expectedErr: "pull secret has no registry credentials",
+		},
+		{
+			name:        "null auths",
+			data:        `{"auths":null}`,
+			expectedErr: "pull secret has no registry credentials",
+		},
+		{
+			name:        "empty auths",
+			data:        `{"auths":{}}`,
+			expectedErr: "pull secret has no registry credentials",
+		},
+		{
+			name:        "no credentials for registry",
+			data:        `{"auths":{"registry.example.com":{"email":"user@example.com"}}}`,
+			expectedErr: "pull secret has no credentials for registry registry.example.com",
+		},
+		{
+			name:        "auth not base64",
+			data:        `{"auths":{"registry.example.com":{"auth":"not base64!"}}}`,
+			expectedErr: "cannot decode the auth of registry registry.example.com",
+		},
+		{
+			name:        "auth without password",
+			data:        `{"auths":{"registry.example.com":{"auth":"dXNlcg=="}}}`,
+			expectedErr: "is not of the form user:password",
+		},
+		{
+			name:        "not json",
+			data:        "not: json",
+			expectedErr: "cannot parse pull secret as a docker config",
+		},
+		{
+			name:        "auths not an object",
+			data:        `{"auths":["registry.example.com"]}`,
+			expectedErr: "cannot parse pull secret as a docker config",
+		},
+		{
+			name:        "auth not a string",
+			data:        `{"auths":{"registry.example.com":{"auth":42}}}`,
+			expectedErr: "cannot parse pull secret as a docker config",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidatePullSecret([]byte(test.data))
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.expectedErr)
+			}
+		})
+	}
+}
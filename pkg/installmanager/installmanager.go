@@ -32,9 +32,9 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/ghodss/yaml"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
@@ -68,6 +68,8 @@ const (
 	metadataRelativePath                = "metadata.json"
 	adminKubeConfigRelativePath         = "auth/kubeconfig"
 	adminPasswordRelativePath           = "auth/kubeadmin-password"
+	bootstrapIgnitionRelativePath       = "bootstrap.ign"
+	customManifestsRelativePath         = "openshift"
 	kubernetesKeyPrefix                 = "kubernetes.io/cluster/"
 	kubeadminUsername                   = "kubeadmin"
 	metadataConfigmapStringTemplate     = "%s-metadata"
@@ -83,9 +85,33 @@ ssh -o "StrictHostKeyChecking=no" -A core@%s '/usr/local/bin/installer-gather.sh
 scp -o "StrictHostKeyChecking=no" core@%s:~/log-bundle.tar.gz .`
 )
 
+// Phases of the install recorded on the install pod.
+const (
+	installPhaseGeneratingAssets       = "GeneratingAssets"
+	installPhaseCreatingInfrastructure = "CreatingInfrastructure"
+	installPhaseWaitingForAPI          = "WaitingForAPI"
+	installPhaseBootstrapping          = "Bootstrapping"
+	installPhaseDestroyingBootstrap    = "DestroyingBootstrap"
+	installPhaseInitializing           = "WaitingForClusterInitialization"
+	installPhaseCleaningUp             = "CleaningUp"
+)
+
 var (
 	// multi-line mode regex that allows removing/mutating any line containing 'password' case-insensitive
 	multiLineRedactLinesWithPassword = regexp.MustCompile(`(?mi)^.*password.*$`)
+
+	// installPhaseMarkers are the progress messages the installer logs as it moves through the phases of
+	// an install, in the order they appear.
+	installPhaseMarkers = []struct {
+		regex *regexp.Regexp
+		phase string
+	}{
+		{regex: regexp.MustCompile(`Creating infrastructure resources`), phase: installPhaseCreatingInfrastructure},
+		{regex: regexp.MustCompile(`Waiting up to \S+ for the Kubernetes API`), phase: installPhaseWaitingForAPI},
+		{regex: regexp.MustCompile(`Waiting up to \S+ for (bootstrapping to complete|the bootstrap-complete event)`), phase: installPhaseBootstrapping},
+		{regex: regexp.MustCompile(`Destroying the bootstrap resources`), phase: installPhaseDestroyingBootstrap},
+		{regex: regexp.MustCompile(`Waiting up to \S+ for the cluster .* to initialize`), phase: installPhaseInitializing},
+	}
 )
 
 // InstallManager coordinates executing the openshift-install binary, modifying
@@ -107,6 +133,9 @@ type InstallManager struct {
 	uploadAdminKubeconfig         func(*hivev1.ClusterDeployment, *InstallManager) (*corev1.Secret, error)
 	uploadAdminPassword           func(*hivev1.ClusterDeployment, *InstallManager) (*corev1.Secret, error)
 	uploadInstallerLog            func(*hivev1.ClusterDeployment, *InstallManager, error) error
+
+	installPhaseLock sync.Mutex
+	installPhase     string
 }
 
 // NewInstallManagerCommand is the entrypoint to create the 'install-manager' subcommand
@@ -221,18 +250,23 @@ func (m *InstallManager) Run() error {
 	// Generate an install-config.yaml:
 	sshKey := os.Getenv("SSH_PUB_KEY")
 	pullSecret := os.Getenv("PULL_SECRET")
+	additionalTrustBundle := ""
+	if additionalTrustBundlePath := os.Getenv("ADDITIONAL_TRUST_BUNDLE_PATH"); additionalTrustBundlePath != "" {
+		m.log.WithField("path", additionalTrustBundlePath).Info("reading additional trust bundle")
+		bundle, err := ioutil.ReadFile(additionalTrustBundlePath)
+		if err != nil {
+			m.log.WithError(err).Error("error reading additional trust bundle")
+			return err
+		}
+		additionalTrustBundle = string(bundle)
+	}
 	m.log.Info("generating install config")
-	ic, err := install.GenerateInstallConfig(cd, sshKey, pullSecret, true)
+	installConfig, err := install.GenerateInstallConfigYAML(cd, sshKey, pullSecret, additionalTrustBundle)
 	if err != nil {
 		m.log.WithError(err).Error("error generating install-config")
 		return err
 	}
-	d, err := yaml.Marshal(ic)
-	if err != nil {
-		m.log.WithError(err).Error("error marshalling install-config.yaml")
-		return err
-	}
-	err = ioutil.WriteFile(filepath.Join(m.WorkDir, "install-config.yaml"), d, 0644)
+	err = ioutil.WriteFile(filepath.Join(m.WorkDir, "install-config.yaml"), []byte(installConfig), 0644)
 	if err != nil {
 		m.log.WithError(err).Error("error writing install-config.yaml to disk")
 		return err
@@ -248,6 +282,7 @@ func (m *InstallManager) Run() error {
 
 	// Generate installer assets we need to modify or upload.
 	m.log.Info("generating assets")
+	m.setInstallPhase(installPhaseGeneratingAssets)
 	if err := m.generateAssets(cd); err != nil {
 		if upErr := m.uploadInstallerLog(cd, m, err); upErr != nil {
 			m.log.WithError(err).Error("error saving asset generation log")
@@ -268,6 +303,7 @@ func (m *InstallManager) Run() error {
 	installErr := m.provisionCluster(cd)
 	if installErr != nil {
 		m.log.WithError(installErr).Error("error running openshift-install, running deprovision to clean up")
+		m.setInstallPhase(installPhaseCleaningUp)
 
 		// gatherLogs(cd, m) when saving log file is implemented
 
@@ -456,6 +492,20 @@ func runUninstaller(clusterName, region, infraID string, logger log.FieldLogger)
 // generateAssets runs openshift-install commands to generate on-disk assets we need to
 // upload or modify prior to provisioning resources in the cloud.
 func (m *InstallManager) generateAssets(cd *hivev1.ClusterDeployment) error {
+	if manifestsPath := os.Getenv("CUSTOM_MANIFESTS_PATH"); manifestsPath != "" {
+		m.log.Info("running openshift-install create manifests")
+		if err := m.runOpenShiftInstallCommand([]string{"create", "manifests", "--dir", m.WorkDir}); err != nil {
+			m.log.WithError(err).Error("error generating installer manifests")
+			return err
+		}
+		m.log.WithField("path", manifestsPath).Info("adding custom manifests")
+		// The installer consumes the manifests from the work dir when creating the ignition configs.
+		if err := copyCustomManifests(manifestsPath, filepath.Join(m.WorkDir, customManifestsRelativePath)); err != nil {
+			m.log.WithError(err).Error("error adding custom manifests")
+			return err
+		}
+	}
+
 	m.log.Info("running openshift-install create ignition-configs")
 	err := m.runOpenShiftInstallCommand([]string{"create", "ignition-configs", "--dir", m.WorkDir})
 	if err != nil {
@@ -463,6 +513,46 @@ func (m *InstallManager) generateAssets(cd *hivev1.ClusterDeployment) error {
 		return err
 	}
 	m.log.Info("assets generated successfully")
+
+	if overridePath := os.Getenv("BOOTSTRAP_IGNITION_OVERRIDE_PATH"); overridePath != "" {
+		m.log.WithField("path", overridePath).Info("overriding bootstrap ignition config")
+		override, err := ioutil.ReadFile(overridePath)
+		if err != nil {
+			m.log.WithError(err).Error("error reading bootstrap ignition override")
+			return err
+		}
+		// The installer consumes the modified bootstrap.ign from the work dir when creating the cluster.
+		if err := ioutil.WriteFile(filepath.Join(m.WorkDir, bootstrapIgnitionRelativePath), override, 0644); err != nil {
+			m.log.WithError(err).Error("error writing bootstrap ignition override")
+			return err
+		}
+	}
+	return nil
+}
+
+// copyCustomManifests copies the manifests mounted from the custom manifests config map into the given
+// manifests directory of the installer. The hidden entries kubernetes uses to update config map volumes
+// are skipped.
+func copyCustomManifests(srcDir, dstDir string) error {
+	files, err := ioutil.ReadDir(srcDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(srcDir, f.Name()))
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dstDir, f.Name()), content, 0644); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -541,6 +631,9 @@ func (m *InstallManager) runOpenShiftInstallCommand(args []string) error {
 				continue
 			}
 
+			if phase := installPhaseForLogLine(fullLine); phase != "" {
+				m.setInstallPhase(phase)
+			}
 			cleanLine := cleanupLogOutput(fullLine)
 			fmt.Println(cleanLine)
 			// clear out the line buffer so we can start again
@@ -560,6 +653,38 @@ func (m *InstallManager) runOpenShiftInstallCommand(args []string) error {
 	return nil
 }
 
+// installPhaseForLogLine returns the install phase the installer has reached when it logs the given line, or
+// an empty string when the line does not mark the start of a phase.
+func installPhaseForLogLine(line string) string {
+	for _, marker := range installPhaseMarkers {
+		if marker.regex.MatchString(line) {
+			return marker.phase
+		}
+	}
+	return ""
+}
+
+// setInstallPhase records the current phase of the install in the status of the cluster deployment. The phase
+// is informational only, so failures are logged and otherwise ignored, and the phase is recorded again with the
+// next phase change.
+func (m *InstallManager) setInstallPhase(phase string) {
+	m.installPhaseLock.Lock()
+	defer m.installPhaseLock.Unlock()
+	if m.installPhase == phase {
+		return
+	}
+	phaseLog := m.log.WithField("phase", phase)
+	err := updateClusterDeploymentStatusWithRetries(m, func(cd *hivev1.ClusterDeployment) {
+		cd.Status.InstallPhase = phase
+	})
+	if err != nil {
+		phaseLog.WithError(err).Warning("unable to record install phase")
+		return
+	}
+	m.installPhase = phase
+	phaseLog.Info("recorded install phase")
+}
+
 func uploadClusterMetadata(cd *hivev1.ClusterDeployment, m *InstallManager) error {
 	m.log.Infoln("extracting cluster ID and uploading cluster metadata")
 	fullMetadataPath := filepath.Join(m.WorkDir, metadataRelativePath)
@@ -37,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -415,6 +416,95 @@ func testSecret(secretType corev1.SecretType, name, key, value string) *corev1.S
 	return s
 }
 
+func TestInstallPhaseForLogLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{
+			line: `level=info msg="Consuming \"Worker Ignition Config\" from target directory"`,
+		},
+		{
+			line:     `level=info msg="Creating infrastructure resources..."`,
+			expected: installPhaseCreatingInfrastructure,
+		},
+		{
+			line:     `level=info msg="Waiting up to 30m0s for the Kubernetes API at https://api.test-cluster.example.com:6443..."`,
+			expected: installPhaseWaitingForAPI,
+		},
+		{
+			line:     `level=info msg="Waiting up to 30m0s for the bootstrap-complete event..."`,
+			expected: installPhaseBootstrapping,
+		},
+		{
+			line:     `level=info msg="Waiting up to 30m0s for bootstrapping to complete..."`,
+			expected: installPhaseBootstrapping,
+		},
+		{
+			line:     `level=info msg="Destroying the bootstrap resources..."`,
+			expected: installPhaseDestroyingBootstrap,
+		},
+		{
+			line:     `level=info msg="Waiting up to 30m0s for the cluster at https://api.test-cluster.example.com:6443 to initialize..."`,
+			expected: installPhaseInitializing,
+		},
+		{
+			line: `level=info msg="Install complete!"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			assert.Equal(t, test.expected, installPhaseForLogLine(test.line))
+		})
+	}
+}
+
+func TestSetInstallPhase(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	fakeClient := &failingStatusClient{Client: fake.NewFakeClient(testClusterDeployment()), failures: 1}
+	im := InstallManager{
+		LogLevel:              "debug",
+		ClusterDeploymentName: testClusterName,
+		Namespace:             testNamespace,
+		DynamicClient:         fakeClient,
+		log:                   log.WithField("test", "TestSetInstallPhase"),
+	}
+
+	im.setInstallPhase(installPhaseBootstrapping)
+	assert.Empty(t, im.installPhase, "install phase should not be cached when it could not be recorded")
+
+	im.setInstallPhase(installPhaseBootstrapping)
+	cd := &hivev1.ClusterDeployment{}
+	err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: testClusterName}, cd)
+	if assert.NoError(t, err) {
+		assert.Equal(t, installPhaseBootstrapping, cd.Status.InstallPhase, "unexpected install phase")
+	}
+	assert.Equal(t, installPhaseBootstrapping, im.installPhase, "recorded install phase should be cached")
+}
+
+// failingStatusClient fails the given number of status updates before passing them on to the wrapped client.
+type failingStatusClient struct {
+	client.Client
+	failures int
+}
+
+func (c *failingStatusClient) Status() client.StatusWriter {
+	return &failingStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type failingStatusWriter struct {
+	client.StatusWriter
+	client *failingStatusClient
+}
+
+func (w *failingStatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	if w.client.failures > 0 {
+		w.client.failures--
+		return fmt.Errorf("status update failed")
+	}
+	return w.StatusWriter.Update(ctx, obj)
+}
+
 func TestCleanupRegex(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -478,6 +568,46 @@ last line with password in text`,
 
 }
 
+func TestCopyCustomManifests(t *testing.T) {
+	srcDir, err := ioutil.TempDir("", "custommanifests")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(srcDir)
+	dstDir, err := ioutil.TempDir("", "installmanifests")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dstDir)
+
+	// Lay the source out like a config map volume, where the manifests are links into a hidden data dir.
+	dataDir := filepath.Join(srcDir, "..data")
+	if !assert.NoError(t, os.Mkdir(dataDir, 0755)) {
+		return
+	}
+	if !assert.NoError(t, ioutil.WriteFile(filepath.Join(dataDir, "99-config.yaml"), []byte("kind: ConfigMap"), 0644)) {
+		return
+	}
+	if !assert.NoError(t, os.Symlink(filepath.Join("..data", "99-config.yaml"), filepath.Join(srcDir, "99-config.yaml"))) {
+		return
+	}
+
+	if !assert.NoError(t, copyCustomManifests(srcDir, filepath.Join(dstDir, customManifestsRelativePath))) {
+		return
+	}
+	files, err := ioutil.ReadDir(filepath.Join(dstDir, customManifestsRelativePath))
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, files, 1, "only the manifest should be copied") {
+		assert.Equal(t, "99-config.yaml", files[0].Name())
+	}
+	content, err := ioutil.ReadFile(filepath.Join(dstDir, customManifestsRelativePath, "99-config.yaml"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "kind: ConfigMap", string(content), "unexpected manifest contents")
+	}
+}
+
 func TestGatherLogs(t *testing.T) {
 	fakeBootstrapIP := "1.2.3.4"
 
@@ -616,6 +616,9 @@ spec:
           - /opt/services/manager
           - --log-level
           - info
+        ports:
+        - name: metrics
+          containerPort: 2112
         volumeMounts:
         - name: kubectl-cache
           mountPath: /var/cache/kubectl
@@ -655,6 +658,7 @@ spec:
   ports:
   - name: metrics
     port: 2112
+    targetPort: metrics
 `)
 
 func configManagerServiceYamlBytes() ([]byte, error) {
@@ -1197,6 +1201,19 @@ rules:
   - get
   - list
   - watch
+- apiGroups:
+  - ""
+  resources:
+  - pods/log
+  verbs:
+  - get
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+  - patch
 - apiGroups:
   - rbac.authorization.k8s.io
   resources:
@@ -1236,6 +1253,30 @@ rules:
   - update
   - patch
   - delete
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - syncsets
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - clusterdeprovisionrequests
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
 - apiGroups:
   - hive.openshift.io
   resources:
@@ -1244,6 +1285,22 @@ rules:
   - get
   - update
   - patch
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - hiveconfigs
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - hiveconfigs/status
+  verbs:
+  - get
+  - update
+  - patch
 - apiGroups:
   - hive.openshift.io
   resources:
@@ -1265,6 +1322,14 @@ rules:
   - get
   - update
   - patch
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+  - list
+  - watch
 - apiGroups:
   - hive.openshift.io
   resources:
@@ -1441,6 +1506,9 @@ spec:
   - JSONPath: .status.infraID
     name: InfraID
     type: string
+  - JSONPath: .status.installDuration
+    name: InstallDuration
+    type: string
   - JSONPath: .metadata.creationTimestamp
     name: Age
     type: date
@@ -1470,10 +1538,52 @@ spec:
           type: object
         spec:
           properties:
+            additionalDNSRecords:
+              description: AdditionalDNSRecords is a list of DNS records to create
+                in the managed DNS zone in addition to the records required by the
+                cluster, for example a vanity CNAME. Only used when ManageDNS is true.
+              items:
+                properties:
+                  dnsName:
+                    description: The hostname of the DNS record
+                    type: string
+                  labels:
+                    description: Labels stores labels defined for the Endpoint
+                    type: object
+                  providerSpecific:
+                    description: ProviderSpecific stores provider specific config
+                    type: object
+                  recordTTL:
+                    description: TTL for the record
+                    format: int64
+                    type: integer
+                  recordType:
+                    description: RecordType type of record, e.g. CNAME, A, SRV, TXT
+                      etc
+                    type: string
+                  targets:
+                    description: The targets the DNS record points to
+                    items:
+                      type: string
+                    type: array
+                type: object
+              type: array
+            additionalTrustBundle:
+              description: AdditionalTrustBundle is a reference to a secret containing
+                a PEM-encoded X.509 certificate bundle, stored under the "ca-bundle.crt"
+                key, that the installer and the cluster's nodes should trust. This
+                is typically required when installing through a proxy or from a registry
+                using a private certificate authority.
+              type: object
             baseDomain:
               description: BaseDomain is the base domain to which the cluster should
                 belong.
               type: string
+            bootstrapIgnitionOverrideRef:
+              description: BootstrapIgnitionOverrideRef is a reference to a secret
+                containing an ignition config, stored under the "bootstrap.ign" key,
+                which replaces the bootstrap ignition config generated by the installer.
+              type: object
             certificateBundles:
               description: CertificateBundles is a list of certificate bundles associated
                 with this cluster
@@ -1610,6 +1720,19 @@ spec:
                     type: array
                 type: object
               type: array
+            consoleRoute:
+              description: ConsoleRoute is the route in the cluster from which the
+                web console URL is read, for clusters which serve the web console
+                from a different route. Defaults to the console route in the openshift-console
+                namespace.
+              properties:
+                name:
+                  description: Name is the name of the route.
+                  type: string
+                namespace:
+                  description: Namespace is the namespace of the route.
+                  type: string
+              type: object
             controlPlane:
               description: ControlPlane is the MachinePool containing control plane
                 nodes that need to be installed.
@@ -1744,6 +1867,24 @@ spec:
                       type: string
                   type: object
               type: object
+            imageContentSources:
+              description: ImageContentSources lists sources/repositories for the
+                release-image content, used to install from a mirror registry in disconnected
+                environments.
+              items:
+                properties:
+                  mirrors:
+                    description: Mirrors is one or more repositories that may also
+                      contain the same images.
+                    items:
+                      type: string
+                    type: array
+                  source:
+                    description: Source is the repository that users refer to, e.g.
+                      in image pull specifications.
+                    type: string
+                type: object
+              type: array
             imageSet:
               description: ImageSet is a reference to a ClusterImageSet. If values
                 are specified for Images, those will take precedence over the ones
@@ -1811,10 +1952,64 @@ spec:
                 - domain
                 type: object
               type: array
+            installConfigSecret:
+              description: InstallConfigSecret, when true, stores the generated install-config
+                in a Secret rather than a ConfigMap, as it may contain sensitive values
+                such as proxy credentials. Defaults to false.
+              type: boolean
+            installJobPodAnnotations:
+              description: InstallJobPodAnnotations are added to the install pod,
+                for example to opt the pod out of service mesh sidecar injection.
+                Changing them results in a new install job while the cluster is installing.
+                Annotations set by hive on the install pod take precedence.
+              type: object
+            installJobPriorityClassName:
+              description: InstallJobPriorityClassName is the name of the PriorityClass
+                used for the install pod, so that installs are not preempted by lower
+                priority workloads on busy clusters.
+              type: string
+            installPodRestartPolicy:
+              description: InstallPodRestartPolicy is the restart policy of the install
+                pod. OnFailure retries a failed install within the same pod, while
+                Never has the install job create a new pod for each attempt. Defaults
+                to OnFailure, and is always Never for clusters which should only try
+                to install once.
+              enum:
+              - OnFailure
+              - Never
+              type: string
+            installTokenAudience:
+              description: InstallTokenAudience, when set, mounts a projected service
+                account token with this audience into the install pod, for installers
+                which must authenticate to external systems.
+              type: string
             manageDNS:
               description: ManageDNS specifies whether a DNSZone should be created
                 and managed automatically for this ClusterDeployment
               type: boolean
+            managedDNSRecordTTL:
+              description: ManagedDNSRecordTTL is the TTL, in seconds, for the DNS
+                records created for a managed DNS zone. If unset, the DNSZone default
+                is used.
+              format: int64
+              type: integer
+            managedDNSZoneName:
+              description: ManagedDNSZoneName is the name of the DNSZone created by
+                hive when ManageDNS is true. Defaults to a name derived from the ClusterDeployment
+                name. It cannot be set along with ManagedDNSZoneRef.
+              type: string
+            managedDNSZoneRef:
+              description: ManagedDNSZoneRef references an existing DNSZone, in the
+                ClusterDeployment's namespace, which is managed outside of hive. When
+                set along with ManageDNS, hive waits for the referenced zone to become
+                available instead of creating its own, and never deletes it.
+              type: object
+            manifestsConfigMapRef:
+              description: ManifestsConfigMapRef is a reference to a config map of
+                additional manifests which are added to the manifests generated by
+                the installer. Each key is the file name of a manifest and must end
+                in .yaml, .yml or .json.
+              type: object
             networking:
               description: Networking defines the pod network provider in the cluster.
               properties:
@@ -1880,10 +2075,26 @@ spec:
                             type: string
                           type: array
                       type: object
+                    publish:
+                      description: Publish controls whether the API and ingress of
+                        the cluster are reachable from the internet (External) or
+                        only from within the VPC of the cluster and the networks connected
+                        to it (Internal). Internal clusters must be installed into
+                        existing Subnets. Defaults to External.
+                      type: string
                     region:
                       description: Region specifies the AWS region where the cluster
                         will be created.
                       type: string
+                    subnets:
+                      description: Subnets specifies existing subnets (by ID) where
+                        cluster resources will be created. The availability zones
+                        of these subnets bound where machines, including the control
+                        plane, are placed. Leave unset to have the installer create
+                        new subnets.
+                      items:
+                        type: string
+                      type: array
                     userTags:
                       description: UserTags specifies additional tags for AWS resources
                         created for the cluster.
@@ -1940,7 +2151,9 @@ spec:
               type: object
             platformSecrets:
               description: PlatformSecrets contains credentials and secrets for the
-                cluster infrastructure.
+                cluster infrastructure. When no AWS credentials are set for an AWS
+                cluster, the default-aws-credentials secret in the namespace of the
+                ClusterDeployment is used if it exists.
               properties:
                 aws:
                   properties:
@@ -1948,15 +2161,30 @@ spec:
                       description: Credentials refers to a secret that contains the
                         AWS account access credentials.
                       type: object
+                    dnsCredentials:
+                      description: DNSCredentials refers to a secret that contains
+                        the access credentials of the AWS account hosting the managed
+                        DNS zone of the cluster, for when DNS is delegated to a different
+                        account than the one the cluster is installed in. Credentials
+                        is used for the managed DNS zone when this is unset.
+                      type: object
                   type: object
               type: object
+            postInstallManifests:
+              description: PostInstallManifests is a list of manifests to apply to
+                the cluster once it has been installed. They are synced to the cluster
+                through a SyncSet owned by the ClusterDeployment.
+              items:
+                type: object
+              type: array
             preserveOnDelete:
               description: PreserveOnDelete allows the user to disconnect a cluster
                 from Hive without deprovisioning it
               type: boolean
             pullSecret:
               description: PullSecret is the reference to the secret to use when pulling
-                images.
+                images. When unset, the default pull secret configured in the HiveConfig
+                is used.
               type: object
             sshKey:
               description: SSHKey is the reference to the secret that contains a public
@@ -1969,7 +2197,6 @@ spec:
           - controlPlane
           - compute
           - platform
-          - pullSecret
           - platformSecrets
           type: object
         status:
@@ -2004,6 +2231,11 @@ spec:
                 generated during installation. Used for reporting metrics among other
                 places.
               type: string
+            clusterVersionLastCheckTime:
+              description: ClusterVersionLastCheckTime is the last time the remote
+                cluster's ClusterVersion was fetched.
+              format: date-time
+              type: string
             clusterVersionStatus:
               description: ClusterVersionStatus will hold a copy of the remote cluster's
                 ClusterVersion.Status
@@ -2166,6 +2398,11 @@ spec:
                     type: string
                 type: object
               type: array
+            deprovisionProgress:
+              description: DeprovisionProgress is the cleanup step reached since the
+                ClusterDeployment was deleted. Each step is safe to repeat, so cleanup
+                resumes from here when hive is restarted.
+              type: string
             federated:
               description: Federated is true if the cluster deployment has been federated
                 with the host cluster.
@@ -2174,23 +2411,94 @@ spec:
               description: FederatedClusterRef is the reference to the federated cluster
                 resource associated with this ClusterDeployment.
               type: object
+            imageResolutionTimedOutGeneration:
+              description: ImageResolutionTimedOutGeneration is the generation of
+                the cluster deployment for which the installer image was not resolved
+                in time. No imageset job is created again until the generation changes.
+              format: int64
+              type: integer
             infraID:
               description: InfraID is an identifier for this cluster generated during
                 installation and used for tagging/naming resources in cloud providers.
               type: string
+            ingressDomainMigrations:
+              description: IngressDomainMigrations records the ingress domains in
+                the spec which were rewritten by hive to remove a leading wildcard.
+              items:
+                properties:
+                  from:
+                    description: From is the domain before the rewrite.
+                    type: string
+                  name:
+                    description: Name of the ClusterIngress whose domain was rewritten.
+                    type: string
+                  to:
+                    description: To is the domain after the rewrite.
+                    type: string
+                type: object
+              type: array
+            installDuration:
+              description: InstallDuration is how long the install has taken, from
+                InstallStartedTimestamp to InstalledTimestamp, in a human readable
+                form. While the install is running it is the time elapsed so far,
+                rounded down to the minute.
+              type: string
+            installMetadata:
+              description: InstallMetadata is a subset of the installer metadata for
+                the cluster. It is copied from the cluster's metadata ConfigMap, which
+                remains the source of truth, when enabled in the HiveConfig.
+              properties:
+                clusterID:
+                  description: ClusterID is the globally unique identifier of the
+                    cluster.
+                  type: string
+                infraID:
+                  description: InfraID is the identifier used by the installer to
+                    tag and name cloud resources.
+                  type: string
+                region:
+                  description: Region is the cloud region the cluster was installed
+                    in.
+                  type: string
+              type: object
+            installPhase:
+              description: InstallPhase is the phase the running install has reached,
+                for example Bootstrapping, as last reported by the install manager.
+                It is cleared once the cluster is installed.
+              type: string
+            installPodTerminationReason:
+              description: InstallPodTerminationReason is the most common reason the
+                containers of the clusters install pods last terminated, for example
+                OOMKilled or Error.
+              type: string
             installRestarts:
               description: InstallRestarts is the total count of container restarts
                 on the clusters install job.
               format: int64
               type: integer
+            installStartedTimestamp:
+              description: InstallStartedTimestamp is when the first install job for
+                the cluster was created. It is not reset when the install job is replaced.
+              format: date-time
+              type: string
             installed:
               description: Installed is true if the installer job has successfully
                 completed for this cluster.
               type: boolean
+            installedTimestamp:
+              description: InstalledTimestamp is when the cluster finished installing.
+              format: date-time
+              type: string
             installerImage:
               description: InstallerImage is the name of the installer image to use
                 when installing the target cluster
               type: string
+            installerImageReleaseImage:
+              description: InstallerImageReleaseImage is the release image InstallerImage
+                was resolved from, when it was resolved from a release image. The
+                installer image is resolved again when the release image changes before
+                the cluster is installed.
+              type: string
             selectorSyncSetStatus:
               description: SelectorSyncSetStatus is the list of status for SelectorSyncSets
                 which apply to the cluster deployment.
@@ -2590,6 +2898,9 @@ spec:
   - JSONPath: .status.completed
     name: Completed
     type: boolean
+  - JSONPath: .status.failed
+    name: Failed
+    type: boolean
   - JSONPath: .metadata.creationTimestamp
     name: Age
     type: date
@@ -2642,6 +2953,10 @@ spec:
                       description: Region is the AWS region for this deprovisioning
                         request
                       type: string
+                    userTags:
+                      description: UserTags are the additional tags the cluster's
+                        AWS resources were created with.
+                      type: object
                   type: object
               type: object
           type: object
@@ -2650,6 +2965,16 @@ spec:
             completed:
               description: Completed is true when the uninstall has completed successfully
               type: boolean
+            deletedResources:
+              description: DeletedResources is the number of cloud resources removed
+                by the uninstall, by resource type such as "ec2:instance". It is set
+                once the uninstall has completed, when the uninstall job reported
+                the counts.
+              type: object
+            failed:
+              description: Failed is true when the uninstall job has failed and will
+                not be retried by this request
+              type: boolean
           type: object
   version: v1alpha1
 status:
@@ -2722,7 +3047,9 @@ spec:
             hiveImage:
               description: HiveImage is the Hive image to use when installing or destroying
                 a cluster. If not present, the default Hive image for the clusterdeployment
-                controller is used.
+                controller is used. Changing it recreates the install job of clusters
+                which are still installing, without resolving their installer image
+                again. Installed clusters are not affected.
               type: string
             installerImage:
               description: InstallerImage is the image used to install a cluster.
@@ -2736,6 +3063,18 @@ spec:
               type: string
           type: object
         status:
+          properties:
+            installerImage:
+              description: InstallerImage is the installer image resolved from ReleaseImage
+                by the imageset job of a cluster deployment. Other cluster deployments
+                using the ClusterImageSet reuse it instead of resolving the same release
+                image again.
+              type: string
+            releaseImage:
+              description: ReleaseImage is the release image from which InstallerImage
+                was resolved. InstallerImage is ignored once spec.releaseImage no
+                longer matches it.
+              type: string
           type: object
   version: v1alpha1
 status:
@@ -2886,6 +3225,36 @@ spec:
           type: object
         spec:
           properties:
+            additionalRecords:
+              description: AdditionalRecords is a list of DNS records to create in
+                the zone in addition to the records required by the cluster, for example
+                a vanity CNAME. Records without a TTL use the RecordTTL of the zone.
+              items:
+                properties:
+                  dnsName:
+                    description: The hostname of the DNS record
+                    type: string
+                  labels:
+                    description: Labels stores labels defined for the Endpoint
+                    type: object
+                  providerSpecific:
+                    description: ProviderSpecific stores provider specific config
+                    type: object
+                  recordTTL:
+                    description: TTL for the record
+                    format: int64
+                    type: integer
+                  recordType:
+                    description: RecordType type of record, e.g. CNAME, A, SRV, TXT
+                      etc
+                    type: string
+                  targets:
+                    description: The targets the DNS record points to
+                    items:
+                      type: string
+                    type: array
+                type: object
+              type: array
             aws:
               description: AWS specifies AWS-specific cloud configuration
               properties:
@@ -2908,6 +3277,18 @@ spec:
                         type: string
                     type: object
                   type: array
+                privateZone:
+                  description: PrivateZone makes the hosted zone a private hosted
+                    zone, whose records only resolve from within the VPC it is associated
+                    with. Private zones are not linked to their parent domain.
+                  properties:
+                    subnets:
+                      description: Subnets are subnets (by ID) within the VPC to associate
+                        the private hosted zone with.
+                      items:
+                        type: string
+                      type: array
+                  type: object
                 region:
                   description: Region specifies the region-specific API endpoint to
                     use
@@ -2917,6 +3298,12 @@ spec:
               description: LinkToParentDomain specifies whether DNS records should
                 be automatically created to link this DNSZone with a parent domain.
               type: boolean
+            recordTTL:
+              description: RecordTTL is the TTL, in seconds, used for the DNS records
+                created for this zone, such as the records linking it to its parent
+                domain. If unset, a default TTL is used.
+              format: int64
+              type: integer
             zone:
               description: Zone is the DNS zone to host
               type: string
@@ -3041,6 +3428,65 @@ spec:
               items:
                 type: object
               type: array
+            clientMetricsExcludedControllers:
+              description: ClientMetricsExcludedControllers is a list of controller
+                names whose kube client requests should not be reported individually
+                in the hive_kube_client_requests_total metric. Requests from these
+                controllers are counted under the "other" controller label instead.
+              items:
+                type: string
+              type: array
+            clusterDeploymentResyncInterval:
+              description: ClusterDeploymentResyncInterval is the longest a ClusterDeployment
+                which is still installing goes without being reconciled, for example
+                "10m". This catches drift which produces no events, such as a manually
+                deleted install job. Periodic resyncs are disabled when unset.
+              type: string
+            clusterVersionPollInterval:
+              description: ClusterVersionPollInterval is the interval at which the
+                ClusterVersion of installed clusters is re-fetched to detect upgrades
+                performed outside of hive, for example "30m". A value of "0" disables
+                periodic polling. Defaults to 30 minutes.
+              type: string
+            consoleRouteCheckInterval:
+              description: ConsoleRouteCheckInterval is how often a freshly installed
+                cluster is checked for its console route until the route has been
+                created, for example "30s". The cluster stops being checked when the
+                route has not been created within an hour. Defaults to 30 seconds.
+              type: string
+            controllerHeartbeatTimeout:
+              description: ControllerHeartbeatTimeout is how long a hive controller
+                may go without completing a reconcile before the HiveControllersUnhealthy
+                condition is set, for example "2h". Every controller is probed every
+                few minutes with a request that does no work, so an idle controller
+                keeps reconciling and only a controller which has stopped working
+                through its queue is reported. Defaults to 1h.
+              type: string
+            defaultPullSecret:
+              description: DefaultPullSecret is a reference to a pull secret in the
+                hive namespace which is used for ClusterDeployments that do not reference
+                a pull secret of their own. It is copied into the namespace of each
+                ClusterDeployment using it.
+              type: object
+            defaultSSHKey:
+              description: DefaultSSHKey is a reference to a secret in the hive namespace
+                holding the public SSH key which is used for ClusterDeployments that
+                do not reference an SSH key of their own. It is copied into the namespace
+                of each ClusterDeployment using it.
+              type: object
+            deleteInstallConfigAfterInstall:
+              description: DeleteInstallConfigAfterInstall deletes the install-config
+                ConfigMap or Secret of a cluster once it is installed, rather than
+                keeping it for debugging. It is created again if the cluster is reinstalled.
+              type: boolean
+            disableInstallPodServiceAccountToken:
+              description: DisableInstallPodServiceAccountToken stops the service
+                account token from being mounted into the installer container of install
+                pods, for environments where the installer only needs its cloud credentials.
+                The install manager container keeps the token, as it reads the cluster
+                deployment and reports the progress of the install. Enabling or disabling
+                it replaces the install jobs of clusters which are still installing.
+              type: boolean
             externalDNS:
               description: ExternalDNS specifies configuration for external-dns if
                 it is to be deployed by Hive. If absent, external-dns will not be
@@ -3062,6 +3508,116 @@ spec:
                     be used.
                   type: string
               type: object
+            failedInstallLogBytes:
+              description: FailedInstallLogBytes is the number of bytes from the end
+                of the install pod log to save when an install fails. The log tail
+                is stored in a ConfigMap owned by the ClusterDeployment so that it
+                survives the deletion of the install pod. ConfigMaps are limited to
+                1MiB so this should be kept well below that. Zero disables capturing
+                install logs.
+              format: int64
+              type: integer
+            imageResolutionTimeout:
+              description: ImageResolutionTimeout is the longest the installer image
+                of a cluster may take to be resolved, for example "30m". It is measured
+                from the creation of the cluster deployment, or from the first spec
+                change after a previous timeout. A cluster which exceeds it has its
+                imageset job deleted and the ImageResolutionTimedOut condition set,
+                and no further imageset jobs are created for it until its spec changes.
+                Disabled when unset.
+              type: string
+            imageSetJobCPURequest:
+              description: ImageSetJobCPURequest is the CPU requested by each container
+                of the jobs which resolve the installer image for a release image,
+                for example "10m". Defaults to 10m.
+              type: string
+            imageSetJobMemoryRequest:
+              description: ImageSetJobMemoryRequest is the memory requested by each
+                container of the jobs which resolve the installer image for a release
+                image, for example "64Mi". Defaults to 64Mi.
+              type: string
+            imageSetJobTTLSecondsAfterFinished:
+              description: ImageSetJobTTLSecondsAfterFinished is how long a finished
+                job which resolves the installer image of a cluster is kept before
+                it is garbage collected, so that failed jobs are cleaned up even while
+                the hive controllers are down. Defaults to 3600 seconds.
+              format: int32
+              type: integer
+            installFailureCircuitBreaker:
+              description: InstallFailureCircuitBreaker pauses the creation of new
+                install jobs for all ClusterDeployments when too many recent installs
+                have failed, for example because of a bad release image or a cloud
+                provider outage. The InstallCircuitBreakerOpen condition is set while
+                installs are paused. The circuit breaker is disabled when unset.
+              properties:
+                cooldown:
+                  description: Cooldown is how long new installs are paused once the
+                    circuit breaker trips, for example "30m". Install outcomes are
+                    counted afresh once the cooldown has passed. Defaults to 30 minutes.
+                  type: string
+                failurePercent:
+                  description: FailurePercent is the percentage of the installs finished
+                    within the window which must have failed for new installs to be
+                    paused.
+                  format: int32
+                  maximum: 100
+                  minimum: 1
+                  type: integer
+                minimumInstalls:
+                  description: MinimumInstalls is the number of installs which must
+                    have finished within the window before new installs can be paused,
+                    so that a single early failure does not trip the circuit breaker.
+                    Defaults to 5.
+                  format: int32
+                  type: integer
+                window:
+                  description: Window is how far back finished installs are counted
+                    towards the failure rate, for example "1h". Defaults to 1 hour.
+                  type: string
+              type: object
+            installLogScanLines:
+              description: InstallLogScanLines is the number of lines from the end
+                of a failed install log which are scanned for known install failures.
+                Patterns spanning several lines are matched as long as all of their
+                lines fall within this window. Defaults to 1000.
+              format: int32
+              type: integer
+            installPodAntiAffinity:
+              description: InstallPodAntiAffinity asks the scheduler to avoid placing
+                install pods on nodes which are already running an install pod. When
+                TargetNamespaces is set, install pods are spread across all the target
+                namespaces; otherwise only install pods from the same namespace are
+                spread, as pod anti-affinity cannot select every namespace. Enabling
+                or disabling it replaces the install jobs of clusters which are still
+                installing.
+              type: boolean
+            installPodTerminationGracePeriodSeconds:
+              description: InstallPodTerminationGracePeriodSeconds is how long install
+                pods are given to clean up cloud resources when they are terminated,
+                including when an outdated install job is replaced. Defaults to 600
+                seconds. Changing it replaces the install jobs of clusters which are
+                still installing.
+              format: int64
+              type: integer
+            jobDeletionPropagation:
+              description: JobDeletionPropagation is the propagation policy with which
+                the install and imageset jobs of clusters are deleted. Foreground
+                waits for the pods of a job to be deleted before the job is removed,
+                while Background removes the job straight away and lets the garbage
+                collector delete its pods, which speeds up teardown when clusters
+                are created and deleted frequently. The install job of a deleted cluster
+                is always deleted in the foreground, so that deprovision does not
+                start while the installer is still running. Defaults to Foreground.
+              enum:
+              - Foreground
+              - Background
+              type: string
+            maintenanceMode:
+              description: MaintenanceMode stops hive from launching new install and
+                imageset jobs, for example during an upgrade of hive. Jobs which are
+                already running are left to complete, and status updates and deletions
+                of ClusterDeployments continue to be processed.
+              type: boolean
             managedDomains:
               description: 'ManagedDomains is the list of DNS domains that are managed
                 by the Hive cluster When specifying ''managedDNS: true'' in a ClusterDeployment,
@@ -3071,6 +3627,114 @@ spec:
               items:
                 type: string
               type: array
+            maxConcurrentDeprovisions:
+              description: MaxConcurrentDeprovisions is the maximum number of deprovision
+                requests which may be in progress at once across all namespaces. Deleted
+                clusters beyond this limit wait for running deprovisions to complete
+                before their deprovision request is created. Zero means no limit.
+              format: int32
+              type: integer
+            maxInstallLifetime:
+              description: MaxInstallLifetime is the longest a cluster may spend installing,
+                measured from the creation of its first install job, for example "6h".
+                A cluster which exceeds it has its install job deleted and the ProvisionCompleted
+                condition set to false with the InstallTimedOut reason, and no further
+                install jobs are created for it. Unlike a deadline on an individual
+                install job, this covers every install attempt for the cluster. Disabled
+                when unset.
+              type: string
+            metrics:
+              description: Metrics configures the endpoint on which the hive controllers
+                serve their metrics.
+              properties:
+                bindAddress:
+                  description: BindAddress is the address the metrics endpoint listens
+                    on. Defaults to ":2112".
+                  type: string
+                buckets:
+                  description: Buckets overrides the bucket boundaries of the hive
+                    histograms. Histograms without an override keep their default
+                    buckets.
+                  properties:
+                    imageSetJobDelay:
+                      description: ImageSetJobDelay are the buckets of hive_cluster_deployment_imageset_job_delay_seconds.
+                      items:
+                        format: int64
+                        type: integer
+                      type: array
+                    installJobDelay:
+                      description: InstallJobDelay are the buckets of hive_cluster_deployment_install_job_delay_seconds.
+                      items:
+                        format: int64
+                        type: integer
+                      type: array
+                    installJobDuration:
+                      description: InstallJobDuration are the buckets of hive_cluster_deployment_install_job_duration_seconds.
+                      items:
+                        format: int64
+                        type: integer
+                      type: array
+                  type: object
+                tlsSecret:
+                  description: TLSSecret references a secret in the hive namespace
+                    holding the tls.crt and tls.key with which the metrics endpoint
+                    is served over HTTPS. Metrics are served over plain HTTP when
+                    unset.
+                  type: object
+              type: object
+            readOnlyMode:
+              description: ReadOnlyMode stops the clusterdeployment controller from
+                creating, updating or deleting any objects, for example to audit its
+                behavior in a new environment. Each write the controller would have
+                made, including the events it would have recorded, is logged instead.
+              type: boolean
+            reconcileFailureBackoff:
+              description: ReconcileFailureBackoff configures how long the clusterdeployment
+                controller waits before retrying a ClusterDeployment whose reconcile
+                keeps failing. Each ClusterDeployment backs off on its own, so a ClusterDeployment
+                which fails on every reconcile does not delay the others.
+              properties:
+                baseDelay:
+                  description: BaseDelay is the delay before the first retry, for
+                    example "1s". Defaults to 1 second.
+                  type: string
+                maxDelay:
+                  description: MaxDelay is the longest delay between retries, for
+                    example "10m". Defaults to 10 minutes.
+                  type: string
+              type: object
+            reportInstallMetadata:
+              description: ReportInstallMetadata copies the infra ID, cluster ID and
+                region from the metadata ConfigMap of each installed ClusterDeployment
+                into its status, so that they can be read without fetching the ConfigMap.
+              type: boolean
+            skipCRDReapply:
+              description: SkipCRDReapply disables the re-application of the hive
+                CRDs on every reconcile of the operator. This should be set when the
+                CRDs are managed outside of hive, for example through GitOps.
+              type: boolean
+            targetNamespaces:
+              description: TargetNamespaces restricts the hive controllers to the
+                objects in the listed namespaces. Namespaced objects are only watched
+                in these namespaces, and ClusterDeployments and the other namespaced
+                hive objects in any other namespace are ignored. Cluster-scoped objects
+                such as ClusterImageSets are still used. The controllers reconcile
+                every namespace when unset.
+              items:
+                type: string
+              type: array
+            validateInstallConfig:
+              description: ValidateInstallConfig enables validation of the install-config
+                generated for each ClusterDeployment before an install is launched.
+                Clusters with an invalid install-config will have the InstallConfigInvalid
+                condition set instead of starting an install that is bound to fail.
+              type: boolean
+            validateRegion:
+              description: ValidateRegion enables checking that the AWS region of
+                each ClusterDeployment is enabled for its credentials before an install
+                is launched. Clusters whose region is not enabled will have the RegionUnavailable
+                condition set instead of starting an install that is bound to fail.
+              type: boolean
           type: object
         status:
           properties:
@@ -3079,6 +3743,35 @@ spec:
                 client CA configmap data from the openshift-config-managed namespace.
                 When the configmap changes, admission is redeployed.
               type: string
+            conditions:
+              description: Conditions includes more detailed status for hive.
+              items:
+                properties:
+                  lastProbeTime:
+                    description: LastProbeTime is the last time we probed the condition.
+                    format: date-time
+                    type: string
+                  lastTransitionTime:
+                    description: LastTransitionTime is the last time the condition
+                      transitioned from one status to another.
+                    format: date-time
+                    type: string
+                  message:
+                    description: Message is a human-readable message indicating details
+                      about last transition.
+                    type: string
+                  reason:
+                    description: Reason is a unique, one-word, CamelCase reason for
+                      the condition's last transition.
+                    type: string
+                  status:
+                    description: Status is the status of the condition.
+                    type: string
+                  type:
+                    description: Type is the type of the condition.
+                    type: string
+                type: object
+              type: array
           type: object
   version: v1alpha1
 status:
@@ -3730,7 +4423,9 @@ spec:
                     type: string
                   patchType:
                     description: PatchType indicates the PatchType as "strategic"
-                      (default), "json", or "merge".
+                      (default), "json", "merge", or "auto". The "auto" type uses
+                      a strategic merge patch where the resource supports it, and
+                      a merge patch otherwise (for example for custom resources).
                     type: string
                 type: object
               type: array
@@ -4404,7 +5099,9 @@ spec:
                     type: string
                   patchType:
                     description: PatchType indicates the PatchType as "strategic"
-                      (default), "json", or "merge".
+                      (default), "json", "merge", or "auto". The "auto" type uses
+                      a strategic merge patch where the resource supports it, and
+                      a merge patch otherwise (for example for custom resources).
                     type: string
                 type: object
               type: array
@@ -4463,6 +5160,13 @@ data:
     - "PendingVerification: Your request for accessing resources in this region is being validated"
     installFailingReason: PendingVerification
     installFailingMessage: Account pending verification for region
+  QuotaExceeded: |
+    searchRegexStrings:
+    # Only the codes of exhausted quotas. RequestLimitExceeded is API throttling, which is retried.
+    - "(Address|Instance|InternetGateway|NatGateway|NetworkInterface|Route|RouteTable|RulesPerSecurityGroup|SecurityGroup|Vcpu|Volume|Vpc|VpcEndpoint)LimitExceeded: "
+    - "Quota '[A-Z_]+' exceeded"
+    installFailingReason: QuotaExceeded
+    installFailingMessage: Cloud account quota exceeded
 `)
 
 func configConfigmapsInstallLogRegexesConfigmapYamlBytes() ([]byte, error) {
@@ -21,11 +21,16 @@ import (
 	"context"
 	"crypto/md5"
 	"fmt"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/constants"
 	"github.com/openshift/hive/pkg/controller/images"
 	"github.com/openshift/hive/pkg/operator/assets"
 	"github.com/openshift/hive/pkg/operator/util"
@@ -39,6 +44,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -49,8 +56,28 @@ const (
 	// that will contain the aggregate of all AdditionalCertificateAuthorities
 	// secrets specified in HiveConfig
 	hiveAdditionalCASecret = "hive-additional-ca"
+
+	// metricsPortName is the name of the container port of the hive controllers serving metrics.
+	metricsPortName = "metrics"
+
+	metricsTLSVolumeName = "metrics-tls"
+	metricsTLSMountPath  = "/etc/metrics-tls"
 )
 
+// crdAssets are the hive CRDs re-applied by the operator on every reconcile.
+var crdAssets = []string{
+	"config/crds/hive_v1alpha1_clusterdeployment.yaml",
+	"config/crds/hive_v1alpha1_clusterdeprovisionrequest.yaml",
+	"config/crds/hive_v1alpha1_clusterimageset.yaml",
+	"config/crds/hive_v1alpha1_dnsendpoint.yaml",
+	"config/crds/hive_v1alpha1_dnszone.yaml",
+	"config/crds/hive_v1alpha1_hiveconfig.yaml",
+	"config/crds/hive_v1alpha1_selectorsyncidentityprovider.yaml",
+	"config/crds/hive_v1alpha1_selectorsyncset.yaml",
+	"config/crds/hive_v1alpha1_syncidentityprovider.yaml",
+	"config/crds/hive_v1alpha1_syncset.yaml",
+}
+
 func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helper, instance *hivev1.HiveConfig, recorder events.Recorder) error {
 
 	asset := assets.MustAsset("config/manager/deployment.yaml")
@@ -77,6 +104,231 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, dnsServersEnvVar)
 	}
 
+	if instance.Spec.ValidateInstallConfig {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ValidateInstallConfigEnvVar,
+			Value: "true",
+		})
+	}
+
+	if instance.Spec.ValidateRegion {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ValidateRegionEnvVar,
+			Value: "true",
+		})
+	}
+
+	if instance.Spec.MaintenanceMode {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.MaintenanceModeEnvVar,
+			Value: "true",
+		})
+	}
+
+	if instance.Spec.ReadOnlyMode {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ReadOnlyModeEnvVar,
+			Value: "true",
+		})
+	}
+
+	if instance.Spec.ClusterVersionPollInterval != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ClusterVersionPollIntervalEnvVar,
+			Value: instance.Spec.ClusterVersionPollInterval,
+		})
+	}
+
+	if len(instance.Spec.ClientMetricsExcludedControllers) > 0 {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ClientMetricsExcludedControllersEnvVar,
+			Value: strings.Join(instance.Spec.ClientMetricsExcludedControllers, ","),
+		})
+	}
+
+	if instance.Spec.FailedInstallLogBytes > 0 {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.FailedInstallLogBytesEnvVar,
+			Value: strconv.FormatInt(instance.Spec.FailedInstallLogBytes, 10),
+		})
+	}
+
+	if instance.Spec.ConsoleRouteCheckInterval != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ConsoleRouteCheckIntervalEnvVar,
+			Value: instance.Spec.ConsoleRouteCheckInterval,
+		})
+	}
+
+	if instance.Spec.MaxConcurrentDeprovisions > 0 {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.MaxConcurrentDeprovisionsEnvVar,
+			Value: strconv.FormatInt(int64(instance.Spec.MaxConcurrentDeprovisions), 10),
+		})
+	}
+
+	if instance.Spec.DefaultPullSecret != nil && instance.Spec.DefaultPullSecret.Name != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.DefaultPullSecretEnvVar,
+			Value: instance.Spec.DefaultPullSecret.Name,
+		})
+	}
+
+	if instance.Spec.DefaultSSHKey != nil && instance.Spec.DefaultSSHKey.Name != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.DefaultSSHKeyEnvVar,
+			Value: instance.Spec.DefaultSSHKey.Name,
+		})
+	}
+
+	if instance.Spec.ReportInstallMetadata {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ReportInstallMetadataEnvVar,
+			Value: "true",
+		})
+	}
+
+	if instance.Spec.ImageSetJobCPURequest != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ImageSetJobCPURequestEnvVar,
+			Value: instance.Spec.ImageSetJobCPURequest,
+		})
+	}
+
+	if instance.Spec.ImageSetJobMemoryRequest != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ImageSetJobMemoryRequestEnvVar,
+			Value: instance.Spec.ImageSetJobMemoryRequest,
+		})
+	}
+
+	if instance.Spec.InstallLogScanLines > 0 {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.InstallLogScanLinesEnvVar,
+			Value: strconv.FormatInt(int64(instance.Spec.InstallLogScanLines), 10),
+		})
+	}
+
+	if instance.Spec.ClusterDeploymentResyncInterval != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ClusterDeploymentResyncIntervalEnvVar,
+			Value: instance.Spec.ClusterDeploymentResyncInterval,
+		})
+	}
+
+	if instance.Spec.MaxInstallLifetime != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.MaxInstallLifetimeEnvVar,
+			Value: instance.Spec.MaxInstallLifetime,
+		})
+	}
+
+	if instance.Spec.ImageResolutionTimeout != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ImageResolutionTimeoutEnvVar,
+			Value: instance.Spec.ImageResolutionTimeout,
+		})
+	}
+
+	if instance.Spec.JobDeletionPropagation != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.JobDeletionPropagationEnvVar,
+			Value: string(instance.Spec.JobDeletionPropagation),
+		})
+	}
+
+	if instance.Spec.ControllerHeartbeatTimeout != "" {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ControllerHeartbeatTimeoutEnvVar,
+			Value: instance.Spec.ControllerHeartbeatTimeout,
+		})
+	}
+
+	if cb := instance.Spec.InstallFailureCircuitBreaker; cb != nil && cb.FailurePercent > 0 {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.InstallCircuitBreakerFailurePercentEnvVar,
+			Value: strconv.FormatInt(int64(cb.FailurePercent), 10),
+		})
+		if cb.MinimumInstalls > 0 {
+			hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  constants.InstallCircuitBreakerMinimumInstallsEnvVar,
+				Value: strconv.FormatInt(int64(cb.MinimumInstalls), 10),
+			})
+		}
+		if cb.Window != "" {
+			hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  constants.InstallCircuitBreakerWindowEnvVar,
+				Value: cb.Window,
+			})
+		}
+		if cb.Cooldown != "" {
+			hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  constants.InstallCircuitBreakerCooldownEnvVar,
+				Value: cb.Cooldown,
+			})
+		}
+	}
+
+	if instance.Spec.InstallPodAntiAffinity {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.InstallPodAntiAffinityEnvVar,
+			Value: "true",
+		})
+	}
+
+	if instance.Spec.InstallPodTerminationGracePeriodSeconds > 0 {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.InstallPodTerminationGracePeriodEnvVar,
+			Value: strconv.FormatInt(instance.Spec.InstallPodTerminationGracePeriodSeconds, 10),
+		})
+	}
+
+	if instance.Spec.ImageSetJobTTLSecondsAfterFinished > 0 {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.ImageSetJobTTLSecondsAfterFinishedEnvVar,
+			Value: strconv.Itoa(int(instance.Spec.ImageSetJobTTLSecondsAfterFinished)),
+		})
+	}
+
+	if instance.Spec.DisableInstallPodServiceAccountToken {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.DisableInstallPodServiceAccountTokenEnvVar,
+			Value: "true",
+		})
+	}
+
+	if backoff := instance.Spec.ReconcileFailureBackoff; backoff != nil {
+		if backoff.BaseDelay != "" {
+			hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  constants.ReconcileFailureBaseDelayEnvVar,
+				Value: backoff.BaseDelay,
+			})
+		}
+		if backoff.MaxDelay != "" {
+			hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  constants.ReconcileFailureMaxDelayEnvVar,
+				Value: backoff.MaxDelay,
+			})
+		}
+	}
+
+	if instance.Spec.DeleteInstallConfigAfterInstall {
+		hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  constants.DeleteInstallConfigAfterInstallEnvVar,
+			Value: "true",
+		})
+	}
+
+	if err := configureMetrics(instance, hiveDeployment); err != nil {
+		hLog.WithError(err).Error("error configuring metrics endpoint")
+		return err
+	}
+
+	if err := configureTargetNamespaces(instance, hiveDeployment); err != nil {
+		hLog.WithError(err).Error("error configuring target namespaces")
+		return err
+	}
+
 	if err := r.includeAdditionalCAs(hLog, h, instance, hiveDeployment); err != nil {
 		return err
 	}
@@ -88,33 +340,7 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 	}
 	hLog.Infof("deployment applied (%s)", result)
 
-	applyAssets := []string{
-		"config/manager/service.yaml",
-
-		// Deploy the desired ClusterImageSets representing installable releases of OpenShift.
-		// TODO: in future this should be pipelined somehow.
-		"config/clusterimagesets/openshift-4.0-latest.yaml",
-		"config/rbac/hive_admin_role.yaml",
-		"config/rbac/hive_admin_role_binding.yaml",
-		"config/rbac/hive_reader_role.yaml",
-		"config/rbac/hive_reader_role_binding.yaml",
-
-		// Due to bug with OLM not updating CRDs on upgrades, we are re-applying
-		// the latest in the operator to ensure updates roll out.
-		"config/crds/hive_v1alpha1_clusterdeployment.yaml",
-		"config/crds/hive_v1alpha1_clusterdeprovisionrequest.yaml",
-		"config/crds/hive_v1alpha1_clusterimageset.yaml",
-		"config/crds/hive_v1alpha1_dnsendpoint.yaml",
-		"config/crds/hive_v1alpha1_dnszone.yaml",
-		"config/crds/hive_v1alpha1_hiveconfig.yaml",
-		"config/crds/hive_v1alpha1_selectorsyncidentityprovider.yaml",
-		"config/crds/hive_v1alpha1_selectorsyncset.yaml",
-		"config/crds/hive_v1alpha1_syncidentityprovider.yaml",
-		"config/crds/hive_v1alpha1_syncset.yaml",
-
-		"config/configmaps/install-log-regexes-configmap.yaml",
-	}
-	for _, a := range applyAssets {
+	for _, a := range hiveAssets(instance) {
 		err = util.ApplyAsset(h, a, hLog)
 		if err != nil {
 			return err
@@ -150,6 +376,129 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 	return nil
 }
 
+// hiveAssets returns the assets that deployHive applies after the hive-controllers deployment.
+func hiveAssets(instance *hivev1.HiveConfig) []string {
+	applyAssets := []string{
+		"config/manager/service.yaml",
+
+		// Deploy the desired ClusterImageSets representing installable releases of OpenShift.
+		// TODO: in future this should be pipelined somehow.
+		"config/clusterimagesets/openshift-4.0-latest.yaml",
+		"config/rbac/hive_admin_role.yaml",
+		"config/rbac/hive_admin_role_binding.yaml",
+		"config/rbac/hive_reader_role.yaml",
+		"config/rbac/hive_reader_role_binding.yaml",
+	}
+
+	// Due to bug with OLM not updating CRDs on upgrades, we are re-applying
+	// the latest in the operator to ensure updates roll out. This can be disabled
+	// when the CRDs are managed outside of hive.
+	if !instance.Spec.SkipCRDReapply {
+		applyAssets = append(applyAssets, crdAssets...)
+	}
+
+	applyAssets = append(applyAssets, "config/configmaps/install-log-regexes-configmap.yaml")
+	return applyAssets
+}
+
+// configureTargetNamespaces restricts the hive controllers to the target namespaces of the HiveConfig. The
+// controllers reconcile every namespace when none are set.
+func configureTargetNamespaces(instance *hivev1.HiveConfig, hiveDeployment *appsv1.Deployment) error {
+	if len(instance.Spec.TargetNamespaces) == 0 {
+		return nil
+	}
+	namespaces := sets.NewString()
+	for _, ns := range instance.Spec.TargetNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("invalid target namespace %q: %s", ns, strings.Join(errs, ", "))
+		}
+		namespaces.Insert(ns)
+	}
+	hiveDeployment.Spec.Template.Spec.Containers[0].Env = append(hiveDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  constants.TargetNamespacesEnvVar,
+		Value: strings.Join(namespaces.List(), ","),
+	})
+	return nil
+}
+
+// configureMetrics points the metrics endpoint of the hive controllers at the configured bind address, and
+// mounts the TLS secret the endpoint is served with when one is configured. The metrics port of the container,
+// which the hive-controllers service targets, follows the bind address.
+func configureMetrics(instance *hivev1.HiveConfig, hiveDeployment *appsv1.Deployment) error {
+	config := instance.Spec.Metrics
+	if config == nil {
+		return nil
+	}
+	container := &hiveDeployment.Spec.Template.Spec.Containers[0]
+
+	if config.BindAddress != "" {
+		_, portStr, err := net.SplitHostPort(config.BindAddress)
+		if err != nil {
+			return fmt.Errorf("invalid metrics bind address %q: %v", config.BindAddress, err)
+		}
+		port, err := strconv.ParseInt(portStr, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid metrics bind address %q: %v", config.BindAddress, err)
+		}
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  constants.MetricsBindAddressEnvVar,
+			Value: config.BindAddress,
+		})
+		for i := range container.Ports {
+			if container.Ports[i].Name == metricsPortName {
+				container.Ports[i].ContainerPort = int32(port)
+			}
+		}
+	}
+
+	if config.TLSSecret != nil {
+		hiveDeployment.Spec.Template.Spec.Volumes = append(hiveDeployment.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: metricsTLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: config.TLSSecret.Name,
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      metricsTLSVolumeName,
+			MountPath: metricsTLSMountPath,
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  constants.MetricsTLSCertDirEnvVar,
+			Value: metricsTLSMountPath,
+		})
+	}
+
+	if buckets := config.Buckets; buckets != nil {
+		for _, histogram := range []struct {
+			envVar string
+			bounds []int64
+		}{
+			{envVar: constants.InstallJobDurationBucketsEnvVar, bounds: buckets.InstallJobDuration},
+			{envVar: constants.InstallJobDelayBucketsEnvVar, bounds: buckets.InstallJobDelay},
+			{envVar: constants.ImageSetJobDelayBucketsEnvVar, bounds: buckets.ImageSetJobDelay},
+		} {
+			if len(histogram.bounds) == 0 {
+				continue
+			}
+			bounds := make([]string, len(histogram.bounds))
+			for i, bound := range histogram.bounds {
+				if bound < 0 || (i > 0 && bound <= histogram.bounds[i-1]) {
+					return fmt.Errorf("invalid metrics buckets %v: bucket boundaries must be non-negative and strictly increasing", histogram.bounds)
+				}
+				bounds[i] = strconv.FormatInt(bound, 10)
+			}
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  histogram.envVar,
+				Value: strings.Join(bounds, ","),
+			})
+		}
+	}
+	return nil
+}
+
 func (r *ReconcileHiveConfig) includeAdditionalCAs(hLog log.FieldLogger, h *resource.Helper, instance *hivev1.HiveConfig, hiveDeployment *appsv1.Deployment) error {
 	additionalCA := &bytes.Buffer{}
 	for _, clientCARef := range instance.Spec.AdditionalCertificateAuthorities {
@@ -195,6 +544,7 @@ func (r *ReconcileHiveConfig) includeAdditionalCAs(hLog log.FieldLogger, h *reso
 		return err
 	}
 	hLog.Infof("additional cert secret applied (%s)", result)
+	recordAdditionalCAApplied(metricAdditionalCALastUpdated, result, time.Now())
 
 	// Generating a volume name with a hash based on the contents of the additional CA
 	// secret will ensure that when there are changes to the secret, the hive controller
@@ -0,0 +1,310 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1alpha1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/operator/assets"
+	"github.com/openshift/hive/pkg/resource"
+)
+
+func TestHiveAssets(t *testing.T) {
+	tests := []struct {
+		name           string
+		skipCRDReapply bool
+		expectCRDs     bool
+	}{
+		{
+			name:       "re-apply CRDs by default",
+			expectCRDs: true,
+		},
+		{
+			name:           "skip CRD re-apply",
+			skipCRDReapply: true,
+			expectCRDs:     false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instance := &hivev1.HiveConfig{
+				Spec: hivev1.HiveConfigSpec{
+					SkipCRDReapply: test.skipCRDReapply,
+				},
+			}
+			applyAssets := hiveAssets(instance)
+			for _, crd := range crdAssets {
+				if test.expectCRDs {
+					assert.Contains(t, applyAssets, crd, "expected CRD to be applied")
+				} else {
+					assert.NotContains(t, applyAssets, crd, "CRD should not be applied")
+				}
+			}
+			assert.Contains(t, applyAssets, "config/manager/service.yaml", "non-CRD assets should always be applied")
+			for _, a := range applyAssets {
+				_, err := assets.Asset(a)
+				assert.NoError(t, err, "missing asset %s", a)
+			}
+		})
+	}
+}
+
+func TestConfigureMetrics(t *testing.T) {
+	tests := []struct {
+		name              string
+		metrics           *hivev1.MetricsConfig
+		expectErr         bool
+		expectedEnv       map[string]string
+		expectedPort      int32
+		expectedTLSSecret string
+	}{
+		{
+			name:         "default",
+			expectedEnv:  map[string]string{},
+			expectedPort: 2112,
+		},
+		{
+			name:    "bind address",
+			metrics: &hivev1.MetricsConfig{BindAddress: ":8443"},
+			expectedEnv: map[string]string{
+				constants.MetricsBindAddressEnvVar: ":8443",
+			},
+			expectedPort: 8443,
+		},
+		{
+			name: "tls",
+			metrics: &hivev1.MetricsConfig{
+				BindAddress: "0.0.0.0:8443",
+				TLSSecret:   &corev1.LocalObjectReference{Name: "metrics-serving-cert"},
+			},
+			expectedEnv: map[string]string{
+				constants.MetricsBindAddressEnvVar: "0.0.0.0:8443",
+				constants.MetricsTLSCertDirEnvVar:  metricsTLSMountPath,
+			},
+			expectedPort:      8443,
+			expectedTLSSecret: "metrics-serving-cert",
+		},
+		{
+			name:      "invalid bind address",
+			metrics:   &hivev1.MetricsConfig{BindAddress: "8443"},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("config/manager/deployment.yaml"))
+			instance := &hivev1.HiveConfig{
+				Spec: hivev1.HiveConfigSpec{
+					Metrics: test.metrics,
+				},
+			}
+			err := configureMetrics(instance, deployment)
+			if test.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			env := map[string]string{}
+			for _, e := range container.Env {
+				if e.Name == constants.MetricsBindAddressEnvVar || e.Name == constants.MetricsTLSCertDirEnvVar {
+					env[e.Name] = e.Value
+				}
+			}
+			assert.Equal(t, test.expectedEnv, env, "unexpected metrics environment")
+
+			var port int32
+			for _, p := range container.Ports {
+				if p.Name == metricsPortName {
+					port = p.ContainerPort
+				}
+			}
+			assert.Equal(t, test.expectedPort, port, "unexpected metrics port")
+
+			var tlsSecret string
+			for _, v := range deployment.Spec.Template.Spec.Volumes {
+				if v.Name == metricsTLSVolumeName && v.Secret != nil {
+					tlsSecret = v.Secret.SecretName
+				}
+			}
+			assert.Equal(t, test.expectedTLSSecret, tlsSecret, "unexpected TLS secret volume")
+			mounted := false
+			for _, m := range container.VolumeMounts {
+				if m.Name == metricsTLSVolumeName {
+					mounted = m.MountPath == metricsTLSMountPath
+				}
+			}
+			assert.Equal(t, test.expectedTLSSecret != "", mounted, "unexpected TLS secret mount")
+		})
+	}
+}
+
+func TestRecordAdditionalCAApplied(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_additional_ca_last_updated"})
+	gaugeValue := func() float64 {
+		m := &dto.Metric{}
+		if err := gauge.Write(m); err != nil {
+			t.Fatalf("unexpected error reading gauge: %v", err)
+		}
+		return m.GetGauge().GetValue()
+	}
+
+	created := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	recordAdditionalCAApplied(gauge, resource.CreatedApplyResult, created)
+	assert.Equal(t, float64(created.Unix()), gaugeValue(), "timestamp should be set when the bundle is created")
+
+	recordAdditionalCAApplied(gauge, resource.UnchangedApplyResult, created.Add(time.Hour))
+	assert.Equal(t, float64(created.Unix()), gaugeValue(), "timestamp should not change for an unchanged bundle")
+
+	changed := created.Add(2 * time.Hour)
+	recordAdditionalCAApplied(gauge, resource.ConfiguredApplyResult, changed)
+	assert.Equal(t, float64(changed.Unix()), gaugeValue(), "timestamp should be updated for a changed bundle")
+}
+
+func TestConfigureMetricsBuckets(t *testing.T) {
+	tests := []struct {
+		name        string
+		buckets     *hivev1.MetricsBucketsConfig
+		expectErr   bool
+		expectedEnv map[string]string
+	}{
+		{
+			name:        "default buckets",
+			expectedEnv: map[string]string{},
+		},
+		{
+			name: "configured buckets",
+			buckets: &hivev1.MetricsBucketsConfig{
+				InstallJobDuration: []int64{600, 1800, 3600, 7200},
+				ImageSetJobDelay:   []int64{5, 10, 60},
+			},
+			expectedEnv: map[string]string{
+				constants.InstallJobDurationBucketsEnvVar: "600,1800,3600,7200",
+				constants.ImageSetJobDelayBucketsEnvVar:   "5,10,60",
+			},
+		},
+		{
+			name: "decreasing buckets",
+			buckets: &hivev1.MetricsBucketsConfig{
+				InstallJobDelay: []int64{60, 30},
+			},
+			expectErr: true,
+		},
+		{
+			name: "negative bucket",
+			buckets: &hivev1.MetricsBucketsConfig{
+				InstallJobDelay: []int64{-1, 30},
+			},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("config/manager/deployment.yaml"))
+			instance := &hivev1.HiveConfig{
+				Spec: hivev1.HiveConfigSpec{
+					Metrics: &hivev1.MetricsConfig{Buckets: test.buckets},
+				},
+			}
+			err := configureMetrics(instance, deployment)
+			if test.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			env := map[string]string{}
+			for _, e := range deployment.Spec.Template.Spec.Containers[0].Env {
+				switch e.Name {
+				case constants.InstallJobDurationBucketsEnvVar, constants.InstallJobDelayBucketsEnvVar, constants.ImageSetJobDelayBucketsEnvVar:
+					env[e.Name] = e.Value
+				}
+			}
+			assert.Equal(t, test.expectedEnv, env, "unexpected metrics buckets environment")
+		})
+	}
+}
+
+func TestConfigureTargetNamespaces(t *testing.T) {
+	tests := []struct {
+		name             string
+		targetNamespaces []string
+		expectErr        bool
+		expectedEnv      []string
+	}{
+		{
+			name: "cluster-wide by default",
+		},
+		{
+			name:             "single namespace",
+			targetNamespaces: []string{"clusters"},
+			expectedEnv:      []string{"clusters"},
+		},
+		{
+			name:             "sorted and deduplicated",
+			targetNamespaces: []string{"team-b", "team-a", "team-b"},
+			expectedEnv:      []string{"team-a,team-b"},
+		},
+		{
+			name:             "invalid namespace",
+			targetNamespaces: []string{"Team_A"},
+			expectErr:        true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployment := resourceread.ReadDeploymentV1OrDie(assets.MustAsset("config/manager/deployment.yaml"))
+			instance := &hivev1.HiveConfig{
+				Spec: hivev1.HiveConfigSpec{
+					TargetNamespaces: test.targetNamespaces,
+				},
+			}
+			err := configureTargetNamespaces(instance, deployment)
+			if test.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			if !assert.NoError(t, err, "unexpected error") {
+				return
+			}
+
+			var env []string
+			for _, e := range deployment.Spec.Template.Spec.Containers[0].Env {
+				if e.Name == constants.TargetNamespacesEnvVar {
+					env = append(env, e.Value)
+				}
+			}
+			assert.Equal(t, test.expectedEnv, env, "unexpected target namespaces environment")
+		})
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hive
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openshift/hive/pkg/resource"
+)
+
+var (
+	metricAdditionalCALastUpdated = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "hive_operator_additional_ca_last_updated_timestamp",
+		Help: "Unix timestamp of the last time the operator applied a changed additional CA bundle.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricAdditionalCALastUpdated)
+}
+
+// recordAdditionalCAApplied sets the gauge to the given time if applying the additional CA bundle changed it.
+func recordAdditionalCAApplied(gauge prometheus.Gauge, result resource.ApplyResult, now time.Time) {
+	switch result {
+	case resource.CreatedApplyResult, resource.ConfiguredApplyResult:
+		gauge.Set(float64(now.Unix()))
+	}
+}
@@ -20,11 +20,19 @@ import (
 	"bytes"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	kcmdpatch "k8s.io/kubernetes/pkg/kubectl/cmd/patch"
 	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+	kubectlscheme "k8s.io/kubernetes/pkg/kubectl/scheme"
+)
+
+const (
+	// AutoPatchType selects a strategic merge patch for resources which support it, and a merge
+	// patch for those which don't, such as custom resources.
+	AutoPatchType = "auto"
 )
 
 var (
@@ -79,15 +87,42 @@ func (r *Helper) setupPatchCommand(name, kind, apiVersion, patchType string, f c
 
 	o := kcmdpatch.NewPatchOptions(ioStreams)
 	o.Complete(f, cmd, args)
+	if patchType == AutoPatchType {
+		mapper, err := f.ToRESTMapper()
+		if err != nil {
+			r.logger.WithError(err).Error("cannot get REST mapper")
+			return nil, err
+		}
+		patchType, err = resolveAutoPatchType(mapper, gv.WithKind(kind))
+		if err != nil {
+			r.logger.WithError(err).WithField("kind", kind).Error("cannot determine patch type")
+			return nil, err
+		}
+		r.logger.WithField("patchType", patchType).Debug("resolved automatic patch type")
+	}
 	if patchType == "" {
 		patchType = "strategic"
 	}
 	_, ok := patchTypes[patchType]
 	if !ok {
-		return nil, fmt.Errorf("Invalid patch type: %s. Valid patch types are 'strategic', 'merge' or 'json'", patchType)
+		return nil, fmt.Errorf("Invalid patch type: %s. Valid patch types are 'strategic', 'merge', 'json' or 'auto'", patchType)
 	}
 	o.PatchType = patchType
 	o.Patch = patch
 
 	return o, nil
 }
+
+// resolveAutoPatchType returns the patch type to use for the given kind when the automatic patch type is
+// requested. Strategic merge patches rely on the Go type of the resource, so only kinds known to the kubectl
+// scheme support them. Anything else, such as a custom resource, is patched with a merge patch.
+func resolveAutoPatchType(mapper meta.RESTMapper, gvk schema.GroupVersionKind) (string, error) {
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return "", err
+	}
+	if kubectlscheme.Scheme.Recognizes(mapping.GroupVersionKind) {
+		return "strategic", nil
+	}
+	return "merge", nil
+}
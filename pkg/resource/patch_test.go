@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestResolveAutoPatchType(t *testing.T) {
+	configMapGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	deploymentGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	clusterDeploymentGVK := schema.GroupVersionKind{Group: "hive.openshift.io", Version: "v1alpha1", Kind: "ClusterDeployment"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(configMapGVK, meta.RESTScopeNamespace)
+	mapper.Add(deploymentGVK, meta.RESTScopeNamespace)
+	mapper.Add(clusterDeploymentGVK, meta.RESTScopeNamespace)
+
+	tests := []struct {
+		name              string
+		gvk               schema.GroupVersionKind
+		expectedPatchType string
+		expectErr         bool
+	}{
+		{
+			name:              "core type",
+			gvk:               configMapGVK,
+			expectedPatchType: "strategic",
+		},
+		{
+			name:              "apps type",
+			gvk:               deploymentGVK,
+			expectedPatchType: "strategic",
+		},
+		{
+			name:              "custom resource",
+			gvk:               clusterDeploymentGVK,
+			expectedPatchType: "merge",
+		},
+		{
+			name:      "unknown kind",
+			gvk:       schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			patchType, err := resolveAutoPatchType(mapper, test.gvk)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expectedPatchType, patchType)
+			}
+		})
+	}
+}